@@ -39,6 +39,7 @@ func AddCommands(cmd *cobra.Command, dockerCli command.Cli) {
 		// container
 		container.NewContainerCommand(dockerCli),
 		container.NewRunCommand(dockerCli),
+		container.NewDebugCommand(dockerCli),
 
 		// image
 		image.NewImageCommand(dockerCli),
@@ -8,6 +8,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
 )
 
 type fakeClient struct {
@@ -19,17 +20,24 @@ type fakeClient struct {
 		hostConfig *container.HostConfig,
 		networkingConfig *network.NetworkingConfig,
 		containerName string) (container.ContainerCreateCreatedBody, error)
-	containerStartFunc      func(container string, options types.ContainerStartOptions) error
-	imageCreateFunc         func(parentReference string, options types.ImageCreateOptions) (io.ReadCloser, error)
-	infoFunc                func() (types.Info, error)
-	containerStatPathFunc   func(container, path string) (types.ContainerPathStat, error)
-	containerCopyFromFunc   func(container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error)
-	logFunc                 func(string, types.ContainerLogsOptions) (io.ReadCloser, error)
-	waitFunc                func(string) (<-chan container.ContainerWaitOKBody, <-chan error)
-	containerListFunc       func(types.ContainerListOptions) ([]types.Container, error)
-	containerExportFunc     func(string) (io.ReadCloser, error)
-	containerExecResizeFunc func(id string, options types.ResizeOptions) error
-	Version                 string
+	containerStartFunc       func(container string, options types.ContainerStartOptions) error
+	imageCreateFunc          func(parentReference string, options types.ImageCreateOptions) (io.ReadCloser, error)
+	infoFunc                 func() (types.Info, error)
+	containerStatPathFunc    func(container, path string) (types.ContainerPathStat, error)
+	containerCopyFromFunc    func(container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error)
+	logFunc                  func(string, types.ContainerLogsOptions) (io.ReadCloser, error)
+	waitFunc                 func(string) (<-chan container.ContainerWaitOKBody, <-chan error)
+	containerListFunc        func(types.ContainerListOptions) ([]types.Container, error)
+	containerExportFunc      func(string) (io.ReadCloser, error)
+	containerExecResizeFunc  func(id string, options types.ResizeOptions) error
+	containerMountFunc       func(container string) (string, error)
+	containerUnmountFunc     func(container string, options types.UnmountOptions) error
+	containerDiffAgainstFunc func(container, image string, options types.DiffOptions) ([]types.FileChange, error)
+	containerListFilesFunc   func(container string, options types.ListFilesOptions) ([]types.FileEntry, error)
+	containerUpdatePortsFunc func(container string, config container.PortsUpdateConfig) (nat.PortMap, error)
+	containerDebugFunc       func(container string, config container.DebugConfig) (container.ContainerCreateCreatedBody, error)
+	imageInspectFunc         func(image string) (types.ImageInspect, []byte, error)
+	Version                  string
 }
 
 func (f *fakeClient) ContainerList(_ context.Context, options types.ContainerListOptions) ([]types.Container, error) {
@@ -143,3 +151,52 @@ func (f *fakeClient) ContainerExecResize(_ context.Context, id string, options t
 	}
 	return nil
 }
+
+func (f *fakeClient) ContainerMount(_ context.Context, container string) (string, error) {
+	if f.containerMountFunc != nil {
+		return f.containerMountFunc(container)
+	}
+	return "", nil
+}
+
+func (f *fakeClient) ContainerUnmount(_ context.Context, container string, options types.UnmountOptions) error {
+	if f.containerUnmountFunc != nil {
+		return f.containerUnmountFunc(container, options)
+	}
+	return nil
+}
+
+func (f *fakeClient) ContainerDiffAgainst(_ context.Context, container, image string, options types.DiffOptions) ([]types.FileChange, error) {
+	if f.containerDiffAgainstFunc != nil {
+		return f.containerDiffAgainstFunc(container, image, options)
+	}
+	return nil, nil
+}
+
+func (f *fakeClient) ContainerListFiles(_ context.Context, container string, options types.ListFilesOptions) ([]types.FileEntry, error) {
+	if f.containerListFilesFunc != nil {
+		return f.containerListFilesFunc(container, options)
+	}
+	return nil, nil
+}
+
+func (f *fakeClient) ContainerUpdatePorts(_ context.Context, container string, config container.PortsUpdateConfig) (nat.PortMap, error) {
+	if f.containerUpdatePortsFunc != nil {
+		return f.containerUpdatePortsFunc(container, config)
+	}
+	return nil, nil
+}
+
+func (f *fakeClient) ContainerDebug(_ context.Context, ctr string, config container.DebugConfig) (container.ContainerCreateCreatedBody, error) {
+	if f.containerDebugFunc != nil {
+		return f.containerDebugFunc(ctr, config)
+	}
+	return container.ContainerCreateCreatedBody{}, nil
+}
+
+func (f *fakeClient) ImageInspectWithRaw(_ context.Context, image string) (types.ImageInspect, []byte, error) {
+	if f.imageInspectFunc != nil {
+		return f.imageInspectFunc(image)
+	}
+	return types.ImageInspect{}, nil, nil
+}
@@ -19,11 +19,15 @@ func NewContainerCommand(dockerCli command.Cli) *cobra.Command {
 		NewCommitCommand(dockerCli),
 		NewCopyCommand(dockerCli),
 		NewCreateCommand(dockerCli),
+		NewDebugCommand(dockerCli),
 		NewDiffCommand(dockerCli),
 		NewExecCommand(dockerCli),
+		newExecHistoryCommand(dockerCli),
 		NewExportCommand(dockerCli),
 		NewKillCommand(dockerCli),
 		NewLogsCommand(dockerCli),
+		newLsFilesCommand(dockerCli),
+		newMountCommand(dockerCli),
 		NewPauseCommand(dockerCli),
 		NewPortCommand(dockerCli),
 		NewRenameCommand(dockerCli),
@@ -35,6 +39,7 @@ func NewContainerCommand(dockerCli command.Cli) *cobra.Command {
 		NewStopCommand(dockerCli),
 		NewTopCommand(dockerCli),
 		NewUnpauseCommand(dockerCli),
+		newUnmountCommand(dockerCli),
 		NewUpdateCommand(dockerCli),
 		NewWaitCommand(dockerCli),
 		newListCommand(dockerCli),
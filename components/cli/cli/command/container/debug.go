@@ -0,0 +1,162 @@
+package container
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	apiclient "github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/signal"
+	"github.com/docker/docker/pkg/term"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type debugOptions struct {
+	image      string
+	rootfsPath string
+	user       string
+	env        opts.ListOpts
+	capAdd     opts.ListOpts
+	detachKeys string
+	container  string
+	command    []string
+}
+
+// NewDebugCommand creates a new cobra.Command for `docker debug`
+func NewDebugCommand(dockerCli command.Cli) *cobra.Command {
+	options := debugOptions{
+		env:    opts.NewListOpts(opts.ValidateEnv),
+		capAdd: opts.NewListOpts(nil),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "debug [OPTIONS] CONTAINER [COMMAND] [ARG...]",
+		Short: "Run an ephemeral debug container in the namespaces of a running container",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.container = args[0]
+			options.command = args[1:]
+			return runDebug(dockerCli, options)
+		},
+		Annotations: map[string]string{
+			"version": "1.41",
+			"ostype":  "linux",
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetInterspersed(false)
+
+	flags.StringVar(&options.image, "image", "busybox", "Image of the debug container")
+	flags.StringVar(&options.rootfsPath, "rootfs-path", "/target", "Path at which the filesystem of the container is mounted in the debug container")
+	flags.StringVarP(&options.user, "user", "u", "", "Username or UID (format: <name|uid>[:<group|gid>])")
+	flags.VarP(&options.env, "env", "e", "Set environment variables")
+	flags.Var(&options.capAdd, "cap-add", "Add Linux capabilities")
+	flags.StringVar(&options.detachKeys, "detach-keys", "", "Override the key sequence for detaching a container")
+
+	return cmd
+}
+
+// nolint: gocyclo
+func runDebug(dockerCli command.Cli, options debugOptions) error {
+	ctx, cancelFun := context.WithCancel(context.Background())
+	defer cancelFun()
+
+	client := dockerCli.Client()
+	stderr := dockerCli.Err()
+
+	debugConfig := container.DebugConfig{
+		Image:      options.image,
+		Cmd:        options.command,
+		Env:        options.env.GetAll(),
+		User:       options.user,
+		Tty:        dockerCli.In().IsTerminal() && dockerCli.Out().IsTerminal(),
+		RootfsPath: options.rootfsPath,
+		CapAdd:     options.capAdd.GetAll(),
+	}
+
+	// Make "not exist" errors of the container take precedence over the
+	// pull of the image of the debug container.
+	if _, err := client.ContainerInspect(ctx, options.container); err != nil {
+		return err
+	}
+	if _, _, err := client.ImageInspectWithRaw(ctx, debugConfig.Image); err != nil {
+		if !apiclient.IsErrNotFound(err) {
+			return err
+		}
+		fmt.Fprintf(stderr, "Unable to find image '%s' locally\n", debugConfig.Image)
+		if err := pullImage(ctx, dockerCli, debugConfig.Image, "", stderr); err != nil {
+			return err
+		}
+	}
+
+	response, err := client.ContainerDebug(ctx, options.container, debugConfig)
+	if err != nil {
+		return err
+	}
+	for _, warning := range response.Warnings {
+		fmt.Fprintf(stderr, "WARNING: %s\n", warning)
+	}
+
+	config := &container.Config{
+		Tty:          debugConfig.Tty,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		OpenStdin:    true,
+		StdinOnce:    true,
+	}
+	if !config.Tty {
+		sigc := ForwardAllSignals(ctx, dockerCli, response.ID)
+		defer signal.StopCatch(sigc)
+	}
+
+	if options.detachKeys != "" {
+		dockerCli.ConfigFile().DetachKeys = options.detachKeys
+	}
+	var errCh chan error
+	close, err := attachContainer(ctx, dockerCli, &errCh, config, response.ID)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	statusChan := waitExitOrRemoved(ctx, dockerCli, response.ID, true)
+
+	if err := client.ContainerStart(ctx, response.ID, types.ContainerStartOptions{}); err != nil {
+		// notify the hijackedIOStreamer that we are going to exit and wait
+		// for it, so that the terminal is restored.
+		cancelFun()
+		<-errCh
+
+		reportError(stderr, "debug", err.Error(), false)
+		<-statusChan
+		return runStartContainerErr(err)
+	}
+
+	if config.Tty {
+		if err := MonitorTtySize(ctx, dockerCli, response.ID, false); err != nil {
+			fmt.Fprintln(stderr, "Error monitoring TTY size:", err)
+		}
+	}
+
+	if err := <-errCh; err != nil {
+		if _, ok := err.(term.EscapeError); ok {
+			// The user entered the detach escape sequence.
+			return nil
+		}
+
+		logrus.Debugf("Error hijack: %s", err)
+		return err
+	}
+
+	if status := <-statusChan; status != 0 {
+		return cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package container
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestDebugCommandConfig(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerDebugFunc: func(ctr string, config container.DebugConfig) (container.ContainerCreateCreatedBody, error) {
+			assert.Check(t, is.Equal("foo", ctr))
+			assert.Check(t, is.DeepEqual(container.DebugConfig{
+				Image:      "alpine",
+				Cmd:        strslice.StrSlice{"ps", "aux"},
+				Env:        []string{"FOO=bar"},
+				User:       "root",
+				RootfsPath: "/debug",
+				CapAdd:     []string{"SYS_PTRACE"},
+			}, config))
+			return container.ContainerCreateCreatedBody{}, errors.New("error creating the debug container")
+		},
+	})
+	cmd := NewDebugCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--image", "alpine", "--rootfs-path", "/debug", "-e", "FOO=bar", "-u", "root", "--cap-add", "SYS_PTRACE", "foo", "ps", "aux"})
+	assert.Check(t, is.Error(cmd.Execute(), "error creating the debug container"))
+}
+
+func TestDebugCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedError    string
+		inspectFunc      func(string) (types.ContainerJSON, error)
+		imageInspectFunc func(string) (types.ImageInspect, []byte, error)
+	}{
+		{
+			name:          "without-container",
+			args:          []string{},
+			expectedError: "requires at least 1 argument",
+		},
+		{
+			name:          "container-inspect-error",
+			args:          []string{"foo"},
+			expectedError: "no such container",
+			inspectFunc: func(string) (types.ContainerJSON, error) {
+				return types.ContainerJSON{}, errors.New("no such container")
+			},
+		},
+		{
+			name:          "image-inspect-error",
+			args:          []string{"foo"},
+			expectedError: "error inspecting the image",
+			imageInspectFunc: func(string) (types.ImageInspect, []byte, error) {
+				return types.ImageInspect{}, nil, errors.New("error inspecting the image")
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewDebugCommand(test.NewFakeCli(&fakeClient{
+				inspectFunc:      tc.inspectFunc,
+				imageInspectFunc: tc.imageInspectFunc,
+			}))
+			cmd.SetOutput(ioutil.Discard)
+			cmd.SetArgs(tc.args)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+		})
+	}
+}
@@ -6,20 +6,24 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/api/types"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type diffOptions struct {
 	container string
+	against   string
+	content   bool
+	format    string
 }
 
 // NewDiffCommand creates a new cobra.Command for `docker diff`
 func NewDiffCommand(dockerCli command.Cli) *cobra.Command {
 	var opts diffOptions
 
-	return &cobra.Command{
-		Use:   "diff CONTAINER",
+	cmd := &cobra.Command{
+		Use:   "diff [OPTIONS] CONTAINER",
 		Short: "Inspect changes to files or directories on a container's filesystem",
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -27,6 +31,16 @@ func NewDiffCommand(dockerCli command.Cli) *cobra.Command {
 			return runDiff(dockerCli, &opts)
 		},
 	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.against, "against", "", "Compare against an image instead of the image of the container")
+	flags.SetAnnotation("against", "version", []string{"1.41"})
+	flags.BoolVar(&opts.content, "content", false, "Show a diff of the content of modified text files")
+	flags.SetAnnotation("content", "version", []string{"1.41"})
+	flags.StringVar(&opts.format, "format", "", "Pretty-print changes using a Go template, or \"json\"")
+	flags.SetAnnotation("format", "version", []string{"1.41"})
+
+	return cmd
 }
 
 func runDiff(dockerCli command.Cli, opts *diffOptions) error {
@@ -35,13 +49,36 @@ func runDiff(dockerCli command.Cli, opts *diffOptions) error {
 	}
 	ctx := context.Background()
 
-	changes, err := dockerCli.Client().ContainerDiff(ctx, opts.container)
+	if opts.against == "" && !opts.content && opts.format == "" {
+		changes, err := dockerCli.Client().ContainerDiff(ctx, opts.container)
+		if err != nil {
+			return err
+		}
+		diffCtx := formatter.Context{
+			Output: dockerCli.Out(),
+			Format: NewDiffFormat("{{.Type}} {{.Path}}"),
+		}
+		return DiffFormatWrite(diffCtx, changes)
+	}
+
+	against := opts.against
+	if against == "" {
+		ctr, err := dockerCli.Client().ContainerInspect(ctx, opts.container)
+		if err != nil {
+			return err
+		}
+		against = ctr.Image
+	}
+	changes, err := dockerCli.Client().ContainerDiffAgainst(ctx, opts.container, against, types.DiffOptions{Content: opts.content})
 	if err != nil {
 		return err
 	}
+	if opts.format == "" {
+		opts.format = formatter.TableFormatKey
+	}
 	diffCtx := formatter.Context{
 		Output: dockerCli.Out(),
-		Format: NewDiffFormat("{{.Type}} {{.Path}}"),
+		Format: formatter.NewFileChangeFormat(opts.format),
 	}
-	return DiffFormatWrite(diffCtx, changes)
+	return formatter.FileChangeWrite(diffCtx, changes)
 }
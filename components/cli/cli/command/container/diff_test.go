@@ -0,0 +1,48 @@
+package container
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/archive"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestNewDiffCommandAgainst(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerDiffAgainstFunc: func(container, image string, options types.DiffOptions) ([]types.FileChange, error) {
+			assert.Check(t, is.Equal("foo", container))
+			assert.Check(t, is.Equal("busybox", image))
+			assert.Check(t, !options.Content)
+			return []types.FileChange{
+				{Kind: archive.ChangeAdd, Path: "/foo", New: &types.FileChangeInfo{Mode: 0644, Size: 6}},
+			}, nil
+		},
+	})
+	cmd := NewDiffCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--against", "busybox", "--format", "{{.Type}} {{.Path}} {{.Owner}} {{.Size}}", "foo"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("A /foo 0:0 6B\n", cli.OutBuffer().String()))
+}
+
+func TestNewDiffCommandContentDefaultsToContainerImage(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(container string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{Image: "sha256:abc"}}, nil
+		},
+		containerDiffAgainstFunc: func(container, image string, options types.DiffOptions) ([]types.FileChange, error) {
+			assert.Check(t, is.Equal("sha256:abc", image))
+			assert.Check(t, options.Content)
+			return []types.FileChange{}, nil
+		},
+	})
+	cmd := NewDiffCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--content", "--format", "json", "foo"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("[]\n", cli.OutBuffer().String()))
+}
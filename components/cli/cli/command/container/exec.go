@@ -27,6 +27,8 @@ type execOptions struct {
 	workdir     string
 	container   string
 	command     []string
+	log         bool
+	logSet      bool
 }
 
 func newExecOptions() execOptions {
@@ -44,6 +46,7 @@ func NewExecCommand(dockerCli command.Cli) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options.container = args[0]
 			options.command = args[1:]
+			options.logSet = cmd.Flags().Changed("log")
 			return runExec(dockerCli, options)
 		},
 	}
@@ -61,6 +64,8 @@ func NewExecCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation("env", "version", []string{"1.25"})
 	flags.StringVarP(&options.workdir, "workdir", "w", "", "Working directory inside the container")
 	flags.SetAnnotation("workdir", "version", []string{"1.35"})
+	flags.BoolVar(&options.log, "log", false, "Record the standard streams of the command in the logs of the container (defaults to the daemon setting)")
+	flags.SetAnnotation("log", "version", []string{"1.41"})
 
 	return cmd
 }
@@ -195,6 +200,9 @@ func parseExec(opts execOptions, configFile *configfile.ConfigFile) *types.ExecC
 		Env:        opts.env.GetAll(),
 		WorkingDir: opts.workdir,
 	}
+	if opts.logSet {
+		execConfig.Log = &opts.log
+	}
 
 	// If -d is not set, attach to everything by default
 	if !opts.detach {
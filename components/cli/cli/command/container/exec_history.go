@@ -0,0 +1,54 @@
+package container
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/spf13/cobra"
+)
+
+type execHistoryOptions struct {
+	container string
+	format    string
+	noTrunc   bool
+}
+
+// newExecHistoryCommand creates a new cobra.Command for `docker container exec-history`
+func newExecHistoryCommand(dockerCli command.Cli) *cobra.Command {
+	var opts execHistoryOptions
+
+	cmd := &cobra.Command{
+		Use:   "exec-history [OPTIONS] CONTAINER",
+		Short: "Show the history of the commands executed in a container",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.container = args[0]
+			return runExecHistory(dockerCli, opts)
+		},
+		Annotations: map[string]string{"version": "1.41"},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", "Pretty-print the history using a Go template, or \"json\"")
+	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Don't truncate output")
+
+	return cmd
+}
+
+func runExecHistory(dockerCli command.Cli, opts execHistoryOptions) error {
+	ctr, err := dockerCli.Client().ContainerInspect(context.Background(), opts.container)
+	if err != nil {
+		return err
+	}
+	if opts.format == "" {
+		opts.format = formatter.TableFormatKey
+	}
+	execHistoryCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: NewExecHistoryFormat(opts.format),
+		Trunc:  !opts.noTrunc,
+	}
+	return ExecHistoryWrite(execHistoryCtx, ctr.ExecHistory)
+}
@@ -0,0 +1,104 @@
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestExecHistoryContextFormatWrite(t *testing.T) {
+	exitCode := 3
+	start := time.Date(2019, 7, 4, 8, 4, 12, 0, time.UTC)
+	entries := []*types.ExecHistoryEntry{
+		{
+			ID:       "b35395de42bc8abd327f9dd65d913b9ba28c74d2f0734eeeae84fa1c616a0fca",
+			Cmd:      []string{"sh", "-c", "cat /etc/shadow; exit 3"},
+			User:     "root",
+			Caller:   "cn=alice",
+			Logged:   true,
+			Start:    start,
+			End:      start.Add(2 * time.Second),
+			ExitCode: &exitCode,
+		},
+		{
+			ID:    "3fc1232e5cd20c8de182ed81178503dc6437f4e7ef12b52cc5e8de020652f1c4",
+			Cmd:   []string{"top"},
+			User:  "1000",
+			Start: start,
+		},
+		{
+			ID:    "6e5a4f0c6e3b4dd5f0d7d8e6ad3d1d8a9c6f2f4b2a2e1c1a7d6e0f8a9b7c6d5e",
+			Cmd:   []string{"sleep", "60"},
+			Start: start,
+			End:   start.Add(time.Minute),
+		},
+	}
+
+	cases := []struct {
+		context  formatter.Context
+		expected string
+	}{
+		{
+			formatter.Context{Format: NewExecHistoryFormat("table {{.ID}}\t{{.Command}}\t{{.User}}\t{{.Duration}}\t{{.Status}}"), Trunc: true},
+			`EXEC ID             COMMAND                  USER                DURATION            STATUS
+b35395de42bc        "sh -c cat /etc/shad…"   root                2 seconds           Exited (3)
+3fc1232e5cd2        "top"                    1000                                    Running
+6e5a4f0c6e3b        "sleep 60"                                   About a minute      Unknown
+`,
+		},
+		{
+			formatter.Context{Format: NewExecHistoryFormat("{{.ID}} {{.Command}} {{.Logged}} {{.Caller}}")},
+			`b35395de42bc8abd327f9dd65d913b9ba28c74d2f0734eeeae84fa1c616a0fca "sh -c cat /etc/shadow; exit 3" true cn=alice
+3fc1232e5cd20c8de182ed81178503dc6437f4e7ef12b52cc5e8de020652f1c4 "top" false 
+6e5a4f0c6e3b4dd5f0d7d8e6ad3d1d8a9c6f2f4b2a2e1c1a7d6e0f8a9b7c6d5e "sleep 60" false 
+`,
+		},
+	}
+
+	for _, testcase := range cases {
+		out := bytes.NewBufferString("")
+		testcase.context.Output = out
+		err := ExecHistoryWrite(testcase.context, entries)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(testcase.expected, out.String()))
+	}
+}
+
+func TestExecHistoryContextWriteJSON(t *testing.T) {
+	out := bytes.NewBufferString("")
+	err := ExecHistoryWrite(formatter.Context{Format: NewExecHistoryFormat(formatter.JSONFormatKey), Output: out}, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("[]\n", out.String()))
+
+	out.Reset()
+	err = ExecHistoryWrite(formatter.Context{Format: NewExecHistoryFormat(formatter.JSONFormatKey), Output: out}, []*types.ExecHistoryEntry{{ID: "1234", Cmd: []string{"ls"}}})
+	assert.NilError(t, err)
+	var entries []*types.ExecHistoryEntry
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &entries))
+	assert.Check(t, is.DeepEqual([]*types.ExecHistoryEntry{{ID: "1234", Cmd: []string{"ls"}}}, entries))
+}
+
+func TestExecHistoryCommand(t *testing.T) {
+	exitCode := 0
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(container string) (types.ContainerJSON, error) {
+			assert.Check(t, is.Equal("foo", container))
+			return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+				ExecHistory: []*types.ExecHistoryEntry{{ID: "1234", Cmd: []string{"ls", "/"}, User: "root", ExitCode: &exitCode}},
+			}}, nil
+		},
+	})
+	cmd := newExecHistoryCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--format", "{{.ID}}: {{.Command}} as {{.User}}, {{.Status}}", "foo"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("1234: \"ls /\" as root, Exited (0)\n", cli.OutBuffer().String()))
+}
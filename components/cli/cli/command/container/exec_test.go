@@ -24,6 +24,7 @@ func withDefaultOpts(options execOptions) execOptions {
 }
 
 func TestParseExec(t *testing.T) {
+	logDisabled := false
 	testcases := []struct {
 		options    execOptions
 		configFile configfile.ConfigFile
@@ -102,6 +103,17 @@ func TestParseExec(t *testing.T) {
 				Detach:     true,
 			},
 		},
+		{
+			options: withDefaultOpts(execOptions{
+				detach: true,
+				logSet: true,
+			}),
+			expected: types.ExecConfig{
+				Cmd:    []string{"command"},
+				Detach: true,
+				Log:    &logDisabled,
+			},
+		},
 	}
 
 	for _, testcase := range testcases {
@@ -0,0 +1,133 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stringid"
+	units "github.com/docker/go-units"
+)
+
+const (
+	defaultExecHistoryTableFormat = "table {{.ID}}\t{{.Command}}\t{{.User}}\t{{.Started}}\t{{.Duration}}\t{{.Status}}"
+
+	execIDHeader       = "EXEC ID"
+	execCommandHeader  = "COMMAND"
+	execUserHeader     = "USER"
+	execCallerHeader   = "CALLER"
+	execStartedHeader  = "STARTED"
+	execDurationHeader = "DURATION"
+	execLoggedHeader   = "LOGGED"
+)
+
+// NewExecHistoryFormat returns a format for use with an exec history Context
+func NewExecHistoryFormat(source string) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		return defaultExecHistoryTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// ExecHistoryWrite writes formatted exec history entries using the Context.
+// The json format writes the entries as a JSON array.
+func ExecHistoryWrite(ctx formatter.Context, entries []*types.ExecHistoryEntry) error {
+	if ctx.Format == formatter.JSONFormatKey {
+		if entries == nil {
+			entries = []*types.ExecHistoryEntry{}
+		}
+		enc := json.NewEncoder(ctx.Output)
+		enc.SetIndent("", "    ")
+		return enc.Encode(entries)
+	}
+
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, entry := range entries {
+			if err := format(&execHistoryContext{trunc: ctx.Trunc, e: entry}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(newExecHistoryContext(), render)
+}
+
+type execHistoryContext struct {
+	formatter.HeaderContext
+	trunc bool
+	e     *types.ExecHistoryEntry
+}
+
+func newExecHistoryContext() *execHistoryContext {
+	execHistoryCtx := execHistoryContext{}
+	execHistoryCtx.Header = formatter.SubHeaderContext{
+		"ID":       execIDHeader,
+		"Command":  execCommandHeader,
+		"User":     execUserHeader,
+		"Caller":   execCallerHeader,
+		"Started":  execStartedHeader,
+		"Duration": execDurationHeader,
+		"Status":   formatter.StatusHeader,
+		"Logged":   execLoggedHeader,
+	}
+	return &execHistoryCtx
+}
+
+func (c *execHistoryContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *execHistoryContext) ID() string {
+	if c.trunc {
+		return stringid.TruncateID(c.e.ID)
+	}
+	return c.e.ID
+}
+
+func (c *execHistoryContext) Command() string {
+	command := strings.Join(c.e.Cmd, " ")
+	if c.trunc {
+		command = formatter.Ellipsis(command, 20)
+	}
+	return strconv.Quote(command)
+}
+
+func (c *execHistoryContext) User() string {
+	return c.e.User
+}
+
+func (c *execHistoryContext) Caller() string {
+	return c.e.Caller
+}
+
+func (c *execHistoryContext) Started() string {
+	return units.HumanDuration(time.Now().UTC().Sub(c.e.Start)) + " ago"
+}
+
+func (c *execHistoryContext) Duration() string {
+	if c.e.End.IsZero() {
+		return ""
+	}
+	return units.HumanDuration(c.e.End.Sub(c.e.Start))
+}
+
+func (c *execHistoryContext) Status() string {
+	switch {
+	case c.e.ExitCode != nil:
+		return fmt.Sprintf("Exited (%d)", *c.e.ExitCode)
+	case c.e.End.IsZero():
+		return "Running"
+	default:
+		// the container stopped before the exec reported its exit code
+		return "Unknown"
+	}
+}
+
+func (c *execHistoryContext) Logged() string {
+	return strconv.FormatBool(c.e.Logged)
+}
@@ -0,0 +1,63 @@
+package container
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/spf13/cobra"
+)
+
+type unmountOptions struct {
+	container string
+	force     bool
+}
+
+// newMountCommand creates a new cobra.Command for `docker container mount`
+func newMountCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "mount CONTAINER",
+		Short: "Mount the filesystem of a container on the daemon host",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMount(dockerCli, args[0])
+		},
+		Annotations: map[string]string{"version": "1.41"},
+	}
+}
+
+// newUnmountCommand creates a new cobra.Command for `docker container unmount`
+func newUnmountCommand(dockerCli command.Cli) *cobra.Command {
+	var opts unmountOptions
+
+	cmd := &cobra.Command{
+		Use:   "unmount [OPTIONS] CONTAINER",
+		Short: "Unmount the filesystem of a container",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.container = args[0]
+			return runUnmount(dockerCli, opts)
+		},
+		Annotations: map[string]string{"version": "1.41"},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.force, "force", "f", false, "Release all mounts of the filesystem")
+
+	return cmd
+}
+
+func runMount(dockerCli command.Cli, container string) error {
+	path, err := dockerCli.Client().ContainerMount(context.Background(), container)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), path)
+	return nil
+}
+
+func runUnmount(dockerCli command.Cli, opts unmountOptions) error {
+	return dockerCli.Client().ContainerUnmount(context.Background(), opts.container, types.UnmountOptions{Force: opts.force})
+}
@@ -0,0 +1,61 @@
+package container
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestNewMountCommand(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerMountFunc: func(container string) (string, error) {
+			assert.Check(t, is.Equal("foo", container))
+			return "/var/lib/docker/mnt/foo", nil
+		},
+	})
+	cmd := newMountCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"foo"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("/var/lib/docker/mnt/foo\n", cli.OutBuffer().String()))
+}
+
+func TestNewMountCommandErrors(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerMountFunc: func(container string) (string, error) {
+			return "", errors.New("error mounting")
+		},
+	})
+	cmd := newMountCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"foo"})
+	assert.ErrorContains(t, cmd.Execute(), "error mounting")
+
+	cmd = newMountCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{})
+	assert.ErrorContains(t, cmd.Execute(), "requires exactly 1 argument")
+}
+
+func TestNewUnmountCommand(t *testing.T) {
+	var unmounted []types.UnmountOptions
+	cli := test.NewFakeCli(&fakeClient{
+		containerUnmountFunc: func(container string, options types.UnmountOptions) error {
+			assert.Check(t, is.Equal("foo", container))
+			unmounted = append(unmounted, options)
+			return nil
+		},
+	})
+	for _, args := range [][]string{{"foo"}, {"--force", "foo"}} {
+		cmd := newUnmountCommand(cli)
+		cmd.SetOutput(ioutil.Discard)
+		cmd.SetArgs(args)
+		assert.NilError(t, cmd.Execute())
+	}
+	assert.Check(t, is.DeepEqual([]types.UnmountOptions{{}, {Force: true}}, unmounted))
+}
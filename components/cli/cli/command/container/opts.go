@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/url"
+	"os"
 	"path"
 	"regexp"
 	"strconv"
@@ -16,6 +18,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	networktypes "github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/strslice"
+	swarmtypes "github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/signal"
@@ -35,10 +38,13 @@ type containerOptions struct {
 	volumes            opts.ListOpts
 	tmpfs              opts.ListOpts
 	mounts             opts.MountOpt
+	secrets            opts.SecretOpt
+	configs            opts.ConfigOpt
 	blkioWeightDevice  opts.WeightdeviceOpt
 	deviceReadBps      opts.ThrottledeviceOpt
 	deviceWriteBps     opts.ThrottledeviceOpt
 	links              opts.ListOpts
+	dependsOn          opts.ListOpts
 	aliases            opts.ListOpts
 	linkLocalIPs       opts.ListOpts
 	deviceReadIOps     opts.ThrottledeviceOpt
@@ -48,6 +54,7 @@ type containerOptions struct {
 	deviceCgroupRules  opts.ListOpts
 	devices            opts.ListOpts
 	gpus               opts.GpuOpts
+	ociHooks           opts.OCIHookOpt
 	ulimits            *opts.UlimitOpt
 	sysctls            *opts.MapOpts
 	publish            opts.ListOpts
@@ -138,6 +145,7 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 		dns:               opts.NewListOpts(opts.ValidateIPAddress),
 		dnsOptions:        opts.NewListOpts(nil),
 		dnsSearch:         opts.NewListOpts(opts.ValidateDNSSearch),
+		dependsOn:         opts.NewListOpts(nil),
 		deviceCgroupRules: opts.NewListOpts(validateDeviceCgroupRule),
 		deviceReadBps:     opts.NewThrottledeviceOpt(opts.ValidateThrottleBpsDevice),
 		deviceReadIOps:    opts.NewThrottledeviceOpt(opts.ValidateThrottleIOpsDevice),
@@ -190,6 +198,8 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 	flags.StringVarP(&copts.user, "user", "u", "", "Username or UID (format: <name|uid>[:<group|gid>])")
 	flags.StringVarP(&copts.workingDir, "workdir", "w", "", "Working directory inside the container")
 	flags.BoolVar(&copts.autoRemove, "rm", false, "Automatically remove the container when it exits")
+	flags.Var(&copts.dependsOn, "depends-on", "Start the container after another container is running, or healthy (format: <container>[:started|healthy])")
+	flags.SetAnnotation("depends-on", "version", []string{"1.41"})
 
 	// Security
 	flags.Var(&copts.capAdd, "cap-add", "Add Linux capabilities")
@@ -197,6 +207,9 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 	flags.BoolVar(&copts.privileged, "privileged", false, "Give extended privileges to this container")
 	flags.Var(&copts.securityOpt, "security-opt", "Security Options")
 	flags.StringVar(&copts.usernsMode, "userns", "", "User namespace to use")
+	flags.Var(&copts.ociHooks, "oci-hook", "Add an OCI lifecycle hook executed on the host (format: stage=<prestart|poststart|poststop>,path=<path>[,arg=<arg>][,env=<KEY=value>][,timeout=<seconds>])")
+	flags.SetAnnotation("oci-hook", "version", []string{"1.41"})
+	flags.SetAnnotation("oci-hook", "ostype", []string{"linux"})
 
 	// Network and port publishing flag
 	flags.Var(&copts.extraHosts, "add-host", "Add a custom host-to-IP mapping (host:ip)")
@@ -233,6 +246,10 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 	flags.Var(&copts.volumesFrom, "volumes-from", "Mount volumes from the specified container(s)")
 	flags.VarP(&copts.volumes, "volume", "v", "Bind mount a volume")
 	flags.Var(&copts.mounts, "mount", "Attach a filesystem mount to the container")
+	flags.Var(&copts.secrets, "secret", "Mount a secret of the daemon in the container")
+	flags.SetAnnotation("secret", "version", []string{"1.41"})
+	flags.Var(&copts.configs, "config", "Mount a config of the daemon in the container")
+	flags.SetAnnotation("config", "version", []string{"1.41"})
 
 	// Health-checking
 	flags.StringVar(&copts.healthCmd, "health-cmd", "", "Command to run to check health")
@@ -377,7 +394,10 @@ func parse(flags *pflag.FlagSet, copts *containerOptions, serverOS string) (*con
 		entrypoint = []string{""}
 	}
 
-	publishOpts := copts.publish.GetAll()
+	publishOpts, unixSocketBindings, err := parseUnixSocketPublishOpts(copts.publish.GetAll())
+	if err != nil {
+		return nil, err
+	}
 	var ports map[nat.Port]struct{}
 	var portBindings map[nat.Port][]nat.PortBinding
 
@@ -492,6 +512,11 @@ func parse(flags *pflag.FlagSet, copts *containerOptions, serverOS string) (*con
 		return nil, err
 	}
 
+	dependsOn, err := parseDependencies(copts.dependsOn.GetAll())
+	if err != nil {
+		return nil, err
+	}
+
 	// Healthcheck
 	var healthConfig *container.HealthConfig
 	haveHealthSettings := copts.healthCmd != "" ||
@@ -598,14 +623,15 @@ func parse(flags *pflag.FlagSet, copts *containerOptions, serverOS string) (*con
 	}
 
 	hostConfig := &container.HostConfig{
-		Binds:           binds,
-		ContainerIDFile: copts.containerIDFile,
-		OomScoreAdj:     copts.oomScoreAdj,
-		AutoRemove:      copts.autoRemove,
-		Privileged:      copts.privileged,
-		PortBindings:    portBindings,
-		Links:           copts.links.GetAll(),
-		PublishAllPorts: copts.publishAll,
+		Binds:              binds,
+		ContainerIDFile:    copts.containerIDFile,
+		OomScoreAdj:        copts.oomScoreAdj,
+		AutoRemove:         copts.autoRemove,
+		Privileged:         copts.privileged,
+		PortBindings:       portBindings,
+		UnixSocketBindings: unixSocketBindings,
+		Links:              copts.links.GetAll(),
+		PublishAllPorts:    copts.publishAll,
 		// Make sure the dns fields are never nil.
 		// New containers don't ever have those fields nil,
 		// but pre created containers can still have those nil values.
@@ -639,6 +665,10 @@ func parse(flags *pflag.FlagSet, copts *containerOptions, serverOS string) (*con
 		Mounts:         mounts,
 		MaskedPaths:    maskedPaths,
 		ReadonlyPaths:  readonlyPaths,
+		Secrets:        convertSecretReferences(copts.secrets.Value()),
+		Configs:        convertConfigReferences(copts.configs.Value()),
+		DependsOn:      dependsOn,
+		OCIHooks:       copts.ociHooks.Value(),
 	}
 
 	if copts.autoRemove && !hostConfig.RestartPolicy.IsNone() {
@@ -794,6 +824,74 @@ func parsePortOpts(publishOpts []string) ([]string, error) {
 	return optsList, nil
 }
 
+// parseUnixSocketPublishOpts separates the ports published on Unix sockets of
+// the host, in the form unix://<path>[?uid=<uid>&gid=<gid>&mode=<mode>]:<port>[/tcp],
+// from the other published ports.
+func parseUnixSocketPublishOpts(publishOpts []string) ([]string, map[nat.Port][]container.UnixSocketBinding, error) {
+	const prefix = "unix://"
+	var (
+		others   []string
+		bindings map[nat.Port][]container.UnixSocketBinding
+	)
+	for _, publish := range publishOpts {
+		if !strings.HasPrefix(publish, prefix) {
+			others = append(others, publish)
+			continue
+		}
+		i := strings.LastIndex(publish, ":")
+		if i < len(prefix) {
+			return nil, nil, errors.Errorf("invalid publish opts format (should be unix://<path>:<port> but got '%s')", publish)
+		}
+		socket, containerPort := publish[len(prefix):i], publish[i+1:]
+		proto, port := nat.SplitProtoPort(containerPort)
+		if proto != "tcp" {
+			return nil, nil, errors.Errorf("invalid proto %s for %s: only TCP ports can be published on Unix sockets", proto, publish)
+		}
+		if _, err := nat.ParsePort(port); err != nil || port == "" {
+			return nil, nil, errors.Errorf("invalid containerPort: %s", containerPort)
+		}
+		p, err := nat.NewPort(proto, port)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var query string
+		if j := strings.Index(socket, "?"); j >= 0 {
+			socket, query = socket[:j], socket[j+1:]
+		}
+		if socket == "" {
+			return nil, nil, errors.Errorf("invalid publish opts format (missing socket path in '%s')", publish)
+		}
+		binding := container.UnixSocketBinding{Path: socket}
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid options for socket %s", socket)
+		}
+		for key := range values {
+			value := values.Get(key)
+			switch key {
+			case "uid":
+				binding.UID = value
+			case "gid":
+				binding.GID = value
+			case "mode":
+				mode, err := strconv.ParseUint(value, 8, 32)
+				if err != nil {
+					return nil, nil, errors.Errorf("invalid mode %s for socket %s", value, socket)
+				}
+				binding.Mode = os.FileMode(mode)
+			default:
+				return nil, nil, errors.Errorf("unknown option %s for socket %s", key, socket)
+			}
+		}
+		if bindings == nil {
+			bindings = make(map[nat.Port][]container.UnixSocketBinding)
+		}
+		bindings[p] = append(bindings[p], binding)
+	}
+	return others, bindings, nil
+}
+
 func parseLoggingOpts(loggingDriver string, loggingOpts []string) (map[string]string, error) {
 	loggingOptsMap := opts.ConvertKVStringsToMap(loggingOpts)
 	if loggingDriver == "none" && len(loggingOpts) > 0 {
@@ -813,7 +911,9 @@ func parseSecurityOpts(securityOpts []string) ([]string, error) {
 				return securityOpts, errors.Errorf("Invalid --security-opt: %q", opt)
 			}
 		}
-		if con[0] == "seccomp" && con[1] != "unconfined" {
+		// seccomp=record:<name> records a profile in the data directory of
+		// the daemon, so it is sent to the daemon as-is.
+		if con[0] == "seccomp" && con[1] != "unconfined" && !strings.HasPrefix(con[1], "record:") {
 			f, err := ioutil.ReadFile(con[1])
 			if err != nil {
 				return securityOpts, errors.Errorf("opening seccomp profile (%s) failed: %v", con[1], err)
@@ -862,6 +962,50 @@ func parseStorageOpts(storageOpts []string) (map[string]string, error) {
 	return m, nil
 }
 
+// parseDependencies parses the dependencies set by --depends-on, in the
+// <container>[:condition] format.
+func parseDependencies(deps []string) ([]container.Dependency, error) {
+	var dependencies []container.Dependency
+	for _, d := range deps {
+		parts := strings.SplitN(d, ":", 2)
+		dep := container.Dependency{Container: parts[0]}
+		if len(parts) == 2 {
+			dep.Condition = container.DependencyCondition(parts[1])
+		}
+		if dep.Container == "" || (len(parts) == 2 && dep.Condition == "") || !dep.Condition.Valid() {
+			return nil, errors.Errorf("invalid --depends-on: %s (format: <container>[:started|healthy])", d)
+		}
+		dependencies = append(dependencies, dep)
+	}
+	return dependencies, nil
+}
+
+// convertSecretReferences converts the secrets parsed by --secret, which use
+// the references of swarm services, to the references of a container.
+func convertSecretReferences(refs []*swarmtypes.SecretReference) []*container.SecretReference {
+	var secrets []*container.SecretReference
+	for _, ref := range refs {
+		secrets = append(secrets, &container.SecretReference{
+			File:       &container.FileTarget{Name: ref.File.Name, UID: ref.File.UID, GID: ref.File.GID, Mode: ref.File.Mode},
+			SecretName: ref.SecretName,
+		})
+	}
+	return secrets
+}
+
+// convertConfigReferences converts the configs parsed by --config, which use
+// the references of swarm services, to the references of a container.
+func convertConfigReferences(refs []*swarmtypes.ConfigReference) []*container.ConfigReference {
+	var configs []*container.ConfigReference
+	for _, ref := range refs {
+		configs = append(configs, &container.ConfigReference{
+			File:       &container.FileTarget{Name: ref.File.Name, UID: ref.File.UID, GID: ref.File.GID, Mode: ref.File.Mode},
+			ConfigName: ref.ConfigName,
+		})
+	}
+	return configs
+}
+
 // parseDevice parses a device mapping string to a container.DeviceMapping struct
 func parseDevice(device, serverOS string) (container.DeviceMapping, error) {
 	switch serverOS {
@@ -1020,5 +1164,8 @@ func validateAPIVersion(c *containerConfig, serverAPIVersion string) error {
 			return errors.Errorf("bind-nonrecursive requires API v1.40 or later")
 		}
 	}
+	if len(c.HostConfig.UnixSocketBindings) > 0 && versions.LessThan(serverAPIVersion, "1.41") {
+		return errors.Errorf("publishing ports on Unix sockets requires API v1.41 or later")
+	}
 	return nil
 }
@@ -660,6 +660,75 @@ func TestParseLoggingOpts(t *testing.T) {
 	}
 }
 
+func TestParseSecretsAndConfigs(t *testing.T) {
+	_, hostconfig := mustParse(t, "--secret db-password --secret source=api-key,target=key,uid=1000,mode=0400 --config source=app.conf,target=/etc/app.conf")
+	assert.Check(t, is.DeepEqual(hostconfig.Secrets, []*container.SecretReference{
+		{
+			File:       &container.FileTarget{Name: "db-password", UID: "0", GID: "0", Mode: 0444},
+			SecretName: "db-password",
+		},
+		{
+			File:       &container.FileTarget{Name: "key", UID: "1000", GID: "0", Mode: 0400},
+			SecretName: "api-key",
+		},
+	}))
+	assert.Check(t, is.DeepEqual(hostconfig.Configs, []*container.ConfigReference{
+		{
+			File:       &container.FileTarget{Name: "/etc/app.conf", UID: "0", GID: "0", Mode: 0444},
+			ConfigName: "app.conf",
+		},
+	}))
+
+	_, hostconfig, _, err := parseRun([]string{"img"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(hostconfig.Secrets, 0))
+	assert.Check(t, is.Len(hostconfig.Configs, 0))
+
+	parseMustError(t, "--secret target=foo,uid=0")
+}
+
+func TestParseDependsOn(t *testing.T) {
+	_, hostconfig := mustParse(t, "--depends-on db:healthy --depends-on cache --depends-on queue:started")
+	assert.Check(t, is.DeepEqual(hostconfig.DependsOn, []container.Dependency{
+		{Container: "db", Condition: container.DependencyConditionHealthy},
+		{Container: "cache"},
+		{Container: "queue", Condition: container.DependencyConditionStarted},
+	}))
+
+	for _, invalid := range []string{"db:ready", "db:", ":healthy"} {
+		_, _, _, err := parseRun([]string{"--depends-on", invalid, "img", "cmd"})
+		assert.Check(t, is.ErrorContains(err, "invalid --depends-on: "+invalid), invalid)
+	}
+}
+
+func TestParseUnixSocketPublish(t *testing.T) {
+	config, hostconfig := mustParse(t, "-p unix:///run/app.sock:8080 -p unix:///run/admin.sock?uid=1000&gid=33&mode=0600:9000/tcp -p 127.0.0.1:80:80")
+	assert.Check(t, is.DeepEqual(hostconfig.UnixSocketBindings, map[nat.Port][]container.UnixSocketBinding{
+		"8080/tcp": {{Path: "/run/app.sock"}},
+		"9000/tcp": {{Path: "/run/admin.sock", UID: "1000", GID: "33", Mode: 0600}},
+	}))
+	assert.Check(t, is.DeepEqual(hostconfig.PortBindings, nat.PortMap{
+		"80/tcp": {{HostIP: "127.0.0.1", HostPort: "80"}},
+	}))
+	assert.Check(t, is.DeepEqual(config.ExposedPorts, nat.PortSet{"80/tcp": {}}))
+
+	testCases := []struct {
+		publish  string
+		expected string
+	}{
+		{"unix:///run/app.sock", "invalid publish opts format (should be unix://<path>:<port> but got 'unix:///run/app.sock')"},
+		{"unix://:8080", "invalid publish opts format (missing socket path in 'unix://:8080')"},
+		{"unix:///run/app.sock:53/udp", "invalid proto udp for unix:///run/app.sock:53/udp: only TCP ports can be published on Unix sockets"},
+		{"unix:///run/app.sock:http", "invalid containerPort: http"},
+		{"unix:///run/app.sock?mode=rw:8080", "invalid mode rw for socket /run/app.sock"},
+		{"unix:///run/app.sock?owner=www-data:8080", "unknown option owner for socket /run/app.sock"},
+	}
+	for _, tc := range testCases {
+		_, _, _, err := parseRun([]string{"-p", tc.publish, "img"})
+		assert.Check(t, is.Error(err, tc.expected), tc.publish)
+	}
+}
+
 func TestParseEnvfileVariables(t *testing.T) {
 	e := "open nonexistent: no such file or directory"
 	if runtime.GOOS == "windows" {
@@ -854,3 +923,10 @@ func TestParseSystemPaths(t *testing.T) {
 		assert.DeepEqual(t, readonlyPaths, tc.readonly)
 	}
 }
+
+func TestParseSecurityOptsSeccompRecord(t *testing.T) {
+	opts := []string{"seccomp=record:app.json", "apparmor=unconfined"}
+	securityOpts, err := parseSecurityOpts(opts)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, securityOpts, []string{"seccomp=record:app.json", "apparmor=unconfined"})
+}
@@ -34,6 +34,10 @@ func NewPortCommand(dockerCli command.Cli) *cobra.Command {
 			return runPort(dockerCli, &opts)
 		},
 	}
+	cmd.AddCommand(
+		newPortAddCommand(dockerCli),
+		newPortRemoveCommand(dockerCli),
+	)
 	return cmd
 }
 
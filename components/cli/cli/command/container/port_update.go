@@ -0,0 +1,102 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/spf13/cobra"
+)
+
+type portUpdateOptions struct {
+	container string
+	ports     []string
+}
+
+func newPortAddCommand(dockerCli command.Cli) *cobra.Command {
+	var opts portUpdateOptions
+
+	cmd := &cobra.Command{
+		Use:   "add CONTAINER [IP:][HOST_PORT:]CONTAINER_PORT[/PROTO] [[IP:][HOST_PORT:]CONTAINER_PORT[/PROTO]...]",
+		Short: "Publish ports of a container",
+		Args:  cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.container = args[0]
+			opts.ports = args[1:]
+			return runPortAdd(dockerCli, &opts)
+		},
+	}
+	cmd.Annotations = map[string]string{"version": "1.41", "ostype": "linux"}
+	return cmd
+}
+
+func newPortRemoveCommand(dockerCli command.Cli) *cobra.Command {
+	var opts portUpdateOptions
+
+	cmd := &cobra.Command{
+		Use:     "rm CONTAINER PRIVATE_PORT[/PROTO] [PRIVATE_PORT[/PROTO]...]",
+		Aliases: []string{"remove"},
+		Short:   "Unpublish ports of a container",
+		Args:    cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.container = args[0]
+			opts.ports = args[1:]
+			return runPortRemove(dockerCli, &opts)
+		},
+	}
+	cmd.Annotations = map[string]string{"version": "1.41", "ostype": "linux"}
+	return cmd
+}
+
+func runPortAdd(dockerCli command.Cli, opts *portUpdateOptions) error {
+	_, bindings, err := nat.ParsePortSpecs(opts.ports)
+	if err != nil {
+		return err
+	}
+	return updatePorts(dockerCli, opts.container, container.PortsUpdateConfig{Add: bindings})
+}
+
+func runPortRemove(dockerCli command.Cli, opts *portUpdateOptions) error {
+	var ports []nat.Port
+	for _, p := range opts.ports {
+		proto, port := nat.SplitProtoPort(p)
+		start, end, err := nat.ParsePortRangeToInt(port)
+		if err != nil || start == 0 {
+			return fmt.Errorf("invalid port %s", p)
+		}
+		for i := start; i <= end; i++ {
+			natPort, err := nat.NewPort(proto, strconv.Itoa(i))
+			if err != nil {
+				return err
+			}
+			ports = append(ports, natPort)
+		}
+	}
+	return updatePorts(dockerCli, opts.container, container.PortsUpdateConfig{Remove: ports})
+}
+
+// updatePorts updates the ports of the container, and prints the ports it
+// publishes.
+func updatePorts(dockerCli command.Cli, containerID string, config container.PortsUpdateConfig) error {
+	ports, err := dockerCli.Client().ContainerUpdatePorts(context.Background(), containerID, config)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	for from, frontends := range ports {
+		for _, frontend := range frontends {
+			lines = append(lines, fmt.Sprintf("%s -> %s:%s", from, frontend.HostIP, frontend.HostPort))
+		}
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		fmt.Fprintln(dockerCli.Out(), line)
+	}
+	return nil
+}
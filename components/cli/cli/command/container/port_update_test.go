@@ -0,0 +1,92 @@
+package container
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestPortAddCommand(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerUpdatePortsFunc: func(container string, config container.PortsUpdateConfig) (nat.PortMap, error) {
+			assert.Check(t, is.Equal("foo", container))
+			assert.Check(t, is.DeepEqual(nat.PortMap{
+				"80/tcp": {{HostIP: "127.0.0.1", HostPort: "8080"}},
+				"53/udp": {{HostPort: ""}},
+			}, config.Add))
+			assert.Check(t, is.Len(config.Remove, 0))
+			return nat.PortMap{
+				"80/tcp": {{HostIP: "127.0.0.1", HostPort: "8080"}},
+				"53/udp": {{HostIP: "0.0.0.0", HostPort: "32768"}},
+			}, nil
+		},
+	})
+	cmd := NewPortCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"add", "foo", "127.0.0.1:8080:80", "53/udp"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("53/udp -> 0.0.0.0:32768\n80/tcp -> 127.0.0.1:8080\n", cli.OutBuffer().String()))
+}
+
+func TestPortRemoveCommand(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerUpdatePortsFunc: func(container string, config container.PortsUpdateConfig) (nat.PortMap, error) {
+			assert.Check(t, is.Equal("foo", container))
+			assert.Check(t, is.DeepEqual([]nat.Port{"80/tcp", "9000/udp", "9001/udp"}, config.Remove))
+			assert.Check(t, is.Len(config.Add, 0))
+			return nat.PortMap{}, nil
+		},
+	})
+	cmd := NewPortCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"rm", "foo", "80", "9000-9001/udp"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("", cli.OutBuffer().String()))
+}
+
+func TestPortUpdateCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+		updateFunc    func(container string, config container.PortsUpdateConfig) (nat.PortMap, error)
+	}{
+		{
+			name:          "add-wrong-args",
+			args:          []string{"add", "foo"},
+			expectedError: "requires at least 2 arguments.",
+		},
+		{
+			name:          "add-invalid-port",
+			args:          []string{"add", "foo", "8080:http"},
+			expectedError: "Invalid containerPort: http",
+		},
+		{
+			name:          "rm-invalid-port",
+			args:          []string{"rm", "foo", "http"},
+			expectedError: "invalid port http",
+		},
+		{
+			name:          "update-failed",
+			args:          []string{"add", "foo", "8080:80"},
+			expectedError: "host port 0.0.0.0:8080/tcp is already published by container /bar",
+			updateFunc: func(container string, config container.PortsUpdateConfig) (nat.PortMap, error) {
+				return nil, errors.New("host port 0.0.0.0:8080/tcp is already published by container /bar")
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewPortCommand(test.NewFakeCli(&fakeClient{containerUpdatePortsFunc: tc.updateFunc}))
+			cmd.SetOutput(ioutil.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
@@ -29,6 +29,8 @@ type updateOptions struct {
 	restartPolicy      string
 	pidsLimit          int64
 	cpus               opts.NanoCPUs
+	devicesAdd         opts.ListOpts
+	devicesRemove      opts.ListOpts
 
 	nFlag int
 
@@ -37,7 +39,10 @@ type updateOptions struct {
 
 // NewUpdateCommand creates a new cobra.Command for `docker update`
 func NewUpdateCommand(dockerCli command.Cli) *cobra.Command {
-	var options updateOptions
+	options := updateOptions{
+		devicesAdd:    opts.NewListOpts(nil),
+		devicesRemove: opts.NewListOpts(nil),
+	}
 
 	cmd := &cobra.Command{
 		Use:   "update [OPTIONS] CONTAINER [CONTAINER...]",
@@ -72,6 +77,13 @@ func NewUpdateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.Var(&options.cpus, "cpus", "Number of CPUs")
 	flags.SetAnnotation("cpus", "version", []string{"1.29"})
 
+	flags.Var(&options.devicesAdd, "device-add", "Add a host device to the container")
+	flags.SetAnnotation("device-add", "version", []string{"1.41"})
+	flags.SetAnnotation("device-add", "ostype", []string{"linux"})
+	flags.Var(&options.devicesRemove, "device-rm", "Remove a device from the container, by its path in the container")
+	flags.SetAnnotation("device-rm", "version", []string{"1.41"})
+	flags.SetAnnotation("device-rm", "ostype", []string{"linux"})
+
 	return cmd
 }
 
@@ -110,9 +122,20 @@ func runUpdate(dockerCli command.Cli, options *updateOptions) error {
 		resources.PidsLimit = &options.pidsLimit
 	}
 
+	var devicesAdd []containertypes.DeviceMapping
+	for _, device := range options.devicesAdd.GetAll() {
+		deviceMapping, err := parseLinuxDevice(device)
+		if err != nil {
+			return err
+		}
+		devicesAdd = append(devicesAdd, deviceMapping)
+	}
+
 	updateConfig := containertypes.UpdateConfig{
 		Resources:     resources,
 		RestartPolicy: restartPolicy,
+		DevicesAdd:    devicesAdd,
+		DevicesRemove: options.devicesRemove.GetAll(),
 	}
 
 	ctx := context.Background()
@@ -0,0 +1,140 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/archive"
+	units "github.com/docker/go-units"
+)
+
+const (
+	defaultFileChangeTableFormat = "table {{.Type}}\t{{.Path}}\t{{.Mode}}\t{{.Owner}}\t{{.Size}}"
+
+	fileChangeTypeHeader = "CHANGE TYPE"
+	filePathHeader       = "PATH"
+	fileModeHeader       = "MODE"
+	fileOwnerHeader      = "OWNER"
+	fileDiffHeader       = "DIFF"
+)
+
+// NewFileChangeFormat returns a format for use with a file change Context
+func NewFileChangeFormat(source string) Format {
+	switch source {
+	case TableFormatKey:
+		return defaultFileChangeTableFormat
+	}
+	return Format(source)
+}
+
+// FileChangeWrite writes formatted changes between two filesystems using
+// the Context. The json format writes the changes as a JSON array. With the
+// default table format, the diffs of the content of the files follow the
+// table.
+func FileChangeWrite(ctx Context, changes []types.FileChange) error {
+	if ctx.Format == JSONFormatKey {
+		enc := json.NewEncoder(ctx.Output)
+		enc.SetIndent("", "    ")
+		return enc.Encode(changes)
+	}
+
+	render := func(format func(subContext SubContext) error) error {
+		for _, change := range changes {
+			if err := format(&fileChangeContext{c: change}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := ctx.Write(newFileChangeContext(), render); err != nil {
+		return err
+	}
+	if ctx.Format != defaultFileChangeTableFormat {
+		return nil
+	}
+	for _, change := range changes {
+		if change.Diff != "" {
+			fmt.Fprintf(ctx.Output, "\n%s", change.Diff)
+		}
+	}
+	return nil
+}
+
+type fileChangeContext struct {
+	HeaderContext
+	c types.FileChange
+}
+
+func newFileChangeContext() *fileChangeContext {
+	fileChangeCtx := fileChangeContext{}
+	fileChangeCtx.Header = SubHeaderContext{
+		"Type":  fileChangeTypeHeader,
+		"Path":  filePathHeader,
+		"Mode":  fileModeHeader,
+		"Owner": fileOwnerHeader,
+		"Size":  SizeHeader,
+		"Diff":  fileDiffHeader,
+	}
+	return &fileChangeCtx
+}
+
+func (c *fileChangeContext) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+func (c *fileChangeContext) Type() string {
+	switch c.c.Kind {
+	case archive.ChangeModify:
+		return "C"
+	case archive.ChangeAdd:
+		return "A"
+	case archive.ChangeDelete:
+		return "D"
+	}
+	return ""
+}
+
+func (c *fileChangeContext) Path() string {
+	return c.c.Path
+}
+
+// compare formats a property of the file, showing both the old and the new
+// values if the property changed.
+func (c *fileChangeContext) compare(value func(*types.FileChangeInfo) string) string {
+	switch {
+	case c.c.Old == nil && c.c.New == nil:
+		return ""
+	case c.c.Old == nil:
+		return value(c.c.New)
+	case c.c.New == nil:
+		return value(c.c.Old)
+	}
+	oldValue, newValue := value(c.c.Old), value(c.c.New)
+	if oldValue == newValue {
+		return newValue
+	}
+	return oldValue + " -> " + newValue
+}
+
+func (c *fileChangeContext) Mode() string {
+	return c.compare(func(f *types.FileChangeInfo) string {
+		return f.Mode.String()
+	})
+}
+
+func (c *fileChangeContext) Owner() string {
+	return c.compare(func(f *types.FileChangeInfo) string {
+		return fmt.Sprintf("%d:%d", f.UID, f.GID)
+	})
+}
+
+func (c *fileChangeContext) Size() string {
+	return c.compare(func(f *types.FileChangeInfo) string {
+		return units.HumanSizeWithPrecision(float64(f.Size), 3)
+	})
+}
+
+func (c *fileChangeContext) Diff() string {
+	return c.c.Diff
+}
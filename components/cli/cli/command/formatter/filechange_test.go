@@ -0,0 +1,108 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/archive"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestFileChangeContext(t *testing.T) {
+	var ctx fileChangeContext
+	cases := []struct {
+		fileChangeCtx fileChangeContext
+		expValue      string
+		call          func() string
+	}{
+		{fileChangeContext{
+			c: types.FileChange{Kind: archive.ChangeAdd},
+		}, "A", ctx.Type},
+		{fileChangeContext{
+			c: types.FileChange{Kind: archive.ChangeDelete},
+		}, "D", ctx.Type},
+		{fileChangeContext{
+			c: types.FileChange{Kind: archive.ChangeModify, Path: "/etc/passwd"},
+		}, "/etc/passwd", ctx.Path},
+		{fileChangeContext{
+			c: types.FileChange{New: &types.FileChangeInfo{Mode: 0644}},
+		}, "-rw-r--r--", ctx.Mode},
+		{fileChangeContext{
+			c: types.FileChange{Old: &types.FileChangeInfo{Mode: 0644}, New: &types.FileChangeInfo{Mode: 0600}},
+		}, "-rw-r--r-- -> -rw-------", ctx.Mode},
+		{fileChangeContext{
+			c: types.FileChange{Old: &types.FileChangeInfo{UID: 1000, GID: 1000}},
+		}, "1000:1000", ctx.Owner},
+		{fileChangeContext{
+			c: types.FileChange{Old: &types.FileChangeInfo{Size: 10}, New: &types.FileChangeInfo{Size: 2048}},
+		}, "10B -> 2.05kB", ctx.Size},
+		{fileChangeContext{
+			c: types.FileChange{Old: &types.FileChangeInfo{Size: 10}, New: &types.FileChangeInfo{Size: 10}},
+		}, "10B", ctx.Size},
+	}
+
+	for _, c := range cases {
+		ctx = c.fileChangeCtx
+		assert.Check(t, is.Equal(c.expValue, c.call()))
+	}
+}
+
+func TestFileChangeContextWrite(t *testing.T) {
+	changes := []types.FileChange{
+		{
+			Kind: archive.ChangeModify,
+			Path: "/etc/passwd",
+			Old:  &types.FileChangeInfo{Mode: 0644, Size: 340},
+			New:  &types.FileChangeInfo{Mode: 0644, Size: 385},
+			Diff: "--- a/etc/passwd\n",
+		},
+		{
+			Kind: archive.ChangeAdd,
+			Path: "/foo",
+			New:  &types.FileChangeInfo{Mode: 0755, UID: 1000, GID: 1000, Size: 6},
+		},
+	}
+
+	cases := []struct {
+		context  Context
+		expected string
+	}{
+		{
+			Context{Format: NewFileChangeFormat("table")},
+			`CHANGE TYPE         PATH                MODE                OWNER               SIZE
+C                   /etc/passwd         -rw-r--r--          0:0                 340B -> 385B
+A                   /foo                -rwxr-xr-x          1000:1000           6B
+
+--- a/etc/passwd
+`,
+		},
+		{
+			Context{Format: NewFileChangeFormat("{{.Type}} {{.Path}}")},
+			"C /etc/passwd\nA /foo\n",
+		},
+	}
+
+	for _, testcase := range cases {
+		out := bytes.NewBufferString("")
+		testcase.context.Output = out
+		err := FileChangeWrite(testcase.context, changes)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(testcase.expected, out.String()))
+	}
+}
+
+func TestFileChangeContextWriteJSON(t *testing.T) {
+	changes := []types.FileChange{
+		{Kind: archive.ChangeDelete, Path: "/etc/group", Old: &types.FileChangeInfo{Size: 10}},
+	}
+	out := bytes.NewBufferString("")
+	err := FileChangeWrite(Context{Format: NewFileChangeFormat(JSONFormatKey), Output: out}, changes)
+	assert.NilError(t, err)
+
+	var decoded []types.FileChange
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Check(t, is.DeepEqual(changes, decoded))
+}
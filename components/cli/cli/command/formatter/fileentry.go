@@ -0,0 +1,104 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	units "github.com/docker/go-units"
+)
+
+const (
+	defaultFileEntryTableFormat = "table {{.Mode}}\t{{.Owner}}\t{{.Size}}\t{{.ModifiedSince}}\t{{.Path}}\t{{.LinkTarget}}"
+
+	fileModifiedSinceHeader = "MODIFIED"
+	fileModifiedAtHeader    = "MODIFIED AT"
+	fileNameHeader          = "NAME"
+	fileLinkTargetHeader    = "LINK TARGET"
+)
+
+// NewFileEntryFormat returns a format for use with a file entry Context
+func NewFileEntryFormat(source string) Format {
+	switch source {
+	case TableFormatKey:
+		return defaultFileEntryTableFormat
+	}
+	return Format(source)
+}
+
+// FileEntryWrite writes formatted files of a container or image filesystem
+// using the Context. The json format writes the files as a JSON array.
+func FileEntryWrite(ctx Context, entries []types.FileEntry) error {
+	if ctx.Format == JSONFormatKey {
+		enc := json.NewEncoder(ctx.Output)
+		enc.SetIndent("", "    ")
+		return enc.Encode(entries)
+	}
+
+	render := func(format func(subContext SubContext) error) error {
+		for _, entry := range entries {
+			if err := format(&fileEntryContext{e: entry}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(newFileEntryContext(), render)
+}
+
+type fileEntryContext struct {
+	HeaderContext
+	e types.FileEntry
+}
+
+func newFileEntryContext() *fileEntryContext {
+	fileEntryCtx := fileEntryContext{}
+	fileEntryCtx.Header = SubHeaderContext{
+		"Name":          fileNameHeader,
+		"Path":          filePathHeader,
+		"Mode":          fileModeHeader,
+		"Owner":         fileOwnerHeader,
+		"Size":          SizeHeader,
+		"ModifiedSince": fileModifiedSinceHeader,
+		"ModifiedAt":    fileModifiedAtHeader,
+		"LinkTarget":    fileLinkTargetHeader,
+	}
+	return &fileEntryCtx
+}
+
+func (c *fileEntryContext) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+func (c *fileEntryContext) Name() string {
+	return c.e.Name
+}
+
+func (c *fileEntryContext) Path() string {
+	return c.e.Path
+}
+
+func (c *fileEntryContext) Mode() string {
+	return c.e.Mode.String()
+}
+
+func (c *fileEntryContext) Owner() string {
+	return fmt.Sprintf("%d:%d", c.e.UID, c.e.GID)
+}
+
+func (c *fileEntryContext) Size() string {
+	return units.HumanSizeWithPrecision(float64(c.e.Size), 3)
+}
+
+func (c *fileEntryContext) ModifiedSince() string {
+	return units.HumanDuration(time.Now().UTC().Sub(c.e.Mtime)) + " ago"
+}
+
+func (c *fileEntryContext) ModifiedAt() string {
+	return c.e.Mtime.String()
+}
+
+func (c *fileEntryContext) LinkTarget() string {
+	return c.e.LinkTarget
+}
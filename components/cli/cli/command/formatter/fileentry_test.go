@@ -0,0 +1,94 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestFileEntryContext(t *testing.T) {
+	var ctx fileEntryContext
+	cases := []struct {
+		fileEntryCtx fileEntryContext
+		expValue     string
+		call         func() string
+	}{
+		{fileEntryContext{
+			e: types.FileEntry{Name: "passwd", Path: "/etc/passwd"},
+		}, "passwd", ctx.Name},
+		{fileEntryContext{
+			e: types.FileEntry{Name: "passwd", Path: "/etc/passwd"},
+		}, "/etc/passwd", ctx.Path},
+		{fileEntryContext{
+			e: types.FileEntry{Mode: os.ModeDir | 0755},
+		}, "drwxr-xr-x", ctx.Mode},
+		{fileEntryContext{
+			e: types.FileEntry{UID: 1000, GID: 1001},
+		}, "1000:1001", ctx.Owner},
+		{fileEntryContext{
+			e: types.FileEntry{Size: 2048},
+		}, "2.05kB", ctx.Size},
+		{fileEntryContext{
+			e: types.FileEntry{Mtime: time.Now().Add(-2 * time.Hour)},
+		}, "2 hours ago", ctx.ModifiedSince},
+		{fileEntryContext{
+			e: types.FileEntry{Mode: os.ModeSymlink | 0777, LinkTarget: "../lib"},
+		}, "../lib", ctx.LinkTarget},
+	}
+
+	for _, c := range cases {
+		ctx = c.fileEntryCtx
+		assert.Check(t, is.Equal(c.expValue, c.call()))
+	}
+}
+
+func TestFileEntryContextWrite(t *testing.T) {
+	entries := []types.FileEntry{
+		{Name: "bin", Path: "/bin", Mode: os.ModeDir | 0755, Size: 4096},
+		{Name: "sh", Path: "/bin/sh", Mode: os.ModeSymlink | 0777, Size: 7, LinkTarget: "busybox"},
+	}
+
+	cases := []struct {
+		context  Context
+		expected string
+	}{
+		{
+			Context{Format: NewFileEntryFormat("table {{.Mode}}\t{{.Owner}}\t{{.Size}}\t{{.Path}}\t{{.LinkTarget}}")},
+			`MODE                OWNER               SIZE                PATH                LINK TARGET
+drwxr-xr-x          0:0                 4.1kB               /bin                
+Lrwxrwxrwx          0:0                 7B                  /bin/sh             busybox
+`,
+		},
+		{
+			Context{Format: NewFileEntryFormat("{{.Name}}")},
+			"bin\nsh\n",
+		},
+	}
+
+	for _, testcase := range cases {
+		out := bytes.NewBufferString("")
+		testcase.context.Output = out
+		err := FileEntryWrite(testcase.context, entries)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(testcase.expected, out.String()))
+	}
+}
+
+func TestFileEntryContextWriteJSON(t *testing.T) {
+	entries := []types.FileEntry{
+		{Name: "passwd", Path: "/etc/passwd", Mode: 0644, Size: 340, Mtime: time.Date(2019, 6, 12, 9, 24, 17, 0, time.UTC)},
+	}
+	out := bytes.NewBufferString("")
+	err := FileEntryWrite(Context{Format: NewFileEntryFormat(JSONFormatKey), Output: out}, entries)
+	assert.NilError(t, err)
+
+	var decoded []types.FileEntry
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Check(t, is.DeepEqual(entries, decoded))
+}
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/docker/distribution/reference"
@@ -18,6 +19,7 @@ const (
 	repositoryHeader = "REPOSITORY"
 	tagHeader        = "TAG"
 	digestHeader     = "DIGEST"
+	lastUsedAtHeader = "LAST USED AT"
 )
 
 // ImageContext contains image specific information required by the formatter, encapsulate a Context struct.
@@ -183,6 +185,14 @@ func imageFormatTaggedAndDigest(ctx ImageContext, image types.ImageSummary) []*i
 	return images
 }
 
+// imageLastUsedSort sorts images so that the least recently used images come
+// first. Images that were never used are sorted before all others.
+func imageLastUsedSort(images []*types.ImageSummary) {
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].LastUsed < images[j].LastUsed
+	})
+}
+
 type imageContext struct {
 	HeaderContext
 	trunc  bool
@@ -195,17 +205,19 @@ type imageContext struct {
 func newImageContext() *imageContext {
 	imageCtx := imageContext{}
 	imageCtx.Header = SubHeaderContext{
-		"ID":           imageIDHeader,
-		"Repository":   repositoryHeader,
-		"Tag":          tagHeader,
-		"Digest":       digestHeader,
-		"CreatedSince": CreatedSinceHeader,
-		"CreatedAt":    CreatedAtHeader,
-		"Size":         SizeHeader,
-		"Containers":   containersHeader,
-		"VirtualSize":  SizeHeader,
-		"SharedSize":   sharedSizeHeader,
-		"UniqueSize":   uniqueSizeHeader,
+		"ID":            imageIDHeader,
+		"Repository":    repositoryHeader,
+		"Tag":           tagHeader,
+		"Digest":        digestHeader,
+		"CreatedSince":  CreatedSinceHeader,
+		"CreatedAt":     CreatedAtHeader,
+		"Size":          SizeHeader,
+		"Containers":    containersHeader,
+		"VirtualSize":   SizeHeader,
+		"SharedSize":    sharedSizeHeader,
+		"UniqueSize":    uniqueSizeHeader,
+		"LastUsedAt":    lastUsedAtHeader,
+		"LastUsedSince": lastUsedSinceHeader,
 	}
 	return &imageCtx
 }
@@ -242,6 +254,21 @@ func (c *imageContext) CreatedAt() string {
 	return time.Unix(c.i.Created, 0).String()
 }
 
+func (c *imageContext) LastUsedAt() string {
+	if c.i.LastUsed == 0 {
+		return ""
+	}
+	return time.Unix(c.i.LastUsed, 0).String()
+}
+
+func (c *imageContext) LastUsedSince() string {
+	if c.i.LastUsed == 0 {
+		return "Never"
+	}
+	lastUsedAt := time.Unix(c.i.LastUsed, 0)
+	return units.HumanDuration(time.Now().UTC().Sub(lastUsedAt)) + " ago"
+}
+
 func (c *imageContext) Size() string {
 	return units.HumanSizeWithPrecision(float64(c.i.Size), 3)
 }
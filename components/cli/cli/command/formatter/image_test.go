@@ -76,6 +76,16 @@ func TestImageContext(t *testing.T) {
 				i: types.ImageSummary{SharedSize: 5000, VirtualSize: 20000},
 			}, "15kB", ctx.UniqueSize,
 		},
+		{
+			imageContext{
+				i: types.ImageSummary{LastUsed: unix},
+			}, time.Unix(unix, 0).String(), ctx.LastUsedAt,
+		},
+		{
+			imageContext{
+				i: types.ImageSummary{},
+			}, "Never", ctx.LastUsedSince,
+		},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,194 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+const (
+	// SPDXFormatKey is the key used to format an inventory of packages as an
+	// SPDX document
+	SPDXFormatKey = "spdx"
+
+	defaultImagePackageTableFormat = "table {{.Type}}\t{{.Name}}\t{{.Version}}\t{{.Path}}"
+
+	packageTypeHeader    = "TYPE"
+	packageVersionHeader = "VERSION"
+	packageArchHeader    = "ARCH"
+	packageSourceHeader  = "SOURCE"
+	packageLicenseHeader = "LICENSE"
+	packagePURLHeader    = "PURL"
+)
+
+// NewImagePackageFormat returns a format for use with an image package Context
+func NewImagePackageFormat(source string) Format {
+	switch source {
+	case TableFormatKey:
+		return defaultImagePackageTableFormat
+	}
+	return Format(source)
+}
+
+// ImagePackagesWrite writes the formatted inventory of the packages of an
+// image using the Context. The json format writes the inventory as returned
+// by the daemon, and the spdx format writes it as an SPDX document.
+func ImagePackagesWrite(ctx Context, name string, inventory types.ImagePackages) error {
+	switch ctx.Format {
+	case JSONFormatKey:
+		enc := json.NewEncoder(ctx.Output)
+		enc.SetIndent("", "    ")
+		return enc.Encode(inventory)
+	case SPDXFormatKey:
+		enc := json.NewEncoder(ctx.Output)
+		enc.SetIndent("", "    ")
+		return enc.Encode(newSPDXDocument(name, inventory))
+	}
+
+	render := func(format func(subContext SubContext) error) error {
+		for _, pkg := range inventory.Packages {
+			if err := format(&imagePackageContext{p: pkg}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(newImagePackageContext(), render)
+}
+
+type imagePackageContext struct {
+	HeaderContext
+	p types.ImagePackage
+}
+
+func newImagePackageContext() *imagePackageContext {
+	imagePackageCtx := imagePackageContext{}
+	imagePackageCtx.Header = SubHeaderContext{
+		"Type":    packageTypeHeader,
+		"Name":    NameHeader,
+		"Version": packageVersionHeader,
+		"Arch":    packageArchHeader,
+		"Source":  packageSourceHeader,
+		"License": packageLicenseHeader,
+		"Path":    filePathHeader,
+		"PURL":    packagePURLHeader,
+	}
+	return &imagePackageCtx
+}
+
+func (c *imagePackageContext) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+func (c *imagePackageContext) Type() string {
+	return c.p.Type
+}
+
+func (c *imagePackageContext) Name() string {
+	return c.p.Name
+}
+
+func (c *imagePackageContext) Version() string {
+	return c.p.Version
+}
+
+func (c *imagePackageContext) Arch() string {
+	return c.p.Arch
+}
+
+func (c *imagePackageContext) Source() string {
+	return c.p.Source
+}
+
+func (c *imagePackageContext) License() string {
+	return c.p.License
+}
+
+func (c *imagePackageContext) Path() string {
+	return c.p.Path
+}
+
+func (c *imagePackageContext) PURL() string {
+	return c.p.PURL
+}
+
+// spdxDocument is an SPDX document in the JSON format of version 2.2 of the
+// specification, with the fields needed to describe packages.
+type spdxDocument struct {
+	SPDXVersion       string           `json:"spdxVersion"`
+	DataLicense       string           `json:"dataLicense"`
+	SPDXID            string           `json:"SPDXID"`
+	Name              string           `json:"name"`
+	DocumentNamespace string           `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo `json:"creationInfo"`
+	Packages          []spdxPackage    `json:"packages"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	SourceInfo       string            `json:"sourceInfo,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+const spdxNoAssertion = "NOASSERTION"
+
+func newSPDXDocument(name string, inventory types.ImagePackages) spdxDocument {
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.2",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: "http://spdx.org/spdxdocs/" + strings.TrimPrefix(inventory.ID, "sha256:"),
+		CreationInfo: spdxCreationInfo{
+			Created:  inventory.Created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: docker"},
+		},
+		Packages: []spdxPackage{},
+	}
+	for i, p := range inventory.Packages {
+		license := p.License
+		if license == "" {
+			license = spdxNoAssertion
+		}
+		pkg := spdxPackage{
+			Name:             p.Name,
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i+1),
+			VersionInfo:      p.Version,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  license,
+			CopyrightText:    spdxNoAssertion,
+			SourceInfo:       "found in " + p.Path,
+		}
+		if p.PURL != "" {
+			pkg.ExternalRefs = []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  p.PURL,
+			}}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+	}
+	return doc
+}
@@ -0,0 +1,98 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+var testImagePackages = types.ImagePackages{
+	ID:        "sha256:e7d92cdc71feacf90708cb59182d0df1b911f8ae022d29e8e95d75ca6a99776a",
+	OS:        "alpine",
+	OSVersion: "3.11.3",
+	Created:   time.Date(2020, 2, 3, 10, 4, 5, 0, time.UTC),
+	Packages: []types.ImagePackage{
+		{Name: "musl", Version: "1.1.24-r2", Type: "apk", Arch: "x86_64", License: "MIT", Path: "/lib/apk/db/installed", PURL: "pkg:apk/alpine/musl@1.1.24-r2?arch=x86_64"},
+		{Name: "github.com/pkg/errors", Version: "v0.9.1", Type: "golang", Path: "/usr/bin/app"},
+	},
+}
+
+func TestImagePackagesWrite(t *testing.T) {
+	cases := []struct {
+		context  Context
+		expected string
+	}{
+		{
+			Context{Format: NewImagePackageFormat("table")},
+			`TYPE                NAME                    VERSION             PATH
+apk                 musl                    1.1.24-r2           /lib/apk/db/installed
+golang              github.com/pkg/errors   v0.9.1              /usr/bin/app
+`,
+		},
+		{
+			Context{Format: NewImagePackageFormat("{{.PURL}} {{.License}}")},
+			"pkg:apk/alpine/musl@1.1.24-r2?arch=x86_64 MIT\n \n",
+		},
+	}
+
+	for _, testcase := range cases {
+		out := bytes.NewBufferString("")
+		testcase.context.Output = out
+		err := ImagePackagesWrite(testcase.context, "alpine:3.11", testImagePackages)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(testcase.expected, out.String()))
+	}
+}
+
+func TestImagePackagesWriteJSON(t *testing.T) {
+	out := bytes.NewBufferString("")
+	err := ImagePackagesWrite(Context{Format: NewImagePackageFormat(JSONFormatKey), Output: out}, "alpine:3.11", testImagePackages)
+	assert.NilError(t, err)
+
+	var decoded types.ImagePackages
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Check(t, is.DeepEqual(testImagePackages, decoded))
+}
+
+func TestImagePackagesWriteSPDX(t *testing.T) {
+	out := bytes.NewBufferString("")
+	err := ImagePackagesWrite(Context{Format: NewImagePackageFormat(SPDXFormatKey), Output: out}, "alpine:3.11", testImagePackages)
+	assert.NilError(t, err)
+
+	var doc spdxDocument
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &doc))
+	assert.Check(t, is.Equal(doc.SPDXVersion, "SPDX-2.2"))
+	assert.Check(t, is.Equal(doc.Name, "alpine:3.11"))
+	assert.Check(t, is.Equal(doc.DocumentNamespace, "http://spdx.org/spdxdocs/e7d92cdc71feacf90708cb59182d0df1b911f8ae022d29e8e95d75ca6a99776a"))
+	assert.Check(t, is.Equal(doc.CreationInfo.Created, "2020-02-03T10:04:05Z"))
+	assert.Check(t, is.DeepEqual(doc.Packages, []spdxPackage{
+		{
+			Name:             "musl",
+			SPDXID:           "SPDXRef-Package-1",
+			VersionInfo:      "1.1.24-r2",
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  "MIT",
+			CopyrightText:    "NOASSERTION",
+			SourceInfo:       "found in /lib/apk/db/installed",
+			ExternalRefs: []spdxExternalRef{
+				{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:apk/alpine/musl@1.1.24-r2?arch=x86_64"},
+			},
+		},
+		{
+			Name:             "github.com/pkg/errors",
+			SPDXID:           "SPDXRef-Package-2",
+			VersionInfo:      "v0.9.1",
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  "NOASSERTION",
+			CopyrightText:    "NOASSERTION",
+			SourceInfo:       "found in /usr/bin/app",
+		},
+	}))
+}
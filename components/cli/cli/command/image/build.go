@@ -68,6 +68,7 @@ type buildOptions struct {
 	securityOpt    []string
 	networkMode    string
 	squash         bool
+	sbom           bool
 	target         string
 	imageIDFile    string
 	stream         bool
@@ -164,6 +165,9 @@ func NewBuildCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation("squash", "experimental", nil)
 	flags.SetAnnotation("squash", "version", []string{"1.25"})
 
+	flags.BoolVar(&options.sbom, "sbom", false, "Record the inventory of the packages of the image")
+	flags.SetAnnotation("sbom", "version", []string{"1.41"})
+
 	flags.BoolVar(&options.stream, "stream", false, "Stream attaches to server to negotiate build context")
 	flags.SetAnnotation("stream", "experimental", nil)
 	flags.SetAnnotation("stream", "version", []string{"1.31"})
@@ -649,6 +653,7 @@ func imageBuildOptions(dockerCli command.Cli, options buildOptions) types.ImageB
 		SecurityOpt:    options.securityOpt,
 		NetworkMode:    options.networkMode,
 		Squash:         options.squash,
+		SBOM:           options.sbom,
 		ExtraHosts:     options.extraHosts.GetAll(),
 		Target:         options.target,
 		Platform:       options.platform,
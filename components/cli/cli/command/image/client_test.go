@@ -15,19 +15,24 @@ import (
 
 type fakeClient struct {
 	client.Client
-	imageTagFunc     func(string, string) error
-	imageSaveFunc    func(images []string) (io.ReadCloser, error)
-	imageRemoveFunc  func(image string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
-	imagePushFunc    func(ref string, options types.ImagePushOptions) (io.ReadCloser, error)
-	infoFunc         func() (types.Info, error)
-	imagePullFunc    func(ref string, options types.ImagePullOptions) (io.ReadCloser, error)
-	imagesPruneFunc  func(pruneFilter filters.Args) (types.ImagesPruneReport, error)
-	imageLoadFunc    func(input io.Reader, quiet bool) (types.ImageLoadResponse, error)
-	imageListFunc    func(options types.ImageListOptions) ([]types.ImageSummary, error)
-	imageInspectFunc func(image string) (types.ImageInspect, []byte, error)
-	imageImportFunc  func(source types.ImageImportSource, ref string, options types.ImageImportOptions) (io.ReadCloser, error)
-	imageHistoryFunc func(image string) ([]image.HistoryResponseItem, error)
-	imageBuildFunc   func(context.Context, io.Reader, types.ImageBuildOptions) (types.ImageBuildResponse, error)
+	imageTagFunc       func(string, string) error
+	imageSaveFunc      func(images []string, options types.ImageSaveOptions) (io.ReadCloser, error)
+	imageRemoveFunc    func(image string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
+	imagePushFunc      func(ref string, options types.ImagePushOptions) (io.ReadCloser, error)
+	infoFunc           func() (types.Info, error)
+	imagePullFunc      func(ref string, options types.ImagePullOptions) (io.ReadCloser, error)
+	imagesPruneFunc    func(pruneFilter filters.Args) (types.ImagesPruneReport, error)
+	imageLoadFunc      func(input io.Reader, quiet bool) (types.ImageLoadResponse, error)
+	imageListFunc      func(options types.ImageListOptions) ([]types.ImageSummary, error)
+	imageInspectFunc   func(image string) (types.ImageInspect, []byte, error)
+	imageImportFunc    func(source types.ImageImportSource, ref string, options types.ImageImportOptions) (io.ReadCloser, error)
+	imageHistoryFunc   func(image string) ([]image.HistoryResponseItem, error)
+	imageBuildFunc     func(context.Context, io.Reader, types.ImageBuildOptions) (types.ImageBuildResponse, error)
+	imageMountFunc     func(image string) (string, error)
+	imageUnmountFunc   func(image string, options types.UnmountOptions) error
+	imageDiffFunc      func(base, target string, options types.DiffOptions) ([]types.FileChange, error)
+	imageListFilesFunc func(image string, options types.ListFilesOptions) ([]types.FileEntry, error)
+	imagePackagesFunc  func(image string) (types.ImagePackages, error)
 }
 
 func (cli *fakeClient) ImageTag(_ context.Context, image, ref string) error {
@@ -37,9 +42,9 @@ func (cli *fakeClient) ImageTag(_ context.Context, image, ref string) error {
 	return nil
 }
 
-func (cli *fakeClient) ImageSave(_ context.Context, images []string) (io.ReadCloser, error) {
+func (cli *fakeClient) ImageSave(_ context.Context, images []string, options types.ImageSaveOptions) (io.ReadCloser, error) {
 	if cli.imageSaveFunc != nil {
-		return cli.imageSaveFunc(images)
+		return cli.imageSaveFunc(images, options)
 	}
 	return ioutil.NopCloser(strings.NewReader("")), nil
 }
@@ -122,3 +127,38 @@ func (cli *fakeClient) ImageBuild(ctx context.Context, context io.Reader, option
 	}
 	return types.ImageBuildResponse{Body: ioutil.NopCloser(strings.NewReader(""))}, nil
 }
+
+func (cli *fakeClient) ImageMount(_ context.Context, image string) (string, error) {
+	if cli.imageMountFunc != nil {
+		return cli.imageMountFunc(image)
+	}
+	return "", nil
+}
+
+func (cli *fakeClient) ImageUnmount(_ context.Context, image string, options types.UnmountOptions) error {
+	if cli.imageUnmountFunc != nil {
+		return cli.imageUnmountFunc(image, options)
+	}
+	return nil
+}
+
+func (cli *fakeClient) ImageDiff(_ context.Context, base, target string, options types.DiffOptions) ([]types.FileChange, error) {
+	if cli.imageDiffFunc != nil {
+		return cli.imageDiffFunc(base, target, options)
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) ImageListFiles(_ context.Context, image string, options types.ListFilesOptions) ([]types.FileEntry, error) {
+	if cli.imageListFilesFunc != nil {
+		return cli.imageListFilesFunc(image, options)
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) ImagePackages(_ context.Context, image string) (types.ImagePackages, error) {
+	if cli.imagePackagesFunc != nil {
+		return cli.imagePackagesFunc(image)
+	}
+	return types.ImagePackages{}, nil
+}
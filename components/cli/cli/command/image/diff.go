@@ -0,0 +1,56 @@
+package image
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/api/types"
+	"github.com/spf13/cobra"
+)
+
+type diffOptions struct {
+	base    string
+	target  string
+	content bool
+	format  string
+}
+
+// newDiffCommand creates a new cobra.Command for `docker image diff`
+func newDiffCommand(dockerCli command.Cli) *cobra.Command {
+	var opts diffOptions
+
+	cmd := &cobra.Command{
+		Use:   "diff [OPTIONS] IMAGE IMAGE",
+		Short: "Show changes between the filesystems of two images",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.base = args[0]
+			opts.target = args[1]
+			return runDiff(dockerCli, opts)
+		},
+		Annotations: map[string]string{"version": "1.41"},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.content, "content", false, "Show a diff of the content of modified text files")
+	flags.StringVar(&opts.format, "format", "", "Pretty-print changes using a Go template, or \"json\"")
+
+	return cmd
+}
+
+func runDiff(dockerCli command.Cli, opts diffOptions) error {
+	changes, err := dockerCli.Client().ImageDiff(context.Background(), opts.base, opts.target, types.DiffOptions{Content: opts.content})
+	if err != nil {
+		return err
+	}
+	if opts.format == "" {
+		opts.format = formatter.TableFormatKey
+	}
+	diffCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: formatter.NewFileChangeFormat(opts.format),
+	}
+	return formatter.FileChangeWrite(diffCtx, changes)
+}
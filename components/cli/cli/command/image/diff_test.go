@@ -0,0 +1,70 @@
+package image
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestNewDiffCommand(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imageDiffFunc: func(base, target string, options types.DiffOptions) ([]types.FileChange, error) {
+			assert.Check(t, is.Equal("base", base))
+			assert.Check(t, is.Equal("target", target))
+			assert.Check(t, options.Content)
+			return []types.FileChange{
+				{
+					Kind: archive.ChangeModify,
+					Path: "/etc/passwd",
+					Old:  &types.FileChangeInfo{Mode: 0644, Size: 340},
+					New:  &types.FileChangeInfo{Mode: 0600, Size: 340},
+				},
+				{
+					Kind: archive.ChangeDelete,
+					Path: "/etc/group",
+					Old:  &types.FileChangeInfo{Mode: 0644, Size: 10},
+				},
+			}, nil
+		},
+	})
+	cmd := newDiffCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--content", "--format", "{{.Type}} {{.Path}} {{.Mode}}", "base", "target"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("C /etc/passwd -rw-r--r-- -> -rw-------\nD /etc/group -rw-r--r--\n", cli.OutBuffer().String()))
+}
+
+func TestNewDiffCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+		imageDiffFunc func(base, target string, options types.DiffOptions) ([]types.FileChange, error)
+	}{
+		{
+			name:          "wrong-args",
+			args:          []string{"base"},
+			expectedError: "requires exactly 2 arguments.",
+		},
+		{
+			name:          "diff-failed",
+			args:          []string{"base", "target"},
+			expectedError: "something went wrong",
+			imageDiffFunc: func(base, target string, options types.DiffOptions) ([]types.FileChange, error) {
+				return nil, errors.Errorf("something went wrong")
+			},
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newDiffCommand(test.NewFakeCli(&fakeClient{imageDiffFunc: tc.imageDiffFunc}))
+		cmd.SetOutput(ioutil.Discard)
+		cmd.SetArgs(tc.args)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
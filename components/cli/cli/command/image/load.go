@@ -6,6 +6,7 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/system"
 	"github.com/pkg/errors"
@@ -32,7 +33,7 @@ func NewLoadCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 
-	flags.StringVarP(&opts.input, "input", "i", "", "Read from tar archive file, instead of STDIN")
+	flags.StringVarP(&opts.input, "input", "i", "", "Read from tar archive file or image layout directory, instead of STDIN")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the load output")
 
 	return cmd
@@ -50,6 +51,20 @@ func runLoad(dockerCli command.Cli, opts loadOptions) error {
 		}
 		defer file.Close()
 		input = file
+
+		fi, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			// An image layout directory is sent to the daemon as an archive
+			tarball, err := archive.TarWithOptions(opts.input, &archive.TarOptions{})
+			if err != nil {
+				return err
+			}
+			defer tarball.Close()
+			input = tarball
+		}
 	}
 
 	// To avoid getting stuck, verify that a tar file is given either in
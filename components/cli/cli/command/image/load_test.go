@@ -1,6 +1,7 @@
 package image
 
 import (
+	"archive/tar"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -11,6 +12,8 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
 	"gotest.tools/golden"
 )
 
@@ -99,3 +102,30 @@ func TestNewLoadCommandSuccess(t *testing.T) {
 		golden.Assert(t, cli.OutBuffer().String(), fmt.Sprintf("load-command-success.%s.golden", tc.name))
 	}
 }
+
+func TestNewLoadCommandFromDirectory(t *testing.T) {
+	dir := fs.NewDir(t, "load-test", fs.WithFile("oci-layout", `{"imageLayoutVersion":"1.0.0"}`))
+	defer dir.Remove()
+
+	cli := test.NewFakeCli(&fakeClient{
+		imageLoadFunc: func(input io.Reader, quiet bool) (types.ImageLoadResponse, error) {
+			tr := tar.NewReader(input)
+			var names []string
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				assert.NilError(t, err)
+				names = append(names, hdr.Name)
+			}
+			assert.Check(t, is.DeepEqual([]string{"oci-layout"}, names))
+			return types.ImageLoadResponse{Body: ioutil.NopCloser(strings.NewReader("Success"))}, nil
+		},
+	})
+	cmd := NewLoadCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--input", dir.Path()})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("Success", cli.OutBuffer().String()))
+}
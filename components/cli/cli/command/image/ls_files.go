@@ -0,0 +1,65 @@
+package image
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/api/types"
+	"github.com/spf13/cobra"
+)
+
+type lsFilesOptions struct {
+	image     string
+	path      string
+	recursive bool
+	depth     int
+	format    string
+}
+
+// newLsFilesCommand creates a new cobra.Command for `docker image ls-files`
+func newLsFilesCommand(dockerCli command.Cli) *cobra.Command {
+	var opts lsFilesOptions
+
+	cmd := &cobra.Command{
+		Use:   "ls-files [OPTIONS] IMAGE [PATH]",
+		Short: "List files in the filesystem of an image",
+		Args:  cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.image = args[0]
+			opts.path = "/"
+			if len(args) > 1 {
+				opts.path = args[1]
+			}
+			return runLsFiles(dockerCli, opts)
+		},
+		Annotations: map[string]string{"version": "1.41"},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.recursive, "recursive", "R", false, "List the content of sub-directories")
+	flags.IntVar(&opts.depth, "depth", 0, "Maximum depth of sub-directories to list with --recursive (0 for no limit)")
+	flags.StringVar(&opts.format, "format", "", "Pretty-print files using a Go template, or \"json\"")
+
+	return cmd
+}
+
+func runLsFiles(dockerCli command.Cli, opts lsFilesOptions) error {
+	entries, err := dockerCli.Client().ImageListFiles(context.Background(), opts.image, types.ListFilesOptions{
+		Path:      opts.path,
+		Recursive: opts.recursive,
+		Depth:     opts.depth,
+	})
+	if err != nil {
+		return err
+	}
+	if opts.format == "" {
+		opts.format = formatter.TableFormatKey
+	}
+	filesCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: formatter.NewFileEntryFormat(opts.format),
+	}
+	return formatter.FileEntryWrite(filesCtx, entries)
+}
@@ -0,0 +1,80 @@
+package image
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestNewLsFilesCommand(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected types.ListFilesOptions
+	}{
+		{
+			name:     "root",
+			args:     []string{"busybox", "--format", "{{.Path}} {{.Mode}}"},
+			expected: types.ListFilesOptions{Path: "/"},
+		},
+		{
+			name:     "recursive",
+			args:     []string{"-R", "--depth", "2", "--format", "{{.Path}} {{.Mode}}", "busybox", "/etc"},
+			expected: types.ListFilesOptions{Path: "/etc", Recursive: true, Depth: 2},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{
+				imageListFilesFunc: func(image string, options types.ListFilesOptions) ([]types.FileEntry, error) {
+					assert.Check(t, is.Equal("busybox", image))
+					assert.Check(t, is.DeepEqual(tc.expected, options))
+					return []types.FileEntry{
+						{Name: "etc", Path: "/etc", Mode: os.ModeDir | 0755},
+						{Name: "passwd", Path: "/etc/passwd", Mode: 0644},
+					}, nil
+				},
+			})
+			cmd := newLsFilesCommand(cli)
+			cmd.SetOutput(ioutil.Discard)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.Equal("/etc drwxr-xr-x\n/etc/passwd -rw-r--r--\n", cli.OutBuffer().String()))
+		})
+	}
+}
+
+func TestNewLsFilesCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+		listFilesFunc func(image string, options types.ListFilesOptions) ([]types.FileEntry, error)
+	}{
+		{
+			name:          "wrong-args",
+			args:          []string{},
+			expectedError: "requires at least 1 and at most 2 arguments.",
+		},
+		{
+			name:          "list-failed",
+			args:          []string{"busybox"},
+			expectedError: "something went wrong",
+			listFilesFunc: func(image string, options types.ListFilesOptions) ([]types.FileEntry, error) {
+				return nil, errors.Errorf("something went wrong")
+			},
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newLsFilesCommand(test.NewFakeCli(&fakeClient{imageListFilesFunc: tc.listFilesFunc}))
+		cmd.SetOutput(ioutil.Discard)
+		cmd.SetArgs(tc.args)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
@@ -0,0 +1,63 @@
+package image
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/spf13/cobra"
+)
+
+type unmountOptions struct {
+	image string
+	force bool
+}
+
+// newMountCommand creates a new cobra.Command for `docker image mount`
+func newMountCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "mount IMAGE",
+		Short: "Mount the filesystem of an image read-only on the daemon host",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMount(dockerCli, args[0])
+		},
+		Annotations: map[string]string{"version": "1.41"},
+	}
+}
+
+// newUnmountCommand creates a new cobra.Command for `docker image unmount`
+func newUnmountCommand(dockerCli command.Cli) *cobra.Command {
+	var opts unmountOptions
+
+	cmd := &cobra.Command{
+		Use:   "unmount [OPTIONS] IMAGE",
+		Short: "Unmount the filesystem of an image",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.image = args[0]
+			return runUnmount(dockerCli, opts)
+		},
+		Annotations: map[string]string{"version": "1.41"},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.force, "force", "f", false, "Release all mounts of the filesystem")
+
+	return cmd
+}
+
+func runMount(dockerCli command.Cli, image string) error {
+	path, err := dockerCli.Client().ImageMount(context.Background(), image)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), path)
+	return nil
+}
+
+func runUnmount(dockerCli command.Cli, opts unmountOptions) error {
+	return dockerCli.Client().ImageUnmount(context.Background(), opts.image, types.UnmountOptions{Force: opts.force})
+}
@@ -0,0 +1,51 @@
+package image
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/spf13/cobra"
+)
+
+type packagesOptions struct {
+	image  string
+	format string
+}
+
+// newPackagesCommand creates a new cobra.Command for `docker image packages`
+func newPackagesCommand(dockerCli command.Cli) *cobra.Command {
+	var opts packagesOptions
+
+	cmd := &cobra.Command{
+		Use:   "packages [OPTIONS] IMAGE",
+		Short: "List the packages installed in an image",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.image = args[0]
+			return runPackages(dockerCli, opts)
+		},
+		Annotations: map[string]string{"version": "1.41"},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", "Pretty-print packages using a Go template, \"json\", or \"spdx\"")
+
+	return cmd
+}
+
+func runPackages(dockerCli command.Cli, opts packagesOptions) error {
+	inventory, err := dockerCli.Client().ImagePackages(context.Background(), opts.image)
+	if err != nil {
+		return err
+	}
+	if opts.format == "" {
+		opts.format = formatter.TableFormatKey
+	}
+	packagesCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: formatter.NewImagePackageFormat(opts.format),
+	}
+	return formatter.ImagePackagesWrite(packagesCtx, opts.image, inventory)
+}
@@ -0,0 +1,60 @@
+package image
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestNewPackagesCommand(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imagePackagesFunc: func(image string) (types.ImagePackages, error) {
+			assert.Check(t, is.Equal("alpine", image))
+			return types.ImagePackages{
+				Packages: []types.ImagePackage{
+					{Name: "busybox", Version: "1.31.1-r9", Type: "apk"},
+					{Name: "musl", Version: "1.1.24-r2", Type: "apk"},
+				},
+			}, nil
+		},
+	})
+	cmd := newPackagesCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--format", "{{.Name}}@{{.Version}}", "alpine"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("busybox@1.31.1-r9\nmusl@1.1.24-r2\n", cli.OutBuffer().String()))
+}
+
+func TestNewPackagesCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name              string
+		args              []string
+		expectedError     string
+		imagePackagesFunc func(image string) (types.ImagePackages, error)
+	}{
+		{
+			name:          "wrong-args",
+			args:          []string{},
+			expectedError: "requires exactly 1 argument.",
+		},
+		{
+			name:          "packages-failed",
+			args:          []string{"alpine"},
+			expectedError: "something went wrong",
+			imagePackagesFunc: func(image string) (types.ImagePackages, error) {
+				return types.ImagePackages{}, errors.Errorf("something went wrong")
+			},
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newPackagesCommand(test.NewFakeCli(&fakeClient{imagePackagesFunc: tc.imagePackagesFunc}))
+		cmd.SetOutput(ioutil.Discard)
+		cmd.SetArgs(tc.args)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
@@ -3,16 +3,22 @@ package image
 import (
 	"context"
 	"io"
+	"os"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type saveOptions struct {
-	images []string
-	output string
+	images        []string
+	output        string
+	format        string
+	excludeBase   []string
+	excludeLayers []string
 }
 
 // NewSaveCommand creates a new `docker save` command
@@ -31,7 +37,13 @@ func NewSaveCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 
-	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file or an existing directory, instead of STDOUT")
+	flags.StringVar(&opts.format, "format", "", `Format of the archive ("docker"|"oci")`)
+	flags.SetAnnotation("format", "version", []string{"1.41"})
+	flags.StringSliceVar(&opts.excludeBase, "exclude-base", []string{}, "Omit the layers of an image already present where the archive is loaded")
+	flags.SetAnnotation("exclude-base", "version", []string{"1.41"})
+	flags.StringSliceVar(&opts.excludeLayers, "exclude-layer", []string{}, "Omit a layer, by digest, from the archive")
+	flags.SetAnnotation("exclude-layer", "version", []string{"1.41"})
 
 	return cmd
 }
@@ -46,7 +58,11 @@ func RunSave(dockerCli command.Cli, opts saveOptions) error {
 		return errors.Wrap(err, "failed to save image")
 	}
 
-	responseBody, err := dockerCli.Client().ImageSave(context.Background(), opts.images)
+	responseBody, err := dockerCli.Client().ImageSave(context.Background(), opts.images, types.ImageSaveOptions{
+		Format:        opts.format,
+		ExcludeBase:   opts.excludeBase,
+		ExcludeLayers: opts.excludeLayers,
+	})
 	if err != nil {
 		return err
 	}
@@ -57,5 +73,11 @@ func RunSave(dockerCli command.Cli, opts saveOptions) error {
 		return err
 	}
 
+	// Saving to an existing directory extracts the archive into it, which
+	// produces an image layout directory.
+	if fi, err := os.Stat(opts.output); err == nil && fi.IsDir() {
+		return archive.Untar(responseBody, opts.output, &archive.TarOptions{NoLchown: true})
+	}
+
 	return command.CopyToFile(opts.output, responseBody)
 }
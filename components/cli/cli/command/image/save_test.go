@@ -1,6 +1,8 @@
 package image
 
 import (
+	"archive/tar"
+	"bytes"
 	"io"
 	"io/ioutil"
 	"os"
@@ -8,9 +10,11 @@ import (
 	"testing"
 
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
 )
 
 func TestNewSaveCommandErrors(t *testing.T) {
@@ -19,7 +23,7 @@ func TestNewSaveCommandErrors(t *testing.T) {
 		args          []string
 		isTerminal    bool
 		expectedError string
-		imageSaveFunc func(images []string) (io.ReadCloser, error)
+		imageSaveFunc func(images []string, options types.ImageSaveOptions) (io.ReadCloser, error)
 	}{
 		{
 			name:          "wrong args",
@@ -37,7 +41,7 @@ func TestNewSaveCommandErrors(t *testing.T) {
 			args:          []string{"arg1"},
 			isTerminal:    false,
 			expectedError: "error saving image",
-			imageSaveFunc: func(images []string) (io.ReadCloser, error) {
+			imageSaveFunc: func(images []string, options types.ImageSaveOptions) (io.ReadCloser, error) {
 				return ioutil.NopCloser(strings.NewReader("")), errors.Errorf("error saving image")
 			},
 		},
@@ -66,13 +70,13 @@ func TestNewSaveCommandSuccess(t *testing.T) {
 	testCases := []struct {
 		args          []string
 		isTerminal    bool
-		imageSaveFunc func(images []string) (io.ReadCloser, error)
+		imageSaveFunc func(images []string, options types.ImageSaveOptions) (io.ReadCloser, error)
 		deferredFunc  func()
 	}{
 		{
 			args:       []string{"-o", "save_tmp_file", "arg1"},
 			isTerminal: true,
-			imageSaveFunc: func(images []string) (io.ReadCloser, error) {
+			imageSaveFunc: func(images []string, options types.ImageSaveOptions) (io.ReadCloser, error) {
 				assert.Assert(t, is.Len(images, 1))
 				assert.Check(t, is.Equal("arg1", images[0]))
 				return ioutil.NopCloser(strings.NewReader("")), nil
@@ -84,7 +88,7 @@ func TestNewSaveCommandSuccess(t *testing.T) {
 		{
 			args:       []string{"arg1", "arg2"},
 			isTerminal: false,
-			imageSaveFunc: func(images []string) (io.ReadCloser, error) {
+			imageSaveFunc: func(images []string, options types.ImageSaveOptions) (io.ReadCloser, error) {
 				assert.Assert(t, is.Len(images, 2))
 				assert.Check(t, is.Equal("arg1", images[0]))
 				assert.Check(t, is.Equal("arg2", images[1]))
@@ -94,7 +98,7 @@ func TestNewSaveCommandSuccess(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		cmd := NewSaveCommand(test.NewFakeCli(&fakeClient{
-			imageSaveFunc: func(images []string) (io.ReadCloser, error) {
+			imageSaveFunc: func(images []string, options types.ImageSaveOptions) (io.ReadCloser, error) {
 				return ioutil.NopCloser(strings.NewReader("")), nil
 			},
 		}))
@@ -106,3 +110,43 @@ func TestNewSaveCommandSuccess(t *testing.T) {
 		}
 	}
 }
+
+func TestNewSaveCommandToDirectory(t *testing.T) {
+	dir := fs.NewDir(t, "save-test")
+	defer dir.Remove()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	content := []byte(`{"imageLayoutVersion":"1.0.0"}`)
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "oci-layout", Mode: 0644, Size: int64(len(content))}))
+	_, err := tw.Write(content)
+	assert.NilError(t, err)
+	assert.NilError(t, tw.Close())
+
+	cmd := NewSaveCommand(test.NewFakeCli(&fakeClient{
+		imageSaveFunc: func(images []string, options types.ImageSaveOptions) (io.ReadCloser, error) {
+			assert.Check(t, is.Equal("oci", options.Format))
+			return ioutil.NopCloser(&buf), nil
+		},
+	}))
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--format", "oci", "-o", dir.Path(), "arg1"})
+	assert.NilError(t, cmd.Execute())
+
+	actual, err := ioutil.ReadFile(dir.Join("oci-layout"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), string(actual)))
+}
+
+func TestNewSaveCommandExcludeBase(t *testing.T) {
+	cmd := NewSaveCommand(test.NewFakeCli(&fakeClient{
+		imageSaveFunc: func(images []string, options types.ImageSaveOptions) (io.ReadCloser, error) {
+			assert.Check(t, is.DeepEqual([]string{"busybox:latest"}, options.ExcludeBase))
+			assert.Check(t, is.DeepEqual([]string{"sha256:1234", "sha256:5678"}, options.ExcludeLayers))
+			return ioutil.NopCloser(strings.NewReader("")), nil
+		},
+	}))
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--exclude-base", "busybox:latest", "--exclude-layer", "sha256:1234", "--exclude-layer", "sha256:5678", "arg1"})
+	assert.NilError(t, cmd.Execute())
+}
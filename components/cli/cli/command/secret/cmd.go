@@ -16,7 +16,6 @@ func NewSecretCommand(dockerCli command.Cli) *cobra.Command {
 		RunE:  command.ShowHelp(dockerCli.Err()),
 		Annotations: map[string]string{
 			"version": "1.25",
-			"swarm":   "",
 		},
 	}
 	cmd.AddCommand(
@@ -0,0 +1,86 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/spf13/cobra"
+)
+
+type checkOptions struct {
+	repair       bool
+	verifyLayers bool
+}
+
+// newCheckCommand creates a new cobra.Command for `docker system check`
+func newCheckCommand(dockerCli command.Cli) *cobra.Command {
+	var opts checkOptions
+
+	cmd := &cobra.Command{
+		Use:   "check [OPTIONS]",
+		Short: "Check the consistency of Docker data",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCheck(dockerCli, opts)
+		},
+		Annotations: map[string]string{"version": "1.41"},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.repair, "repair", false, "Repair the problems which can be repaired safely")
+	flags.BoolVar(&opts.verifyLayers, "verify-layers", false, "Verify the content of all layers")
+
+	return cmd
+}
+
+func runCheck(dockerCli command.Cli, opts checkOptions) error {
+	report, err := dockerCli.Client().SystemCheck(context.Background(), types.SystemCheckOptions{
+		Repair:       opts.repair,
+		VerifyLayers: opts.verifyLayers,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(report.Problems) == 0 {
+		fmt.Fprintln(dockerCli.Out(), "No problems found")
+		return nil
+	}
+
+	var remaining int
+	w := tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
+	fmt.Fprintln(w, "KIND\tID\tMESSAGE\tACTION")
+	for _, p := range report.Problems {
+		if !p.Repaired {
+			remaining++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Kind, p.ID, p.Message, checkAction(p, opts.repair))
+	}
+	w.Flush()
+
+	if remaining == 0 {
+		fmt.Fprintf(dockerCli.Out(), "\n%d problem(s) found and repaired\n", len(report.Problems))
+		return nil
+	}
+	fmt.Fprintf(dockerCli.Out(), "\n%d problem(s) found, %d not repaired\n", len(report.Problems), remaining)
+	return cli.StatusError{StatusCode: 1}
+}
+
+func checkAction(p types.SystemCheckProblem, repair bool) string {
+	switch {
+	case p.Repair == "":
+		return "none"
+	case p.Repaired:
+		return p.Repair + ": done"
+	case p.RepairError != "":
+		return p.Repair + ": failed: " + p.RepairError
+	case !repair:
+		return p.Repair + " (dry run)"
+	default:
+		return p.Repair
+	}
+}
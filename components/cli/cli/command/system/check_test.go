@@ -0,0 +1,59 @@
+package system
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestCheckNoProblems(t *testing.T) {
+	fakeCli := test.NewFakeCli(&fakeClient{
+		systemCheckFunc: func(ctx context.Context, options types.SystemCheckOptions) (types.SystemCheckReport, error) {
+			return types.SystemCheckReport{}, nil
+		},
+	})
+	cmd := newCheckCommand(fakeCli)
+	cmd.SetArgs([]string{})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("No problems found\n", fakeCli.OutBuffer().String()))
+}
+
+func TestCheckDryRun(t *testing.T) {
+	fakeCli := test.NewFakeCli(&fakeClient{
+		systemCheckFunc: func(ctx context.Context, options types.SystemCheckOptions) (types.SystemCheckReport, error) {
+			assert.Check(t, !options.Repair)
+			assert.Check(t, options.VerifyLayers)
+			return types.SystemCheckReport{Problems: []types.SystemCheckProblem{
+				{Kind: "orphan-layer", ID: "sha256:abc", Message: "layer is not used", Repair: "remove layer"},
+				{Kind: "missing-volume-data", ID: "myvol", Message: "volume data directory does not exist"},
+			}}, nil
+		},
+	})
+	cmd := newCheckCommand(fakeCli)
+	cmd.SetArgs([]string{"--verify-layers"})
+	err := cmd.Execute()
+	assert.Check(t, is.DeepEqual(cli.StatusError{StatusCode: 1}, err))
+	assert.Check(t, is.Contains(fakeCli.OutBuffer().String(), "remove layer (dry run)"))
+	assert.Check(t, is.Contains(fakeCli.OutBuffer().String(), "2 problem(s) found, 2 not repaired"))
+}
+
+func TestCheckRepair(t *testing.T) {
+	fakeCli := test.NewFakeCli(&fakeClient{
+		systemCheckFunc: func(ctx context.Context, options types.SystemCheckOptions) (types.SystemCheckReport, error) {
+			assert.Check(t, options.Repair)
+			return types.SystemCheckReport{Problems: []types.SystemCheckProblem{
+				{Kind: "orphan-layer", ID: "sha256:abc", Message: "layer is not used", Repair: "remove layer", Repaired: true},
+			}}, nil
+		},
+	})
+	cmd := newCheckCommand(fakeCli)
+	cmd.SetArgs([]string{"--repair"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(fakeCli.OutBuffer().String(), "remove layer: done"))
+	assert.Check(t, is.Contains(fakeCli.OutBuffer().String(), "1 problem(s) found and repaired"))
+}
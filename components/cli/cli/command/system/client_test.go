@@ -10,8 +10,9 @@ import (
 type fakeClient struct {
 	client.Client
 
-	version       string
-	serverVersion func(ctx context.Context) (types.Version, error)
+	version         string
+	serverVersion   func(ctx context.Context) (types.Version, error)
+	systemCheckFunc func(ctx context.Context, options types.SystemCheckOptions) (types.SystemCheckReport, error)
 }
 
 func (cli *fakeClient) ServerVersion(ctx context.Context) (types.Version, error) {
@@ -21,3 +22,7 @@ func (cli *fakeClient) ServerVersion(ctx context.Context) (types.Version, error)
 func (cli *fakeClient) ClientVersion() string {
 	return cli.version
 }
+
+func (cli *fakeClient) SystemCheck(ctx context.Context, options types.SystemCheckOptions) (types.SystemCheckReport, error) {
+	return cli.systemCheckFunc(ctx, options)
+}
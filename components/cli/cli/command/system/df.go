@@ -6,11 +6,13 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/api/types"
 	"github.com/spf13/cobra"
 )
 
 type diskUsageOptions struct {
 	verbose bool
+	fresh   bool
 	format  string
 }
 
@@ -31,13 +33,17 @@ func newDiskUsageCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 
 	flags.BoolVarP(&opts.verbose, "verbose", "v", false, "Show detailed information on space usage")
+	flags.BoolVar(&opts.fresh, "fresh", false, "Recalculate sizes instead of using cached sizes")
+	flags.SetAnnotation("fresh", "version", []string{"1.41"})
 	flags.StringVar(&opts.format, "format", "", "Pretty-print images using a Go template")
 
 	return cmd
 }
 
 func runDiskUsage(dockerCli command.Cli, opts diskUsageOptions) error {
-	du, err := dockerCli.Client().DiskUsage(context.Background())
+	du, err := dockerCli.Client().DiskUsageWithOptions(context.Background(), types.DiskUsageOptions{
+		Fresh: opts.fresh,
+	})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,103 @@
+package opts
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// OCIHookOpt is a Value type for parsing OCI hooks
+type OCIHookOpt struct {
+	values *container.OCIHooks
+}
+
+// Set a new OCI hook value, in the form
+// "stage=<prestart|poststart|poststop>,path=<path>[,arg=<arg>...][,env=<KEY=value>...][,timeout=<seconds>]"
+func (o *OCIHookOpt) Set(value string) error {
+	csvReader := csv.NewReader(strings.NewReader(value))
+	fields, err := csvReader.Read()
+	if err != nil {
+		return err
+	}
+
+	var (
+		stage string
+		hook  container.OCIHook
+	)
+	for _, field := range fields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid field '%s' must be a key=value pair", field)
+		}
+		key, value := parts[0], parts[1]
+		switch key {
+		case "stage":
+			stage = value
+		case "path":
+			hook.Path = value
+		case "arg":
+			hook.Args = append(hook.Args, value)
+		case "env":
+			hook.Env = append(hook.Env, value)
+		case "timeout":
+			timeout, err := strconv.Atoi(value)
+			if err != nil || timeout <= 0 {
+				return fmt.Errorf("invalid timeout '%s': the timeout must be a positive number of seconds", value)
+			}
+			hook.Timeout = &timeout
+		default:
+			return fmt.Errorf("unexpected key '%s' in '%s'", key, field)
+		}
+	}
+
+	if hook.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+	if o.values == nil {
+		o.values = &container.OCIHooks{}
+	}
+	switch stage {
+	case "prestart":
+		o.values.Prestart = append(o.values.Prestart, hook)
+	case "poststart":
+		o.values.Poststart = append(o.values.Poststart, hook)
+	case "poststop":
+		o.values.Poststop = append(o.values.Poststop, hook)
+	case "":
+		return fmt.Errorf("stage is required")
+	default:
+		return fmt.Errorf("invalid stage '%s': the stage must be prestart, poststart or poststop", stage)
+	}
+	return nil
+}
+
+// Type returns the type of this option
+func (o *OCIHookOpt) Type() string {
+	return "oci-hook"
+}
+
+// String returns a string repr of this option
+func (o *OCIHookOpt) String() string {
+	if o.values == nil {
+		return ""
+	}
+	var hooks []string
+	for stage, hs := range map[string][]container.OCIHook{
+		"prestart":  o.values.Prestart,
+		"poststart": o.values.Poststart,
+		"poststop":  o.values.Poststop,
+	} {
+		for _, h := range hs {
+			hooks = append(hooks, stage+"="+h.Path)
+		}
+	}
+	return strings.Join(hooks, ", ")
+}
+
+// Value returns the OCI hooks
+func (o *OCIHookOpt) Value() *container.OCIHooks {
+	return o.values
+}
@@ -0,0 +1,45 @@
+package opts
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestOCIHookOptSet(t *testing.T) {
+	var hooks OCIHookOpt
+	assert.Check(t, is.Nil(hooks.Value()))
+
+	assert.NilError(t, hooks.Set("stage=prestart,path=/usr/local/lib/hooks/register,arg=register,arg=--id,env=LEVEL=debug,timeout=5"))
+	assert.NilError(t, hooks.Set("stage=poststop,path=/usr/local/lib/hooks/unregister"))
+
+	timeout := 5
+	assert.Check(t, is.DeepEqual(hooks.Value(), &container.OCIHooks{
+		Prestart: []container.OCIHook{{
+			Path:    "/usr/local/lib/hooks/register",
+			Args:    []string{"register", "--id"},
+			Env:     []string{"LEVEL=debug"},
+			Timeout: &timeout,
+		}},
+		Poststop: []container.OCIHook{{Path: "/usr/local/lib/hooks/unregister"}},
+	}))
+}
+
+func TestOCIHookOptSetErrors(t *testing.T) {
+	testCases := []struct {
+		value, expected string
+	}{
+		{"path=/hook", "stage is required"},
+		{"stage=prestart", "path is required"},
+		{"stage=prestop,path=/hook", "invalid stage 'prestop'"},
+		{"stage=prestart,path=/hook,timeout=0", "invalid timeout '0'"},
+		{"stage=prestart,path=/hook,user=root", "unexpected key 'user'"},
+		{"stage=prestart,/hook", "invalid field '/hook'"},
+	}
+	for _, tc := range testCases {
+		var hooks OCIHookOpt
+		assert.Check(t, is.ErrorContains(hooks.Set(tc.value), tc.expected), tc.value)
+	}
+}
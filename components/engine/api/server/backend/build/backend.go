@@ -21,6 +21,7 @@ import (
 type ImageComponent interface {
 	SquashImage(from string, to string) (string, error)
 	TagImageWithReference(image.ID, reference.Named) error
+	StoreImagePackages(ctx context.Context, id image.ID) (*types.ImagePackages, error)
 }
 
 // Builder defines interface for running a build
@@ -87,6 +88,17 @@ func (b *Backend) Build(ctx context.Context, config backend.BuildConfig) (string
 		}
 	}
 
+	if options.SBOM && imageID != "" {
+		packages, err := b.imageComponent.StoreImagePackages(ctx, image.ID(imageID))
+		if err != nil {
+			return "", errors.Wrap(err, "failed to record the packages of the image")
+		}
+		if !useBuildKit {
+			stdout := config.ProgressWriter.StdoutFormatter
+			fmt.Fprintf(stdout, "Recorded %d packages\n", len(packages.Packages))
+		}
+	}
+
 	if !useBuildKit {
 		stdout := config.ProgressWriter.StdoutFormatter
 		fmt.Fprintf(stdout, "Successfully built %s\n", stringid.TruncateID(imageID))
@@ -50,7 +50,9 @@ func WriteLogStream(_ context.Context, w io.Writer, msgs <-chan *backend.LogMess
 		if config.Timestamps {
 			logLine = append([]byte(msg.Timestamp.Format(jsonmessage.RFC3339NanoFixed)+" "), logLine...)
 		}
-		if msg.Source == "stdout" && config.ShowStdout {
+		// the standard input of execs which are recorded in the logs is shown
+		// with the standard output
+		if (msg.Source == "stdout" || msg.Source == "stdin") && config.ShowStdout {
 			outStream.Write(logLine)
 		}
 		if msg.Source == "stderr" && config.ShowStderr {
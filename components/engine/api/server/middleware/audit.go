@@ -0,0 +1,299 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/authorization"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/sirupsen/logrus"
+)
+
+// maxAuditBodySize is the maximum size of the request bodies from which the
+// parameters are recorded, and of the response bodies from which the IDs of
+// the created objects are recorded.
+const maxAuditBodySize = 64 * 1024
+
+// auditedParams are the request body parameters recorded in the audit log,
+// as paths in the JSON body.
+var auditedParams = [][]string{
+	{"Image"},
+	{"Cmd"},
+	{"Entrypoint"},
+	{"User"},
+	{"Privileged"},
+	{"Name"},
+	{"Driver"},
+	{"HostConfig", "Privileged"},
+	{"HostConfig", "Binds"},
+	{"HostConfig", "Mounts"},
+	{"HostConfig", "CapAdd"},
+	{"HostConfig", "CapDrop"},
+	{"HostConfig", "Devices"},
+	{"HostConfig", "NetworkMode"},
+	{"HostConfig", "PidMode"},
+	{"HostConfig", "IpcMode"},
+	{"HostConfig", "UsernsMode"},
+	{"HostConfig", "SecurityOpt"},
+}
+
+// AuditRecord is a record of the audit log, describing an API request which
+// modifies the daemon state and its result.
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+
+	// User is the common name of the verified TLS client certificate.
+	User string `json:"user,omitempty"`
+	// AuthMethod is "TLS" when the client was identified by its certificate,
+	// or "unix" when it was identified by its credentials on a Unix socket.
+	AuthMethod string `json:"authMethod,omitempty"`
+	// UID, GIDs and PID are the credentials of the client process on a Unix
+	// socket.
+	UID        *uint32  `json:"uid,omitempty"`
+	GIDs       []uint32 `json:"gids,omitempty"`
+	PID        int32    `json:"pid,omitempty"`
+	RemoteAddr string   `json:"remoteAddr,omitempty"`
+
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	// Target is the ID or the name of the object targeted by the request,
+	// or the ID of the object it created.
+	Target string                 `json:"target,omitempty"`
+	Query  map[string]string      `json:"query,omitempty"`
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// AuditMiddleware writes the API requests which modify the daemon state to
+// an audit log, one JSON record per request.
+type AuditMiddleware struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewAuditMiddleware creates a new AuditMiddleware writing the records to w.
+func NewAuditMiddleware(w io.Writer) *AuditMiddleware {
+	return &AuditMiddleware{w: w}
+}
+
+// WrapHandler returns a new handler function wrapping the previous one in the request chain.
+func (a *AuditMiddleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return handler(ctx, w, r, vars)
+		}
+
+		record := newAuditRecord(r, vars)
+		aw := &auditResponseWriter{ResponseWriter: w, captureBody: strings.HasSuffix(record.Endpoint, "/create")}
+		err := handler(ctx, aw, r, vars)
+
+		switch {
+		case err != nil:
+			record.Status = errdefs.GetHTTPErrorStatusCode(err)
+			record.Error = err.Error()
+		case aw.status != 0:
+			record.Status = aw.status
+		default:
+			record.Status = http.StatusOK
+		}
+		if record.Target == "" && err == nil {
+			record.Target = createdID(aw.body.Bytes())
+		}
+		a.write(record)
+		return err
+	}
+}
+
+func (a *AuditMiddleware) write(record *AuditRecord) {
+	b, err := json.Marshal(record)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to encode audit record")
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(append(b, '\n')); err != nil {
+		logrus.WithError(err).Errorf("Failed to write audit record for %s %s", record.Method, record.Endpoint)
+	}
+}
+
+// newAuditRecord records the identity of the client and the parameters of a
+// request.
+func newAuditRecord(r *http.Request, vars map[string]string) *AuditRecord {
+	record := &AuditRecord{
+		Time:     time.Now().UTC(),
+		Method:   r.Method,
+		Endpoint: r.URL.Path,
+	}
+	if v := vars["version"]; v != "" {
+		record.Endpoint = strings.TrimPrefix(record.Endpoint, "/v"+v)
+	}
+
+	if cert := authorization.VerifiedClientCertificate(r); cert != nil {
+		record.User = cert.Subject.CommonName
+		record.AuthMethod = "TLS"
+	}
+	if peer, ok := authorization.ParsePeerCredentials(r.RemoteAddr); ok {
+		uid := peer.UID
+		record.UID, record.GIDs, record.PID = &uid, peer.GIDs, peer.PID
+		if record.AuthMethod == "" {
+			record.AuthMethod = "unix"
+		}
+	} else if r.RemoteAddr != "" && r.RemoteAddr != "@" {
+		record.RemoteAddr = r.RemoteAddr
+	}
+
+	if name := vars["name"]; name != "" {
+		record.Target = name
+	} else if id := vars["id"]; id != "" {
+		record.Target = id
+	}
+
+	if q := r.URL.Query(); len(q) > 0 {
+		record.Query = make(map[string]string, len(q))
+		for k, v := range q {
+			record.Query[k] = strings.Join(v, ",")
+		}
+	}
+	record.Params = requestParams(r)
+	return record
+}
+
+// requestParams returns the audited parameters of the JSON body of a request,
+// keeping the body available for the handler.
+func requestParams(r *http.Request) map[string]interface{} {
+	if r.Body == nil || r.ContentLength > maxAuditBodySize || httputils.CheckForJSON(r) != nil {
+		return nil
+	}
+	body := r.Body
+	bufReader := bufio.NewReaderSize(body, maxAuditBodySize)
+	r.Body = ioutils.NewReadCloserWrapper(bufReader, func() error { return body.Close() })
+
+	b, err := bufReader.Peek(maxAuditBodySize)
+	if err != io.EOF {
+		// either there was an error reading, or the body is too large
+		return nil
+	}
+	var form map[string]interface{}
+	if err := json.Unmarshal(b, &form); err != nil {
+		return nil
+	}
+
+	params := make(map[string]interface{})
+	for _, p := range auditedParams {
+		var v interface{} = form
+		for _, key := range p {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				v = nil
+				break
+			}
+			v = m[key]
+		}
+		if v != nil {
+			params[strings.Join(p, ".")] = v
+		}
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	return params
+}
+
+// createdID returns the ID of the object created by a request, from the
+// response body.
+func createdID(body []byte) string {
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &created) != nil {
+		return ""
+	}
+	return created.ID
+}
+
+// auditResponseWriter records the status code of a response and, for the
+// requests creating objects, the beginning of its body.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	captureBody bool
+	body        limitedBuffer
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.captureBody {
+		w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Hijack uses the internal hijack API of the wrapped http.ResponseWriter
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("Internal response writer doesn't support the Hijacker interface")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+// CloseNotify uses the internal close notify API of the wrapped http.ResponseWriter
+func (w *auditResponseWriter) CloseNotify() <-chan bool {
+	closeNotifier, ok := w.ResponseWriter.(http.CloseNotifier)
+	if !ok {
+		logrus.Error("Internal response writer doesn't support the CloseNotifier interface")
+		return nil
+	}
+	return closeNotifier.CloseNotify()
+}
+
+// Flush uses the internal flush API of the wrapped http.ResponseWriter
+func (w *auditResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// limitedBuffer keeps the first maxAuditBodySize bytes written to it.
+type limitedBuffer struct {
+	buf []byte
+}
+
+func (b *limitedBuffer) Write(p []byte) {
+	if n := maxAuditBodySize - len(b.buf); n > 0 {
+		if len(p) > n {
+			p = p[:n]
+		}
+		b.buf = append(b.buf, p...)
+	}
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf
+}
@@ -0,0 +1,117 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/authorization"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestAuditMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewAuditMiddleware(&buf)
+
+	body := `{"Image":"busybox","Cmd":["sh"],"Env":["TOKEN=secret"],"HostConfig":{"Privileged":true,"Binds":["/:/host"],"CapAdd":["SYS_ADMIN"]}}`
+	r := httptest.NewRequest(http.MethodPost, "/v1.40/containers/create?name=web", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.RemoteAddr = authorization.PeerCredentials{PID: 42, UID: 1000, GIDs: []uint32{1000, 999}}.String()
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		b, err := ioutil.ReadAll(r.Body)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(string(b), body), "the request body must be kept for the handler")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(map[string]string{"Id": "abcdef"})
+	}
+	err := m.WrapHandler(handler)(context.Background(), httptest.NewRecorder(), r, map[string]string{"version": "1.40"})
+	assert.NilError(t, err)
+
+	var record AuditRecord
+	assert.NilError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Check(t, is.Equal(record.Method, http.MethodPost))
+	assert.Check(t, is.Equal(record.Endpoint, "/containers/create"))
+	assert.Check(t, is.Equal(record.AuthMethod, "unix"))
+	assert.Assert(t, record.UID != nil)
+	assert.Check(t, is.Equal(*record.UID, uint32(1000)))
+	assert.Check(t, is.DeepEqual(record.GIDs, []uint32{1000, 999}))
+	assert.Check(t, is.Equal(record.PID, int32(42)))
+	assert.Check(t, is.Equal(record.Target, "abcdef"))
+	assert.Check(t, is.DeepEqual(record.Query, map[string]string{"name": "web"}))
+	assert.Check(t, is.Equal(record.Params["Image"], "busybox"))
+	assert.Check(t, is.Equal(record.Params["HostConfig.Privileged"], true))
+	assert.Check(t, is.DeepEqual(record.Params["HostConfig.Binds"], []interface{}{"/:/host"}))
+	assert.Check(t, is.DeepEqual(record.Params["HostConfig.CapAdd"], []interface{}{"SYS_ADMIN"}))
+	assert.Check(t, is.DeepEqual(record.Params["Cmd"], []interface{}{"sh"}))
+	_, ok := record.Params["Env"]
+	assert.Check(t, !ok, "unaudited parameters must not be recorded")
+	assert.Check(t, is.Equal(record.Status, http.StatusCreated))
+}
+
+func TestAuditMiddlewareError(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewAuditMiddleware(&buf)
+
+	r := httptest.NewRequest(http.MethodDelete, "/containers/web", nil)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		return errdefs.NotFound(errors.New("No such container: web"))
+	}
+	err := m.WrapHandler(handler)(context.Background(), httptest.NewRecorder(), r, map[string]string{"name": "web"})
+	assert.Check(t, is.ErrorContains(err, "No such container"))
+
+	var record AuditRecord
+	assert.NilError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Check(t, is.Equal(record.Target, "web"))
+	assert.Check(t, is.Equal(record.Status, http.StatusNotFound))
+	assert.Check(t, is.Equal(record.Error, "No such container: web"))
+	assert.Check(t, record.UID == nil)
+}
+
+func TestAuditMiddlewareSkipsReads(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewAuditMiddleware(&buf)
+
+	r := httptest.NewRequest(http.MethodGet, "/containers/json", nil)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		return nil
+	}
+	assert.NilError(t, m.WrapHandler(handler)(context.Background(), httptest.NewRecorder(), r, nil))
+	assert.Check(t, is.Equal(buf.Len(), 0))
+}
+
+func TestAuditMiddlewareTLS(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "alice"}}
+	for _, tc := range []struct {
+		doc   string
+		state *tls.ConnectionState
+		user  string
+	}{
+		{"verified certificate", &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}, VerifiedChains: [][]*x509.Certificate{{cert}}}, "alice"},
+		{"unverified certificate", &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}, ""},
+	} {
+		var buf bytes.Buffer
+		m := NewAuditMiddleware(&buf)
+		r := httptest.NewRequest(http.MethodPost, "/containers/web/stop", nil)
+		r.TLS = tc.state
+		handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+			return nil
+		}
+		err := m.WrapHandler(handler)(context.Background(), httptest.NewRecorder(), r, map[string]string{"name": "web"})
+		assert.NilError(t, err)
+
+		var record AuditRecord
+		assert.NilError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Check(t, is.Equal(record.User, tc.user), tc.doc)
+	}
+}
@@ -41,22 +41,46 @@ func (e versionUnsupportedError) Error() string {
 
 func (e versionUnsupportedError) InvalidParameter() {}
 
+type apiVersionRangeKey struct{}
+
+type apiVersionRange struct {
+	min, max string
+}
+
+// WithAPIVersionRange returns a context which restricts the API versions
+// accepted by the VersionMiddleware, for the requests received on a listener
+// with its own range of versions. An empty version does not restrict the
+// versions accepted by the server.
+func WithAPIVersionRange(ctx context.Context, min, max string) context.Context {
+	return context.WithValue(ctx, apiVersionRangeKey{}, apiVersionRange{min: min, max: max})
+}
+
 // WrapHandler returns a new handler function wrapping the previous one in the request chain.
 func (v VersionMiddleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		minVersion, maxVersion := v.minVersion, v.defaultVersion
+		if vr, ok := ctx.Value(apiVersionRangeKey{}).(apiVersionRange); ok {
+			if vr.min != "" && versions.GreaterThan(vr.min, minVersion) {
+				minVersion = vr.min
+			}
+			if vr.max != "" && versions.LessThan(vr.max, maxVersion) {
+				maxVersion = vr.max
+			}
+		}
+
 		w.Header().Set("Server", fmt.Sprintf("Docker/%s (%s)", v.serverVersion, runtime.GOOS))
-		w.Header().Set("API-Version", v.defaultVersion)
+		w.Header().Set("API-Version", maxVersion)
 		w.Header().Set("OSType", runtime.GOOS)
 
 		apiVersion := vars["version"]
 		if apiVersion == "" {
-			apiVersion = v.defaultVersion
+			apiVersion = maxVersion
 		}
-		if versions.LessThan(apiVersion, v.minVersion) {
-			return versionUnsupportedError{version: apiVersion, minVersion: v.minVersion}
+		if versions.LessThan(apiVersion, minVersion) {
+			return versionUnsupportedError{version: apiVersion, minVersion: minVersion}
 		}
-		if versions.GreaterThan(apiVersion, v.defaultVersion) {
-			return versionUnsupportedError{version: apiVersion, maxVersion: v.defaultVersion}
+		if versions.GreaterThan(apiVersion, maxVersion) {
+			return versionUnsupportedError{version: apiVersion, maxVersion: maxVersion}
 		}
 		ctx = context.WithValue(ctx, httputils.APIVersionKey{}, apiVersion)
 		return handler(ctx, w, r, vars)
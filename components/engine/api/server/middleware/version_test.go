@@ -90,3 +90,24 @@ func TestVersionMiddlewareWithErrorsReturnsHeaders(t *testing.T) {
 	assert.Check(t, is.Equal(hdr.Get("API-Version"), defaultVersion))
 	assert.Check(t, is.Equal(hdr.Get("OSType"), runtime.GOOS))
 }
+
+func TestVersionMiddlewareListenerRange(t *testing.T) {
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		assert.Check(t, is.Equal(httputils.VersionFromContext(ctx), "1.8.0"))
+		return nil
+	}
+
+	m := NewVersionMiddleware("1.10.0", "1.10.0", "1.2.0")
+	h := m.WrapHandler(handler)
+	ctx := WithAPIVersionRange(context.Background(), "1.5.0", "1.8.0")
+
+	req, _ := http.NewRequest("GET", "/containers/json", nil)
+	resp := httptest.NewRecorder()
+	assert.Check(t, h(ctx, resp, req, map[string]string{}))
+	assert.Check(t, is.Equal(resp.Result().Header.Get("API-Version"), "1.8.0"))
+
+	err := h(ctx, httptest.NewRecorder(), req, map[string]string{"version": "1.9.0"})
+	assert.Check(t, is.Error(err, "client version 1.9.0 is too new. Maximum supported API version is 1.8.0"))
+	err = h(ctx, httptest.NewRecorder(), req, map[string]string{"version": "1.4.0"})
+	assert.Check(t, is.Error(err, "client version 1.4.0 is too old. Minimum supported API version is 1.5.0, please upgrade your client to a newer version"))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	containerpkg "github.com/docker/docker/container"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/go-connections/nat"
 )
 
 // execBackend includes functions to implement to provide exec functionality.
@@ -28,27 +29,31 @@ type copyBackend interface {
 	ContainerExport(name string, out io.Writer) error
 	ContainerExtractToDir(name, path string, copyUIDGID, noOverwriteDirNonDir bool, content io.Reader) error
 	ContainerStatPath(name string, path string) (stat *types.ContainerPathStat, err error)
+	ContainerListFiles(name string, opts types.ListFilesOptions) ([]types.FileEntry, error)
 }
 
 // stateBackend includes functions to implement to provide container state lifecycle functionality.
 type stateBackend interface {
 	ContainerCreate(config types.ContainerCreateConfig) (container.ContainerCreateCreatedBody, error)
+	ContainerDebug(name string, config *container.DebugConfig) (container.ContainerCreateCreatedBody, error)
 	ContainerKill(name string, sig uint64) error
 	ContainerPause(name string) error
 	ContainerRename(oldName, newName string) error
 	ContainerResize(name string, height, width int) error
 	ContainerRestart(name string, seconds *int) error
 	ContainerRm(name string, config *types.ContainerRmConfig) error
-	ContainerStart(name string, hostConfig *container.HostConfig, checkpoint string, checkpointDir string) error
+	ContainerStart(ctx context.Context, name string, hostConfig *container.HostConfig, checkpoint string, checkpointDir string) error
 	ContainerStop(name string, seconds *int) error
 	ContainerUnpause(name string) error
-	ContainerUpdate(name string, hostConfig *container.HostConfig) (container.ContainerUpdateOKBody, error)
+	ContainerUpdate(name string, updateConfig *container.UpdateConfig) (container.ContainerUpdateOKBody, error)
+	ContainerUpdatePorts(name string, config *container.PortsUpdateConfig) (nat.PortMap, error)
 	ContainerWait(ctx context.Context, name string, condition containerpkg.WaitCondition) (<-chan containerpkg.StateStatus, error)
 }
 
 // monitorBackend includes functions to implement to provide containers monitoring functionality.
 type monitorBackend interface {
 	ContainerChanges(name string) ([]archive.Change, error)
+	ContainerDiff(ctx context.Context, name, against string, opts types.DiffOptions) ([]types.FileChange, error)
 	ContainerInspect(name string, size bool, version string) (interface{}, error)
 	ContainerLogs(ctx context.Context, name string, config *types.ContainerLogsOptions) (msgs <-chan *backend.LogMessage, tty bool, err error)
 	ContainerStats(ctx context.Context, name string, config *backend.ContainerStatsConfig) error
@@ -67,6 +72,12 @@ type systemBackend interface {
 	ContainersPrune(ctx context.Context, pruneFilters filters.Args) (*types.ContainersPruneReport, error)
 }
 
+// mountBackend includes functions to implement to provide container filesystem mount functionality.
+type mountBackend interface {
+	ContainerMount(name string) (string, error)
+	ContainerUnmount(name string, force bool) error
+}
+
 type commitBackend interface {
 	CreateImageFromContainer(name string, config *backend.CreateImageConfig) (imageID string, err error)
 }
@@ -80,4 +91,5 @@ type Backend interface {
 	monitorBackend
 	attachBackend
 	systemBackend
+	mountBackend
 }
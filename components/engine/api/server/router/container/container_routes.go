@@ -200,7 +200,7 @@ func (s *containerRouter) postContainersStart(ctx context.Context, w http.Respon
 
 	checkpoint := r.Form.Get("checkpoint")
 	checkpointDir := r.Form.Get("checkpoint-dir")
-	if err := s.backend.ContainerStart(vars["name"], hostConfig, checkpoint, checkpointDir); err != nil {
+	if err := s.backend.ContainerStart(ctx, vars["name"], hostConfig, checkpoint, checkpointDir); err != nil {
 		return err
 	}
 
@@ -316,6 +316,28 @@ func (s *containerRouter) postContainersUnpause(ctx context.Context, w http.Resp
 	return nil
 }
 
+func (s *containerRouter) postContainersMount(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	path, err := s.backend.ContainerMount(vars["name"])
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, &types.MountResponse{Path: path})
+}
+
+func (s *containerRouter) postContainersUnmount(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	if err := s.backend.ContainerUnmount(vars["name"], httputils.BoolValue(r, "force")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
 func (s *containerRouter) postContainersWait(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	// Behavior changed in version 1.30 to handle wait condition and to
 	// return headers immediately.
@@ -376,6 +398,19 @@ func (s *containerRouter) postContainersWait(ctx context.Context, w http.Respons
 }
 
 func (s *containerRouter) getContainersChanges(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if against := r.Form.Get("against"); against != "" && versions.GreaterThanOrEqualTo(httputils.VersionFromContext(ctx), "1.41") {
+		opts := types.DiffOptions{Content: httputils.BoolValue(r, "content")}
+		changes, err := s.backend.ContainerDiff(ctx, vars["name"], against, opts)
+		if err != nil {
+			return err
+		}
+		return httputils.WriteJSON(w, http.StatusOK, changes)
+	}
+
 	changes, err := s.backend.ContainerChanges(vars["name"])
 	if err != nil {
 		return err
@@ -425,9 +460,14 @@ func (s *containerRouter) postContainerUpdate(ctx context.Context, w http.Respon
 	if err := decoder.Decode(&updateConfig); err != nil {
 		return err
 	}
-	if versions.LessThan(httputils.VersionFromContext(ctx), "1.40") {
+	version := httputils.VersionFromContext(ctx)
+	if versions.LessThan(version, "1.40") {
 		updateConfig.PidsLimit = nil
 	}
+	if versions.LessThan(version, "1.41") {
+		updateConfig.DevicesAdd = nil
+		updateConfig.DevicesRemove = nil
+	}
 	if updateConfig.PidsLimit != nil && *updateConfig.PidsLimit <= 0 {
 		// Both `0` and `-1` are accepted to set "unlimited" when updating.
 		// Historically, any negative value was accepted, so treat them as
@@ -436,13 +476,8 @@ func (s *containerRouter) postContainerUpdate(ctx context.Context, w http.Respon
 		updateConfig.PidsLimit = &unlimited
 	}
 
-	hostConfig := &container.HostConfig{
-		Resources:     updateConfig.Resources,
-		RestartPolicy: updateConfig.RestartPolicy,
-	}
-
 	name := vars["name"]
-	resp, err := s.backend.ContainerUpdate(name, hostConfig)
+	resp, err := s.backend.ContainerUpdate(name, &updateConfig)
 	if err != nil {
 		return err
 	}
@@ -450,6 +485,52 @@ func (s *containerRouter) postContainerUpdate(ctx context.Context, w http.Respon
 	return httputils.WriteJSON(w, http.StatusOK, resp)
 }
 
+func (s *containerRouter) postContainerPorts(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+
+	var config container.PortsUpdateConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		if err == io.EOF {
+			return errdefs.InvalidParameter(errors.New("got EOF while reading request body"))
+		}
+		return errdefs.InvalidParameter(err)
+	}
+
+	ports, err := s.backend.ContainerUpdatePorts(vars["name"], &config)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, ports)
+}
+
+func (s *containerRouter) postContainersDebug(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	// The body is optional, to create a debug container with the defaults.
+	var config container.DebugConfig
+	if r.ContentLength != 0 {
+		if err := httputils.CheckForJSON(r); err != nil {
+			return err
+		}
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil && err != io.EOF {
+			return errdefs.InvalidParameter(err)
+		}
+	}
+
+	ccr, err := s.backend.ContainerDebug(vars["name"], &config)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, ccr)
+}
+
 func (s *containerRouter) postContainersCreate(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -495,6 +576,15 @@ func (s *containerRouter) postContainersCreate(ctx context.Context, w http.Respo
 		if hostConfig.CgroupnsMode.IsEmpty() {
 			hostConfig.CgroupnsMode = container.CgroupnsMode("host")
 		}
+		// Ignore Secrets and Configs because they were added in API 1.41.
+		hostConfig.Secrets = nil
+		hostConfig.Configs = nil
+		// Ignore DependsOn because it was added in API 1.41.
+		hostConfig.DependsOn = nil
+		// Ignore OCIHooks because it was added in API 1.41.
+		hostConfig.OCIHooks = nil
+		// Ignore UnixSocketBindings because they were added in API 1.41.
+		hostConfig.UnixSocketBindings = nil
 	}
 
 	if hostConfig != nil && hostConfig.PidsLimit != nil && *hostConfig.PidsLimit <= 0 {
@@ -132,6 +132,27 @@ func (s *containerRouter) getContainersArchive(ctx context.Context, w http.Respo
 	return writeCompressedResponse(w, r, tarArchive)
 }
 
+func (s *containerRouter) getContainersFiles(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	depth, err := httputils.Int64ValueOrDefault(r, "depth", 0)
+	if err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+
+	entries, err := s.backend.ContainerListFiles(vars["name"], types.ListFilesOptions{
+		Path:      r.Form.Get("path"),
+		Recursive: httputils.BoolValue(r, "recursive"),
+		Depth:     int(depth),
+	})
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, entries)
+}
+
 func (s *containerRouter) putContainersArchive(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	v, err := httputils.ArchiveFormValues(r, vars)
 	if err != nil {
@@ -55,6 +55,10 @@ func (s *containerRouter) postContainerExecCreate(ctx context.Context, w http.Re
 		return execCommandError{}
 	}
 
+	if versions.LessThan(httputils.VersionFromContext(ctx), "1.41") {
+		execConfig.Log = nil
+	}
+
 	// Register an instance of Exec in container.
 	id, err := s.backend.ContainerExecCreate(name, execConfig)
 	if err != nil {
@@ -22,16 +22,21 @@ type Backend interface {
 type imageBackend interface {
 	ImageDelete(imageRef string, force, prune bool) ([]types.ImageDeleteResponseItem, error)
 	ImageHistory(imageName string) ([]*image.HistoryResponseItem, error)
+	DiffImages(ctx context.Context, base, target string, opts types.DiffOptions) ([]types.FileChange, error)
 	Images(imageFilters filters.Args, all bool, withExtraAttrs bool) ([]*types.ImageSummary, error)
 	LookupImage(name string) (*types.ImageInspect, error)
 	TagImage(imageName, repository, tag string) (string, error)
 	ImagesPrune(ctx context.Context, pruneFilters filters.Args) (*types.ImagesPruneReport, error)
+	MountImage(refOrID string) (string, error)
+	UnmountImage(refOrID string, force bool) error
+	ListImageFiles(refOrID string, opts types.ListFilesOptions) ([]types.FileEntry, error)
+	ImagePackages(ctx context.Context, refOrID string) (*types.ImagePackages, error)
 }
 
 type importExportBackend interface {
 	LoadImage(inTar io.ReadCloser, outStream io.Writer, quiet bool) error
 	ImportImage(src string, repository, platform string, tag string, msg string, inConfig io.ReadCloser, outStream io.Writer, changes []string) error
-	ExportImage(names []string, outStream io.Writer) error
+	ExportImage(names []string, opts types.ImageSaveOptions, outStream io.Writer) error
 }
 
 type registryBackend interface {
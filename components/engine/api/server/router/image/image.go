@@ -30,6 +30,9 @@ func (r *imageRouter) initRoutes() {
 		router.NewGetRoute("/images/search", r.getImagesSearch),
 		router.NewGetRoute("/images/get", r.getImagesGet),
 		router.NewGetRoute("/images/{name:.*}/get", r.getImagesGet),
+		router.NewGetRoute("/images/{name:.*}/changes", r.getImagesChanges),
+		router.NewGetRoute("/images/{name:.*}/files", r.getImagesFiles),
+		router.NewGetRoute("/images/{name:.*}/packages", r.getImagesPackages),
 		router.NewGetRoute("/images/{name:.*}/history", r.getImagesHistory),
 		router.NewGetRoute("/images/{name:.*}/json", r.getImagesByName),
 		// POST
@@ -37,6 +40,8 @@ func (r *imageRouter) initRoutes() {
 		router.NewPostRoute("/images/create", r.postImagesCreate),
 		router.NewPostRoute("/images/{name:.*}/push", r.postImagesPush),
 		router.NewPostRoute("/images/{name:.*}/tag", r.postImagesTag),
+		router.NewPostRoute("/images/{name:.*}/mount", r.postImagesMount),
+		router.NewPostRoute("/images/{name:.*}/unmount", r.postImagesUnmount),
 		router.NewPostRoute("/images/prune", r.postImagesPrune),
 		// DELETE
 		router.NewDeleteRoute("/images/{name:.*}", r.deleteImages),
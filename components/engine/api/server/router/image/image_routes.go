@@ -146,6 +146,12 @@ func (s *imageRouter) getImagesGet(ctx context.Context, w http.ResponseWriter, r
 		return err
 	}
 
+	opts := types.ImageSaveOptions{
+		Format:        r.Form.Get("format"),
+		ExcludeBase:   r.Form["exclude-base"],
+		ExcludeLayers: r.Form["exclude-layers"],
+	}
+
 	w.Header().Set("Content-Type", "application/x-tar")
 
 	output := ioutils.NewWriteFlusher(w)
@@ -157,7 +163,7 @@ func (s *imageRouter) getImagesGet(ctx context.Context, w http.ResponseWriter, r
 		names = r.Form["names"]
 	}
 
-	if err := s.backend.ExportImage(names, output); err != nil {
+	if err := s.backend.ExportImage(names, opts, output); err != nil {
 		if !output.Flushed() {
 			return err
 		}
@@ -255,6 +261,54 @@ func (s *imageRouter) getImagesHistory(ctx context.Context, w http.ResponseWrite
 	return httputils.WriteJSON(w, http.StatusOK, history)
 }
 
+func (s *imageRouter) getImagesChanges(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	against := r.Form.Get("against")
+	if against == "" {
+		return errdefs.InvalidParameter(errors.New("against is required"))
+	}
+
+	opts := types.DiffOptions{Content: httputils.BoolValue(r, "content")}
+	changes, err := s.backend.DiffImages(ctx, against, vars["name"], opts)
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, changes)
+}
+
+func (s *imageRouter) getImagesFiles(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	depth, err := httputils.Int64ValueOrDefault(r, "depth", 0)
+	if err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+
+	entries, err := s.backend.ListImageFiles(vars["name"], types.ListFilesOptions{
+		Path:      r.Form.Get("path"),
+		Recursive: httputils.BoolValue(r, "recursive"),
+		Depth:     int(depth),
+	})
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, entries)
+}
+
+func (s *imageRouter) getImagesPackages(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	packages, err := s.backend.ImagePackages(ctx, vars["name"])
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, packages)
+}
+
 func (s *imageRouter) postImagesTag(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -266,6 +320,28 @@ func (s *imageRouter) postImagesTag(ctx context.Context, w http.ResponseWriter,
 	return nil
 }
 
+func (s *imageRouter) postImagesMount(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	path, err := s.backend.MountImage(vars["name"])
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, &types.MountResponse{Path: path})
+}
+
+func (s *imageRouter) postImagesUnmount(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	if err := s.backend.UnmountImage(vars["name"], httputils.BoolValue(r, "force")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
 func (s *imageRouter) getImagesSearch(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
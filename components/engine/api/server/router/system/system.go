@@ -35,6 +35,7 @@ func NewRouter(b Backend, c ClusterBackend, fscache *fscache.FSCache, builder *b
 		router.NewGetRoute("/info", r.getInfo),
 		router.NewGetRoute("/version", r.getVersion),
 		router.NewGetRoute("/system/df", r.getDiskUsage),
+		router.NewPostRoute("/system/check", r.postSystemCheck),
 		router.NewPostRoute("/auth", r.postAuth),
 	}
 
@@ -3,9 +3,11 @@ package server // import "github.com/docker/docker/api/server"
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/docker/docker/api/server/httputils"
 	"github.com/docker/docker/api/server/middleware"
@@ -13,6 +15,7 @@ import (
 	"github.com/docker/docker/api/server/router/debug"
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/authorization"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
@@ -33,10 +36,37 @@ type Config struct {
 // Server contains instance details for the server
 type Server struct {
 	cfg           *Config
-	servers       []*HTTPServer
 	routers       []router.Router
 	routerSwapper *routerSwapper
 	middlewares   []middleware.Middleware
+
+	mu      sync.Mutex
+	servers []*HTTPServer
+	// serving is set once the server started serving the API, after which
+	// the listeners which are accepted are served immediately.
+	serving bool
+	// closed is set by Close, which closes stop to stop serving the API.
+	// Closing listeners with CloseListeners does not stop the server, even
+	// if no listener is left, as the listeners may be replaced.
+	closed  bool
+	stop    chan struct{}
+	results chan serveResult
+	done    chan struct{}
+}
+
+// ListenerConfig holds the settings of a listener of the API, which
+// restrict the settings of the server.
+type ListenerConfig struct {
+	// MinAPIVersion and MaxAPIVersion restrict the range of API versions
+	// accepted on the listener.
+	MinAPIVersion string
+	MaxAPIVersion string
+}
+
+// serveResult is the result of serving the API on a listener.
+type serveResult struct {
+	srv *HTTPServer
+	err error
 }
 
 // New returns a new instance of the server based on the specified configuration.
@@ -55,49 +85,128 @@ func (s *Server) UseMiddleware(m middleware.Middleware) {
 
 // Accept sets a listener the server accepts connections into.
 func (s *Server) Accept(addr string, listeners ...net.Listener) {
+	s.AcceptWithConfig(addr, ListenerConfig{}, listeners...)
+}
+
+// AcceptWithConfig sets a listener the server accepts connections into, with
+// its own settings. The listener is served immediately if the server is
+// already serving the API.
+func (s *Server) AcceptWithConfig(addr string, cfg ListenerConfig, listeners ...net.Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	for _, listener := range listeners {
 		httpServer := &HTTPServer{
 			srv: &http.Server{
 				Addr: addr,
 			},
-			l: listener,
+			l:   listener,
+			cfg: cfg,
 		}
 		s.servers = append(s.servers, httpServer)
+		if s.serving {
+			s.serve(httpServer)
+		}
+	}
+}
+
+// CloseListeners stops serving the API on the listeners accepted for addr,
+// without stopping the server.
+func (s *Server) CloseListeners(addr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var (
+		servers []*HTTPServer
+		errs    []string
+	)
+	for _, srv := range s.servers {
+		if srv.srv.Addr != addr {
+			servers = append(servers, srv)
+			continue
+		}
+		srv.removed = true
+		if err := srv.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	s.servers = servers
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
 	}
+	return nil
 }
 
 // Close closes servers and thus stop receiving requests
 func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	for _, srv := range s.servers {
 		if err := srv.Close(); err != nil {
 			logrus.Error(err)
 		}
 	}
+	if !s.closed {
+		s.closed = true
+		if s.stop != nil {
+			close(s.stop)
+		}
+	}
 }
 
 // serveAPI loops through all initialized servers and spawns goroutine
 // with Serve method for each. It sets createMux() as Handler also.
+// It returns when the server is closed, or when a server fails.
 func (s *Server) serveAPI() error {
-	var chErrors = make(chan error, len(s.servers))
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.stop = make(chan struct{})
+	s.results = make(chan serveResult)
+	s.done = make(chan struct{})
+	defer close(s.done)
 	for _, srv := range s.servers {
-		srv.srv.Handler = s.routerSwapper
-		go func(srv *HTTPServer) {
-			var err error
-			logrus.Infof("API listen on %s", srv.l.Addr())
-			if err = srv.Serve(); err != nil && strings.Contains(err.Error(), "use of closed network connection") {
-				err = nil
-			}
-			chErrors <- err
-		}(srv)
+		s.serve(srv)
 	}
+	s.serving = true
+	s.mu.Unlock()
 
-	for range s.servers {
-		err := <-chErrors
-		if err != nil {
-			return err
+	for {
+		select {
+		case res := <-s.results:
+			s.mu.Lock()
+			removed := res.srv.removed
+			s.mu.Unlock()
+			if res.err != nil && !removed {
+				return res.err
+			}
+		case <-s.stop:
+			return nil
 		}
 	}
-	return nil
+}
+
+// serve serves the API on a listener. It must be called with s.mu held.
+func (s *Server) serve(srv *HTTPServer) {
+	srv.srv.Handler = s.routerSwapper
+	if srv.cfg != (ListenerConfig{}) {
+		cfg := srv.cfg
+		srv.srv.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(middleware.WithAPIVersionRange(r.Context(), cfg.MinAPIVersion, cfg.MaxAPIVersion))
+			s.routerSwapper.ServeHTTP(w, r)
+		})
+	}
+	go func(srv *HTTPServer) {
+		var err error
+		logrus.Infof("API listen on %s", srv.l.Addr())
+		if err = srv.Serve(); err != nil && strings.Contains(err.Error(), "use of closed network connection") {
+			err = nil
+		}
+		select {
+		case s.results <- serveResult{srv: srv, err: err}:
+		case <-s.done:
+		}
+	}(srv)
 }
 
 // HTTPServer contains an instance of http server and the listener.
@@ -106,6 +215,9 @@ func (s *Server) serveAPI() error {
 type HTTPServer struct {
 	srv *http.Server
 	l   net.Listener
+	cfg ListenerConfig
+	// removed is set when the listener is closed by CloseListeners.
+	removed bool
 }
 
 // Serve starts listening for inbound requests.
@@ -131,6 +243,7 @@ func (s *Server) makeHTTPHandler(handler httputils.APIFunc) http.HandlerFunc {
 		// use intermediate variable to prevent "should not use basic type
 		// string as key in context.WithValue" golint errors
 		ctx := context.WithValue(r.Context(), dockerversion.UAStringKey{}, r.Header.Get("User-Agent"))
+		ctx = authorization.WithCaller(ctx, r)
 		r = r.WithContext(ctx)
 		handlerFunc := s.handlerWithGlobalMiddlewares(handler)
 
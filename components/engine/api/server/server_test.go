@@ -2,14 +2,19 @@ package server // import "github.com/docker/docker/api/server"
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/api/server/httputils"
 	"github.com/docker/docker/api/server/middleware"
+	"github.com/docker/docker/api/server/router"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 )
 
 func TestMiddlewares(t *testing.T) {
@@ -43,3 +48,94 @@ func TestMiddlewares(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+type pingRouter struct{}
+
+func (pingRouter) Routes() []router.Route {
+	return []router.Route{
+		router.NewGetRoute("/_ping", func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+			_, err := w.Write([]byte("OK"))
+			return err
+		}),
+	}
+}
+
+func TestServerAcceptAndCloseListenersWhileServing(t *testing.T) {
+	srv := New(&Config{})
+	srv.UseMiddleware(middleware.NewVersionMiddleware("0.1omega2", api.DefaultVersion, api.MinVersion))
+	srv.InitRouter(pingRouter{})
+
+	l1, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	srv.Accept("first", l1)
+
+	waitChan := make(chan error)
+	go srv.Wait(waitChan)
+
+	l2, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	srv.AcceptWithConfig("second", ListenerConfig{MaxAPIVersion: "1.30"}, l2)
+
+	resp, err := http.Get("http://" + l2.Addr().String() + "/_ping")
+	assert.NilError(t, err)
+	resp.Body.Close()
+	assert.Check(t, is.Equal(resp.StatusCode, http.StatusOK))
+	assert.Check(t, is.Equal(resp.Header.Get("API-Version"), "1.30"))
+
+	resp, err = http.Get("http://" + l1.Addr().String() + "/_ping")
+	assert.NilError(t, err)
+	resp.Body.Close()
+	assert.Check(t, is.Equal(resp.Header.Get("API-Version"), api.DefaultVersion))
+
+	assert.NilError(t, srv.CloseListeners("second"))
+	_, err = net.Dial("tcp", l2.Addr().String())
+	assert.Check(t, err != nil, "the closed listener must not accept connections")
+
+	select {
+	case err := <-waitChan:
+		t.Fatalf("the server stopped after closing a listener: %v", err)
+	default:
+	}
+
+	srv.Close()
+	assert.NilError(t, <-waitChan)
+}
+
+func TestServerReplaceOnlyListener(t *testing.T) {
+	srv := New(&Config{})
+	srv.UseMiddleware(middleware.NewVersionMiddleware("0.1omega2", api.DefaultVersion, api.MinVersion))
+	srv.InitRouter(pingRouter{})
+
+	l1, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	srv.Accept("host", l1)
+
+	waitChan := make(chan error)
+	go srv.Wait(waitChan)
+
+	// The only listener is closed before the listener replacing it is
+	// accepted, as when the settings of the only host are reloaded.
+	assert.NilError(t, srv.CloseListeners("host"))
+	select {
+	case err := <-waitChan:
+		t.Fatalf("the server stopped after closing its only listener: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	l2, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	srv.Accept("host", l2)
+	resp, err := http.Get("http://" + l2.Addr().String() + "/_ping")
+	assert.NilError(t, err)
+	resp.Body.Close()
+	assert.Check(t, is.Equal(resp.StatusCode, http.StatusOK))
+
+	select {
+	case err := <-waitChan:
+		t.Fatalf("the server stopped after replacing its only listener: %v", err)
+	default:
+	}
+
+	srv.Close()
+	assert.NilError(t, <-waitChan)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/idtools"
 )
 
 // ContainerAttachConfig holds the streams to use when connecting to a container to view logs.
@@ -124,5 +125,9 @@ type CommitConfig struct {
 	ContainerID         string
 	ContainerMountLabel string
 	ContainerOS         string
-	ParentImageID       string
+	// ContainerIDMapping is the user namespace mapping of a container with
+	// its own mapping, through which the IDs of the files of its RW layer
+	// are mapped back to the IDs of the image.
+	ContainerIDMapping *idtools.IdentityMapping
+	ParentImageID      string
 }
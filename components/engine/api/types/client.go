@@ -69,6 +69,23 @@ type ContainerListOptions struct {
 	Filters filters.Args
 }
 
+// DiskUsageOptions holds parameters for system disk usage query.
+type DiskUsageOptions struct {
+	// Fresh forces sizes to be recalculated instead of returning
+	// cached sizes.
+	Fresh bool
+}
+
+// SystemCheckOptions holds parameters for a system consistency check.
+type SystemCheckOptions struct {
+	// Repair repairs the problems which can be repaired safely. Without
+	// it, problems are only reported.
+	Repair bool
+	// VerifyLayers verifies the content of every layer against its
+	// DiffID, which requires reading all layer data.
+	VerifyLayers bool
+}
+
 // ContainerLogsOptions holds parameters to filter logs with.
 type ContainerLogsOptions struct {
 	ShowStdout bool
@@ -190,6 +207,9 @@ type ImageBuildOptions struct {
 	// Outputs defines configurations for exporting build results. Only supported
 	// in BuildKit mode
 	Outputs []ImageBuildOutput
+	// SBOM records the inventory of the packages of the resulting image,
+	// which is then returned by the image packages request
+	SBOM bool
 }
 
 // ImageBuildOutput defines configuration for exporting a build result
@@ -274,6 +294,39 @@ type ImageRemoveOptions struct {
 	PruneChildren bool
 }
 
+// ImageSaveOptions holds parameters to save images.
+type ImageSaveOptions struct {
+	// Format is the format of the archive, either "docker" (the default)
+	// or "oci" for an OCI image layout.
+	Format string
+	// ExcludeBase lists images whose layers are not included in the
+	// archive.
+	ExcludeBase []string
+	// ExcludeLayers lists the digests of layers (DiffIDs) which are not
+	// included in the archive.
+	ExcludeLayers []string
+}
+
+// UnmountOptions holds parameters to unmount the filesystem of an image or
+// a container.
+type UnmountOptions struct {
+	Force bool // Force releases all mounts of the filesystem
+}
+
+// DiffOptions holds parameters to compare the filesystem of an image or a
+// container against an image.
+type DiffOptions struct {
+	Content bool // Content includes a diff of the content of modified text files
+}
+
+// ListFilesOptions holds parameters to list the files of a container or an
+// image.
+type ListFilesOptions struct {
+	Path      string // Path is the path to list, the root directory if empty
+	Recursive bool   // Recursive lists the content of subdirectories
+	Depth     int    // Depth limits the levels of directories listed recursively, 0 for no limit
+}
+
 // ImageSearchOptions holds parameters to search images with.
 type ImageSearchOptions struct {
 	RegistryAuth  string
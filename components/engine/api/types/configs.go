@@ -39,6 +39,7 @@ type ExecConfig struct {
 	Env          []string // Environment variables
 	WorkingDir   string   // Working directory
 	Cmd          []string // Execution commands and args
+	Log          *bool    `json:",omitempty"` // Record the standard streams in the logs of the container, defaults to the daemon configuration
 }
 
 // PluginRmConfig holds arguments for plugin remove.
@@ -1,6 +1,7 @@
 package container // import "github.com/docker/docker/api/types/container"
 
 import (
+	"os"
 	"strings"
 
 	"github.com/docker/docker/api/types/blkiodev"
@@ -166,11 +167,17 @@ func (n UsernsMode) IsPrivate() bool {
 	return !(n.IsHost())
 }
 
+// IsAuto indicates whether the container uses a private userns with its own
+// range of subordinate IDs, allocated by the daemon.
+func (n UsernsMode) IsAuto() bool {
+	return n == "auto"
+}
+
 // Valid indicates whether the userns is valid.
 func (n UsernsMode) Valid() bool {
 	parts := strings.Split(string(n), ":")
 	switch mode := parts[0]; mode {
-	case "", "host":
+	case "", "host", "auto":
 	default:
 		return false
 	}
@@ -205,7 +212,7 @@ type UTSMode string
 
 // IsPrivate indicates whether the container uses its private UTS namespace.
 func (n UTSMode) IsPrivate() bool {
-	return !(n.IsHost())
+	return !(n.IsHost() || n.IsContainer())
 }
 
 // IsHost indicates whether the container uses the host's UTS namespace.
@@ -213,17 +220,36 @@ func (n UTSMode) IsHost() bool {
 	return n == "host"
 }
 
+// IsContainer indicates whether the container uses a container's UTS namespace.
+func (n UTSMode) IsContainer() bool {
+	parts := strings.SplitN(string(n), ":", 2)
+	return len(parts) > 1 && parts[0] == "container"
+}
+
 // Valid indicates whether the UTS namespace is valid.
 func (n UTSMode) Valid() bool {
 	parts := strings.Split(string(n), ":")
 	switch mode := parts[0]; mode {
 	case "", "host":
+	case "container":
+		if len(parts) != 2 || parts[1] == "" {
+			return false
+		}
 	default:
 		return false
 	}
 	return true
 }
 
+// Container returns the name of the container whose UTS namespace is going to be used.
+func (n UTSMode) Container() string {
+	parts := strings.SplitN(string(n), ":", 2)
+	if len(parts) > 1 {
+		return parts[1]
+	}
+	return ""
+}
+
 // PidMode represents the pid namespace of the container.
 type PidMode string
 
@@ -383,6 +409,45 @@ type UpdateConfig struct {
 	// Contains container's resources (cgroups, ulimits)
 	Resources
 	RestartPolicy RestartPolicy
+
+	// DevicesAdd lists the devices to add to the container. The devices are
+	// also made available in the container if it is running.
+	DevicesAdd []DeviceMapping `json:",omitempty"`
+	// DevicesRemove lists the paths in the container of the devices to
+	// remove from the container.
+	DevicesRemove []string `json:",omitempty"`
+}
+
+// PortsUpdateConfig holds the port bindings to publish, and the ports to
+// unpublish, on a container.
+type PortsUpdateConfig struct {
+	// Add holds the port bindings to publish, by port of the container.
+	Add nat.PortMap `json:",omitempty"`
+	// Remove holds the ports of the container to unpublish. All the bindings
+	// of these ports are removed.
+	Remove []nat.Port `json:",omitempty"`
+}
+
+// DebugConfig holds the configuration of an ephemeral debug container. The
+// debug container joins the PID, network, IPC and UTS namespaces of a running
+// container, the root filesystem of this container is mounted in it, and it
+// is removed when it exits.
+type DebugConfig struct {
+	// Image of the debug container, "busybox" by default
+	Image string `json:",omitempty"`
+	// Cmd is the command to run, the command of the image by default
+	Cmd strslice.StrSlice `json:",omitempty"`
+	// Env lists the environment variables to set in the debug container
+	Env []string `json:",omitempty"`
+	// User that runs the command, also support user:group
+	User string `json:",omitempty"`
+	// Tty attaches the standard streams to a tty
+	Tty bool `json:",omitempty"`
+	// RootfsPath is the path in the debug container at which the root
+	// filesystem of the container is mounted, "/target" by default
+	RootfsPath string `json:",omitempty"`
+	// CapAdd lists the kernel capabilities to add to the debug container
+	CapAdd strslice.StrSlice `json:",omitempty"`
 }
 
 // HostConfig the non-portable Config structure of a container.
@@ -445,4 +510,108 @@ type HostConfig struct {
 
 	// Run a custom init inside the container, if null, use the daemon's configured settings
 	Init *bool `json:",omitempty"`
+
+	// Secrets of the daemon to mount in the container, when the daemon is not part of a swarm
+	Secrets []*SecretReference `json:",omitempty"`
+
+	// Configs of the daemon to mount in the container, when the daemon is not part of a swarm
+	Configs []*ConfigReference `json:",omitempty"`
+
+	// DependsOn lists the containers which must be started, or healthy, before the container is started
+	DependsOn []Dependency `json:",omitempty"`
+	// OCIHooks are the OCI lifecycle hooks of the container, executed on the host (Linux only)
+	OCIHooks *OCIHooks `json:",omitempty"`
+	// UnixSocketBindings publishes ports of the container on Unix sockets of the host (Linux only)
+	UnixSocketBindings map[nat.Port][]UnixSocketBinding `json:",omitempty"`
+}
+
+// UnixSocketBinding is a Unix socket of the host on which a TCP port of a
+// container is published. The connections to the socket are proxied to the
+// port of the container by the daemon.
+type UnixSocketBinding struct {
+	// Path is the absolute path of the socket on the host, in the sockets
+	// directory of the exec root of the daemon
+	Path string
+	// UID and GID are the owner of the socket, root by default
+	UID string `json:",omitempty"`
+	GID string `json:",omitempty"`
+	// Mode is the file mode of the socket, 0660 by default
+	Mode os.FileMode `json:",omitempty"`
+}
+
+// OCIHooks are the OCI lifecycle hooks of a container. The paths, arguments
+// and environment variables of the hooks must be allowed by the
+// "allowed-oci-hooks" setting of the daemon.
+type OCIHooks struct {
+	Prestart  []OCIHook `json:",omitempty"`
+	Poststart []OCIHook `json:",omitempty"`
+	Poststop  []OCIHook `json:",omitempty"`
+}
+
+// OCIHook is an OCI lifecycle hook of a container
+type OCIHook struct {
+	// Path is the absolute path of the hook on the host
+	Path string
+	// Args are the arguments of the hook, including argv[0]. The path of
+	// the hook is used as argv[0] if Args is empty.
+	Args []string `json:",omitempty"`
+	// Env are the environment variables of the hook
+	Env []string `json:",omitempty"`
+	// Timeout is the number of seconds after which the hook is killed
+	Timeout *int `json:",omitempty"`
+}
+
+// DependencyCondition is the condition a dependency of a container must meet
+// before the container is started.
+type DependencyCondition string
+
+const (
+	// DependencyConditionStarted waits for the dependency to be running. It
+	// is the default condition.
+	DependencyConditionStarted DependencyCondition = "started"
+	// DependencyConditionHealthy waits for the health check of the dependency
+	// to report it as healthy.
+	DependencyConditionHealthy DependencyCondition = "healthy"
+)
+
+// Valid indicates whether the dependency condition is valid
+func (c DependencyCondition) Valid() bool {
+	return c == "" || c == DependencyConditionStarted || c == DependencyConditionHealthy
+}
+
+// Dependency is a container which must meet a condition before a container
+// depending on it is started.
+type Dependency struct {
+	// Container is the name or the ID of the dependency
+	Container string
+	// Condition is the condition the dependency must meet, "started" by default
+	Condition DependencyCondition `json:",omitempty"`
+}
+
+// FileTarget is the file in which a secret or a config is mounted in a
+// container. Relative names are relative to /run/secrets for secrets, and to
+// the root of the container for configs.
+type FileTarget struct {
+	Name string
+	UID  string
+	GID  string
+	Mode os.FileMode
+}
+
+// SecretReference is a reference to a secret of the daemon which is mounted
+// in a container. It has the same semantics as the secret references of the
+// services of a swarm.
+type SecretReference struct {
+	File       *FileTarget `json:",omitempty"`
+	SecretID   string      `json:",omitempty"`
+	SecretName string      `json:",omitempty"`
+}
+
+// ConfigReference is a reference to a config of the daemon which is mounted
+// in a container. It has the same semantics as the config references of the
+// services of a swarm.
+type ConfigReference struct {
+	File       *FileTarget `json:",omitempty"`
+	ConfigID   string      `json:",omitempty"`
+	ConfigName string      `json:",omitempty"`
 }
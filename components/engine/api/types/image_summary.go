@@ -23,6 +23,10 @@ type ImageSummary struct {
 	// Required: true
 	Labels map[string]string `json:"Labels"`
 
+	// Time at which a container was last created or started from the
+	// image, as a Unix timestamp. Zero if the image was never used.
+	LastUsed int64 `json:"LastUsed,omitempty"`
+
 	// parent Id
 	// Required: true
 	ParentID string `json:"ParentId"`
@@ -50,7 +50,8 @@ type ImageInspect struct {
 
 // ImageMetadata contains engine-local data about the image
 type ImageMetadata struct {
-	LastTagTime time.Time `json:",omitempty"`
+	LastTagTime  time.Time  `json:",omitempty"`
+	LastUsedTime *time.Time `json:",omitempty"`
 }
 
 // Container contains response of Engine API:
@@ -315,6 +316,21 @@ type ContainerState struct {
 	Health     *Health `json:",omitempty"`
 }
 
+// ExecHistoryEntry records an exec which ran in a container. It's part of
+// ContainerJSONBase and returned by the "inspect" command.
+type ExecHistoryEntry struct {
+	ID         string    // ID is the ID of the exec
+	Cmd        []string  // Cmd is the command of the exec, with its arguments
+	User       string    // User is the user which ran the command in the container
+	Caller     string    `json:",omitempty"` // Caller is the identity of the API client which started the exec
+	Privileged bool      // Privileged is set if the exec ran in privileged mode
+	Tty        bool      // Tty is set if the exec had a pseudo-TTY
+	Logged     bool      // Logged is set if the standard streams of the exec were recorded in the logs of the container
+	Start      time.Time // Start is the time the exec started
+	End        time.Time // End is the time the exec ended, or the zero time if it is running
+	ExitCode   *int      `json:",omitempty"` // ExitCode is the exit code of the exec, unset until it ended
+}
+
 // ContainerNode stores information about the node that a container
 // is running on.  It's only available in Docker Swarm
 type ContainerNode struct {
@@ -349,6 +365,7 @@ type ContainerJSONBase struct {
 	ProcessLabel    string
 	AppArmorProfile string
 	ExecIDs         []string
+	ExecHistory     []*ExecHistoryEntry `json:",omitempty"`
 	HostConfig      *container.HostConfig
 	GraphDriver     GraphDriverData
 	SizeRw          *int64 `json:",omitempty"`
@@ -506,8 +523,27 @@ type Checkpoint struct {
 
 // Runtime describes an OCI runtime
 type Runtime struct {
-	Path string   `json:"path"`
+	// Path and Args configure a runc-compatible runtime binary, executed
+	// through the v1 containerd shim.
+	Path string   `json:"path,omitempty"`
 	Args []string `json:"runtimeArgs,omitempty"`
+
+	// Type is the containerd runtime type of the runtime, executed through
+	// a v2 containerd shim (for example "io.containerd.runc.v2"), and Options
+	// are the options of the shim. Type is mutually exclusive with Path and
+	// Args.
+	Type    string                 `json:"runtimeType,omitempty"`
+	Options map[string]interface{} `json:"options,omitempty"`
+
+	// ShimConfig is the configuration of the shim of the runtime, which is
+	// only used internally by the daemon.
+	ShimConfig *ShimConfig `json:"-"`
+}
+
+// ShimConfig is the configuration of the containerd shim of a runtime.
+type ShimConfig struct {
+	Binary string
+	Opts   interface{}
 }
 
 // DiskUsage contains response of Engine API:
@@ -521,6 +557,30 @@ type DiskUsage struct {
 	BuilderSize int64 // deprecated
 }
 
+// SystemCheckProblem describes an inconsistency found by a system check.
+type SystemCheckProblem struct {
+	// Kind is the kind of problem, for example "orphan-layer".
+	Kind string
+	// ID identifies the object with the problem, such as a layer chain ID,
+	// an image ID, a reference, a container ID or a volume name.
+	ID string
+	// Message describes the problem.
+	Message string
+	// Repair describes how the problem is repaired. It is empty if the
+	// problem cannot be repaired automatically.
+	Repair string `json:",omitempty"`
+	// Repaired is set if the problem was repaired.
+	Repaired bool
+	// RepairError holds the error encountered while repairing the problem.
+	RepairError string `json:",omitempty"`
+}
+
+// SystemCheckReport contains the response for Engine API:
+// POST "/system/check"
+type SystemCheckReport struct {
+	Problems []SystemCheckProblem
+}
+
 // ContainersPruneReport contains the response for Engine API:
 // POST "/containers/prune"
 type ContainersPruneReport struct {
@@ -613,3 +673,93 @@ type BuildCachePruneOptions struct {
 	KeepStorage int64
 	Filters     filters.Args
 }
+
+// MountResponse contains the response for Engine API:
+// POST "/images/{name:.*}/mount" and POST "/containers/{name:.*}/mount"
+type MountResponse struct {
+	// Path is the path on the host at which the filesystem is mounted
+	Path string
+}
+
+// FileChange describes a change to a file between two filesystems, as
+// returned by the Engine API:
+// GET "/images/{name:.*}/changes" and GET "/containers/{name:.*}/changes"
+type FileChange struct {
+	// Kind is the kind of change: 0 for a modified file, 1 for an added
+	// file, and 2 for a deleted file
+	Kind uint8
+	// Path is the absolute path of the file
+	Path string
+	// Old is the file in the filesystem which is compared against. It is
+	// not set for an added file.
+	Old *FileChangeInfo `json:",omitempty"`
+	// New is the file in the filesystem which is compared. It is not set
+	// for a deleted file.
+	New *FileChangeInfo `json:",omitempty"`
+	// Diff is a unified diff of the content of a modified text file. It is
+	// only set if requested, and if both versions of the file are small
+	// enough.
+	Diff string `json:",omitempty"`
+}
+
+// FileChangeInfo describes one side of a FileChange.
+type FileChangeInfo struct {
+	Size       int64
+	Mode       os.FileMode
+	UID        int
+	GID        int
+	LinkTarget string `json:",omitempty"`
+	// Digest is the digest of the content of a regular file
+	Digest string `json:",omitempty"`
+}
+
+// FileEntry describes a file in the filesystem of a container or an image,
+// as returned by the Engine API:
+// GET "/containers/{name:.*}/files" and GET "/images/{name:.*}/files"
+type FileEntry struct {
+	// Name is the base name of the file
+	Name string
+	// Path is the absolute path of the file
+	Path  string
+	Size  int64
+	Mode  os.FileMode
+	UID   int
+	GID   int
+	Mtime time.Time
+	// LinkTarget is the target of a symbolic link, as stored in the link
+	LinkTarget string `json:",omitempty"`
+}
+
+// ImagePackages contains the inventory of the packages of an image, as
+// returned by the Engine API: GET "/images/{name:.*}/packages"
+type ImagePackages struct {
+	// ID is the ID of the image
+	ID string
+	// OS is the ID of the distribution of the image, read from os-release
+	OS string `json:",omitempty"`
+	// OSVersion is the version of the distribution of the image
+	OSVersion string `json:",omitempty"`
+	// Created is the time at which the inventory was made
+	Created  time.Time
+	Packages []ImagePackage
+}
+
+// ImagePackage is a package installed in an image, found in the database of
+// a package manager, in a lockfile, or in the build information of a binary.
+type ImagePackage struct {
+	Name    string
+	Version string
+	// Type is the type of the package, as a package URL type: "deb", "apk",
+	// "rpm", "golang", "pypi", or "npm"
+	Type string
+	// Arch is the architecture of the package, for OS packages
+	Arch string `json:",omitempty"`
+	// Source is the source package of an OS package
+	Source string `json:",omitempty"`
+	// License is the license of the package, if it is recorded
+	License string `json:",omitempty"`
+	// Path is the path of the file in which the package was found
+	Path string
+	// PURL is the package URL which identifies the package
+	PURL string `json:",omitempty"`
+}
@@ -0,0 +1,25 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerDebug creates an ephemeral debug container, which joins the
+// namespaces of a running container, based on the given configuration.
+func (cli *Client) ContainerDebug(ctx context.Context, containerID string, config container.DebugConfig) (container.ContainerCreateCreatedBody, error) {
+	var response container.ContainerCreateCreatedBody
+	if err := cli.NewVersionError("1.41", "container debug"); err != nil {
+		return response, err
+	}
+	serverResp, err := cli.post(ctx, "/containers/"+containerID+"/debug", nil, config, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.NewDecoder(serverResp.body).Decode(&response)
+	return response, err
+}
@@ -0,0 +1,58 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestContainerDebugError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.ContainerDebug(context.Background(), "nothing", container.DebugConfig{})
+	assert.Check(t, is.Error(err, "Error response from daemon: Server error"))
+	assert.Check(t, errdefs.IsSystem(err))
+}
+
+func TestContainerDebug(t *testing.T) {
+	expectedURL := "/containers/container_id/debug"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			var config container.DebugConfig
+			if err := json.NewDecoder(req.Body).Decode(&config); err != nil {
+				return nil, err
+			}
+			if config.Image != "alpine" || !config.Tty {
+				return nil, fmt.Errorf("expected Image alpine with a Tty, got %+v", config)
+			}
+			b, err := json.Marshal(container.ContainerCreateCreatedBody{ID: "debug_id"})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+	r, err := client.ContainerDebug(context.Background(), "container_id", container.DebugConfig{Image: "alpine", Tty: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(r.ID, "debug_id"))
+}
@@ -0,0 +1,32 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ContainerDiffAgainst compares the filesystem of a container against that of
+// an image, which need not be the image of the container.
+func (cli *Client) ContainerDiffAgainst(ctx context.Context, containerID, image string, options types.DiffOptions) ([]types.FileChange, error) {
+	if err := cli.NewVersionError("1.41", "container diff against an image"); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("against", image)
+	if options.Content {
+		query.Set("content", "1")
+	}
+
+	var changes []types.FileChange
+	serverResp, err := cli.get(ctx, "/containers/"+containerID+"/changes", query, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return changes, err
+	}
+
+	err = json.NewDecoder(serverResp.body).Decode(&changes)
+	return changes, err
+}
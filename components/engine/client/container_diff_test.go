@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/errdefs"
 )
@@ -62,3 +63,39 @@ func TestContainerDiff(t *testing.T) {
 		t.Fatalf("expected an array of 2 changes, got %v", changes)
 	}
 }
+
+func TestContainerDiffAgainst(t *testing.T) {
+	expectedURL := "/containers/container_id/changes"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if against := req.URL.Query().Get("against"); against != "image_id" {
+				return nil, fmt.Errorf("against not set in URL query properly. Expected 'image_id', got %s", against)
+			}
+			b, err := json.Marshal([]types.FileChange{
+				{
+					Kind: 2,
+					Path: "/path/1",
+					Old:  &types.FileChangeInfo{Size: 10},
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	changes, err := client.ContainerDiffAgainst(context.Background(), "container_id", "image_id", types.DiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Old == nil || changes[0].Old.Size != 10 {
+		t.Fatalf("expected an array of 1 change, got %v", changes)
+	}
+}
@@ -0,0 +1,37 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ContainerListFiles lists the files at a path in the filesystem of a container.
+func (cli *Client) ContainerListFiles(ctx context.Context, containerID string, options types.ListFilesOptions) ([]types.FileEntry, error) {
+	if err := cli.NewVersionError("1.41", "container ls-files"); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	if options.Path != "" {
+		query.Set("path", options.Path)
+	}
+	if options.Recursive {
+		query.Set("recursive", "1")
+	}
+	if options.Depth > 0 {
+		query.Set("depth", strconv.Itoa(options.Depth))
+	}
+
+	var entries []types.FileEntry
+	serverResp, err := cli.get(ctx, "/containers/"+containerID+"/files", query, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return entries, err
+	}
+
+	err = json.NewDecoder(serverResp.body).Decode(&entries)
+	return entries, err
+}
@@ -0,0 +1,25 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ContainerMount mounts the root filesystem of the container on the daemon host,
+// and returns the path at which it is mounted.
+func (cli *Client) ContainerMount(ctx context.Context, containerID string) (string, error) {
+	if err := cli.NewVersionError("1.41", "container mount"); err != nil {
+		return "", err
+	}
+	serverResp, err := cli.post(ctx, "/containers/"+containerID+"/mount", nil, nil, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return "", err
+	}
+
+	var resp types.MountResponse
+	err = json.NewDecoder(serverResp.body).Decode(&resp)
+	return resp.Path, err
+}
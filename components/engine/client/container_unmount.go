@@ -0,0 +1,23 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ContainerUnmount releases a mount of the root filesystem of the container
+// made by ContainerMount.
+func (cli *Client) ContainerUnmount(ctx context.Context, containerID string, options types.UnmountOptions) error {
+	if err := cli.NewVersionError("1.41", "container unmount"); err != nil {
+		return err
+	}
+	query := url.Values{}
+	if options.Force {
+		query.Set("force", "1")
+	}
+	resp, err := cli.post(ctx, "/containers/"+containerID+"/unmount", query, nil, nil)
+	ensureReaderClosed(resp)
+	return err
+}
@@ -0,0 +1,26 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+)
+
+// ContainerUpdatePorts publishes and unpublishes ports of a container, and
+// returns the ports published by the container.
+func (cli *Client) ContainerUpdatePorts(ctx context.Context, containerID string, config container.PortsUpdateConfig) (nat.PortMap, error) {
+	if err := cli.NewVersionError("1.41", "container ports update"); err != nil {
+		return nil, err
+	}
+	serverResp, err := cli.post(ctx, "/containers/"+containerID+"/ports", nil, config, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return nil, err
+	}
+
+	var ports nat.PortMap
+	err = json.NewDecoder(serverResp.body).Decode(&ports)
+	return ports, err
+}
@@ -0,0 +1,59 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestContainerUpdatePortsError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.ContainerUpdatePorts(context.Background(), "nothing", container.PortsUpdateConfig{})
+	assert.Check(t, is.Error(err, "Error response from daemon: Server error"))
+	assert.Check(t, errdefs.IsSystem(err))
+}
+
+func TestContainerUpdatePorts(t *testing.T) {
+	expectedURL := "/containers/container_id/ports"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			var config container.PortsUpdateConfig
+			if err := json.NewDecoder(req.Body).Decode(&config); err != nil {
+				return nil, err
+			}
+			if len(config.Remove) != 1 || config.Remove[0] != "443/tcp" {
+				return nil, fmt.Errorf("expected Remove to be [443/tcp], got %v", config.Remove)
+			}
+			b, err := json.Marshal(nat.PortMap{"80/tcp": {{HostIP: "0.0.0.0", HostPort: "8080"}}})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+	ports, err := client.ContainerUpdatePorts(context.Background(), "container_id", container.PortsUpdateConfig{
+		Add:    nat.PortMap{"80/tcp": {{HostPort: "8080"}}},
+		Remove: []nat.Port{"443/tcp"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(nat.PortMap{"80/tcp": {{HostIP: "0.0.0.0", HostPort: "8080"}}}, ports))
+}
@@ -4,15 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 
 	"github.com/docker/docker/api/types"
 )
 
 // DiskUsage requests the current data usage from the daemon
 func (cli *Client) DiskUsage(ctx context.Context) (types.DiskUsage, error) {
+	return cli.DiskUsageWithOptions(ctx, types.DiskUsageOptions{})
+}
+
+// DiskUsageWithOptions requests the current data usage from the daemon, with
+// the given options
+func (cli *Client) DiskUsageWithOptions(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error) {
 	var du types.DiskUsage
 
-	serverResp, err := cli.get(ctx, "/system/df", nil, nil)
+	query := url.Values{}
+	if options.Fresh {
+		query.Set("fresh", "1")
+	}
+
+	serverResp, err := cli.get(ctx, "/system/df", query, nil)
 	defer ensureReaderClosed(serverResp)
 	if err != nil {
 		return du, err
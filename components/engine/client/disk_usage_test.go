@@ -57,3 +57,26 @@ func TestDiskUsage(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestDiskUsageFresh(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if fresh := req.URL.Query().Get("fresh"); fresh != "1" {
+				return nil, fmt.Errorf("fresh not set in URL query properly. Expected '1', got %s", fresh)
+			}
+
+			b, err := json.Marshal(types.DiskUsage{})
+			if err != nil {
+				return nil, err
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+	if _, err := client.DiskUsageWithOptions(context.Background(), types.DiskUsageOptions{Fresh: true}); err != nil {
+		t.Fatal(err)
+	}
+}
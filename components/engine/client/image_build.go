@@ -133,6 +133,12 @@ func (cli *Client) imageBuildOptionsToQuery(options types.ImageBuildOptions) (ur
 	if options.BuildID != "" {
 		query.Set("buildid", options.BuildID)
 	}
+	if options.SBOM {
+		if err := cli.NewVersionError("1.41", "sbom"); err != nil {
+			return query, err
+		}
+		query.Set("sbom", "1")
+	}
 	query.Set("version", string(options.Version))
 
 	if options.Outputs != nil {
@@ -0,0 +1,32 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ImageDiff compares the filesystem of the target image against that of the
+// base image.
+func (cli *Client) ImageDiff(ctx context.Context, base, target string, options types.DiffOptions) ([]types.FileChange, error) {
+	if err := cli.NewVersionError("1.41", "image diff"); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("against", base)
+	if options.Content {
+		query.Set("content", "1")
+	}
+
+	var changes []types.FileChange
+	serverResp, err := cli.get(ctx, "/images/"+target+"/changes", query, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return changes, err
+	}
+
+	err = json.NewDecoder(serverResp.body).Decode(&changes)
+	return changes, err
+}
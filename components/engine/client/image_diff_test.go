@@ -0,0 +1,69 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestImageDiffError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.ImageDiff(context.Background(), "base", "target", types.DiffOptions{})
+	assert.Check(t, is.Error(err, "Error response from daemon: Server error"))
+	assert.Check(t, errdefs.IsSystem(err))
+}
+
+func TestImageDiffVersionError(t *testing.T) {
+	client := &Client{
+		version: "1.40",
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.ImageDiff(context.Background(), "base", "target", types.DiffOptions{})
+	assert.Check(t, is.Error(err, `"image diff" requires API version 1.41, but the Docker daemon API version is 1.40`))
+}
+
+func TestImageDiff(t *testing.T) {
+	expectedURL := "/images/target/changes"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			query := req.URL.Query()
+			if against := query.Get("against"); against != "base" {
+				return nil, fmt.Errorf("against not set in URL query properly. Expected 'base', got %s", against)
+			}
+			if content := query.Get("content"); content != "1" {
+				return nil, fmt.Errorf("content not set in URL query properly. Expected '1', got %s", content)
+			}
+			b, err := json.Marshal([]types.FileChange{
+				{Kind: 0, Path: "/etc/passwd", Diff: "--- a/etc/passwd"},
+				{Kind: 1, Path: "/etc/group"},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	changes, err := client.ImageDiff(context.Background(), "base", "target", types.DiffOptions{Content: true})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(changes, 2))
+	assert.Check(t, is.Equal(changes[0].Diff, "--- a/etc/passwd"))
+}
@@ -0,0 +1,37 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ImageListFiles lists the files at a path in the filesystem of an image.
+func (cli *Client) ImageListFiles(ctx context.Context, imageID string, options types.ListFilesOptions) ([]types.FileEntry, error) {
+	if err := cli.NewVersionError("1.41", "image ls-files"); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	if options.Path != "" {
+		query.Set("path", options.Path)
+	}
+	if options.Recursive {
+		query.Set("recursive", "1")
+	}
+	if options.Depth > 0 {
+		query.Set("depth", strconv.Itoa(options.Depth))
+	}
+
+	var entries []types.FileEntry
+	serverResp, err := cli.get(ctx, "/images/"+imageID+"/files", query, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return entries, err
+	}
+
+	err = json.NewDecoder(serverResp.body).Decode(&entries)
+	return entries, err
+}
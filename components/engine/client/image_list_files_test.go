@@ -0,0 +1,54 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestImageListFilesError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.ImageListFiles(context.Background(), "nothing", types.ListFilesOptions{})
+	assert.Check(t, is.Error(err, "Error response from daemon: Server error"))
+	assert.Check(t, errdefs.IsSystem(err))
+}
+
+func TestImageListFiles(t *testing.T) {
+	expectedURL := "/images/image_id/files"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			query := req.URL.Query()
+			for key, expected := range map[string]string{"path": "/etc", "recursive": "1", "depth": "2"} {
+				if actual := query.Get(key); actual != expected {
+					return nil, fmt.Errorf("%s not set in URL query properly. Expected '%s', got %s", key, expected, actual)
+				}
+			}
+			b, err := json.Marshal([]types.FileEntry{{Name: "passwd", Path: "/etc/passwd", Size: 340}})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+	entries, err := client.ImageListFiles(context.Background(), "image_id", types.ListFilesOptions{Path: "/etc", Recursive: true, Depth: 2})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]types.FileEntry{{Name: "passwd", Path: "/etc/passwd", Size: 340}}, entries))
+}
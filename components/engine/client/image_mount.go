@@ -0,0 +1,25 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ImageMount mounts the root filesystem of the image on the daemon host,
+// and returns the path at which it is mounted.
+func (cli *Client) ImageMount(ctx context.Context, imageID string) (string, error) {
+	if err := cli.NewVersionError("1.41", "image mount"); err != nil {
+		return "", err
+	}
+	serverResp, err := cli.post(ctx, "/images/"+imageID+"/mount", nil, nil, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return "", err
+	}
+
+	var resp types.MountResponse
+	err = json.NewDecoder(serverResp.body).Decode(&resp)
+	return resp.Path, err
+}
@@ -0,0 +1,25 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ImagePackages returns the inventory of the packages of an image.
+func (cli *Client) ImagePackages(ctx context.Context, image string) (types.ImagePackages, error) {
+	var packages types.ImagePackages
+	if err := cli.NewVersionError("1.41", "image packages"); err != nil {
+		return packages, err
+	}
+
+	serverResp, err := cli.get(ctx, "/images/"+image+"/packages", nil, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return packages, err
+	}
+
+	err = json.NewDecoder(serverResp.body).Decode(&packages)
+	return packages, err
+}
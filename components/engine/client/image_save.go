@@ -4,14 +4,29 @@ import (
 	"context"
 	"io"
 	"net/url"
+
+	"github.com/docker/docker/api/types"
 )
 
 // ImageSave retrieves one or more images from the docker host as an io.ReadCloser.
 // It's up to the caller to store the images and close the stream.
-func (cli *Client) ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error) {
+func (cli *Client) ImageSave(ctx context.Context, imageIDs []string, options types.ImageSaveOptions) (io.ReadCloser, error) {
 	query := url.Values{
 		"names": imageIDs,
 	}
+	if options.Format != "" {
+		if err := cli.NewVersionError("1.41", "format"); err != nil {
+			return nil, err
+		}
+		query.Set("format", options.Format)
+	}
+	if len(options.ExcludeBase) > 0 || len(options.ExcludeLayers) > 0 {
+		if err := cli.NewVersionError("1.41", "layer exclusion"); err != nil {
+			return nil, err
+		}
+		query["exclude-base"] = options.ExcludeBase
+		query["exclude-layers"] = options.ExcludeLayers
+	}
 
 	resp, err := cli.get(ctx, "/images/get", query, nil)
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/errdefs"
 )
 
@@ -17,7 +18,7 @@ func TestImageSaveError(t *testing.T) {
 	client := &Client{
 		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
 	}
-	_, err := client.ImageSave(context.Background(), []string{"nothing"})
+	_, err := client.ImageSave(context.Background(), []string{"nothing"}, types.ImageSaveOptions{})
 	if err == nil || err.Error() != "Error response from daemon: Server error" {
 		t.Fatalf("expected a Server error, got %v", err)
 	}
@@ -39,6 +40,12 @@ func TestImageSave(t *testing.T) {
 			if !reflect.DeepEqual(names, expectedNames) {
 				return nil, fmt.Errorf("names not set in URL query properly. Expected %v, got %v", names, expectedNames)
 			}
+			if format := query.Get("format"); format != "oci" {
+				return nil, fmt.Errorf("format not set in URL query properly. Expected 'oci', got %s", format)
+			}
+			if base := query["exclude-base"]; !reflect.DeepEqual(base, []string{"base"}) {
+				return nil, fmt.Errorf("exclude-base not set in URL query properly. Expected [base], got %v", base)
+			}
 
 			return &http.Response{
 				StatusCode: http.StatusOK,
@@ -46,7 +53,7 @@ func TestImageSave(t *testing.T) {
 			}, nil
 		}),
 	}
-	saveResponse, err := client.ImageSave(context.Background(), []string{"image_id1", "image_id2"})
+	saveResponse, err := client.ImageSave(context.Background(), []string{"image_id1", "image_id2"}, types.ImageSaveOptions{Format: "oci", ExcludeBase: []string{"base"}})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,23 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ImageUnmount releases a mount of the root filesystem of the image
+// made by ImageMount.
+func (cli *Client) ImageUnmount(ctx context.Context, imageID string, options types.UnmountOptions) error {
+	if err := cli.NewVersionError("1.41", "image unmount"); err != nil {
+		return err
+	}
+	query := url.Values{}
+	if options.Force {
+		query.Set("force", "1")
+	}
+	resp, err := cli.post(ctx, "/images/"+imageID+"/unmount", query, nil, nil)
+	ensureReaderClosed(resp)
+	return err
+}
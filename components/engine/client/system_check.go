@@ -0,0 +1,40 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+)
+
+// SystemCheck requests the daemon to check the consistency of its
+// containers, images, layers and volumes, and to repair the problems found
+// if options.Repair is set.
+func (cli *Client) SystemCheck(ctx context.Context, options types.SystemCheckOptions) (types.SystemCheckReport, error) {
+	var report types.SystemCheckReport
+	if err := cli.NewVersionError("1.41", "system check"); err != nil {
+		return report, err
+	}
+
+	query := url.Values{}
+	if options.Repair {
+		query.Set("repair", "1")
+	}
+	if options.VerifyLayers {
+		query.Set("verify-layers", "1")
+	}
+
+	serverResp, err := cli.post(ctx, "/system/check", query, nil, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return report, err
+	}
+
+	if err := json.NewDecoder(serverResp.body).Decode(&report); err != nil {
+		return report, fmt.Errorf("Error retrieving system check report: %v", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,69 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestSystemCheckError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.SystemCheck(context.Background(), types.SystemCheckOptions{})
+	if err == nil || err.Error() != "Error response from daemon: Server error" {
+		t.Fatalf("expected a Server Error, got %v", err)
+	}
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %T", err)
+	}
+}
+
+func TestSystemCheck(t *testing.T) {
+	expectedURL := "/system/check"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			query := req.URL.Query()
+			if repair := query.Get("repair"); repair != "1" {
+				return nil, fmt.Errorf("repair not set in URL query properly. Expected '1', got %s", repair)
+			}
+			if verify := query.Get("verify-layers"); verify != "" {
+				return nil, fmt.Errorf("verify-layers not set in URL query properly. Expected '', got %s", verify)
+			}
+
+			b, err := json.Marshal(types.SystemCheckReport{
+				Problems: []types.SystemCheckProblem{
+					{Kind: "orphan-layer", ID: "sha256:abc", Repair: "remove layer", Repaired: true},
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+	report, err := client.SystemCheck(context.Background(), types.SystemCheckOptions{Repair: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(report.Problems, 1))
+	assert.Check(t, report.Problems[0].Repaired)
+}
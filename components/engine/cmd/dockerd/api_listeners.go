@@ -0,0 +1,386 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	apiserver "github.com/docker/docker/api/server"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/daemon/listeners"
+	dopts "github.com/docker/docker/opts"
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// clientAuthTypes maps the client-auth settings of the listeners to the
+// client authentication policies of the TLS server.
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	config.ClientAuthNone:             tls.NoClientCert,
+	config.ClientAuthRequest:          tls.RequestClientCert,
+	config.ClientAuthRequire:          tls.RequireAnyClientCert,
+	config.ClientAuthVerifyIfGiven:    tls.VerifyClientCertIfGiven,
+	config.ClientAuthRequireAndVerify: tls.RequireAndVerifyClientCert,
+}
+
+// apiListener is a listener of the API opened for a host of the daemon
+// configuration.
+type apiListener struct {
+	proto, addr string
+	settings    config.ListenerConfig
+
+	// tlsConfig is the TLS configuration of the connections accepted on the
+	// listener, which is replaced when the certificates are reloaded.
+	mu        sync.RWMutex
+	tlsConfig *tls.Config
+}
+
+func (l *apiListener) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.tlsConfig, nil
+}
+
+func (l *apiListener) setTLSConfig(tlsConfig *tls.Config) {
+	l.mu.Lock()
+	l.tlsConfig = tlsConfig
+	l.mu.Unlock()
+}
+
+// apiListeners opens the listeners of the API for the hosts of the daemon
+// configuration, and updates them when the configuration is reloaded.
+type apiListeners struct {
+	api         *apiserver.Server
+	socketGroup string
+
+	mu        sync.Mutex
+	listeners map[string]*apiListener
+	conf      *config.Config // conf is the configuration the listeners were last updated with
+}
+
+func newAPIListeners(api *apiserver.Server, socketGroup string) *apiListeners {
+	return &apiListeners{
+		api:         api,
+		socketGroup: socketGroup,
+		listeners:   make(map[string]*apiListener),
+	}
+}
+
+// update opens and closes the listeners of the API so that they match the
+// hosts and the listener settings of conf, and reloads the TLS certificates
+// of the listeners which are kept. The hosts and the listener settings of
+// conf are normalized. It returns the addresses of the hosts.
+func (a *apiListeners) update(conf *config.Config) ([]string, error) {
+	u, err := a.prepare(conf)
+	if err != nil {
+		return nil, err
+	}
+	u.commit()
+	return u.addrs, nil
+}
+
+// listenerUpdate is a change of the listeners of the API prepared by
+// apiListeners.prepare. The new listeners are bound, but the API is only
+// served on them, and the listeners which are removed are only closed, once
+// the update is committed. The listeners are locked until the update is
+// committed or aborted.
+type listenerUpdate struct {
+	a     *apiListeners
+	conf  *config.Config
+	addrs []string
+
+	kept      map[string]bool
+	tlsConfig map[string]*tls.Config
+	opened    []*apiListener
+	bound     [][]net.Listener
+
+	// replaced are the listeners closed to bind the listeners which replace
+	// them on the same address, which are opened again on abort.
+	replaced []*apiListener
+}
+
+// prepare normalizes the hosts and the listener settings of conf, loads the
+// TLS configurations of the listeners and binds the new listeners. On error,
+// the listeners are left untouched.
+func (a *apiListeners) prepare(conf *config.Config) (_ *listenerUpdate, retErr error) {
+	a.mu.Lock()
+	defer func() {
+		if retErr != nil {
+			a.mu.Unlock()
+		}
+	}()
+
+	hostList := conf.Hosts
+	if len(hostList) == 0 {
+		hostList = []string{""}
+	}
+	hosts := make([]string, 0, len(hostList))
+	seen := make(map[string]bool)
+	for _, h := range hostList {
+		host, err := dopts.ParseHost(conf.TLS, honorXDG, h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing -H %s", h)
+		}
+		if !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	var settings map[string]config.ListenerConfig
+	if len(conf.Listeners) > 0 {
+		settings = make(map[string]config.ListenerConfig, len(conf.Listeners))
+		for h, l := range conf.Listeners {
+			host, err := dopts.ParseHost(conf.TLS, honorXDG, h)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error parsing the listener %s", h)
+			}
+			settings[host] = l
+		}
+	}
+	conf.Hosts, conf.Listeners = hosts, settings
+
+	// The TLS configurations are loaded before binding any listener, so
+	// that invalid certificates leave the listeners untouched.
+	u := &listenerUpdate{
+		a:         a,
+		conf:      conf,
+		kept:      make(map[string]bool),
+		tlsConfig: make(map[string]*tls.Config),
+	}
+	for _, host := range hosts {
+		protoAddrParts := strings.SplitN(host, "://", 2)
+		if len(protoAddrParts) != 2 {
+			return nil, fmt.Errorf("bad format %s, expected PROTO://ADDR", host)
+		}
+		u.addrs = append(u.addrs, protoAddrParts[1])
+
+		s := conf.ListenerSettings(host)
+		if *s.TLS {
+			c, err := tlsconfig.Server(tlsconfig.Options{
+				CAFile:             conf.CommonTLSOptions.CAFile,
+				CertFile:           conf.CommonTLSOptions.CertFile,
+				KeyFile:            conf.CommonTLSOptions.KeyFile,
+				ExclusiveRootPools: true,
+				ClientAuth:         clientAuthTypes[s.ClientAuth],
+			})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to load the TLS configuration of %s", host)
+			}
+			u.tlsConfig[host] = c
+		}
+		if l, ok := a.listeners[host]; ok && sameListenerSettings(l.settings, s) {
+			u.kept[host] = true
+			continue
+		}
+		if protoAddrParts[0] == "fd" && len(a.listeners) > 0 {
+			return nil, fmt.Errorf("the socket activated listener %s cannot be changed without restarting the daemon", host)
+		}
+		u.opened = append(u.opened, &apiListener{proto: protoAddrParts[0], addr: protoAddrParts[1], settings: s})
+	}
+	for host, l := range a.listeners {
+		if !u.kept[host] && l.proto == "fd" {
+			return nil, fmt.Errorf("the socket activated listener %s cannot be changed without restarting the daemon", host)
+		}
+	}
+
+	defer func() {
+		if retErr != nil {
+			u.unbind()
+		}
+	}()
+	for _, l := range u.opened {
+		host := l.proto + "://" + l.addr
+		if old, ok := a.listeners[host]; ok {
+			// The address is in use by the listener with the previous
+			// settings, which is closed to bind the new one.
+			a.close(host, old)
+			u.replaced = append(u.replaced, old)
+		}
+		ls, err := a.bind(l, u.tlsConfig[host])
+		if err != nil {
+			return nil, err
+		}
+		u.bound = append(u.bound, ls)
+	}
+	return u, nil
+}
+
+// commit serves the API on the new listeners, closes the listeners which are
+// removed, and reloads the TLS certificates of the listeners which are kept.
+// The new listeners are served before the removed ones are closed, so that
+// the API stays reachable while the hosts are swapped.
+func (u *listenerUpdate) commit() {
+	a := u.a
+	defer a.mu.Unlock()
+
+	listeners := make(map[string]*apiListener, len(u.kept)+len(u.opened))
+	for i, l := range u.opened {
+		a.serve(l, u.bound[i])
+		listeners[l.proto+"://"+l.addr] = l
+	}
+	for host, l := range a.listeners {
+		if u.kept[host] {
+			l.setTLSConfig(u.tlsConfig[host])
+			listeners[host] = l
+			continue
+		}
+		if !u.isReplaced(l) {
+			a.close(host, l)
+		}
+	}
+	a.listeners = listeners
+	a.conf = u.conf
+}
+
+// abort closes the new listeners, and opens again the listeners which were
+// closed to bind them.
+func (u *listenerUpdate) abort() {
+	defer u.a.mu.Unlock()
+	u.unbind()
+}
+
+func (u *listenerUpdate) unbind() {
+	a := u.a
+	for i, ls := range u.bound {
+		for _, ln := range ls {
+			ln.Close()
+		}
+		if u.opened[i].proto == "tcp" {
+			releaseDaemonPort(u.opened[i].addr)
+		}
+	}
+	u.bound = nil
+	for _, l := range u.replaced {
+		host := l.proto + "://" + l.addr
+		l.mu.RLock()
+		tlsConfig := l.tlsConfig
+		l.mu.RUnlock()
+		ls, err := a.bind(l, tlsConfig)
+		if err != nil {
+			logrus.WithError(err).Errorf("Error opening the API listener on %s again", host)
+			delete(a.listeners, host)
+			continue
+		}
+		a.serve(l, ls)
+	}
+	u.replaced = nil
+}
+
+func (u *listenerUpdate) isReplaced(l *apiListener) bool {
+	for _, r := range u.replaced {
+		if r == l {
+			return true
+		}
+	}
+	return false
+}
+
+// bind opens the network listeners of a listener of the API.
+func (a *apiListeners) bind(l *apiListener, tlsConfig *tls.Config) ([]net.Listener, error) {
+	// It's a bad idea to bind to TCP without tlsverify.
+	if l.proto == "tcp" && l.settings.ClientAuth != config.ClientAuthRequireAndVerify {
+		logrus.Warn("[!] DON'T BIND ON ANY IP ADDRESS WITHOUT setting --tlsverify IF YOU DON'T KNOW WHAT YOU'RE DOING [!]")
+	}
+	var listenerTLSConfig *tls.Config
+	if tlsConfig != nil {
+		l.setTLSConfig(tlsConfig)
+		listenerTLSConfig = &tls.Config{GetConfigForClient: l.getConfigForClient}
+	}
+	ls, err := listeners.Init(l.proto, l.addr, a.socketGroup, listenerTLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	ls = wrapListeners(l.proto, ls)
+	// If we're binding to a TCP port, make sure that a container doesn't try to use it.
+	if l.proto == "tcp" {
+		if err := allocateDaemonPort(l.addr); err != nil {
+			for _, ln := range ls {
+				ln.Close()
+			}
+			return nil, err
+		}
+	}
+	return ls, nil
+}
+
+// serve serves the API on the network listeners bound for a listener.
+func (a *apiListeners) serve(l *apiListener, ls []net.Listener) {
+	logrus.Debugf("Listener created for HTTP on %s (%s)", l.proto, l.addr)
+	a.api.AcceptWithConfig(l.addr, apiserver.ListenerConfig{
+		MinAPIVersion: l.settings.MinAPIVersion,
+		MaxAPIVersion: l.settings.MaxAPIVersion,
+	}, ls...)
+}
+
+// close stops serving the API on a listener, and closes it.
+func (a *apiListeners) close(host string, l *apiListener) {
+	logrus.Infof("Closing the API listener on %s", host)
+	if err := a.api.CloseListeners(l.addr); err != nil {
+		logrus.WithError(err).Warnf("Error closing the API listener on %s", host)
+	}
+	if l.proto == "tcp" {
+		releaseDaemonPort(l.addr)
+	}
+}
+
+// reload prepares the update of the listeners of the API with the hosts, the
+// TLS settings and the listener settings set in the reloaded configuration
+// conf, keeping the current settings for the options which are not set. The
+// hosts and the listener settings of conf are normalized for the daemon to
+// reload them. The update must be committed once the daemon is reloaded, or
+// aborted.
+func (a *apiListeners) reload(conf *config.Config) (*listenerUpdate, error) {
+	a.mu.Lock()
+	current := a.conf
+	a.mu.Unlock()
+
+	c := &config.Config{}
+	c.Hosts = current.Hosts
+	c.TLS = current.TLS
+	c.TLSVerify = current.TLSVerify
+	c.CommonTLSOptions = current.CommonTLSOptions
+	c.Listeners = current.Listeners
+
+	if conf.IsValueSet("hosts") {
+		c.Hosts = conf.Hosts
+	}
+	if conf.IsValueSet("tls") {
+		c.TLS = conf.TLS
+	}
+	if conf.IsValueSet(FlagTLSVerify) {
+		// As on startup, setting tlsverify at all turns on TLS.
+		c.TLS = true
+		c.TLSVerify = conf.TLSVerify
+	}
+	if conf.IsValueSet("tlscacert") {
+		c.CAFile = conf.CAFile
+	}
+	if conf.IsValueSet("tlscert") {
+		c.CertFile = conf.CertFile
+	}
+	if conf.IsValueSet("tlskey") {
+		c.KeyFile = conf.KeyFile
+	}
+	if conf.IsValueSet("listeners") {
+		c.Listeners = conf.Listeners
+	}
+
+	u, err := a.prepare(c)
+	if err != nil {
+		return nil, err
+	}
+	conf.Hosts, conf.Listeners = c.Hosts, c.Listeners
+	return u, nil
+}
+
+// sameListenerSettings returns whether a listener opened with the settings
+// a can be kept for the settings b, only reloading its certificates.
+func sameListenerSettings(a, b config.ListenerConfig) bool {
+	return *a.TLS == *b.TLS &&
+		a.ClientAuth == b.ClientAuth &&
+		a.MinAPIVersion == b.MinAPIVersion &&
+		a.MaxAPIVersion == b.MaxAPIVersion
+}
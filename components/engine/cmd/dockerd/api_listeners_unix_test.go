@@ -0,0 +1,108 @@
+// +build !windows
+
+package main
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apiserver "github.com/docker/docker/api/server"
+	"github.com/docker/docker/daemon/config"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestAPIListenersReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "api-listeners")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "file"), nil, 0600))
+
+	a := newAPIListeners(apiserver.New(&apiserver.Config{}), "")
+	hostA := "unix://" + filepath.Join(dir, "a.sock")
+	hostB := "unix://" + filepath.Join(dir, "b.sock")
+	hostsConfig := func(hosts ...string) *config.Config {
+		conf := &config.Config{ValuesSet: map[string]interface{}{"hosts": hosts}}
+		conf.Hosts = hosts
+		return conf
+	}
+	assertListeners := func(hosts ...string) {
+		t.Helper()
+		assert.Check(t, is.Len(a.listeners, len(hosts)))
+		for _, h := range hosts {
+			assert.Check(t, a.listeners[h] != nil, "no listener on %s", h)
+		}
+	}
+
+	_, err = a.update(hostsConfig(hostA))
+	assert.NilError(t, err)
+	assertListeners(hostA)
+
+	// A listener which cannot be bound leaves the listeners untouched
+	_, err = a.reload(hostsConfig(hostB, "unix://"+filepath.Join(dir, "file", "c.sock")))
+	assert.Check(t, err != nil)
+	assertListeners(hostA)
+	_, err = os.Stat(filepath.Join(dir, "a.sock"))
+	assert.Check(t, err)
+	_, err = os.Stat(filepath.Join(dir, "b.sock"))
+	assert.Check(t, os.IsNotExist(err))
+
+	// An aborted update closes the new listeners
+	u, err := a.reload(hostsConfig(hostB))
+	assert.NilError(t, err)
+	u.abort()
+	assertListeners(hostA)
+	_, err = os.Stat(filepath.Join(dir, "b.sock"))
+	assert.Check(t, os.IsNotExist(err))
+
+	// A committed update closes the removed listeners
+	u, err = a.reload(hostsConfig(hostB))
+	assert.NilError(t, err)
+	u.commit()
+	assertListeners(hostB)
+	_, err = os.Stat(filepath.Join(dir, "a.sock"))
+	assert.Check(t, os.IsNotExist(err))
+}
+
+func TestAPIListenersReloadOnlyListener(t *testing.T) {
+	dir, err := ioutil.TempDir("", "api-listeners")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	api := apiserver.New(&apiserver.Config{})
+	api.InitRouter()
+	a := newAPIListeners(api, "")
+	host := "unix://" + filepath.Join(dir, "a.sock")
+	conf := &config.Config{}
+	conf.Hosts = []string{host}
+	_, err = a.update(conf)
+	assert.NilError(t, err)
+
+	waitChan := make(chan error)
+	go api.Wait(waitChan)
+	defer func() {
+		api.Close()
+		<-waitChan
+	}()
+
+	// Changing the settings of the only host replaces its listener.
+	conf = &config.Config{ValuesSet: map[string]interface{}{"listeners": nil}}
+	conf.Listeners = map[string]config.ListenerConfig{host: {MaxAPIVersion: "1.30"}}
+	u, err := a.reload(conf)
+	assert.NilError(t, err)
+	u.commit()
+	assert.Check(t, is.Equal(a.listeners[host].settings.MaxAPIVersion, "1.30"))
+
+	select {
+	case err := <-waitChan:
+		t.Fatalf("the API stopped after replacing its only listener: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	c, err := net.Dial("unix", filepath.Join(dir, "a.sock"))
+	assert.NilError(t, err)
+	c.Close()
+}
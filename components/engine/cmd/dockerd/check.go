@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/daemon"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/pkg/pidfile"
+	"github.com/pkg/errors"
+)
+
+// runOfflineCheck checks the data of the daemon while it is stopped. The PID
+// file is held during the check, so that the daemon cannot be started.
+func runOfflineCheck(conf *config.Config, verifyLayers bool, out io.Writer) error {
+	if conf.Pidfile != "" {
+		pf, err := pidfile.New(conf.Pidfile)
+		if err != nil {
+			return errors.Wrap(err, "failed to check daemon data")
+		}
+		defer pf.Remove()
+	}
+	report, err := daemon.CheckOffline(context.Background(), conf, verifyLayers)
+	if err != nil {
+		return errors.Wrap(err, "failed to check daemon data")
+	}
+	return printCheckReport(out, report)
+}
+
+// printCheckReport prints the problems found by a check, and returns an error
+// if there are any.
+func printCheckReport(out io.Writer, report *types.SystemCheckReport) error {
+	if len(report.Problems) == 0 {
+		fmt.Fprintln(out, "No problems found")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 20, 1, 3, ' ', 0)
+	fmt.Fprintln(w, "KIND\tID\tMESSAGE\tREPAIR")
+	for _, p := range report.Problems {
+		repair := p.Repair
+		if repair == "" {
+			repair = "none"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Kind, p.ID, p.Message, repair)
+	}
+	w.Flush()
+	return errors.Errorf("%d problem(s) found; start the daemon and run `docker system check --repair` to repair them", len(report.Problems))
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestPrintCheckReport(t *testing.T) {
+	var out bytes.Buffer
+	assert.NilError(t, printCheckReport(&out, &types.SystemCheckReport{}))
+	assert.Check(t, is.Equal(out.String(), "No problems found\n"))
+
+	out.Reset()
+	err := printCheckReport(&out, &types.SystemCheckReport{Problems: []types.SystemCheckProblem{
+		{Kind: "corrupted-container", ID: "abc", Message: "container could not be loaded", Repair: "remove container directory"},
+		{Kind: "missing-volume-data", ID: "data", Message: "volume data directory does not exist"},
+	}})
+	assert.Check(t, is.ErrorContains(err, "2 problem(s) found"))
+	assert.Check(t, is.Contains(out.String(), "remove container directory"))
+	assert.Check(t, is.Contains(out.String(), "none"))
+}
@@ -50,6 +50,8 @@ func installConfigFlags(conf *config.Config, flags *pflag.FlagSet) error {
 	flags.StringVar(&conf.BridgeConfig.UserlandProxyPath, "userland-proxy-path", defaultUserlandProxyPath, "Path to the userland proxy binary")
 	flags.StringVar(&conf.CgroupParent, "cgroup-parent", "", "Set parent cgroup for all containers")
 	flags.StringVar(&conf.RemappedRoot, "userns-remap", "", "User/Group setting for user namespaces")
+	flags.StringVar(&conf.UsernsAutoPool, "userns-auto-pool", "", "User/Group whose subordinate ID ranges are allocated to containers with --userns=auto (default \"dockremap\")")
+	flags.IntVar(&conf.UsernsAutoSize, "userns-auto-size", config.DefaultUsernsAutoSize, "Number of IDs mapped in the user namespace of containers with --userns=auto")
 	flags.BoolVar(&conf.LiveRestoreEnabled, "live-restore", false, "Enable live restore of docker when containers are still running")
 	flags.IntVar(&conf.OOMScoreAdjust, "oom-score-adjust", -500, "Set the oom_score_adj for the daemon")
 	flags.BoolVar(&conf.Init, "init", false, "Run an init in the container to forward signals and reap processes")
@@ -2,12 +2,11 @@ package main
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
-	"strings"
 	"time"
 
 	containerddefaults "github.com/containerd/containerd/defaults"
@@ -34,12 +33,11 @@ import (
 	"github.com/docker/docker/builder/fscache"
 	"github.com/docker/docker/cli/debug"
 	"github.com/docker/docker/daemon"
+	"github.com/docker/docker/daemon/auditlog"
 	"github.com/docker/docker/daemon/cluster"
 	"github.com/docker/docker/daemon/config"
-	"github.com/docker/docker/daemon/listeners"
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/libcontainerd/supervisor"
-	dopts "github.com/docker/docker/opts"
 	"github.com/docker/docker/pkg/authorization"
 	"github.com/docker/docker/pkg/homedir"
 	"github.com/docker/docker/pkg/jsonmessage"
@@ -50,7 +48,6 @@ import (
 	"github.com/docker/docker/plugin"
 	"github.com/docker/docker/rootless"
 	"github.com/docker/docker/runconfig"
-	"github.com/docker/go-connections/tlsconfig"
 	swarmapi "github.com/docker/swarmkit/api"
 	"github.com/moby/buildkit/session"
 	"github.com/pkg/errors"
@@ -67,6 +64,8 @@ type DaemonCli struct {
 	api             *apiserver.Server
 	d               *daemon.Daemon
 	authzMiddleware *authorization.Middleware // authzMiddleware enables to dynamically reload the authorization plugins
+	auditLog        io.WriteCloser            // auditLog is the audit log of the API requests, if it is enabled
+	apiListeners    *apiListeners             // apiListeners enables to dynamically reload the hosts and the TLS settings of the API
 }
 
 // NewDaemonCli returns a daemon CLI
@@ -87,6 +86,18 @@ func (cli *DaemonCli) start(opts *daemonOptions) (err error) {
 		return err
 	}
 
+	if opts.Validate {
+		if err := daemon.ValidateConfig(cli.Config); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "configuration OK")
+		return nil
+	}
+
+	if opts.Check {
+		return runOfflineCheck(cli.Config, opts.VerifyLayers, os.Stdout)
+	}
+
 	if err := configureDaemonLogs(cli.Config); err != nil {
 		return err
 	}
@@ -202,6 +213,7 @@ func (cli *DaemonCli) start(opts *daemonOptions) (err error) {
 	}
 
 	d.StoreHosts(hosts)
+	cli.authzMiddleware.SetLabelGetter(d)
 
 	// validate after NewDaemon has restored enabled plugins. Don't change order.
 	if err := validateAuthzPlugins(cli.Config.AuthorizationPlugins, pluginStore); err != nil {
@@ -261,6 +273,12 @@ func (cli *DaemonCli) start(opts *daemonOptions) (err error) {
 
 	shutdownDaemon(d)
 
+	if cli.auditLog != nil {
+		if err := cli.auditLog.Close(); err != nil {
+			logrus.WithError(err).Warn("Error closing the audit log")
+		}
+	}
+
 	// Stop notification processing and any background processes
 	cancel()
 
@@ -360,10 +378,21 @@ func (cli *DaemonCli) reloadConfig() {
 			logrus.Warnf("Configured labels using reserved namespaces is deprecated: %s", err)
 		}
 
+		// Load the TLS certificates and bind the new API listeners, which
+		// are only served, and the removed listeners closed, once the daemon
+		// is reloaded.
+		listenerUpdate, err := cli.apiListeners.reload(c)
+		if err != nil {
+			logrus.Errorf("Error reconfiguring the API listeners: %v", err)
+			return
+		}
+
 		if err := cli.d.Reload(c); err != nil {
+			listenerUpdate.abort()
 			logrus.Errorf("Error reconfiguring the daemon: %v", err)
 			return
 		}
+		listenerUpdate.commit()
 
 		if c.IsValueSet("debug") {
 			debugEnabled := debug.IsEnabled()
@@ -538,8 +567,26 @@ func (cli *DaemonCli) initMiddlewares(s *apiserver.Server, cfg *apiserver.Config
 	}
 
 	cli.authzMiddleware = authorization.NewMiddleware(cli.Config.AuthorizationPlugins, pluginStore)
+	if cli.Config.AuthorizationPolicy != "" {
+		policy, err := authorization.LoadPolicy(cli.Config.AuthorizationPolicy)
+		if err != nil {
+			return err
+		}
+		cli.authzMiddleware.SetPolicy(policy)
+	}
 	cli.Config.AuthzMiddleware = cli.authzMiddleware
 	s.UseMiddleware(cli.authzMiddleware)
+
+	// The audit middleware is added last, so that it is evaluated first and
+	// records the requests denied by the authorization middleware.
+	if cli.Config.AuditLog != nil {
+		w, err := auditlog.New(cli.Config.AuditLog)
+		if err != nil {
+			return err
+		}
+		cli.auditLog = w
+		s.UseMiddleware(middleware.NewAuditMiddleware(w))
+	}
 	return nil
 }
 
@@ -570,25 +617,6 @@ func newAPIServerConfig(cli *DaemonCli) (*apiserver.Config, error) {
 		CorsHeaders: cli.Config.CorsHeaders,
 	}
 
-	if cli.Config.TLS {
-		tlsOptions := tlsconfig.Options{
-			CAFile:             cli.Config.CommonTLSOptions.CAFile,
-			CertFile:           cli.Config.CommonTLSOptions.CertFile,
-			KeyFile:            cli.Config.CommonTLSOptions.KeyFile,
-			ExclusiveRootPools: true,
-		}
-
-		if cli.Config.TLSVerify {
-			// server requires and verifies client's certificate
-			tlsOptions.ClientAuth = tls.RequireAndVerifyClientCert
-		}
-		tlsConfig, err := tlsconfig.Server(tlsOptions)
-		if err != nil {
-			return nil, err
-		}
-		serverConfig.TLSConfig = tlsConfig
-	}
-
 	if len(cli.Config.Hosts) == 0 {
 		cli.Config.Hosts = make([]string, 1)
 	}
@@ -597,43 +625,8 @@ func newAPIServerConfig(cli *DaemonCli) (*apiserver.Config, error) {
 }
 
 func loadListeners(cli *DaemonCli, serverConfig *apiserver.Config) ([]string, error) {
-	var hosts []string
-	for i := 0; i < len(cli.Config.Hosts); i++ {
-		var err error
-		if cli.Config.Hosts[i], err = dopts.ParseHost(cli.Config.TLS, honorXDG, cli.Config.Hosts[i]); err != nil {
-			return nil, errors.Wrapf(err, "error parsing -H %s", cli.Config.Hosts[i])
-		}
-
-		protoAddr := cli.Config.Hosts[i]
-		protoAddrParts := strings.SplitN(protoAddr, "://", 2)
-		if len(protoAddrParts) != 2 {
-			return nil, fmt.Errorf("bad format %s, expected PROTO://ADDR", protoAddr)
-		}
-
-		proto := protoAddrParts[0]
-		addr := protoAddrParts[1]
-
-		// It's a bad idea to bind to TCP without tlsverify.
-		if proto == "tcp" && (serverConfig.TLSConfig == nil || serverConfig.TLSConfig.ClientAuth != tls.RequireAndVerifyClientCert) {
-			logrus.Warn("[!] DON'T BIND ON ANY IP ADDRESS WITHOUT setting --tlsverify IF YOU DON'T KNOW WHAT YOU'RE DOING [!]")
-		}
-		ls, err := listeners.Init(proto, addr, serverConfig.SocketGroup, serverConfig.TLSConfig)
-		if err != nil {
-			return nil, err
-		}
-		ls = wrapListeners(proto, ls)
-		// If we're binding to a TCP port, make sure that a container doesn't try to use it.
-		if proto == "tcp" {
-			if err := allocateDaemonPort(addr); err != nil {
-				return nil, err
-			}
-		}
-		logrus.Debugf("Listener created for HTTP on %s (%s)", proto, addr)
-		hosts = append(hosts, protoAddrParts[1])
-		cli.api.Accept(addr, ls...)
-	}
-
-	return hosts, nil
+	cli.apiListeners = newAPIListeners(cli.api, serverConfig.SocketGroup)
+	return cli.apiListeners.update(cli.Config)
 }
 
 func createAndStartCluster(cli *DaemonCli, d *daemon.Daemon) (*cluster.Cluster, error) {
@@ -651,6 +644,7 @@ func createAndStartCluster(cli *DaemonCli, d *daemon.Daemon) (*cluster.Cluster,
 		ImageBackend:           d.ImageService(),
 		PluginBackend:          d.PluginManager(),
 		NetworkSubnetsProvider: d,
+		SecretStore:            d.SecretStore(),
 		DefaultAdvertiseAddr:   cli.Config.SwarmDefaultAdvertiseAddr,
 		RaftHeartbeatTick:      cli.Config.SwarmRaftHeartbeatTick,
 		RaftElectionTick:       cli.Config.SwarmRaftElectionTick,
@@ -118,6 +118,33 @@ func allocateDaemonPort(addr string) error {
 	return nil
 }
 
+// releaseDaemonPort releases the port allocated by allocateDaemonPort, once
+// the daemon no longer listens on addr.
+func releaseDaemonPort(addr string) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return
+	}
+	intPort, err := strconv.Atoi(port)
+	if err != nil {
+		return
+	}
+
+	var hostIPs []net.IP
+	if parsedIP := net.ParseIP(host); parsedIP != nil {
+		hostIPs = append(hostIPs, parsedIP)
+	} else if hostIPs, err = net.LookupIP(host); err != nil {
+		return
+	}
+
+	pa := portallocator.Get()
+	for _, hostIP := range hostIPs {
+		if err := pa.ReleasePort(hostIP, "tcp", intPort); err != nil {
+			logrus.WithError(err).Warnf("Failed to release daemon listening port %d", intPort)
+		}
+	}
+}
+
 func wrapListeners(proto string, ls []net.Listener) []net.Listener {
 	switch proto {
 	case "unix":
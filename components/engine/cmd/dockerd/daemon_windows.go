@@ -81,6 +81,8 @@ func allocateDaemonPort(addr string) error {
 	return nil
 }
 
+func releaseDaemonPort(addr string) {}
+
 func wrapListeners(proto string, ls []net.Listener) []net.Listener {
 	return ls
 }
@@ -45,6 +45,9 @@ func newDaemonCommand() (*cobra.Command, error) {
 		return nil, err
 	}
 	flags.StringVar(&opts.configFile, "config-file", defaultDaemonConfigFile, "Daemon configuration file")
+	flags.BoolVar(&opts.Validate, "validate", false, "Validate daemon configuration and exit")
+	flags.BoolVar(&opts.Check, "check", false, "Check the consistency of the data of the stopped daemon and exit")
+	flags.BoolVar(&opts.VerifyLayers, "check-verify-layers", false, "Verify the content of all layers with --check")
 	opts.InstallFlags(flags)
 	if err := installConfigFlags(opts.daemonConfig, flags); err != nil {
 		return nil, err
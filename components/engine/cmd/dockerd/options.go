@@ -37,6 +37,9 @@ type daemonOptions struct {
 	TLS          bool
 	TLSVerify    bool
 	TLSOptions   *tlsconfig.Options
+	Validate     bool
+	Check        bool
+	VerifyLayers bool
 }
 
 // newDaemonOptions returns a new daemonFlags
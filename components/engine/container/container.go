@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/containerd/containerd/cio"
+	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
 	mounttypes "github.com/docker/docker/api/types/mount"
 	swarmtypes "github.com/docker/docker/api/types/swarm"
@@ -88,6 +89,10 @@ type Container struct {
 	DependencyStore        agentexec.DependencyGetter `json:"-"`
 	SecretReferences       []*swarmtypes.SecretReference
 	ConfigReferences       []*swarmtypes.ConfigReference
+	// ExecHistory records the last execs which ran in the container
+	ExecHistory []*types.ExecHistoryEntry `json:",omitempty"`
+	// DebugTarget is the ID of the container debugged by a debug container
+	DebugTarget string `json:",omitempty"`
 	// logDriver for closing
 	LogDriver      logger.Logger  `json:"-"`
 	LogCopier      *logger.Copier `json:"-"`
@@ -102,6 +107,10 @@ type Container struct {
 	ResolvConfPath  string
 	SeccompProfile  string
 	NoNewPrivileges bool
+	// UIDMaps and GIDMaps are the user namespace mappings of the container
+	// when it has its own range of subordinate IDs, with --userns=auto.
+	UIDMaps []idtools.IDMap `json:",omitempty"`
+	GIDMaps []idtools.IDMap `json:",omitempty"`
 
 	// Fields here are specific to Windows
 	NetworkSharedContainerID string   `json:"-"`
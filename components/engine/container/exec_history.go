@@ -0,0 +1,65 @@
+package container // import "github.com/docker/docker/container"
+
+import (
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// maxExecHistoryEntries is the maximum number of execs recorded in the exec
+// history of a container. The oldest entries are dropped first.
+const maxExecHistoryEntries = 100
+
+// GetExecHistory returns a copy of the exec history of the container. The
+// caller must hold the container lock.
+func (container *Container) GetExecHistory() []*types.ExecHistoryEntry {
+	if len(container.ExecHistory) == 0 {
+		return nil
+	}
+	history := make([]*types.ExecHistoryEntry, 0, len(container.ExecHistory))
+	for _, entry := range container.ExecHistory {
+		e := *entry
+		history = append(history, &e)
+	}
+	return history
+}
+
+// AddExecHistoryEntry records an exec in the exec history of the container.
+// The caller must hold the container lock.
+func (container *Container) AddExecHistoryEntry(entry *types.ExecHistoryEntry) {
+	container.ExecHistory = append(container.ExecHistory, entry)
+	if l := len(container.ExecHistory); l > maxExecHistoryEntries {
+		container.ExecHistory = append([]*types.ExecHistoryEntry(nil), container.ExecHistory[l-maxExecHistoryEntries:]...)
+	}
+}
+
+// EndExecHistoryEntry records the end time and the exit code of an exec in the
+// exec history of the container. It returns false if the exec is not in the
+// history, or its exit code is already recorded. The caller must hold the
+// container lock.
+func (container *Container) EndExecHistoryEntry(id string, exitCode int, end time.Time) bool {
+	for i := len(container.ExecHistory) - 1; i >= 0; i-- {
+		entry := container.ExecHistory[i]
+		if entry.ID != id {
+			continue
+		}
+		if entry.ExitCode != nil {
+			return false
+		}
+		entry.End = end
+		entry.ExitCode = &exitCode
+		return true
+	}
+	return false
+}
+
+// EndRunningExecHistoryEntries records the end time of the execs of the exec
+// history which are still running, without exit code, when the container
+// stops. The caller must hold the container lock.
+func (container *Container) EndRunningExecHistoryEntries(end time.Time) {
+	for _, entry := range container.ExecHistory {
+		if entry.ExitCode == nil && entry.End.IsZero() {
+			entry.End = end
+		}
+	}
+}
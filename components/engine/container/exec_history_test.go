@@ -0,0 +1,37 @@
+package container // import "github.com/docker/docker/container"
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestExecHistory(t *testing.T) {
+	c := &Container{}
+	for i := 0; i < maxExecHistoryEntries+2; i++ {
+		c.AddExecHistoryEntry(&types.ExecHistoryEntry{ID: strconv.Itoa(i)})
+	}
+	assert.Assert(t, is.Len(c.ExecHistory, maxExecHistoryEntries))
+	assert.Check(t, is.Equal("2", c.ExecHistory[0].ID))
+
+	end := time.Now()
+	assert.Check(t, c.EndExecHistoryEntry("3", 1, end))
+	assert.Check(t, is.Equal(1, *c.ExecHistory[1].ExitCode))
+	assert.Check(t, c.ExecHistory[1].End.Equal(end))
+	assert.Check(t, !c.EndExecHistoryEntry("3", 0, end), "exit code is recorded once")
+	assert.Check(t, !c.EndExecHistoryEntry("0", 0, end), "dropped entry")
+
+	stopped := end.Add(time.Second)
+	c.EndRunningExecHistoryEntries(stopped)
+	assert.Check(t, c.ExecHistory[0].End.Equal(stopped))
+	assert.Check(t, c.ExecHistory[1].End.Equal(end))
+	assert.Check(t, is.Nil(c.ExecHistory[0].ExitCode))
+
+	history := c.GetExecHistory()
+	history[0].ID = "changed"
+	assert.Check(t, is.Equal("2", c.ExecHistory[0].ID))
+}
@@ -0,0 +1,119 @@
+package container // import "github.com/docker/docker/container"
+
+import (
+	"os"
+	"sort"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/containerfs"
+	"github.com/docker/docker/pkg/system"
+	"github.com/pkg/errors"
+)
+
+// ListPath lists the files at the path in the container given by opts. Locks
+// and mounts should be acquired before calling this method.
+func (container *Container) ListPath(opts types.ListFilesOptions) ([]types.FileEntry, error) {
+	if container.BaseFS == nil {
+		return nil, errors.New("ListPath: BaseFS of container " + container.ID + " is unexpectedly nil")
+	}
+	return ListFiles(container.BaseFS, opts)
+}
+
+// ListFiles lists the files at the path of a root filesystem given by opts.
+// If the path is a directory, its content is listed, and that of its subdirectories
+// if opts.Recursive is set. Otherwise, only the file itself is returned.
+// Symbolic links in the path are evaluated in the scope of the root
+// filesystem, except for the last element of the path.
+func ListFiles(fs containerfs.ContainerFS, opts types.ListFilesOptions) ([]types.FileEntry, error) {
+	// Check if a drive letter supplied, it must be the system drive. No-op except on Windows
+	path, err := system.CheckSystemDriveAndRemoveDriveLetter(fs.FromSlash(opts.Path), fs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Consider the given path as an absolute path in the root filesystem,
+	// and resolve its directory in the scope of the root filesystem.
+	absPath := fs.Join(string(fs.Separator()), path)
+	dirPath, basePath := fs.Split(absPath)
+	resolvedDirPath, err := fs.ResolveScopedPath(dirPath, false)
+	if err != nil {
+		return nil, err
+	}
+	resolvedPath := resolvedDirPath + string(fs.Separator()) + basePath
+
+	lstat, err := fs.Lstat(resolvedPath)
+	if err != nil {
+		return nil, err
+	}
+	if !lstat.IsDir() {
+		entry, err := newFileEntry(fs, resolvedPath, absPath, lstat)
+		if err != nil {
+			return nil, err
+		}
+		return []types.FileEntry{entry}, nil
+	}
+
+	entries := []types.FileEntry{}
+	if err := listDir(fs, resolvedPath, absPath, opts, 1, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// listDir appends the content of a directory to entries, sorted by name,
+// each subdirectory being followed by its own content if listed.
+func listDir(fs containerfs.ContainerFS, resolvedPath, absPath string, opts types.ListFilesOptions, depth int, entries *[]types.FileEntry) error {
+	f, err := fs.Open(resolvedPath)
+	if err != nil {
+		return err
+	}
+	infos, err := f.Readdir(-1)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+
+	for _, fi := range infos {
+		childResolvedPath := fs.Join(resolvedPath, fi.Name())
+		childAbsPath := fs.Join(absPath, fi.Name())
+		entry, err := newFileEntry(fs, childResolvedPath, childAbsPath, fi)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The file was removed while listing the directory.
+				continue
+			}
+			return err
+		}
+		*entries = append(*entries, entry)
+
+		if fi.IsDir() && opts.Recursive && (opts.Depth <= 0 || depth < opts.Depth) {
+			err := listDir(fs, childResolvedPath, childAbsPath, opts, depth+1, entries)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newFileEntry(fs containerfs.ContainerFS, resolvedPath, absPath string, fi os.FileInfo) (types.FileEntry, error) {
+	var linkTarget string
+	if fi.Mode()&os.ModeSymlink != 0 {
+		var err error
+		if linkTarget, err = fs.Readlink(resolvedPath); err != nil {
+			return types.FileEntry{}, err
+		}
+	}
+	uid, gid := fileOwner(fi)
+	return types.FileEntry{
+		Name:       fs.Base(absPath),
+		Path:       absPath,
+		Size:       fi.Size(),
+		Mode:       fi.Mode(),
+		UID:        uid,
+		GID:        gid,
+		Mtime:      fi.ModTime(),
+		LinkTarget: linkTarget,
+	}, nil
+}
@@ -0,0 +1,64 @@
+// +build !windows
+
+package container // import "github.com/docker/docker/container"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/containerfs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func entryPaths(entries []types.FileEntry) []string {
+	var paths []string
+	for _, e := range entries {
+		paths = append(paths, e.Path)
+	}
+	return paths
+}
+
+func TestListFiles(t *testing.T) {
+	root, err := ioutil.TempDir("", "list-files-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	assert.NilError(t, os.MkdirAll(filepath.Join(root, "etc", "conf.d", "extra"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(root, "etc", "passwd"), []byte("root\n"), 0644))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(root, "etc", "conf.d", "a.conf"), []byte("a"), 0600))
+	assert.NilError(t, os.Symlink("/etc", filepath.Join(root, "link")))
+	assert.NilError(t, os.Symlink("../../..", filepath.Join(root, "etc", "up")))
+	fs := containerfs.NewLocalContainerFS(root)
+
+	entries, err := ListFiles(fs, types.ListFilesOptions{})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(entryPaths(entries), []string{"/etc", "/link"}))
+	assert.Check(t, is.Equal(entries[1].LinkTarget, "/etc"))
+	assert.Check(t, entries[1].Mode&os.ModeSymlink != 0)
+
+	entries, err = ListFiles(fs, types.ListFilesOptions{Path: "/etc", Recursive: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(entryPaths(entries), []string{"/etc/conf.d", "/etc/conf.d/a.conf", "/etc/conf.d/extra", "/etc/passwd", "/etc/up"}))
+
+	entries, err = ListFiles(fs, types.ListFilesOptions{Path: "/etc", Recursive: true, Depth: 1})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(entryPaths(entries), []string{"/etc/conf.d", "/etc/passwd", "/etc/up"}))
+
+	// A file is listed by itself, and the directories of the path are
+	// resolved in the scope of the root filesystem.
+	entries, err = ListFiles(fs, types.ListFilesOptions{Path: "/link/up/etc/passwd"})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(entries, 1))
+	assert.Check(t, is.Equal(entries[0].Name, "passwd"))
+	assert.Check(t, is.Equal(entries[0].Path, "/link/up/etc/passwd"))
+	assert.Check(t, is.Equal(entries[0].Size, int64(5)))
+	assert.Check(t, is.Equal(entries[0].Mode, os.FileMode(0644)))
+	assert.Check(t, is.Equal(entries[0].UID, os.Getuid()))
+
+	_, err = ListFiles(fs, types.ListFilesOptions{Path: "/missing"})
+	assert.Check(t, os.IsNotExist(err))
+}
@@ -0,0 +1,16 @@
+// +build !windows
+
+package container // import "github.com/docker/docker/container"
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the owner of a file.
+func fileOwner(fi os.FileInfo) (uid, gid int) {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return int(st.Uid), int(st.Gid)
+	}
+	return 0, 0
+}
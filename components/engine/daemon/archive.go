@@ -93,6 +93,30 @@ func (daemon *Daemon) ContainerStatPath(name string, path string) (stat *types.C
 	return nil, errdefs.System(err)
 }
 
+// ContainerListFiles lists the files at the specified path in the container
+// identified by the given name.
+func (daemon *Daemon) ContainerListFiles(name string, opts types.ListFilesOptions) ([]types.FileEntry, error) {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure an online file-system operation is permitted.
+	if err := daemon.isOnlineFSOperationPermitted(container); err != nil {
+		return nil, errdefs.System(err)
+	}
+
+	entries, err := daemon.containerListFiles(container, opts)
+	if err == nil {
+		return entries, nil
+	}
+
+	if os.IsNotExist(err) {
+		return nil, containerFileNotFound{opts.Path, name}
+	}
+	return nil, errdefs.System(err)
+}
+
 // ContainerArchivePath creates an archive of the filesystem resource at the
 // specified path in the container identified by the given name. Returns a
 // tar archive of the resource and whether it was a directory or a single file.
@@ -174,6 +198,25 @@ func (daemon *Daemon) containerStatPath(container *container.Container, path str
 	return container.StatPath(resolvedPath, absPath)
 }
 
+// containerListFiles lists the files at the specified path in the container.
+func (daemon *Daemon) containerListFiles(container *container.Container, opts types.ListFilesOptions) ([]types.FileEntry, error) {
+	container.Lock()
+	defer container.Unlock()
+
+	if err := daemon.Mount(container); err != nil {
+		return nil, err
+	}
+	defer daemon.Unmount(container)
+
+	err := daemon.mountVolumes(container)
+	defer container.DetachAndUnmount(daemon.LogVolumeEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	return container.ListPath(opts)
+}
+
 // containerArchivePath creates an archive of the filesystem resource at the specified
 // path in this container. Returns a tar archive of the resource and stat info
 // about the resource.
@@ -355,7 +398,7 @@ func (daemon *Daemon) containerExtractToDir(container *container.Container, path
 		return ErrRootFSReadOnly
 	}
 
-	options := daemon.defaultTarCopyOptions(noOverwriteDirNonDir)
+	options := daemon.defaultTarCopyOptions(container, noOverwriteDirNonDir)
 
 	if copyUIDGID {
 		var err error
@@ -1,15 +1,17 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"github.com/docker/docker/container"
 	"github.com/docker/docker/pkg/archive"
 )
 
 // defaultTarCopyOptions is the setting that is used when unpacking an archive
 // for a copy API event.
-func (daemon *Daemon) defaultTarCopyOptions(noOverwriteDirNonDir bool) *archive.TarOptions {
+func (daemon *Daemon) defaultTarCopyOptions(container *container.Container, noOverwriteDirNonDir bool) *archive.TarOptions {
+	idMapping := daemon.containerIDMapping(container)
 	return &archive.TarOptions{
 		NoOverwriteDirNonDir: noOverwriteDirNonDir,
-		UIDMaps:              daemon.idMapping.UIDs(),
-		GIDMaps:              daemon.idMapping.GIDs(),
+		UIDMaps:              idMapping.UIDs(),
+		GIDMaps:              idMapping.GIDs(),
 	}
 }
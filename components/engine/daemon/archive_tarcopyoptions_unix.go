@@ -10,7 +10,7 @@ import (
 
 func (daemon *Daemon) tarCopyOptions(container *container.Container, noOverwriteDirNonDir bool) (*archive.TarOptions, error) {
 	if container.Config.User == "" {
-		return daemon.defaultTarCopyOptions(noOverwriteDirNonDir), nil
+		return daemon.defaultTarCopyOptions(container, noOverwriteDirNonDir), nil
 	}
 
 	user, err := idtools.LookupUser(container.Config.User)
@@ -19,6 +19,14 @@ func (daemon *Daemon) tarCopyOptions(container *container.Container, noOverwrite
 	}
 
 	identity := idtools.Identity{UID: user.Uid, GID: user.Gid}
+	if container.UIDMaps != nil {
+		// The files of containers with their own user namespace mapping
+		// are owned by the IDs of their mapping.
+		identity, err = daemon.containerIDMapping(container).ToHost(identity)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &archive.TarOptions{
 		NoOverwriteDirNonDir: noOverwriteDirNonDir,
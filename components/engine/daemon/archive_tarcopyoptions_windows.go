@@ -6,5 +6,5 @@ import (
 )
 
 func (daemon *Daemon) tarCopyOptions(container *container.Container, noOverwriteDirNonDir bool) (*archive.TarOptions, error) {
-	return daemon.defaultTarCopyOptions(noOverwriteDirNonDir), nil
+	return daemon.defaultTarCopyOptions(container, noOverwriteDirNonDir), nil
 }
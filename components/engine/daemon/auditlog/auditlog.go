@@ -0,0 +1,101 @@
+// Package auditlog writes the audit log of the API requests to a rotated file
+// or to syslog.
+package auditlog // import "github.com/docker/docker/daemon/auditlog"
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils"
+	"github.com/docker/docker/daemon/logger/syslog"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+const defaultSyslogTag = "dockerd-audit"
+
+// New returns the writer of the audit log configured in the daemon
+// configuration. Each call to Write writes one record.
+func New(cfg *config.AuditLogConfig) (io.WriteCloser, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	switch cfg.Driver {
+	case config.AuditLogDriverSyslog:
+		return newSyslogWriter(cfg)
+	default:
+		return newFileWriter(cfg)
+	}
+}
+
+// writer writes the records of the audit log as log messages, so that the
+// rotation of the log files and the syslog client of the log drivers are
+// reused.
+type writer struct {
+	log   func(*logger.Message) error
+	close func() error
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	msg := logger.NewMessage()
+	msg.Line = append(msg.Line, bytes.TrimRight(p, "\n")...)
+	msg.Timestamp = time.Now()
+	if err := w.log(msg); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *writer) Close() error {
+	return w.close()
+}
+
+func newFileWriter(cfg *config.AuditLogConfig) (io.WriteCloser, error) {
+	capacity := int64(-1)
+	if cfg.MaxSize != "" {
+		var err error
+		if capacity, err = units.FromHumanSize(cfg.MaxSize); err != nil {
+			return nil, err
+		}
+	}
+	maxFiles := cfg.MaxFile
+	if maxFiles < 1 {
+		maxFiles = 1
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create the directory of the audit log")
+	}
+	marshal := func(msg *logger.Message) ([]byte, error) {
+		// The message is reused once marshalled, so its line is copied.
+		b := make([]byte, 0, len(msg.Line)+1)
+		b = append(b, msg.Line...)
+		return append(b, '\n'), nil
+	}
+	f, err := loggerutils.NewLogFile(cfg.Path, capacity, maxFiles, cfg.Compress, marshal, nil, 0600, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the audit log")
+	}
+	return &writer{log: f.WriteLogEntry, close: f.Close}, nil
+}
+
+func newSyslogWriter(cfg *config.AuditLogConfig) (io.WriteCloser, error) {
+	tag := cfg.SyslogTag
+	if tag == "" {
+		tag = defaultSyslogTag
+	}
+	l, err := syslog.New(logger.Info{
+		Config: map[string]string{
+			"syslog-address": cfg.SyslogAddress,
+			"tag":            tag,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to syslog for the audit log")
+	}
+	return &writer{log: l.Log, close: l.Close}, nil
+}
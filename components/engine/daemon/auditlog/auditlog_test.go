@@ -0,0 +1,47 @@
+package auditlog // import "github.com/docker/docker/daemon/auditlog"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/daemon/config"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestFileWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auditlog")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "audit", "audit.log")
+	w, err := New(&config.AuditLogConfig{Path: path, MaxSize: "20b", MaxFile: 2})
+	assert.NilError(t, err)
+
+	_, err = w.Write([]byte(`{"method":"POST","endpoint":"/containers/create"}` + "\n"))
+	assert.NilError(t, err)
+	_, err = w.Write([]byte(`{"method":"DELETE","endpoint":"/containers/web"}`))
+	assert.NilError(t, err)
+	assert.NilError(t, w.Close())
+
+	b, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), `{"method":"DELETE","endpoint":"/containers/web"}`+"\n"))
+	b, err = ioutil.ReadFile(path + ".1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), `{"method":"POST","endpoint":"/containers/create"}`+"\n"))
+
+	fi, err := os.Stat(path)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0600)))
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := New(&config.AuditLogConfig{Path: "audit.log"})
+	assert.Check(t, is.ErrorContains(err, "must be absolute"))
+
+	_, err = New(&config.AuditLogConfig{Driver: "journald"})
+	assert.Check(t, is.ErrorContains(err, `unsupported driver "journald"`))
+}
@@ -0,0 +1,44 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"fmt"
+)
+
+// ResourceLabels returns the labels of a container, of the container of an
+// exec instance, of a network or of a volume, so that the authorization
+// policy can restrict the API requests to the resources with some labels.
+func (daemon *Daemon) ResourceLabels(kind, name string) (map[string]string, error) {
+	switch kind {
+	case "containers":
+		c, err := daemon.GetContainer(name)
+		if err != nil {
+			return nil, err
+		}
+		return c.Config.Labels, nil
+	case "exec":
+		ec := daemon.execCommands.Get(name)
+		if ec == nil {
+			return nil, errExecNotFound(name)
+		}
+		c, err := daemon.GetContainer(ec.ContainerID)
+		if err != nil {
+			return nil, err
+		}
+		return c.Config.Labels, nil
+	case "networks":
+		n, err := daemon.FindNetwork(name)
+		if err != nil {
+			return nil, err
+		}
+		return n.Info().Labels(), nil
+	case "volumes":
+		v, err := daemon.volumes.Get(context.TODO(), name)
+		if err != nil {
+			return nil, err
+		}
+		return v.Labels, nil
+	default:
+		return nil, fmt.Errorf("unsupported resource kind %q", kind)
+	}
+}
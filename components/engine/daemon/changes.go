@@ -1,10 +1,12 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"context"
 	"errors"
 	"runtime"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/archive"
 )
 
@@ -32,3 +34,39 @@ func (daemon *Daemon) ContainerChanges(name string) ([]archive.Change, error) {
 	containerActions.WithValues("changes").UpdateSince(start)
 	return c, nil
 }
+
+// ContainerDiff compares the filesystem of a container against that of an
+// image, which need not be the image of the container.
+func (daemon *Daemon) ContainerDiff(ctx context.Context, name, against string, opts types.DiffOptions) ([]types.FileChange, error) {
+	start := time.Now()
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if runtime.GOOS == "windows" && container.IsRunning() {
+		return nil, errors.New("Windows does not support diff of a running container")
+	}
+
+	// The container is only locked to take a reference to its RW layer, so
+	// that it is not locked while the layers are compared.
+	container.Lock()
+	if container.RWLayer == nil {
+		container.Unlock()
+		return nil, errors.New("RWLayer of container " + name + " is unexpectedly nil")
+	}
+	imageID := container.ImageID
+	rwlayer, err := daemon.imageService.GetLayerByID(container.ID, container.OS)
+	container.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	defer daemon.imageService.ReleaseLayer(rwlayer, container.OS)
+
+	c, err := daemon.imageService.DiffContainer(ctx, against, imageID, rwlayer, opts)
+	if err != nil {
+		return nil, err
+	}
+	containerActions.WithValues("changes").UpdateSince(start)
+	return c, nil
+}
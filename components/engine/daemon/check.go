@@ -0,0 +1,146 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// SystemCheck checks the consistency of the daemon's containers, images,
+// layers and volumes, and reports the problems it finds. If opts.Repair is
+// set, problems which can be repaired safely are repaired.
+func (daemon *Daemon) SystemCheck(ctx context.Context, opts types.SystemCheckOptions) (*types.SystemCheckReport, error) {
+	if !atomic.CompareAndSwapInt32(&daemon.checkRunning, 0, 1) {
+		return nil, errdefs.Conflict(errors.New("a system check is already running"))
+	}
+	defer atomic.StoreInt32(&daemon.checkRunning, 0)
+
+	rep := &types.SystemCheckReport{Problems: []types.SystemCheckProblem{}}
+	report := func(p types.SystemCheckProblem, repair func() error) {
+		if opts.Repair && repair != nil {
+			if err := repair(); err != nil {
+				logrus.WithError(err).WithField("kind", p.Kind).WithField("id", p.ID).Warn("failed to repair problem")
+				p.RepairError = err.Error()
+			} else {
+				p.Repaired = true
+			}
+		}
+		rep.Problems = append(rep.Problems, p)
+	}
+
+	// Containers are checked first, as removing a stale container leaves
+	// its filesystem unused, which is then removed by the layer check.
+	keepMounts, err := daemon.checkContainers(report)
+	if err != nil {
+		return nil, err
+	}
+	if err := daemon.imageService.CheckImages(ctx, opts.VerifyLayers, keepMounts, report); err != nil {
+		return nil, err
+	}
+	if err := daemon.checkVolumes(ctx, report); err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// checkContainers reports container directories which cannot be loaded and
+// leftover mounts of stopped containers. It returns the IDs of containers
+// which were not loaded, but whose filesystem must be kept.
+func (daemon *Daemon) checkContainers(report func(types.SystemCheckProblem, func() error)) (map[string]bool, error) {
+	dir, err := ioutil.ReadDir(daemon.repository)
+	if err != nil {
+		return nil, err
+	}
+
+	// Containers which are being created have a reserved name, but are not
+	// registered yet.
+	reserved := daemon.containersReplica.Snapshot().GetAllNames()
+
+	keepMounts := make(map[string]bool)
+	for _, v := range dir {
+		id := v.Name()
+		if daemon.containers.Get(id) != nil {
+			continue
+		}
+		if _, ok := reserved[id]; ok {
+			continue
+		}
+
+		root := daemon.containerRoot(id)
+		remove := func() error {
+			return system.EnsureRemoveAll(root)
+		}
+
+		c := daemon.newBaseContainer(id)
+		if err := c.FromDisk(); err != nil {
+			report(types.SystemCheckProblem{
+				Kind:    "corrupted-container",
+				ID:      id,
+				Message: fmt.Sprintf("container could not be loaded: %v", err),
+				Repair:  "remove container directory",
+			}, remove)
+			continue
+		}
+		if c.ID != id {
+			report(types.SystemCheckProblem{
+				Kind:    "corrupted-container",
+				ID:      id,
+				Message: fmt.Sprintf("container %s is stored at %s", c.ID, id),
+				Repair:  "remove container directory",
+			}, remove)
+			continue
+		}
+		if !system.IsOSSupported(c.OS) {
+			continue
+		}
+		// Containers created with another graph driver are not loaded
+		currentDriverForContainerOS := daemon.graphDrivers[c.OS]
+		if !((c.Driver == "" && currentDriverForContainerOS == "aufs") || c.Driver == currentDriverForContainerOS) {
+			continue
+		}
+		if _, err := daemon.imageService.GetLayerMountID(id, c.OS); err == layer.ErrMountDoesNotExist {
+			report(types.SystemCheckProblem{
+				Kind:    "container-missing-layer",
+				ID:      id,
+				Message: "container filesystem does not exist",
+				Repair:  "remove container directory",
+			}, remove)
+			continue
+		}
+		keepMounts[id] = true
+		report(types.SystemCheckProblem{
+			Kind:    "unloaded-container",
+			ID:      id,
+			Message: "container was not loaded by the daemon",
+		}, nil)
+	}
+
+	if err := daemon.checkContainerMounts(report); err != nil {
+		return nil, err
+	}
+	return keepMounts, nil
+}
+
+// checkVolumes reports local volumes whose data is missing.
+func (daemon *Daemon) checkVolumes(ctx context.Context, report func(types.SystemCheckProblem, func() error)) error {
+	missing, err := daemon.volumes.MissingLocalVolumes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range missing {
+		report(types.SystemCheckProblem{
+			Kind:    "missing-volume-data",
+			ID:      name,
+			Message: "volume data directory does not exist",
+		}, nil)
+	}
+	return nil
+}
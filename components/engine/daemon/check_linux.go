@@ -0,0 +1,63 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/mount"
+)
+
+// checkContainerMounts reports filesystems below the daemon root which are
+// still mounted for containers which are not running, and not mounted with
+// ContainerMount.
+func (daemon *Daemon) checkContainerMounts(report func(types.SystemCheckProblem, func() error)) error {
+	infos, err := mount.GetMounts(mount.PrefixFilter(daemon.root))
+	if err != nil {
+		return err
+	}
+
+	for _, c := range daemon.containers.List() {
+		if c.IsRunning() || c.IsRestarting() || daemon.isRootfsMounted(c.ID) {
+			continue
+		}
+		mountID, err := daemon.imageService.GetLayerMountID(c.ID, c.OS)
+		if err != nil || mountID == "" {
+			continue
+		}
+		containerRoot := daemon.containerRoot(c.ID) + string(filepath.Separator)
+
+		// Nested mounts are listed after their parent; unmount them first.
+		for i := len(infos) - 1; i >= 0; i-- {
+			mp := infos[i].Mountpoint
+			if !strings.HasPrefix(mp, containerRoot) && !hasPathElement(mp, mountID) {
+				continue
+			}
+			c := c
+			report(types.SystemCheckProblem{
+				Kind:    "leftover-mount",
+				ID:      c.ID,
+				Message: "filesystem is still mounted at " + mp + " while the container is not running",
+				Repair:  "unmount " + mp,
+			}, func() error {
+				c.Lock()
+				defer c.Unlock()
+				if c.Running || c.Restarting {
+					return errors.New("container is running")
+				}
+				return mount.Unmount(mp)
+			})
+		}
+	}
+	return nil
+}
+
+func hasPathElement(path, elem string) bool {
+	for _, e := range strings.Split(path, string(filepath.Separator)) {
+		if e == elem {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/daemon/images"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/system"
+	"github.com/docker/docker/plugin"
+	refstore "github.com/docker/docker/reference"
+	volumesservice "github.com/docker/docker/volume/service"
+	"github.com/pkg/errors"
+)
+
+// CheckOffline checks the containers, images, layers and volumes of a daemon
+// which is not running, with the checks of SystemCheck. The stores are opened
+// without starting the daemon, and are only read: the problems are reported,
+// and are repaired by SystemCheck once the daemon is started.
+func CheckOffline(ctx context.Context, config *config.Config, verifyLayers bool) (*types.SystemCheckReport, error) {
+	idMapping, err := setupRemappedRoot(config)
+	if err != nil {
+		return nil, err
+	}
+	rootIDs := idMapping.RootPair()
+	root := daemonDataRoot(config, rootIDs)
+	if _, err := os.Stat(root); err != nil {
+		return nil, errors.Wrap(err, "cannot check the daemon data")
+	}
+
+	d := &Daemon{
+		configStore:  config,
+		PluginStore:  plugin.NewStore(),
+		root:         root,
+		repository:   filepath.Join(root, "containers"),
+		idMapping:    idMapping,
+		containers:   container.NewMemoryStore(),
+		rootfsMounts: make(map[string]int),
+		graphDrivers: make(map[string]string),
+	}
+	if d.containersReplica, err = container.NewViewDB(); err != nil {
+		return nil, err
+	}
+
+	driverName := os.Getenv("DOCKER_DRIVER")
+	if driverName == "" {
+		driverName = config.GraphDriver
+	}
+	if runtime.GOOS == "windows" {
+		driverName = "windowsfilter"
+	}
+	// The layer store is not cleaned up, as its graph driver would unmount
+	// the filesystems of the containers.
+	ls, err := layer.NewStoreFromOptions(layer.StoreOptions{
+		Root:                      root,
+		MetadataStorePathTemplate: filepath.Join(root, "image", "%s", "layerdb"),
+		GraphDriver:               driverName,
+		GraphDriverOptions:        config.GraphOptions,
+		IDMapping:                 idMapping,
+		PluginGetter:              d.PluginStore,
+		ExperimentalEnabled:       config.Experimental,
+		OS:                        runtime.GOOS,
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.graphDrivers[runtime.GOOS] = ls.DriverName()
+	layerStores := map[string]layer.Store{runtime.GOOS: ls}
+
+	imageRoot := filepath.Join(root, "image", ls.DriverName())
+	ifs, err := image.NewFSStoreBackend(filepath.Join(imageRoot, "imagedb"))
+	if err != nil {
+		return nil, err
+	}
+	imageStore, err := image.NewImageStore(ifs, map[string]image.LayerGetReleaser{runtime.GOOS: ls})
+	if err != nil {
+		return nil, err
+	}
+	rs, err := refstore.NewReferenceStore(filepath.Join(imageRoot, "repositories.json"))
+	if err != nil {
+		return nil, err
+	}
+	// The image mounts are not restored, so that they are not cleaned up.
+	d.imageService = images.NewImageService(images.ImageServiceConfig{
+		ContainerStore: d.containers,
+		ImageStore:     imageStore,
+		LayerStores:    layerStores,
+		ReferenceStore: rs,
+	})
+
+	if d.volumes, err = volumesservice.NewVolumeService(root, d.PluginStore, rootIDs, d); err != nil {
+		return nil, err
+	}
+	defer d.volumes.Shutdown()
+
+	if err := d.loadOfflineContainers(); err != nil {
+		return nil, err
+	}
+	return d.SystemCheck(ctx, types.SystemCheckOptions{VerifyLayers: verifyLayers})
+}
+
+// loadOfflineContainers loads the containers as the daemon does when it
+// starts, so that the containers which cannot be loaded are reported by the
+// check, and the filesystems of the others are in use.
+func (daemon *Daemon) loadOfflineContainers() error {
+	dir, err := ioutil.ReadDir(daemon.repository)
+	if err != nil {
+		return err
+	}
+	for _, v := range dir {
+		c := daemon.newBaseContainer(v.Name())
+		if err := c.FromDisk(); err != nil || c.ID != v.Name() || !system.IsOSSupported(c.OS) {
+			continue
+		}
+		currentDriverForContainerOS := daemon.graphDrivers[c.OS]
+		if !((c.Driver == "" && currentDriverForContainerOS == "aufs") || c.Driver == currentDriverForContainerOS) {
+			continue
+		}
+		rwlayer, err := daemon.imageService.GetLayerByID(c.ID, c.OS)
+		if err != nil {
+			continue
+		}
+		c.RWLayer = rwlayer
+		daemon.containers.Add(c.ID, c)
+	}
+	return nil
+}
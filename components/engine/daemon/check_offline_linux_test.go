@@ -0,0 +1,43 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/daemon/config"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestCheckOffline(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "skipping test that requires root")
+
+	root, err := ioutil.TempDir("", "check-offline-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	corrupted := filepath.Join(root, "containers", "abc")
+	assert.NilError(t, os.MkdirAll(corrupted, 0700))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(corrupted, "config.v2.json"), []byte("{"), 0600))
+
+	conf := &config.Config{}
+	conf.Root = root
+	conf.GraphDriver = "vfs"
+	report, err := CheckOffline(context.Background(), conf, false)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(report.Problems, 1))
+	assert.Check(t, is.Equal(report.Problems[0].Kind, "corrupted-container"))
+	assert.Check(t, is.Equal(report.Problems[0].ID, "abc"))
+	assert.Check(t, !report.Problems[0].Repaired)
+
+	// The problems are only reported.
+	_, err = os.Stat(corrupted)
+	assert.Check(t, err)
+
+	_, err = CheckOffline(context.Background(), &config.Config{CommonConfig: config.CommonConfig{Root: filepath.Join(root, "missing")}}, false)
+	assert.Check(t, is.ErrorContains(err, "cannot check the daemon data"))
+}
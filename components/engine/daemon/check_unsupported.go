@@ -0,0 +1,9 @@
+// +build !linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import "github.com/docker/docker/api/types"
+
+func (daemon *Daemon) checkContainerMounts(report func(types.SystemCheckProblem, func() error)) error {
+	return nil
+}
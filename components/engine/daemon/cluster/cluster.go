@@ -52,6 +52,7 @@ import (
 	types "github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/daemon/cluster/controllers/plugin"
 	executorpkg "github.com/docker/docker/daemon/cluster/executor"
+	"github.com/docker/docker/daemon/secretstore"
 	"github.com/docker/docker/pkg/signal"
 	lncluster "github.com/docker/libnetwork/cluster"
 	swarmapi "github.com/docker/swarmkit/api"
@@ -90,6 +91,10 @@ type Config struct {
 	VolumeBackend          executorpkg.VolumeBackend
 	NetworkSubnetsProvider NetworkSubnetsProvider
 
+	// SecretStore stores the secrets and the configs of the daemon when the
+	// node is not part of a swarm.
+	SecretStore *secretstore.Store
+
 	// DefaultAdvertiseAddr is the default host/IP or network interface to use
 	// if no AdvertiseAddr value is specified.
 	DefaultAdvertiseAddr string
@@ -344,6 +349,17 @@ func (c *Cluster) currentNodeState() nodeState {
 	return c.nr.State()
 }
 
+// localSecretStore returns the local store of the secrets and the configs of
+// the daemon if the node is not part of a swarm, and nil otherwise.
+func (c *Cluster) localSecretStore() *secretstore.Store {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.currentNodeState().status != types.LocalNodeStateInactive {
+		return nil
+	}
+	return c.config.SecretStore
+}
+
 // errNoManager returns error describing why manager commands can't be used.
 // Call with read lock.
 func (c *Cluster) errNoManager(st nodeState) error {
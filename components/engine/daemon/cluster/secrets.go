@@ -9,8 +9,13 @@ import (
 	swarmapi "github.com/docker/swarmkit/api"
 )
 
-// GetSecret returns a secret from a managed swarm cluster
+// GetSecret returns a secret from a managed swarm cluster, or
+// from the local store of the daemon if the node is not part of a swarm
 func (c *Cluster) GetSecret(input string) (types.Secret, error) {
+	if s := c.localSecretStore(); s != nil {
+		return s.GetSecret(input)
+	}
+
 	var secret *swarmapi.Secret
 
 	if err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
@@ -26,8 +31,13 @@ func (c *Cluster) GetSecret(input string) (types.Secret, error) {
 	return convert.SecretFromGRPC(secret), nil
 }
 
-// GetSecrets returns all secrets of a managed swarm cluster.
+// GetSecrets returns all secrets of a managed swarm cluster, or
+// of the local store of the daemon if the node is not part of a swarm.
 func (c *Cluster) GetSecrets(options apitypes.SecretListOptions) ([]types.Secret, error) {
+	if s := c.localSecretStore(); s != nil {
+		return s.GetSecrets(options)
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -58,8 +68,13 @@ func (c *Cluster) GetSecrets(options apitypes.SecretListOptions) ([]types.Secret
 	return secrets, nil
 }
 
-// CreateSecret creates a new secret in a managed swarm cluster.
+// CreateSecret creates a new secret in a managed swarm cluster, or
+// in the local store of the daemon if the node is not part of a swarm.
 func (c *Cluster) CreateSecret(s types.SecretSpec) (string, error) {
+	if store := c.localSecretStore(); store != nil {
+		return store.CreateSecret(s)
+	}
+
 	var resp *swarmapi.CreateSecretResponse
 	if err := c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
 		secretSpec := convert.SecretSpecToGRPC(s)
@@ -77,8 +92,13 @@ func (c *Cluster) CreateSecret(s types.SecretSpec) (string, error) {
 	return resp.Secret.ID, nil
 }
 
-// RemoveSecret removes a secret from a managed swarm cluster.
+// RemoveSecret removes a secret from a managed swarm cluster, or
+// from the local store of the daemon if the node is not part of a swarm.
 func (c *Cluster) RemoveSecret(input string) error {
+	if s := c.localSecretStore(); s != nil {
+		return s.RemoveSecret(input)
+	}
+
 	return c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
 		secret, err := getSecret(ctx, state.controlClient, input)
 		if err != nil {
@@ -94,9 +114,14 @@ func (c *Cluster) RemoveSecret(input string) error {
 	})
 }
 
-// UpdateSecret updates a secret in a managed swarm cluster.
+// UpdateSecret updates a secret in a managed swarm cluster, or
+// in the local store of the daemon if the node is not part of a swarm.
 // Note: this is not exposed to the CLI but is available from the API only
 func (c *Cluster) UpdateSecret(input string, version uint64, spec types.SecretSpec) error {
+	if s := c.localSecretStore(); s != nil {
+		return s.UpdateSecret(input, version, spec)
+	}
+
 	return c.lockedManagerAction(func(ctx context.Context, state nodeState) error {
 		secret, err := getSecret(ctx, state.controlClient, input)
 		if err != nil {
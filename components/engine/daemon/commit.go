@@ -10,6 +10,7 @@ import (
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/builder/dockerfile"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/pkg/errors"
 )
 
@@ -154,6 +155,10 @@ func (daemon *Daemon) CreateImageFromContainer(name string, c *backend.CreateIma
 		return "", err
 	}
 
+	var idMapping *idtools.IdentityMapping
+	if container.UIDMaps != nil {
+		idMapping = daemon.containerIDMapping(container)
+	}
 	id, err := daemon.imageService.CommitImage(backend.CommitConfig{
 		Author:              c.Author,
 		Comment:             c.Comment,
@@ -162,6 +167,7 @@ func (daemon *Daemon) CreateImageFromContainer(name string, c *backend.CreateIma
 		ContainerID:         container.ID,
 		ContainerMountLabel: container.MountLabel,
 		ContainerOS:         container.OS,
+		ContainerIDMapping:  idMapping,
 		ParentImageID:       string(container.ImageID),
 	})
 	if err != nil {
@@ -0,0 +1,64 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"fmt"
+	"path/filepath"
+
+	units "github.com/docker/go-units"
+)
+
+const (
+	// AuditLogDriverFile writes the audit log to a file, rotated by size.
+	AuditLogDriverFile = "file"
+	// AuditLogDriverSyslog sends the audit log to syslog.
+	AuditLogDriverSyslog = "syslog"
+)
+
+// AuditLogConfig is the configuration of the audit log of the API requests
+// which modify the daemon state.
+type AuditLogConfig struct {
+	// Driver is either "file" (the default) or "syslog".
+	Driver string `json:"driver,omitempty"`
+
+	// Path is the path of the audit log file.
+	Path string `json:"path,omitempty"`
+	// MaxSize is the size, such as "100m", at which the audit log file is
+	// rotated. The file is never rotated if it is not set.
+	MaxSize string `json:"max-size,omitempty"`
+	// MaxFile is the number of audit log files kept when rotating it.
+	MaxFile int `json:"max-file,omitempty"`
+	// Compress compresses the rotated audit log files.
+	Compress bool `json:"compress,omitempty"`
+
+	// SyslogAddress is the address of the syslog server, such as
+	// "udp://1.2.3.4:514". The local syslog daemon is used if it is not set.
+	SyslogAddress string `json:"syslog-address,omitempty"`
+	// SyslogTag is the tag of the syslog messages, "dockerd-audit" by
+	// default.
+	SyslogTag string `json:"syslog-tag,omitempty"`
+}
+
+// Validate checks the audit log configuration.
+func (c *AuditLogConfig) Validate() error {
+	switch c.Driver {
+	case "", AuditLogDriverFile:
+		if c.Path == "" {
+			return fmt.Errorf("audit-log: a path is required to write the audit log to a file")
+		}
+		if !filepath.IsAbs(c.Path) {
+			return fmt.Errorf("audit-log: path %q must be absolute", c.Path)
+		}
+		if c.MaxSize != "" {
+			if _, err := units.FromHumanSize(c.MaxSize); err != nil {
+				return fmt.Errorf("audit-log: invalid max-size %q: %v", c.MaxSize, err)
+			}
+		}
+		if c.MaxFile < 0 {
+			return fmt.Errorf("audit-log: invalid max-file %d", c.MaxFile)
+		}
+	case AuditLogDriverSyslog:
+	default:
+		return fmt.Errorf("audit-log: unsupported driver %q", c.Driver)
+	}
+	return nil
+}
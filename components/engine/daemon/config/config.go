@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"reflect"
 	"strings"
@@ -55,14 +56,20 @@ var flatOptions = map[string]bool{
 	"default-ulimits":    true,
 	"features":           true,
 	"builder":            true,
+	"audit-log":          true,
+	"listeners":          true,
 }
 
 // skipValidateOptions contains configuration keys
 // that will be skipped from findConfigurationConflicts
 // for unknown flag validation.
 var skipValidateOptions = map[string]bool{
-	"features": true,
-	"builder":  true,
+	"features":          true,
+	"builder":           true,
+	"audit-log":         true,
+	"listeners":         true,
+	"allowed-oci-hooks": true,
+	"spec-patches":      true,
 }
 
 // skipDuplicates contains configuration keys that
@@ -114,6 +121,7 @@ type CommonTLSOptions struct {
 type CommonConfig struct {
 	AuthzMiddleware       *authorization.Middleware `json:"-"`
 	AuthorizationPlugins  []string                  `json:"authorization-plugins,omitempty"` // AuthorizationPlugins holds list of authorization plugins
+	AuthorizationPolicy   string                    `json:"authorization-policy,omitempty"`  // AuthorizationPolicy is the path of the built-in authorization policy
 	AutoRestart           bool                      `json:"-"`
 	Context               map[string][]string       `json:"-"`
 	DisableBridge         bool                      `json:"-"`
@@ -192,6 +200,10 @@ type CommonConfig struct {
 
 	MetricsAddress string `json:"metrics-addr"`
 
+	// ExecLog records the standard streams of execs in the logs of their
+	// container, unless the configuration of the exec overrides it.
+	ExecLog bool `json:"exec-log,omitempty"`
+
 	LogConfig
 	BridgeConfig // bridgeConfig holds bridge network specific configuration.
 	NetworkConfig
@@ -222,6 +234,14 @@ type CommonConfig struct {
 	Features map[string]bool `json:"features,omitempty"`
 
 	Builder BuilderConfig `json:"builder,omitempty"`
+
+	// AuditLog configures the audit log of the API requests which modify the
+	// daemon state. The requests are not audited if it is not set.
+	AuditLog *AuditLogConfig `json:"audit-log,omitempty"`
+
+	// Listeners holds the settings of the listeners of the API, by host
+	// address as in Hosts.
+	Listeners map[string]ListenerConfig `json:"listeners,omitempty"`
 }
 
 // IsValueSet returns true if a configuration value
@@ -561,6 +581,10 @@ func Validate(config *Config) error {
 		return err
 	}
 
+	if err := ValidateDefaultAddressPools(config.NetworkConfig.DefaultAddressPools); err != nil {
+		return err
+	}
+
 	if defaultRuntime := config.GetDefaultRuntimeName(); defaultRuntime != "" && defaultRuntime != StockRuntimeName {
 		runtimes := config.GetAllRuntimes()
 		if _, ok := runtimes[defaultRuntime]; !ok {
@@ -568,6 +592,18 @@ func Validate(config *Config) error {
 		}
 	}
 
+	if config.AuditLog != nil {
+		if err := config.AuditLog.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for host, l := range config.Listeners {
+		if err := l.Validate(host); err != nil {
+			return err
+		}
+	}
+
 	// validate platform-specific settings
 	return config.ValidatePlatformConfig()
 }
@@ -586,3 +622,18 @@ func ModifiedDiscoverySettings(config *Config, backendType, advertise string, cl
 
 	return !reflect.DeepEqual(config.ClusterOpts, clusterOpts)
 }
+
+// ValidateDefaultAddressPools validates the default address pools, which are
+// split in subnets of the given size by the IPAM driver.
+func ValidateDefaultAddressPools(pools opts.PoolsOpt) error {
+	for _, pool := range pools.Value() {
+		_, base, err := net.ParseCIDR(pool.Base)
+		if err != nil {
+			return fmt.Errorf("invalid default address pool base %q: %v", pool.Base, err)
+		}
+		if ones, bits := base.Mask.Size(); pool.Size <= 0 || pool.Size < ones || pool.Size > bits {
+			return fmt.Errorf("invalid default address pool size %d for base %s", pool.Size, pool.Base)
+		}
+	}
+	return nil
+}
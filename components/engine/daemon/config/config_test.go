@@ -385,6 +385,28 @@ func TestValidateConfiguration(t *testing.T) {
 	}
 }
 
+func TestValidateDefaultAddressPools(t *testing.T) {
+	for _, tc := range []struct {
+		pool        string
+		expectedErr string
+	}{
+		{pool: "base=10.10.0.0/16,size=24"},
+		{pool: "base=fd00::/48,size=64"},
+		{pool: "base=10.10.0.0,size=24", expectedErr: "invalid default address pool base"},
+		{pool: "base=10.10.0.0/16,size=8", expectedErr: "invalid default address pool size 8"},
+		{pool: "base=10.10.0.0/16,size=33", expectedErr: "invalid default address pool size 33"},
+	} {
+		config := &Config{}
+		assert.NilError(t, config.NetworkConfig.DefaultAddressPools.Set(tc.pool))
+		err := Validate(config)
+		if tc.expectedErr == "" {
+			assert.Check(t, err, tc.pool)
+		} else {
+			assert.Check(t, is.ErrorContains(err, tc.expectedErr), tc.pool)
+		}
+	}
+}
+
 func TestModifiedDiscoverySettings(t *testing.T) {
 	cases := []struct {
 		current  *Config
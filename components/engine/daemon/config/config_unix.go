@@ -4,7 +4,9 @@ package config // import "github.com/docker/docker/daemon/config"
 
 import (
 	"fmt"
+	"regexp"
 
+	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/opts"
 	"github.com/docker/go-units"
@@ -15,8 +17,16 @@ const (
 	DefaultCgroupNamespaceMode = "host" // TODO: change to private
 	// DefaultIpcMode is default for container's IpcMode, if not set otherwise
 	DefaultIpcMode = "private"
+	// DefaultUsernsAutoSize is the default number of IDs of the user namespace
+	// mapping of the containers created with --userns=auto
+	DefaultUsernsAutoSize = 65536
 )
 
+// runtimeTypeRegexp matches the runtime types of the v2 containerd shims, such
+// as "io.containerd.runc.v2", which containerd executes as the
+// containerd-shim-runc-v2 binary.
+var runtimeTypeRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*\.v[0-9]+$`)
+
 // Config defines the configuration of a docker daemon.
 // It includes json tags to deserialize configuration from a file
 // using the same names that the flags in the command line uses.
@@ -29,6 +39,8 @@ type Config struct {
 	CgroupParent         string                   `json:"cgroup-parent,omitempty"`
 	EnableSelinuxSupport bool                     `json:"selinux-enabled,omitempty"`
 	RemappedRoot         string                   `json:"userns-remap,omitempty"`
+	UsernsAutoPool       string                   `json:"userns-auto-pool,omitempty"`
+	UsernsAutoSize       int                      `json:"userns-auto-size,omitempty"`
 	Ulimits              map[string]*units.Ulimit `json:"default-ulimits,omitempty"`
 	CPURealtimePeriod    int64                    `json:"cpu-rt-period,omitempty"`
 	CPURealtimeRuntime   int64                    `json:"cpu-rt-runtime,omitempty"`
@@ -43,6 +55,12 @@ type Config struct {
 	// ResolvConf is the path to the configuration of the host resolver
 	ResolvConf string `json:"resolv-conf,omitempty"`
 	Rootless   bool   `json:"rootless,omitempty"`
+	// AllowedOCIHooks are the OCI hooks which can be set on containers.
+	// Containers cannot set OCI hooks if it is empty.
+	AllowedOCIHooks []AllowedOCIHook `json:"allowed-oci-hooks,omitempty"`
+	// SpecPatches are the JSON patches applied to the OCI runtime spec of
+	// the containers.
+	SpecPatches []SpecPatch `json:"spec-patches,omitempty"`
 }
 
 // BridgeConfig stores all the bridge driver specific
@@ -74,6 +92,24 @@ func (conf *Config) IsSwarmCompatible() error {
 	return nil
 }
 
+func verifyRuntimes(runtimes map[string]types.Runtime) error {
+	for name, rt := range runtimes {
+		if rt.Type == "" {
+			if len(rt.Options) > 0 {
+				return fmt.Errorf("runtime %s: options can only be set for a runtime with a runtimeType", name)
+			}
+			continue
+		}
+		if rt.Path != "" || len(rt.Args) > 0 {
+			return fmt.Errorf("runtime %s: runtimeType cannot be set with path or runtimeArgs", name)
+		}
+		if !runtimeTypeRegexp.MatchString(rt.Type) {
+			return fmt.Errorf("runtime %s: invalid runtimeType %q: the runtime type must be the name of a v2 containerd shim, such as io.containerd.runc.v2", name, rt.Type)
+		}
+	}
+	return nil
+}
+
 func verifyDefaultIpcMode(mode string) error {
 	const hint = "Use \"shareable\" or \"private\"."
 
@@ -101,6 +137,22 @@ func (conf *Config) ValidatePlatformConfig() error {
 	if err := verifyDefaultIpcMode(conf.IpcMode); err != nil {
 		return err
 	}
+	if conf.UsernsAutoSize < 0 {
+		return fmt.Errorf("invalid userns-auto-size %d: the size must be positive", conf.UsernsAutoSize)
+	}
+	if err := verifyRuntimes(conf.Runtimes); err != nil {
+		return err
+	}
+	for _, h := range conf.AllowedOCIHooks {
+		if err := h.Validate(); err != nil {
+			return fmt.Errorf("allowed-oci-hooks: %v", err)
+		}
+	}
+	for i, p := range conf.SpecPatches {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("spec-patches: patch %d: %v", i, err)
+		}
+	}
 
 	return verifyDefaultCgroupNsMode(conf.CgroupNamespaceMode)
 }
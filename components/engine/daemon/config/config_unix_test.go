@@ -132,3 +132,21 @@ func TestDaemonConfigurationMergeShmSize(t *testing.T) {
 	expectedValue := 1 * 1024 * 1024 * 1024
 	assert.Check(t, is.Equal(int64(expectedValue), cc.ShmSize.Value()))
 }
+
+func TestDaemonConfigurationAllowedOCIHooks(t *testing.T) {
+	data := `{"allowed-oci-hooks": ["/usr/local/lib/hooks/*", {"path": "/usr/local/lib/monitoring/register", "args": ["--verbose"], "env": ["MONITORING_URL"]}]}`
+
+	file := fs.NewFile(t, "docker-config", fs.WithContent(data))
+	defer file.Remove()
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cc, err := MergeDaemonConfigurations(&Config{}, flags, file.Path())
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(cc.AllowedOCIHooks, []AllowedOCIHook{
+		{Path: "/usr/local/lib/hooks/*"},
+		{Path: "/usr/local/lib/monitoring/register", Args: []string{"--verbose"}, Env: []string{"MONITORING_URL"}},
+	}))
+
+	assert.Check(t, is.ErrorContains(AllowedOCIHook{Path: "/hooks/*", Env: []string{"LD_PRELOAD=/tmp/evil.so"}}.Validate(), "invalid environment variable name"))
+	assert.Check(t, is.ErrorContains(AllowedOCIHook{Path: "hooks/*"}.Validate(), "must be an absolute path"))
+}
@@ -0,0 +1,86 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/docker/docker/api/types/versions"
+)
+
+const (
+	// ClientAuthNone does not request a client certificate.
+	ClientAuthNone = "none"
+	// ClientAuthRequest requests a client certificate, but does not require
+	// nor verify it.
+	ClientAuthRequest = "request"
+	// ClientAuthRequire requires a client certificate, but does not verify
+	// it.
+	ClientAuthRequire = "require"
+	// ClientAuthVerifyIfGiven verifies the client certificate if one is
+	// given.
+	ClientAuthVerifyIfGiven = "verify-if-given"
+	// ClientAuthRequireAndVerify requires and verifies a client certificate,
+	// as --tlsverify does.
+	ClientAuthRequireAndVerify = "require-and-verify"
+)
+
+var apiVersionRegexp = regexp.MustCompile(`^[0-9]+\.[0-9]+$`)
+
+// ListenerConfig holds the settings of a listener of the API, overriding
+// the settings of the daemon for the connections on that listener.
+type ListenerConfig struct {
+	// TLS enables or disables TLS on the listener. The --tls and --tlsverify
+	// settings of the daemon are used if it is not set.
+	TLS *bool `json:"tls,omitempty"`
+	// ClientAuth is the client certificate authentication mode of the
+	// listener, one of "none", "request", "require", "verify-if-given" or
+	// "require-and-verify".
+	ClientAuth string `json:"client-auth,omitempty"`
+	// MinAPIVersion and MaxAPIVersion restrict the range of API versions
+	// accepted on the listener.
+	MinAPIVersion string `json:"min-api-version,omitempty"`
+	MaxAPIVersion string `json:"max-api-version,omitempty"`
+}
+
+// Validate checks the settings of the listener of host.
+func (c ListenerConfig) Validate(host string) error {
+	switch c.ClientAuth {
+	case "", ClientAuthNone, ClientAuthRequest, ClientAuthRequire, ClientAuthVerifyIfGiven, ClientAuthRequireAndVerify:
+	default:
+		return fmt.Errorf("listeners: invalid client-auth %q for %s", c.ClientAuth, host)
+	}
+	if c.ClientAuth != "" && c.TLS != nil && !*c.TLS {
+		return fmt.Errorf("listeners: client-auth cannot be set for %s, which does not use TLS", host)
+	}
+	for _, v := range []string{c.MinAPIVersion, c.MaxAPIVersion} {
+		if v != "" && !apiVersionRegexp.MatchString(v) {
+			return fmt.Errorf("listeners: invalid API version %q for %s", v, host)
+		}
+	}
+	if c.MinAPIVersion != "" && c.MaxAPIVersion != "" && versions.LessThan(c.MaxAPIVersion, c.MinAPIVersion) {
+		return fmt.Errorf("listeners: max-api-version %s is lower than min-api-version %s for %s", c.MaxAPIVersion, c.MinAPIVersion, host)
+	}
+	return nil
+}
+
+// ListenerSettings returns the settings of the listener of host, with the
+// TLS settings of the daemon for the settings the listener does not
+// override. TLS is always set in the returned settings, and ClientAuth is set
+// when TLS is enabled.
+func (conf *Config) ListenerSettings(host string) ListenerConfig {
+	l := conf.Listeners[host]
+	useTLS := conf.TLS
+	if l.TLS != nil {
+		useTLS = *l.TLS
+	}
+	l.TLS = &useTLS
+	switch {
+	case !useTLS:
+		l.ClientAuth = ""
+	case l.ClientAuth == "" && conf.TLSVerify:
+		l.ClientAuth = ClientAuthRequireAndVerify
+	case l.ClientAuth == "":
+		l.ClientAuth = ClientAuthNone
+	}
+	return l
+}
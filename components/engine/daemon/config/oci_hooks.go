@@ -0,0 +1,98 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// AllowedOCIHook is an OCI hook which can be set on containers. The hooks
+// are run by the runtime as root on the host, so the callers of the API can
+// only pass the arguments and the environment variables it declares.
+type AllowedOCIHook struct {
+	// Path is the pattern of the paths of the hook.
+	Path string `json:"path"`
+	// Args are the patterns of the arguments which can be passed to the
+	// hook, after the path. No argument can be passed if it is empty.
+	Args []string `json:"args,omitempty"`
+	// Env are the names of the environment variables which can be set for
+	// the hook. No variable can be set if it is empty.
+	Env []string `json:"env,omitempty"`
+}
+
+// UnmarshalJSON accepts a path pattern, which allows the hook without any
+// argument or environment variable, or an object.
+func (h *AllowedOCIHook) UnmarshalJSON(b []byte) error {
+	var path string
+	if err := json.Unmarshal(b, &path); err == nil {
+		*h = AllowedOCIHook{Path: path}
+		return nil
+	}
+	type allowedOCIHook AllowedOCIHook
+	return json.Unmarshal(b, (*allowedOCIHook)(h))
+}
+
+// Validate checks the patterns of the allowed hook.
+func (h AllowedOCIHook) Validate() error {
+	if !filepath.IsAbs(h.Path) {
+		return fmt.Errorf("pattern %q must be an absolute path", h.Path)
+	}
+	if _, err := filepath.Match(h.Path, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %v", h.Path, err)
+	}
+	for _, a := range h.Args {
+		if _, err := filepath.Match(a, ""); err != nil {
+			return fmt.Errorf("invalid argument pattern %q of %s: %v", a, h.Path, err)
+		}
+	}
+	for _, e := range h.Env {
+		if e == "" || strings.Contains(e, "=") {
+			return fmt.Errorf("invalid environment variable name %q of %s", e, h.Path)
+		}
+	}
+	return nil
+}
+
+// Allows returns whether the hook allows a path, its arguments, without the
+// first one, and its environment variables.
+func (h AllowedOCIHook) Allows(path string, args, env []string) bool {
+	if ok, _ := filepath.Match(h.Path, path); !ok {
+		return false
+	}
+	for _, a := range args {
+		if !matchAny(h.Args, a) {
+			return false
+		}
+	}
+	for _, e := range env {
+		name := strings.SplitN(e, "=", 2)[0]
+		if !containsString(h.Env, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the path pattern of the hook.
+func (h AllowedOCIHook) String() string {
+	return h.Path
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/pkg/jsonpatch"
+)
+
+// SpecPatch is a JSON patch applied to the OCI runtime spec of the containers
+// selected by their labels.
+type SpecPatch struct {
+	// Selector selects the containers which have all its labels. A label with
+	// an empty value selects the containers which have the label, whatever
+	// its value. The patch is applied to all containers if it is empty.
+	Selector map[string]string `json:"selector,omitempty"`
+	// Patch is the JSON patch (RFC 6902) applied to the spec.
+	Patch jsonpatch.Patch `json:"patch"`
+}
+
+// Validate checks the spec patch.
+func (p SpecPatch) Validate() error {
+	if len(p.Patch) == 0 {
+		return fmt.Errorf("the patch is empty")
+	}
+	return p.Patch.Validate()
+}
+
+// Selects returns whether the patch is applied to a container with labels.
+func (p SpecPatch) Selects(labels map[string]string) bool {
+	for k, v := range p.Selector {
+		l, ok := labels[k]
+		if !ok || (v != "" && l != v) {
+			return false
+		}
+	}
+	return true
+}
@@ -241,6 +241,11 @@ func (daemon *Daemon) verifyContainerSettings(platform string, hostConfig *conta
 	if err := validateHostConfig(hostConfig, platform); err != nil {
 		return warnings, err
 	}
+	if hostConfig != nil {
+		if err := validateUnixSocketBindings(hostConfig, platform, daemon.unixSocketsDir()); err != nil {
+			return warnings, err
+		}
+	}
 
 	// Now do platform-specific verification
 	warnings, err = verifyPlatformContainerSettings(daemon, hostConfig, update)
@@ -293,6 +298,9 @@ func validateHostConfig(hostConfig *containertypes.HostConfig, platform string)
 	if err := validatePortBindings(hostConfig.PortBindings); err != nil {
 		return err
 	}
+	if err := validateDependencies(hostConfig.DependsOn); err != nil {
+		return err
+	}
 	if err := validateRestartPolicy(hostConfig.RestartPolicy); err != nil {
 		return err
 	}
@@ -0,0 +1,122 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/sirupsen/logrus"
+)
+
+// ContainerMount mounts the root filesystem of a container on the host, and
+// returns the path at which it is mounted. Mounts are reference counted: the
+// filesystem stays mounted until ContainerUnmount is called as many times as
+// ContainerMount. A mounted container cannot be removed.
+func (daemon *Daemon) ContainerMount(name string) (string, error) {
+	ctr, err := daemon.GetContainer(name)
+	if err != nil {
+		return "", err
+	}
+
+	ctr.Lock()
+	defer ctr.Unlock()
+	if ctr.RemovalInProgress || ctr.Dead {
+		return "", errdefs.Conflict(fmt.Errorf("cannot mount container %s: container is marked for removal", ctr.ID))
+	}
+	if err := daemon.mountRootfs(ctr); err != nil {
+		return "", err
+	}
+
+	daemon.LogContainerEvent(ctr, "mount")
+	return ctr.BaseFS.Path(), nil
+}
+
+// ContainerUnmount releases a mount of the root filesystem of a container made
+// by ContainerMount. If force is set, all mounts of the container are
+// released.
+func (daemon *Daemon) ContainerUnmount(name string, force bool) error {
+	ctr, err := daemon.GetContainer(name)
+	if err != nil {
+		return err
+	}
+
+	ctr.Lock()
+	defer ctr.Unlock()
+
+	if err := daemon.releaseRootfs(ctr, force); err != nil {
+		return err
+	}
+	daemon.LogContainerEvent(ctr, "unmount")
+	return nil
+}
+
+// mountRootfs mounts the root filesystem of a container, and counts the mount
+// so that the container cannot be removed until it is released by
+// releaseRootfs. The container must be locked.
+func (daemon *Daemon) mountRootfs(ctr *container.Container) error {
+	if err := daemon.Mount(ctr); err != nil {
+		return err
+	}
+
+	daemon.rootfsMountsMu.Lock()
+	daemon.rootfsMounts[ctr.ID]++
+	daemon.rootfsMountsMu.Unlock()
+	return nil
+}
+
+// releaseRootfs releases a mount made by mountRootfs, or all of them if force
+// is set. The container must be locked.
+func (daemon *Daemon) releaseRootfs(ctr *container.Container, force bool) error {
+	daemon.rootfsMountsMu.Lock()
+	count := daemon.rootfsMounts[ctr.ID]
+	if count == 0 {
+		daemon.rootfsMountsMu.Unlock()
+		return errdefs.Conflict(fmt.Errorf("container %s is not mounted", ctr.ID))
+	}
+	release := 1
+	if force {
+		release = count
+	}
+	daemon.rootfsMounts[ctr.ID] = count - release
+	if daemon.rootfsMounts[ctr.ID] == 0 {
+		delete(daemon.rootfsMounts, ctr.ID)
+	}
+	daemon.rootfsMountsMu.Unlock()
+
+	for ; release > 0; release-- {
+		if err := daemon.Unmount(ctr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isRootfsMounted returns whether the root filesystem of the container is
+// mounted by ContainerMount.
+func (daemon *Daemon) isRootfsMounted(id string) bool {
+	daemon.rootfsMountsMu.Lock()
+	defer daemon.rootfsMountsMu.Unlock()
+	return daemon.rootfsMounts[id] > 0
+}
+
+// releaseRootfsMounts releases all mounts made by ContainerMount. It is called
+// on shutdown, as mounts are not restored when the daemon restarts.
+func (daemon *Daemon) releaseRootfsMounts() {
+	daemon.rootfsMountsMu.Lock()
+	mounts := daemon.rootfsMounts
+	daemon.rootfsMounts = make(map[string]int)
+	daemon.rootfsMountsMu.Unlock()
+
+	for id, count := range mounts {
+		ctr := daemon.containers.Get(id)
+		if ctr == nil {
+			continue
+		}
+		for ; count > 0; count-- {
+			if err := daemon.Unmount(ctr); err != nil {
+				logrus.WithError(err).WithField("container", id).Warn("failed to release container mount")
+				break
+			}
+		}
+	}
+}
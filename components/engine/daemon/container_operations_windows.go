@@ -37,9 +37,7 @@ func (daemon *Daemon) setupConfigDir(c *container.Container) (setupErr error) {
 		}
 	}()
 
-	if c.DependencyStore == nil {
-		return fmt.Errorf("config store is not initialized")
-	}
+	store := daemon.dependencyStore(c)
 
 	for _, configRef := range c.ConfigReferences {
 		// TODO (ehazlett): use type switch when more are supported
@@ -59,7 +57,7 @@ func (daemon *Daemon) setupConfigDir(c *container.Container) (setupErr error) {
 		log := logrus.WithFields(logrus.Fields{"name": configRef.File.Name, "path": fPath})
 
 		log.Debug("injecting config")
-		config, err := c.DependencyStore.Configs().Get(configRef.ConfigID)
+		config, err := store.Configs().Get(configRef.ConfigID)
 		if err != nil {
 			return errors.Wrap(err, "unable to get config from config store")
 		}
@@ -109,9 +107,7 @@ func (daemon *Daemon) setupSecretDir(c *container.Container) (setupErr error) {
 		}
 	}()
 
-	if c.DependencyStore == nil {
-		return fmt.Errorf("secret store is not initialized")
-	}
+	store := daemon.dependencyStore(c)
 
 	for _, s := range c.SecretReferences {
 		// TODO (ehazlett): use type switch when more are supported
@@ -130,7 +126,7 @@ func (daemon *Daemon) setupSecretDir(c *container.Container) (setupErr error) {
 			"name": s.File.Name,
 			"path": fPath,
 		}).Debug("injecting secret")
-		secret, err := c.DependencyStore.Secrets().Get(s.SecretID)
+		secret, err := store.Secrets().Get(s.SecretID)
 		if err != nil {
 			return errors.Wrap(err, "unable to get secret from secret store")
 		}
@@ -25,6 +25,7 @@ type createOpts struct {
 	params                  types.ContainerCreateConfig
 	managed                 bool
 	ignoreImagesArgsEscaped bool
+	debugTarget             string
 }
 
 // CreateManagedContainer creates a container that is managed by a Service
@@ -156,6 +157,7 @@ func (daemon *Daemon) create(opts createOpts) (retC *container.Container, retErr
 	if container, err = daemon.newContainer(opts.params.Name, os, opts.params.Config, opts.params.HostConfig, imgID, opts.managed); err != nil {
 		return nil, err
 	}
+	container.DebugTarget = opts.debugTarget
 	defer func() {
 		if retErr != nil {
 			if err := daemon.cleanupContainer(container, true, true); err != nil {
@@ -168,6 +170,18 @@ func (daemon *Daemon) create(opts createOpts) (retC *container.Container, retErr
 		return nil, err
 	}
 
+	if err := daemon.setLocalSecretReferences(container, opts.params.HostConfig); err != nil {
+		return nil, err
+	}
+
+	if err := daemon.verifyDependencies(container); err != nil {
+		return nil, err
+	}
+
+	if err := daemon.allocateIDMapping(container); err != nil {
+		return nil, err
+	}
+
 	container.HostConfig.StorageOpt = opts.params.HostConfig.StorageOpt
 
 	// Fixes: https://github.com/moby/moby/issues/34074 and
@@ -188,13 +202,16 @@ func (daemon *Daemon) create(opts createOpts) (retC *container.Container, retErr
 	}
 
 	// Set RWLayer for container after mount labels have been set
+	// The files of the init layer have the IDs of the daemon: with
+	// --userns=auto, the layers of the image are seen with the IDs of the
+	// container through idmapped mounts.
 	rwLayer, err := daemon.imageService.CreateLayer(container, setupInitLayer(daemon.idMapping))
 	if err != nil {
 		return nil, errdefs.System(err)
 	}
 	container.RWLayer = rwLayer
 
-	rootIDs := daemon.idMapping.RootPair()
+	rootIDs := daemon.containerIDMapping(container).RootPair()
 
 	if err := idtools.MkdirAndChown(container.Root, 0700, rootIDs); err != nil {
 		return nil, err
@@ -224,6 +241,7 @@ func (daemon *Daemon) create(opts createOpts) (retC *container.Container, retErr
 		return nil, err
 	}
 	stateCtr.set(container.ID, "stopped")
+	daemon.imageService.SetImageLastUsed(imgID)
 	daemon.LogContainerEvent(container, "create")
 	return container, nil
 }
@@ -25,7 +25,7 @@ func (daemon *Daemon) createContainerOSSpecificSettings(container *container.Con
 	}
 	defer daemon.Unmount(container)
 
-	rootIDs := daemon.idMapping.RootPair()
+	rootIDs := daemon.containerIDMapping(container).RootPair()
 	if err := container.SetupWorkingDirectory(rootIDs); err != nil {
 		return err
 	}
@@ -81,12 +81,17 @@ func (daemon *Daemon) populateVolumes(c *container.Container) error {
 			continue
 		}
 
-		if mnt.Type != mounttypes.TypeVolume || !mnt.CopyData {
+		if mnt.Type != mounttypes.TypeVolume {
 			continue
 		}
 
-		logrus.Debugf("copying image data from %s:%s, to %s", c.ID, mnt.Destination, mnt.Name)
-		if err := c.CopyImagePathContent(mnt.Volume, mnt.Destination); err != nil {
+		if mnt.CopyData {
+			logrus.Debugf("copying image data from %s:%s, to %s", c.ID, mnt.Destination, mnt.Name)
+			if err := c.CopyImagePathContent(mnt.Volume, mnt.Destination); err != nil {
+				return err
+			}
+		}
+		if err := daemon.chownVolumeRoot(c, mnt.Volume); err != nil {
 			return err
 		}
 	}
@@ -39,6 +39,7 @@ import (
 	"github.com/docker/docker/daemon/images"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/network"
+	"github.com/docker/docker/daemon/secretstore"
 	"github.com/docker/docker/errdefs"
 	"github.com/moby/buildkit/util/resolver"
 	"github.com/moby/buildkit/util/tracing"
@@ -47,15 +48,18 @@ import (
 	// register graph drivers
 	_ "github.com/docker/docker/daemon/graphdriver/register"
 	"github.com/docker/docker/daemon/stats"
+	"github.com/docker/docker/daemon/unixproxy"
 	dmetadata "github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/libcontainerd"
 	libcontainerdtypes "github.com/docker/docker/libcontainerd/types"
+	"github.com/docker/docker/pkg/authorization"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/locker"
 	"github.com/docker/docker/pkg/plugingetter"
+	"github.com/docker/docker/pkg/sizecache"
 	"github.com/docker/docker/pkg/sysinfo"
 	"github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/pkg/truncindex"
@@ -95,12 +99,15 @@ type Daemon struct {
 	EventsService     *events.Events
 	netController     libnetwork.NetworkController
 	volumes           *volumesservice.VolumesService
+	secretStore       *secretstore.Store
 	discoveryWatcher  discovery.Reloader
 	root              string
 	seccompEnabled    bool
 	apparmorEnabled   bool
 	shutdown          bool
 	idMapping         *idtools.IdentityMapping
+	idRangesMu        sync.Mutex
+	idRanges          *idtools.RangeAllocator // see idRangeAllocator
 	// TODO: move graphDrivers field to an InfoService
 	graphDrivers map[string]string // By operating system
 
@@ -122,11 +129,24 @@ type Daemon struct {
 
 	diskUsageRunning int32
 	pruneRunning     int32
+	checkRunning     int32
+	containerSizes   *sizecache.Cache
 	hosts            map[string]bool // hosts stores the addresses the daemon is listening on
 	startupDone      chan struct{}
 
 	attachmentStore       network.AttachmentStore
 	attachableNetworkLock *locker.Locker
+
+	rootfsMountsMu sync.Mutex
+	rootfsMounts   map[string]int // number of mounts by container ID, see ContainerMount
+
+	unixSocketProxiesMu sync.Mutex
+	unixSocketProxies   map[string][]*unixproxy.Proxy // proxies of the Unix socket bindings by container ID
+
+	debugTargetsMu sync.Mutex
+	debugTargets   map[string]string // ID of the debugged container mounted by a running debug container, by debug container ID
+
+	defaultLogConfigMu sync.RWMutex // protects defaultLogConfig, which is changed by Reload
 }
 
 // StoreHosts stores the addresses the daemon is listening on
@@ -287,6 +307,7 @@ func (daemon *Daemon) restore() error {
 				mapLock.Unlock()
 				return
 			}
+			daemon.reserveIDMapping(c)
 
 			// The LogConfig.Type is empty if the container was created before docker 1.12 with default log driver.
 			// We should rewrite it to use the daemon defaults.
@@ -414,6 +435,14 @@ func (daemon *Daemon) restore() error {
 					activeSandboxes[c.NetworkSettings.SandboxID] = options
 					mapLock.Unlock()
 				}
+				if c.IsRunning() {
+					if err := daemon.startUnixSocketProxies(c); err != nil {
+						logrus.WithError(err).WithField("container", c.ID).Warn("Failed to restore the Unix socket bindings of the container")
+					}
+					if err := daemon.mountDebugTarget(c); err != nil {
+						logrus.WithError(err).WithField("container", c.ID).Warn("Failed to mount the root filesystem of the debugged container")
+					}
+				}
 			}
 
 			// get list of containers we need to restart
@@ -478,6 +507,13 @@ func (daemon *Daemon) restore() error {
 	group.Wait()
 
 	for c, notifier := range restartContainers {
+		if len(c.HostConfig.DependsOn) > 0 {
+			// The containers with dependencies are started in the background
+			// once their dependencies meet their condition, so that waiting
+			// for them does not delay the start of the daemon.
+			go daemon.startAfterDependencies(c, notifier, restartContainers)
+			continue
+		}
 		group.Add(1)
 		go func(c *container.Container, chNotify chan struct{}) {
 			_ = sem.Acquire(context.Background(), 1)
@@ -702,6 +738,28 @@ func (daemon *Daemon) IsSwarmCompatible() error {
 	return daemon.configStore.IsSwarmCompatible()
 }
 
+// ValidateConfig checks the settings of a configuration which are validated
+// when the daemon starts, and are not validated by config.Validate when the
+// configuration is loaded: the registries, the options of the runtimes, the
+// default log options and the authorization policy.
+func ValidateConfig(conf *config.Config) error {
+	if _, err := registry.NewService(conf.ServiceOptions); err != nil {
+		return err
+	}
+	if err := validateRuntimes(conf); err != nil {
+		return err
+	}
+	if err := validateLogConfig(conf.LogConfig); err != nil {
+		return err
+	}
+	if conf.AuthorizationPolicy != "" {
+		if _, err := authorization.LoadPolicy(conf.AuthorizationPolicy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NewDaemon sets up everything for the daemon to be able to service
 // requests from the webserver.
 func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.Store) (daemon *Daemon, err error) {
@@ -769,9 +827,10 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	}
 
 	d := &Daemon{
-		configStore: config,
-		PluginStore: pluginStore,
-		startupDone: make(chan struct{}),
+		configStore:    config,
+		PluginStore:    pluginStore,
+		startupDone:    make(chan struct{}),
+		containerSizes: sizecache.New(diskUsageCacheMaxAge, diskUsageConcurrency),
 	}
 	// Ensure the daemon is properly shutdown if there is a failure during
 	// initialization
@@ -960,6 +1019,11 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 		return nil, err
 	}
 
+	d.secretStore, err = secretstore.New(filepath.Join(config.Root, "secrets"), d.secretUsers, d.PluginStore)
+	if err != nil {
+		return nil, err
+	}
+
 	uuid, err := loadOrCreateUUID(filepath.Join(config.Root, "engine_uuid"))
 	if err != nil {
 		return nil, err
@@ -1012,6 +1076,9 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 		return nil, err
 	}
 	d.execCommands = exec.NewStore()
+	d.rootfsMounts = make(map[string]int)
+	d.unixSocketProxies = make(map[string][]*unixproxy.Proxy)
+	d.debugTargets = make(map[string]string)
 	d.idIndex = truncindex.NewTruncIndex([]string{})
 	d.statsCollector = d.newStatsCollector(1 * time.Second)
 
@@ -1034,6 +1101,7 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 		LayerStores:               layerStores,
 		MaxConcurrentDownloads:    *config.MaxConcurrentDownloads,
 		MaxConcurrentUploads:      *config.MaxConcurrentUploads,
+		MountRoot:                 filepath.Join(config.Root, "image-mounts"),
 		ReferenceStore:            rs,
 		RegistryService:           registryService,
 	})
@@ -1171,6 +1239,8 @@ func (daemon *Daemon) Shutdown() error {
 		})
 	}
 
+	daemon.releaseRootfsMounts()
+
 	if daemon.volumes != nil {
 		if err := daemon.volumes.Shutdown(); err != nil {
 			logrus.Errorf("Error shutting down volume store: %v", err)
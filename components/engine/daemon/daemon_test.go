@@ -9,6 +9,7 @@ import (
 
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
 	"github.com/docker/docker/errdefs"
 	_ "github.com/docker/docker/pkg/discovery/memory"
 	"github.com/docker/docker/pkg/idtools"
@@ -317,3 +318,26 @@ func TestFindNetworkErrorType(t *testing.T) {
 		t.Error("The FindNetwork method MUST always return an error that implements the NotFound interface and is ErrNoSuchNetwork")
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	assert.NilError(t, ValidateConfig(&config.Config{}))
+
+	conf := &config.Config{}
+	conf.LogConfig = config.LogConfig{Type: "json-file", Config: map[string]string{"max-size": "10m"}}
+	assert.NilError(t, ValidateConfig(conf))
+	conf.LogConfig.Config["unknown"] = "value"
+	assert.Check(t, is.ErrorContains(ValidateConfig(conf), "failed to set log opts"))
+
+	conf = &config.Config{}
+	conf.Mirrors = []string{"not a mirror"}
+	assert.Check(t, is.ErrorContains(ValidateConfig(conf), "invalid mirror"))
+
+	dir, err := ioutil.TempDir("", "validate-config")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	policyFile := filepath.Join(dir, "policy.json")
+	assert.NilError(t, ioutil.WriteFile(policyFile, []byte(`{"rules":[{"endpoints":["info"]}]}`), 0600))
+	conf = &config.Config{}
+	conf.AuthorizationPolicy = policyFile
+	assert.Check(t, is.ErrorContains(ValidateConfig(conf), "invalid authorization policy"))
+}
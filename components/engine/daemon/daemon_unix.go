@@ -204,6 +204,14 @@ func (daemon *Daemon) parseSecurityOpt(container *container.Container, hostConfi
 	return parseSecurityOpt(container, hostConfig)
 }
 
+// isValidSeccompRecordName returns whether name is a valid name for a profile
+// recorded with --security-opt seccomp=record:<name>. Recorded profiles are
+// written to the seccomp directory of the daemon, so names are plain file
+// names.
+func isValidSeccompRecordName(name string) bool {
+	return name != "" && name != "." && name != ".." && filepath.Base(name) == name
+}
+
 func parseSecurityOpt(container *container.Container, config *containertypes.HostConfig) error {
 	var (
 		labelOpts []string
@@ -237,6 +245,9 @@ func parseSecurityOpt(container *container.Container, config *containertypes.Hos
 		case "apparmor":
 			container.AppArmorProfile = con[1]
 		case "seccomp":
+			if strings.HasPrefix(con[1], "record:") && !isValidSeccompRecordName(strings.TrimPrefix(con[1], "record:")) {
+				return fmt.Errorf("invalid --security-opt: %q: the recorded seccomp profile must be a file name, it is written to the seccomp directory of the daemon", opt)
+			}
 			container.SeccompProfile = con[1]
 		case "no-new-privileges":
 			noNewPrivileges, err := strconv.ParseBool(con[1])
@@ -382,8 +393,8 @@ func (daemon *Daemon) adaptContainerSettings(hostConfig *containertypes.HostConf
 }
 
 // adaptSharedNamespaceContainer replaces container name with its ID in hostConfig.
-// To be more precisely, it modifies `container:name` to `container:ID` of PidMode, IpcMode
-// and NetworkMode.
+// To be more precisely, it modifies `container:name` to `container:ID` of PidMode, IpcMode,
+// UTSMode and NetworkMode.
 //
 // When a container shares its namespace with another container, use ID can keep the namespace
 // sharing connection between the two containers even the another container is renamed.
@@ -403,6 +414,12 @@ func adaptSharedNamespaceContainer(daemon containerGetter, hostConfig *container
 			hostConfig.IpcMode = containertypes.IpcMode(containerPrefix + c.ID)
 		}
 	}
+	if hostConfig.UTSMode.IsContainer() {
+		utsContainer := hostConfig.UTSMode.Container()
+		if c, err := daemon.GetContainer(utsContainer); err == nil {
+			hostConfig.UTSMode = containertypes.UTSMode(containerPrefix + c.ID)
+		}
+	}
 	if hostConfig.NetworkMode.IsContainer() {
 		netContainer := hostConfig.NetworkMode.ConnectedContainer()
 		if c, err := daemon.GetContainer(netContainer); err == nil {
@@ -651,8 +668,20 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 		warnings = append(warnings, "Published ports are discarded when using host network mode")
 	}
 
+	if !hostConfig.UsernsMode.Valid() {
+		return warnings, fmt.Errorf("invalid userns mode: %v", hostConfig.UsernsMode)
+	}
+	if hostConfig.UsernsMode.IsAuto() {
+		if daemon.configStore.RemappedRoot != "" {
+			return warnings, fmt.Errorf("--userns=auto cannot be used when the daemon is started with --userns-remap")
+		}
+		if daemon.configStore.Rootless {
+			return warnings, fmt.Errorf("--userns=auto is not supported in rootless mode")
+		}
+	}
+
 	// check for various conflicting options with user namespaces
-	if daemon.configStore.RemappedRoot != "" && hostConfig.UsernsMode.IsPrivate() {
+	if (daemon.configStore.RemappedRoot != "" && hostConfig.UsernsMode.IsPrivate()) || hostConfig.UsernsMode.IsAuto() {
 		if hostConfig.Privileged {
 			return warnings, fmt.Errorf("privileged mode is incompatible with user namespaces.  You must run the container in the host namespace when running privileged mode")
 		}
@@ -677,6 +706,10 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 		return warnings, fmt.Errorf("Unknown runtime specified %s", hostConfig.Runtime)
 	}
 
+	if err := verifyOCIHooks(hostConfig.OCIHooks, daemon.configStore.AllowedOCIHooks); err != nil {
+		return warnings, err
+	}
+
 	parser := volumemounts.NewParser(runtime.GOOS)
 	for dest := range hostConfig.Tmpfs {
 		if err := parser.ValidateTmpfsMountDestination(dest); err != nil {
@@ -735,6 +768,14 @@ func (daemon *Daemon) initRuntimes(runtimes map[string]types.Runtime) (err error
 	}()
 
 	for name, rt := range runtimes {
+		if rt.Type != "" {
+			rt.ShimConfig, err = getShimConfig(name, rt)
+			if err != nil {
+				return err
+			}
+			runtimes[name] = rt
+			continue
+		}
 		if len(rt.Args) == 0 {
 			continue
 		}
@@ -1194,6 +1235,15 @@ func setupRemappedRoot(config *config.Config) (*idtools.IdentityMapping, error)
 	return &idtools.IdentityMapping{}, nil
 }
 
+// daemonDataRoot returns the directory of the data of the daemon set up by
+// setupDaemonRoot, which is a subdirectory of the root with user namespaces.
+func daemonDataRoot(config *config.Config, rootIdentity idtools.Identity) string {
+	if config.RemappedRoot != "" {
+		return filepath.Join(config.Root, fmt.Sprintf("%d.%d", rootIdentity.UID, rootIdentity.GID))
+	}
+	return config.Root
+}
+
 func setupDaemonRoot(config *config.Config, rootDir string, rootIdentity idtools.Identity) error {
 	config.Root = rootDir
 	// the docker root metadata directory needs to have execute permissions for all users (g+x,o+x)
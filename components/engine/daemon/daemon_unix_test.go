@@ -201,6 +201,21 @@ func TestParseSecurityOpt(t *testing.T) {
 		t.Fatalf("Unexpected SeccompProfile, expected: %q, got %q", sp, container.SeccompProfile)
 	}
 
+	// test seccomp record
+	config.SecurityOpt = []string{"seccomp=record:seccomp_test.json"}
+	if err := parseSecurityOpt(container, config); err != nil {
+		t.Fatalf("Unexpected parseSecurityOpt error: %v", err)
+	}
+	if container.SeccompProfile != "record:seccomp_test.json" {
+		t.Fatalf("Unexpected SeccompProfile, expected: %q, got %q", "record:seccomp_test.json", container.SeccompProfile)
+	}
+	for _, name := range []string{sp, "../seccomp_test.json", "..", ""} {
+		config.SecurityOpt = []string{"seccomp=record:" + name}
+		if err := parseSecurityOpt(container, config); err == nil {
+			t.Fatalf("Expected parseSecurityOpt error for record name %q, got nil", name)
+		}
+	}
+
 	// test valid label
 	config.SecurityOpt = []string{"label=user:USER"}
 	if err := parseSecurityOpt(container, config); err != nil {
@@ -207,6 +207,10 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 		return warnings, fmt.Errorf("Windows client operating systems earlier than version 1809 can only run Hyper-V containers")
 	}
 
+	if hostConfig.OCIHooks != nil {
+		return warnings, fmt.Errorf("OCI hooks are not supported on Windows")
+	}
+
 	w, err := verifyPlatformContainerResources(&hostConfig.Resources, hyperv)
 	warnings = append(warnings, w...)
 	return warnings, err
@@ -478,6 +482,12 @@ func setupRemappedRoot(config *config.Config) (*idtools.IdentityMapping, error)
 	return &idtools.IdentityMapping{}, nil
 }
 
+// daemonDataRoot returns the directory of the data of the daemon set up by
+// setupDaemonRoot.
+func daemonDataRoot(config *config.Config, rootIdentity idtools.Identity) string {
+	return config.Root
+}
+
 func setupDaemonRoot(config *config.Config, rootDir string, rootIdentity idtools.Identity) error {
 	config.Root = rootDir
 	// Create the root directory if it doesn't exists
@@ -669,5 +679,9 @@ func (daemon *Daemon) initRuntimes(_ map[string]types.Runtime) error {
 	return nil
 }
 
+func validateRuntimes(_ *config.Config) error {
+	return nil
+}
+
 func setupResolvConf(config *config.Config) {
 }
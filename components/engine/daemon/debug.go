@@ -0,0 +1,166 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	mounttypes "github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultDebugImage is the image of the debug containers, when the
+	// debug configuration does not specify it.
+	defaultDebugImage = "busybox"
+	// defaultDebugRootfsPath is the path at which the root filesystem of the
+	// debugged container is mounted in the debug containers, when the debug
+	// configuration does not specify it.
+	defaultDebugRootfsPath = "/target"
+	// debugTargetLabel is the label of the debug containers set to the ID of
+	// the container they debug.
+	debugTargetLabel = "com.docker.debug.target"
+)
+
+// ContainerDebug creates an ephemeral debug container for a running
+// container. The debug container joins the PID, network, IPC and UTS
+// namespaces of the container, and the root filesystem of the container is
+// mounted in it. The root filesystem of the container is mounted when the
+// debug container starts, and released when it stops. The debug container is
+// removed when it exits.
+func (daemon *Daemon) ContainerDebug(name string, config *containertypes.DebugConfig) (containertypes.ContainerCreateCreatedBody, error) {
+	if runtime.GOOS == "windows" {
+		return containertypes.ContainerCreateCreatedBody{}, errdefs.NotImplemented(errors.New("debug containers are not supported on Windows"))
+	}
+
+	image := config.Image
+	if image == "" {
+		image = defaultDebugImage
+	}
+	rootfsPath := config.RootfsPath
+	if rootfsPath == "" {
+		rootfsPath = defaultDebugRootfsPath
+	}
+	if !path.IsAbs(rootfsPath) || path.Clean(rootfsPath) == "/" {
+		return containertypes.ContainerCreateCreatedBody{}, errdefs.InvalidParameter(fmt.Errorf("invalid rootfs path %q: the path must be absolute, and not /", rootfsPath))
+	}
+
+	target, err := daemon.GetContainer(name)
+	if err != nil {
+		return containertypes.ContainerCreateCreatedBody{}, err
+	}
+
+	if !target.IsRunning() {
+		return containertypes.ContainerCreateCreatedBody{}, errNotRunning(target.ID)
+	}
+	if target.IsRestarting() {
+		return containertypes.ContainerCreateCreatedBody{}, errContainerIsRestarting(target.ID)
+	}
+
+	var warnings []string
+	ipcMode := containertypes.IpcMode("container:" + target.ID)
+	if err := daemon.checkDebugIpcMode(target); err != nil {
+		ipcMode = ""
+		warnings = append(warnings, fmt.Sprintf("The debug container does not join the IPC namespace of the container: %v", err))
+	}
+
+	ccr, err := daemon.containerCreate(createOpts{
+		params: types.ContainerCreateConfig{
+			Config: &containertypes.Config{
+				Image:        image,
+				Cmd:          config.Cmd,
+				Env:          config.Env,
+				User:         config.User,
+				Tty:          config.Tty,
+				AttachStdin:  true,
+				AttachStdout: true,
+				AttachStderr: true,
+				OpenStdin:    true,
+				StdinOnce:    true,
+				Labels:       map[string]string{debugTargetLabel: target.ID},
+			},
+			HostConfig: &containertypes.HostConfig{
+				AutoRemove:  true,
+				NetworkMode: containertypes.NetworkMode("container:" + target.ID),
+				PidMode:     containertypes.PidMode("container:" + target.ID),
+				IpcMode:     ipcMode,
+				UTSMode:     containertypes.UTSMode("container:" + target.ID),
+				CapAdd:      config.CapAdd,
+				Mounts: []mounttypes.Mount{{
+					Type:   mounttypes.TypeBind,
+					Source: target.BaseFS.Path(),
+					Target: rootfsPath,
+				}},
+			},
+		},
+		debugTarget: target.ID,
+	})
+	if err != nil {
+		return ccr, err
+	}
+
+	ccr.Warnings = append(warnings, ccr.Warnings...)
+	return ccr, nil
+}
+
+// mountDebugTarget mounts the root filesystem of the container debugged by a
+// debug container which starts, or which is restored running. It is released
+// by releaseDebugTarget when the debug container stops. The debug container
+// must be locked.
+func (daemon *Daemon) mountDebugTarget(c *container.Container) error {
+	if c.DebugTarget == "" {
+		return nil
+	}
+	daemon.debugTargetsMu.Lock()
+	_, mounted := daemon.debugTargets[c.ID]
+	daemon.debugTargetsMu.Unlock()
+	if mounted {
+		return nil
+	}
+
+	target, err := daemon.GetContainer(c.DebugTarget)
+	if err != nil {
+		return errors.Wrap(err, "cannot start debug container")
+	}
+	if !target.IsRunning() {
+		return errNotRunning(target.ID)
+	}
+	target.Lock()
+	err = daemon.mountRootfs(target)
+	target.Unlock()
+	if err != nil {
+		return err
+	}
+
+	daemon.debugTargetsMu.Lock()
+	daemon.debugTargets[c.ID] = target.ID
+	daemon.debugTargetsMu.Unlock()
+	return nil
+}
+
+// releaseDebugTarget releases the root filesystem of the container debugged
+// by a debug container which stops.
+func (daemon *Daemon) releaseDebugTarget(c *container.Container) {
+	daemon.debugTargetsMu.Lock()
+	targetID, ok := daemon.debugTargets[c.ID]
+	delete(daemon.debugTargets, c.ID)
+	daemon.debugTargetsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	target := daemon.containers.Get(targetID)
+	if target == nil {
+		return
+	}
+	target.Lock()
+	defer target.Unlock()
+	if err := daemon.releaseRootfs(target, false); err != nil {
+		logrus.WithError(err).WithField("container", targetID).Warn("Failed to release the root filesystem of the debugged container")
+	}
+}
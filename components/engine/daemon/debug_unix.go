@@ -0,0 +1,12 @@
+// +build linux freebsd
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import "github.com/docker/docker/container"
+
+// checkDebugIpcMode checks that the debug containers of a container can join
+// its IPC namespace.
+func (daemon *Daemon) checkDebugIpcMode(target *container.Container) error {
+	_, err := daemon.getIpcContainer(target.ID)
+	return err
+}
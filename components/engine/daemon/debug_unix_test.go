@@ -0,0 +1,37 @@
+// +build linux freebsd
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestContainerDebugInvalidRootfsPath(t *testing.T) {
+	d := &Daemon{}
+	for _, p := range []string{"target", "/", "/target/.."} {
+		_, err := d.ContainerDebug("web", &containertypes.DebugConfig{RootfsPath: p})
+		assert.Check(t, errdefs.IsInvalidParameter(err), p)
+		assert.Check(t, is.ErrorContains(err, "the path must be absolute, and not /"), p)
+	}
+}
+
+func TestContainerDebugNotRunning(t *testing.T) {
+	web := newDependencyTestContainer("aaaaaaaaaaaa", "web")
+	d := newDependencyTestDaemon(t, web)
+
+	_, err := d.ContainerDebug("web", &containertypes.DebugConfig{})
+	assert.Check(t, errdefs.IsConflict(err))
+	assert.Check(t, is.ErrorContains(err, "is not running"))
+
+	web.SetRunning(1234, true)
+	web.SetRestarting(&container.ExitStatus{})
+	_, err = d.ContainerDebug("web", &containertypes.DebugConfig{})
+	assert.Check(t, errdefs.IsConflict(err))
+	assert.Check(t, is.ErrorContains(err, "is restarting"))
+}
@@ -0,0 +1,11 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"errors"
+
+	"github.com/docker/docker/container"
+)
+
+func (daemon *Daemon) checkDebugIpcMode(target *container.Container) error {
+	return errors.New("not supported on Windows")
+}
@@ -77,6 +77,10 @@ func (daemon *Daemon) rmLink(container *container.Container, name string) error
 // cleanupContainer unregisters a container from the daemon, stops stats
 // collection and cleanly removes contents and metadata from the filesystem.
 func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemove, removeVolume bool) (err error) {
+	if daemon.isRootfsMounted(container.ID) {
+		err := fmt.Errorf("You cannot remove container %s while its filesystem is mounted. Unmount it before attempting removal", container.ID)
+		return errdefs.Conflict(err)
+	}
 	if container.IsRunning() {
 		if !forceRemove {
 			state := container.StateString()
@@ -126,6 +130,7 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemo
 		}
 		container.RWLayer = nil
 	}
+	daemon.containerSizes.Invalidate(container.ID)
 
 	if err := system.EnsureRemoveAll(container.Root); err != nil {
 		e := errors.Wrapf(err, "unable to remove filesystem for %s", container.ID)
@@ -141,6 +146,7 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemo
 	if e := daemon.removeMountPoints(container, removeVolume); e != nil {
 		logrus.Error(e)
 	}
+	daemon.releaseIDMapping(container)
 	for _, name := range linkNames {
 		daemon.releaseName(name)
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/sizecache"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 )
@@ -17,8 +18,9 @@ func newDaemonWithTmpRoot(t *testing.T) (*Daemon, func()) {
 	tmp, err := ioutil.TempDir("", "docker-daemon-unix-test-")
 	assert.NilError(t, err)
 	d := &Daemon{
-		repository: tmp,
-		root:       tmp,
+		repository:     tmp,
+		root:           tmp,
+		containerSizes: sizecache.New(diskUsageCacheMaxAge, diskUsageConcurrency),
 	}
 	d.containers = container.NewMemoryStore()
 	return d, func() { os.RemoveAll(tmp) }
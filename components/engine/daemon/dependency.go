@@ -1,6 +1,10 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"strings"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/secretstore"
 	"github.com/docker/swarmkit/agent/exec"
 )
 
@@ -15,3 +19,20 @@ func (daemon *Daemon) SetContainerDependencyStore(name string, store exec.Depend
 
 	return nil
 }
+
+// dependencyStore returns the store from which the secrets and the configs of
+// a container are read: the store set for its swarm task, if it is one, or the
+// local store of the daemon. The details of the container are passed to the
+// secret provider plugins of its secrets.
+func (daemon *Daemon) dependencyStore(c *container.Container) exec.DependencyGetter {
+	if c.DependencyStore != nil {
+		return c.DependencyStore
+	}
+	return daemon.secretStore.DependencyGetter(secretstore.Container{
+		ID:       c.ID,
+		Name:     strings.TrimPrefix(c.Name, "/"),
+		Hostname: c.Config.Hostname,
+		Image:    c.Config.Image,
+		Labels:   c.Config.Labels,
+	})
+}
@@ -0,0 +1,226 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// dependencyTimeout is the maximum time a container waits for its
+// dependencies to meet their condition before it is started.
+const dependencyTimeout = 5 * time.Minute
+
+// validateDependencies checks the syntax of the dependencies of a container.
+func validateDependencies(deps []containertypes.Dependency) error {
+	seen := make(map[string]bool)
+	for _, d := range deps {
+		if d.Container == "" {
+			return errors.New("the container of a dependency must be set")
+		}
+		if !d.Condition.Valid() {
+			return errors.Errorf("invalid condition %q for dependency %s: the condition must be %q or %q", d.Condition, d.Container, containertypes.DependencyConditionStarted, containertypes.DependencyConditionHealthy)
+		}
+		if seen[d.Container] {
+			return errors.Errorf("duplicate dependency %s", d.Container)
+		}
+		seen[d.Container] = true
+	}
+	return nil
+}
+
+// verifyDependencies checks that the dependencies of a new container exist,
+// that the dependencies which must be healthy have a health check, and that
+// they do not depend on the container.
+func (daemon *Daemon) verifyDependencies(c *container.Container) error {
+	for _, d := range c.HostConfig.DependsOn {
+		dep, err := daemon.GetContainer(d.Container)
+		if err != nil {
+			return errdefs.InvalidParameter(errors.Wrapf(err, "invalid dependency %s", d.Container))
+		}
+		if dep.ID == c.ID {
+			return errdefs.InvalidParameter(errors.New("a container cannot depend on itself"))
+		}
+		if d.Condition == containertypes.DependencyConditionHealthy && getProbe(dep) == nil {
+			return errdefs.InvalidParameter(errors.Errorf("dependency %s cannot be healthy: it has no health check", d.Container))
+		}
+	}
+	return daemon.checkDependencyCycle(c)
+}
+
+// checkDependencyCycle returns an error if c depends on itself through its
+// dependencies. The dependencies which do not exist are ignored.
+func (daemon *Daemon) checkDependencyCycle(c *container.Container) error {
+	visited := make(map[string]bool)
+	var visit func(cur *container.Container, path []string) error
+	visit = func(cur *container.Container, path []string) error {
+		for _, d := range cur.HostConfig.DependsOn {
+			dep, err := daemon.GetContainer(d.Container)
+			if err != nil {
+				// c may not be registered yet when it is created.
+				if !refersTo(d.Container, c) {
+					continue
+				}
+				dep = c
+			}
+			depPath := append(path[:len(path):len(path)], strings.TrimPrefix(dep.Name, "/"))
+			if dep.ID == c.ID {
+				return errdefs.InvalidParameter(errors.Errorf("dependency cycle: %s", strings.Join(depPath, " -> ")))
+			}
+			if visited[dep.ID] {
+				continue
+			}
+			visited[dep.ID] = true
+			if err := visit(dep, depPath); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(c, []string{strings.TrimPrefix(c.Name, "/")})
+}
+
+// refersTo returns whether ref is the name or the ID of c.
+func refersTo(ref string, c *container.Container) bool {
+	return "/"+strings.TrimPrefix(ref, "/") == c.Name || ref == c.ID
+}
+
+// waitForDependencies waits until the dependencies of a container meet their
+// condition, or until ctx is done. The dependencies which have a notifier in
+// pending are waited for until the daemon attempted to start them, as on
+// boot.
+func (daemon *Daemon) waitForDependencies(ctx context.Context, c *container.Container, pending map[*container.Container]chan struct{}) error {
+	if len(c.HostConfig.DependsOn) == 0 {
+		return nil
+	}
+	if err := daemon.checkDependencyCycle(c); err != nil {
+		daemon.logDependencyEvent(c, "dependency_failed", containertypes.Dependency{}, err)
+		return err
+	}
+
+	deadline := time.Now().Add(dependencyTimeout)
+	for _, d := range c.HostConfig.DependsOn {
+		if d.Condition == "" {
+			d.Condition = containertypes.DependencyConditionStarted
+		}
+		err := daemon.waitForDependency(ctx, c, d, pending, deadline)
+		if err != nil {
+			daemon.logDependencyEvent(c, "dependency_failed", d, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForDependency waits until a dependency meets its condition. The state
+// of the dependency is checked again on each of its events, such as its
+// start, its death or the change of its health status.
+func (daemon *Daemon) waitForDependency(ctx context.Context, c *container.Container, d containertypes.Dependency, pending map[*container.Container]chan struct{}, deadline time.Time) error {
+	dep, err := daemon.GetContainer(d.Container)
+	if err != nil {
+		return errdefs.InvalidParameter(errors.Wrapf(err, "cannot start container %s: dependency %s", c.ID, d.Container))
+	}
+
+	timeout := time.NewTimer(time.Until(deadline))
+	defer timeout.Stop()
+	timedOut := func() error {
+		return errdefs.Unavailable(errors.Errorf("cannot start container %s: timed out waiting for dependency %s to be %s", c.ID, d.Container, d.Condition))
+	}
+
+	if notifier, ok := pending[dep]; ok {
+		select {
+		case <-notifier:
+		case <-timeout.C:
+			return timedOut()
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "cannot start container %s", c.ID)
+		}
+	}
+
+	// Subscribe to the events of the dependency before checking its state,
+	// so that no change of its state is missed.
+	ef := events.NewFilter(filters.NewArgs(
+		filters.Arg("type", "container"),
+		filters.Arg("container", dep.ID),
+	))
+	_, l := daemon.EventsService.SubscribeTopic(time.Time{}, time.Time{}, ef)
+	defer daemon.EventsService.Evict(l)
+
+	blocked := false
+	for {
+		ready, err := dependencyReady(dep, d.Condition)
+		if err != nil {
+			return errors.Wrapf(err, "cannot start container %s", c.ID)
+		}
+		if ready {
+			return nil
+		}
+		if !blocked {
+			blocked = true
+			logrus.Infof("Container %s is waiting for dependency %s to be %s", c.ID, d.Container, d.Condition)
+			daemon.logDependencyEvent(c, "dependency_wait", d, nil)
+		}
+		select {
+		case <-l:
+		case <-timeout.C:
+			return timedOut()
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "cannot start container %s", c.ID)
+		}
+	}
+}
+
+// startAfterDependencies starts a container with a restart policy on boot,
+// once its dependencies meet their condition. The notifier of the container
+// is closed once it was started, or failed to start.
+func (daemon *Daemon) startAfterDependencies(c *container.Container, notifier chan struct{}, pending map[*container.Container]chan struct{}) {
+	defer close(notifier)
+
+	if err := daemon.waitForDependencies(context.Background(), c, pending); err != nil {
+		logrus.Errorf("Failed to start container %s: %s", c.ID, err)
+		return
+	}
+	// Make sure networks are available before starting
+	daemon.waitForNetworks(c)
+	if err := daemon.containerStart(c, "", "", true); err != nil {
+		logrus.Errorf("Failed to start container %s: %s", c.ID, err)
+	}
+}
+
+// dependencyReady returns whether a dependency meets a condition, and an
+// error if it cannot meet it.
+func dependencyReady(dep *container.Container, condition containertypes.DependencyCondition) (bool, error) {
+	dep.Lock()
+	defer dep.Unlock()
+
+	if !dep.Running && !dep.Restarting {
+		return false, errdefs.Conflict(errors.Errorf("dependency %s is not running", strings.TrimPrefix(dep.Name, "/")))
+	}
+	if condition != containertypes.DependencyConditionHealthy {
+		return dep.Running, nil
+	}
+	if getProbe(dep) == nil {
+		return false, errdefs.Conflict(errors.Errorf("dependency %s cannot be healthy: it has no health check", strings.TrimPrefix(dep.Name, "/")))
+	}
+	return dep.Running && dep.Health != nil && dep.Health.Status() == types.Healthy, nil
+}
+
+func (daemon *Daemon) logDependencyEvent(c *container.Container, action string, d containertypes.Dependency, err error) {
+	attributes := map[string]string{}
+	if d.Container != "" {
+		attributes["dependency"] = d.Container
+		attributes["condition"] = string(d.Condition)
+	}
+	if err != nil {
+		attributes["error"] = err.Error()
+	}
+	daemon.LogContainerEventWithAttributes(c, action, attributes)
+}
@@ -0,0 +1,160 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/truncindex"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newDependencyTestDaemon(t *testing.T, containers ...*container.Container) *Daemon {
+	containersReplica, err := container.NewViewDB()
+	assert.NilError(t, err)
+	daemon := &Daemon{
+		containers:        container.NewMemoryStore(),
+		containersReplica: containersReplica,
+		idIndex:           truncindex.NewTruncIndex([]string{}),
+		EventsService:     events.New(),
+	}
+	for _, c := range containers {
+		daemon.containers.Add(c.ID, c)
+		assert.NilError(t, daemon.idIndex.Add(c.ID))
+		_, err := daemon.reserveName(c.ID, c.Name)
+		assert.NilError(t, err)
+	}
+	return daemon
+}
+
+func newDependencyTestContainer(id, name string, deps ...string) *container.Container {
+	c := container.NewBaseContainer(id, "")
+	c.Name = "/" + name
+	c.Config = &containertypes.Config{}
+	c.HostConfig = &containertypes.HostConfig{}
+	for _, d := range deps {
+		c.HostConfig.DependsOn = append(c.HostConfig.DependsOn, containertypes.Dependency{Container: d})
+	}
+	return c
+}
+
+func TestValidateDependencies(t *testing.T) {
+	assert.Check(t, validateDependencies([]containertypes.Dependency{
+		{Container: "db", Condition: containertypes.DependencyConditionHealthy},
+		{Container: "cache"},
+	}))
+	assert.Check(t, is.ErrorContains(validateDependencies([]containertypes.Dependency{{}}), "must be set"))
+	assert.Check(t, is.ErrorContains(validateDependencies([]containertypes.Dependency{
+		{Container: "db", Condition: "ready"},
+	}), `invalid condition "ready"`))
+	assert.Check(t, is.ErrorContains(validateDependencies([]containertypes.Dependency{
+		{Container: "db"}, {Container: "db", Condition: containertypes.DependencyConditionHealthy},
+	}), "duplicate dependency db"))
+}
+
+func TestCheckDependencyCycle(t *testing.T) {
+	web := newDependencyTestContainer("aaaaaaaaaaaa", "web", "api")
+	api := newDependencyTestContainer("bbbbbbbbbbbb", "api", "db")
+	db := newDependencyTestContainer("cccccccccccc", "db", "web")
+	worker := newDependencyTestContainer("dddddddddddd", "worker", "api", "missing")
+	daemon := newDependencyTestDaemon(t, web, api, db, worker)
+
+	err := daemon.checkDependencyCycle(web)
+	assert.Check(t, is.ErrorContains(err, "dependency cycle: web -> api -> db -> web"))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	// worker depends on a cycle, but is not part of it.
+	assert.Check(t, daemon.checkDependencyCycle(worker))
+}
+
+func TestVerifyDependenciesCycleOnCreate(t *testing.T) {
+	// app depends on a db container which was removed, and a new db
+	// container is created depending on app.
+	app := newDependencyTestContainer("aaaaaaaaaaaa", "app", "db")
+	daemon := newDependencyTestDaemon(t, app)
+
+	db := newDependencyTestContainer("bbbbbbbbbbbb", "db", "app")
+	assert.Check(t, is.ErrorContains(daemon.verifyDependencies(db), "dependency cycle: db -> app -> db"))
+
+	db = newDependencyTestContainer("bbbbbbbbbbbb", "db", "missing")
+	assert.Check(t, is.ErrorContains(daemon.verifyDependencies(db), "invalid dependency missing"))
+
+	db = newDependencyTestContainer("bbbbbbbbbbbb", "db")
+	db.HostConfig.DependsOn = []containertypes.Dependency{{Container: "app", Condition: containertypes.DependencyConditionHealthy}}
+	assert.Check(t, is.ErrorContains(daemon.verifyDependencies(db), "it has no health check"))
+}
+
+func TestDependencyReady(t *testing.T) {
+	db := newDependencyTestContainer("aaaaaaaaaaaa", "db")
+
+	_, err := dependencyReady(db, containertypes.DependencyConditionStarted)
+	assert.Check(t, is.ErrorContains(err, "dependency db is not running"))
+	assert.Check(t, errdefs.IsConflict(err))
+
+	db.Running = true
+	ready, err := dependencyReady(db, containertypes.DependencyConditionStarted)
+	assert.NilError(t, err)
+	assert.Check(t, ready)
+
+	_, err = dependencyReady(db, containertypes.DependencyConditionHealthy)
+	assert.Check(t, is.ErrorContains(err, "it has no health check"))
+
+	db.Config.Healthcheck = &containertypes.HealthConfig{Test: []string{"CMD", "true"}}
+	db.Health = &container.Health{}
+	db.Health.SetStatus(types.Starting)
+	ready, err = dependencyReady(db, containertypes.DependencyConditionHealthy)
+	assert.NilError(t, err)
+	assert.Check(t, !ready)
+
+	db.Health.SetStatus(types.Healthy)
+	ready, err = dependencyReady(db, containertypes.DependencyConditionHealthy)
+	assert.NilError(t, err)
+	assert.Check(t, ready)
+}
+
+func TestWaitForDependency(t *testing.T) {
+	db := newDependencyTestContainer("aaaaaaaaaaaa", "db")
+	db.Running = true
+	db.Config.Healthcheck = &containertypes.HealthConfig{Test: []string{"CMD", "true"}}
+	db.Health = &container.Health{}
+	db.Health.SetStatus(types.Starting)
+	web := newDependencyTestContainer("bbbbbbbbbbbb", "web")
+	daemon := newDependencyTestDaemon(t, db, web)
+	dep := containertypes.Dependency{Container: "db", Condition: containertypes.DependencyConditionHealthy}
+	deadline := time.Now().Add(time.Minute)
+
+	// The dependency is checked again when its health status changes
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- daemon.waitForDependency(context.Background(), web, dep, nil, deadline)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	db.Health.SetStatus(types.Healthy)
+	daemon.LogContainerEvent(db, "health_status: "+types.Healthy)
+	select {
+	case err := <-errCh:
+		assert.NilError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the dependency")
+	}
+
+	// The wait ends when the context is cancelled
+	db.Health.SetStatus(types.Unhealthy)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		errCh <- daemon.waitForDependency(ctx, web, dep, nil, deadline)
+	}()
+	cancel()
+	select {
+	case err := <-errCh:
+		assert.Check(t, is.ErrorContains(err, context.Canceled.Error()))
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the cancellation")
+	}
+}
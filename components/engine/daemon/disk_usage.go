@@ -3,23 +3,34 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"context"
 	"fmt"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	// diskUsageCacheMaxAge is the maximum age of a cached container size
+	// before it is recalculated.
+	diskUsageCacheMaxAge = 5 * time.Minute
+	// diskUsageConcurrency is the maximum number of container sizes that
+	// are calculated at the same time.
+	diskUsageConcurrency = 4
 )
 
 // SystemDiskUsage returns information about the daemon data disk usage
-func (daemon *Daemon) SystemDiskUsage(ctx context.Context) (*types.DiskUsage, error) {
+func (daemon *Daemon) SystemDiskUsage(ctx context.Context, opts types.DiskUsageOptions) (*types.DiskUsage, error) {
 	if !atomic.CompareAndSwapInt32(&daemon.diskUsageRunning, 0, 1) {
 		return nil, fmt.Errorf("a disk usage operation is already running")
 	}
 	defer atomic.StoreInt32(&daemon.diskUsageRunning, 0)
 
-	// Retrieve container list
+	// Retrieve container list; sizes are filled in below from the cache
 	allContainers, err := daemon.Containers(&types.ContainerListOptions{
-		Size: true,
-		All:  true,
+		All: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve container list: %v", err)
@@ -31,7 +42,11 @@ func (daemon *Daemon) SystemDiskUsage(ctx context.Context) (*types.DiskUsage, er
 		return nil, fmt.Errorf("failed to retrieve image list: %v", err)
 	}
 
-	localVolumes, err := daemon.volumes.LocalVolumesSize(ctx)
+	if err := daemon.containersDiskUsage(ctx, allContainers, allImages, opts.Fresh); err != nil {
+		return nil, err
+	}
+
+	localVolumes, err := daemon.volumes.LocalVolumesSize(ctx, opts.Fresh)
 	if err != nil {
 		return nil, err
 	}
@@ -48,3 +63,59 @@ func (daemon *Daemon) SystemDiskUsage(ctx context.Context) (*types.DiskUsage, er
 		Images:     allImages,
 	}, nil
 }
+
+// containersDiskUsage sets the size of the RW layer and root filesystem of
+// each container. RW layer sizes are cached until the container exits or is
+// removed, or until the cached size expires; set fresh to recalculate them.
+// At most diskUsageConcurrency sizes are calculated at the same time.
+func (daemon *Daemon) containersDiskUsage(ctx context.Context, containers []*types.Container, images []*types.ImageSummary, fresh bool) error {
+	imageSizes := make(map[string]int64, len(images))
+	for _, img := range images {
+		imageSizes[img.ID] = img.Size
+	}
+	for _, c := range containers {
+		if _, ok := imageSizes[c.ImageID]; !ok {
+			// the container's image is not a top-level image
+			var imageSize int64
+			if img, err := daemon.imageService.LookupImage(c.ImageID); err == nil {
+				imageSize = img.Size
+			}
+			imageSizes[c.ImageID] = imageSize
+		}
+	}
+
+	var (
+		wg    sync.WaitGroup
+		errMu sync.Mutex
+		err   error
+	)
+	sem := semaphore.NewWeighted(diskUsageConcurrency)
+	for _, c := range containers {
+		if err := sem.Acquire(ctx, 1); err != nil {
+			wg.Wait()
+			return err
+		}
+		wg.Add(1)
+		go func(c *types.Container) {
+			defer wg.Done()
+			defer sem.Release(1)
+			sizeRw, sizeErr := daemon.containerSizes.Get(ctx, c.ID, fresh, func(ctx context.Context) (int64, error) {
+				sizeRw, _ := daemon.imageService.GetContainerLayerSize(c.ID)
+				return sizeRw, nil
+			})
+			if sizeErr != nil {
+				errMu.Lock()
+				err = sizeErr
+				errMu.Unlock()
+				return
+			}
+			c.SizeRw = sizeRw
+			c.SizeRootFs = imageSizes[c.ImageID]
+			if sizeRw != -1 {
+				c.SizeRootFs += sizeRw
+			}
+		}(c)
+	}
+	wg.Wait()
+	return err
+}
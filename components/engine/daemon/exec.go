@@ -13,7 +13,9 @@ import (
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/container/stream"
 	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/authorization"
 	"github.com/docker/docker/pkg/pools"
 	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/docker/pkg/term"
@@ -124,6 +126,19 @@ func (d *Daemon) ContainerExecCreate(name string, config *types.ExecConfig) (str
 	execConfig.Privileged = config.Privileged
 	execConfig.User = config.User
 	execConfig.WorkingDir = config.WorkingDir
+	execConfig.Log = d.configStore.ExecLog
+	if config.Log != nil {
+		execConfig.Log = *config.Log
+	}
+	if execConfig.Log && !logger.SupportsMessageAttributes(cntr.HostConfig.LogConfig.Type) {
+		// The ID of the exec is recorded in the attributes of the messages,
+		// without which the logs of the exec cannot be told apart from the
+		// ones of the container.
+		if config.Log != nil {
+			return "", errdefs.InvalidParameter(errors.Errorf("the standard streams of execs cannot be recorded with the %s logging driver", cntr.HostConfig.LogConfig.Type))
+		}
+		execConfig.Log = false
+	}
 
 	linkedEnv, err := d.setupLinkedContainers(cntr)
 	if err != nil {
@@ -182,6 +197,8 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 	}
 	d.LogContainerEventWithAttributes(c, "exec_start: "+ec.Entrypoint+" "+strings.Join(ec.Args, " "), attributes)
 
+	logDriver := d.recordExecStart(c, ec, authorization.CallerFromContext(ctx))
+
 	defer func() {
 		if err != nil {
 			ec.Lock()
@@ -193,6 +210,7 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 			}
 			ec.Unlock()
 			c.ExecCommands.Delete(ec.ID, ec.Pid)
+			d.recordExecExit(c, ec.ID, exitCode, time.Now().UTC())
 		}
 	}()
 
@@ -211,6 +229,11 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 	if ec.OpenStderr {
 		cStderr = stderr
 	}
+	if logDriver != nil {
+		var logStreams *execLogStreams
+		cStdin, cStdout, cStderr, logStreams = teeExecStreams(logDriver, ec.ID, ec.Tty, cStdin, cStdout, cStderr)
+		defer logStreams.Close()
+	}
 
 	if ec.OpenStdin {
 		ec.StreamConfig.NewInputPipes()
@@ -34,6 +34,10 @@ type Config struct {
 	WorkingDir   string
 	Env          []string
 	Pid          int
+	Log          bool
+	// Probe is set for the execs of healthcheck probes, which are not
+	// recorded in the exec history of the container.
+	Probe bool
 }
 
 // NewConfig initializes the a new exec configuration
@@ -0,0 +1,119 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// recordExecStart records an exec in the exec history of its container, with
+// the identity of the API client which started it. It returns the log driver
+// of the container if the standard streams of the exec are recorded in the
+// logs, or nil. The execs of healthcheck probes are not recorded, so that
+// they neither push the other execs out of the history nor save the
+// container on each probe.
+func (d *Daemon) recordExecStart(c *container.Container, ec *exec.Config, caller string) logger.Logger {
+	if ec.Probe {
+		return nil
+	}
+	c.Lock()
+	defer c.Unlock()
+
+	var l logger.Logger
+	if ec.Log {
+		l = c.LogDriver
+	}
+	c.AddExecHistoryEntry(&types.ExecHistoryEntry{
+		ID:         ec.ID,
+		Cmd:        append([]string{ec.Entrypoint}, ec.Args...),
+		User:       ec.User,
+		Caller:     caller,
+		Privileged: ec.Privileged,
+		Tty:        ec.Tty,
+		Logged:     l != nil,
+		Start:      time.Now().UTC(),
+	})
+	if err := c.CheckpointTo(d.containersReplica); err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Warn("Failed to save the exec history of the container")
+	}
+	return l
+}
+
+// recordExecExit records the exit code of an exec in the exec history of its
+// container.
+func (d *Daemon) recordExecExit(c *container.Container, execID string, exitCode int, end time.Time) {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.EndExecHistoryEntry(execID, exitCode, end) {
+		return
+	}
+	if err := c.CheckpointTo(d.containersReplica); err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Warn("Failed to save the exec history of the container")
+	}
+}
+
+// execLogStreams records the standard streams of an exec in the logs of its
+// container.
+type execLogStreams struct {
+	copier  *logger.Copier
+	writers []io.Closer
+}
+
+// teeExecStreams returns standard streams of an exec which also record the
+// data which goes through them to l, with the ID of the exec in the
+// attributes of the messages. The standard input is recorded with the
+// "stdin" source, unless the exec has a TTY, which echoes the input to the
+// standard output. Nil streams are not recorded.
+func teeExecStreams(l logger.Logger, execID string, tty bool, stdin io.ReadCloser, stdout, stderr io.Writer) (io.ReadCloser, io.Writer, io.Writer, *execLogStreams) {
+	s := &execLogStreams{}
+	srcs := make(map[string]io.Reader)
+	pipe := func(source string) io.Writer {
+		r, w := io.Pipe()
+		srcs[source] = r
+		s.writers = append(s.writers, w)
+		return execLogWriter{w}
+	}
+
+	if stdin != nil && !tty {
+		stdin = struct {
+			io.Reader
+			io.Closer
+		}{io.TeeReader(stdin, pipe("stdin")), stdin}
+	}
+	if stdout != nil {
+		stdout = io.MultiWriter(stdout, pipe("stdout"))
+	}
+	if stderr != nil {
+		stderr = io.MultiWriter(stderr, pipe("stderr"))
+	}
+
+	s.copier = logger.NewCopier(srcs, logger.WithAttributes(l, []backend.LogAttr{{Key: "exec-id", Value: execID}}))
+	s.copier.Run()
+	return stdin, stdout, stderr, s
+}
+
+// Close waits for the data written to the streams to be logged.
+func (s *execLogStreams) Close() {
+	for _, w := range s.writers {
+		w.Close()
+	}
+	s.copier.Wait()
+}
+
+// execLogWriter writes to the pipe of a stream which is logged, and ignores
+// the errors so that the exec does not fail if the stream is not logged.
+type execLogWriter struct {
+	w io.Writer
+}
+
+func (w execLogWriter) Write(p []byte) (int, error) {
+	w.w.Write(p)
+	return len(p), nil
+}
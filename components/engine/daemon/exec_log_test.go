@@ -0,0 +1,116 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/backend"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/docker/daemon/logger"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type recordingLogger struct {
+	mu   sync.Mutex
+	msgs []logger.Message
+}
+
+func (l *recordingLogger) Log(msg *logger.Message) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	m := *msg
+	m.Line = append([]byte(nil), msg.Line...)
+	m.Attrs = append([]backend.LogAttr(nil), msg.Attrs...)
+	l.msgs = append(l.msgs, m)
+	logger.PutMessage(msg)
+	return nil
+}
+
+func (l *recordingLogger) Name() string { return "recording" }
+
+func (l *recordingLogger) Close() error { return nil }
+
+func TestTeeExecStreams(t *testing.T) {
+	l := &recordingLogger{}
+	var stdout bytes.Buffer
+	stdin, cStdout, cStderr, logStreams := teeExecStreams(l, "1234", false, ioutil.NopCloser(strings.NewReader("ls\n")), &stdout, nil)
+	assert.Check(t, is.Nil(cStderr))
+
+	in, err := ioutil.ReadAll(stdin)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("ls\n", string(in)))
+	_, err = cStdout.Write([]byte("bin\netc\n"))
+	assert.NilError(t, err)
+	logStreams.Close()
+
+	assert.Check(t, is.Equal("bin\netc\n", stdout.String()))
+
+	lines := map[string][]string{}
+	for _, msg := range l.msgs {
+		assert.Check(t, is.DeepEqual([]backend.LogAttr{{Key: "exec-id", Value: "1234"}}, msg.Attrs))
+		lines[msg.Source] = append(lines[msg.Source], string(msg.Line))
+	}
+	assert.Check(t, is.DeepEqual(map[string][]string{"stdin": {"ls"}, "stdout": {"bin", "etc"}}, lines))
+
+	// the exec does not fail once its streams are not logged anymore
+	_, err = cStdout.Write([]byte("usr\n"))
+	assert.NilError(t, err)
+}
+
+func TestTeeExecStreamsTTY(t *testing.T) {
+	l := &recordingLogger{}
+	var stdout bytes.Buffer
+	stdin, cStdout, _, logStreams := teeExecStreams(l, "1234", true, ioutil.NopCloser(strings.NewReader("ls\n")), &stdout, nil)
+
+	in, err := ioutil.ReadAll(stdin)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("ls\n", string(in)))
+	// the terminal echoes the input
+	_, err = cStdout.Write([]byte("ls\nbin\n"))
+	assert.NilError(t, err)
+	logStreams.Close()
+
+	var lines []string
+	for _, msg := range l.msgs {
+		assert.Check(t, is.Equal("stdout", msg.Source))
+		lines = append(lines, string(msg.Line))
+	}
+	assert.Check(t, is.DeepEqual([]string{"ls", "bin"}, lines))
+}
+
+func TestRecordExecStartSkipsProbes(t *testing.T) {
+	root, err := ioutil.TempDir("", "exec-history-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+	store, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{containersReplica: store}
+	c := container.NewBaseContainer("exec-history", root)
+	c.Config = &containertypes.Config{}
+	c.HostConfig = &containertypes.HostConfig{}
+
+	probe := exec.NewConfig()
+	probe.Entrypoint = "healthcheck"
+	probe.Probe = true
+	assert.Check(t, is.Nil(d.recordExecStart(c, probe, "")))
+	d.recordExecExit(c, probe.ID, 0, time.Now())
+	assert.Check(t, is.Len(c.GetExecHistory(), 0))
+	_, err = os.Stat(filepath.Join(root, "config.v2.json"))
+	assert.Check(t, os.IsNotExist(err), "the container must not be saved for probes")
+
+	ec := exec.NewConfig()
+	ec.Entrypoint = "sh"
+	d.recordExecStart(c, ec, "uid=1000")
+	history := c.GetExecHistory()
+	assert.Assert(t, is.Len(history, 1))
+	assert.Check(t, is.Equal(history[0].ID, ec.ID))
+}
@@ -68,8 +68,8 @@ func (daemon *Daemon) containerExport(container *container.Container) (arch io.R
 
 	archive, err := archivePath(basefs, basefs.Path(), &archive.TarOptions{
 		Compression: archive.Uncompressed,
-		UIDMaps:     daemon.idMapping.UIDs(),
-		GIDMaps:     daemon.idMapping.GIDs(),
+		UIDMaps:     daemon.containerIDMapping(container).UIDs(),
+		GIDMaps:     daemon.containerIDMapping(container).GIDs(),
 	})
 	if err != nil {
 		rwlayer.Unmount()
@@ -34,6 +34,10 @@ var (
 type CreateOpts struct {
 	MountLabel string
 	StorageOpt map[string]string
+	// IDMapping is the user namespace mapping through which the lower
+	// layers of a read-write layer are seen when it is mounted. It is only
+	// supported by drivers with the IDMappedLayers capability.
+	IDMapping *idtools.IdentityMapping
 }
 
 // InitFunc initializes the storage driver.
@@ -114,6 +118,9 @@ type Capabilities struct {
 	// for consistent tar streams, and avoid extra processing to account
 	// for potential differences (eg: the layer store's use of tar-split).
 	ReproducesExactDiffs bool
+	// Flags that this driver can mount the lower layers of a read-write
+	// layer through idmapped mounts, see CreateOpts.IDMapping.
+	IDMappedLayers bool
 }
 
 // CapabilityDriver is the interface for layered file system drivers that
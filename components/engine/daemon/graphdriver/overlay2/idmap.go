@@ -0,0 +1,211 @@
+// +build linux
+
+package overlay2 // import "github.com/docker/docker/daemon/graphdriver/overlay2"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/reexec"
+	"golang.org/x/sys/unix"
+)
+
+// The lower layers of a read-write layer which is created with an ID mapping
+// are mounted through idmapped mounts, in its "mapped" directory, so that
+// the container sees the files of its image with the IDs of its user
+// namespace while they keep the IDs of the daemon on disk. The upper layer
+// is not idmapped: the files copied up or created in it have the IDs seen in
+// the container. Overlay supports idmapped lower layers since Linux 5.19.
+const (
+	idMapFile     = "idmap"
+	mappedDirName = "mapped"
+)
+
+// The mount API system calls have the same numbers on all architectures.
+const (
+	sysOpenTree     = 428
+	sysMoveMount    = 429
+	sysMountSetattr = 442
+
+	openTreeClone       = 0x1
+	moveMountFEmptyPath = 0x4
+	mountAttrIDMap      = 0x100000
+)
+
+// mountAttr is struct mount_attr of mount_setattr(2).
+type mountAttr struct {
+	attrSet     uint64
+	attrClr     uint64
+	propagation uint64
+	usernsFd    uint64
+}
+
+func init() {
+	reexec.Register("docker-overlay-userns", usernsMain)
+}
+
+// usernsMain is the entry-point for docker-overlay-userns on re-exec. The
+// process only keeps its user namespace alive until its stdin is closed.
+func usernsMain() {
+	ioutil.ReadAll(os.Stdin)
+	os.Exit(0)
+}
+
+// idMap is the content of the idmap file of a read-write layer.
+type idMap struct {
+	UIDMaps []idtools.IDMap
+	GIDMaps []idtools.IDMap
+}
+
+func writeIDMap(dir string, m *idtools.IdentityMapping) error {
+	data, err := json.Marshal(idMap{UIDMaps: m.UIDs(), GIDMaps: m.GIDs()})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(dir, idMapFile), data, 0600)
+}
+
+// readIDMap returns the ID mapping of a read-write layer, or nil if the layer
+// has none.
+func readIDMap(dir string) (*idtools.IdentityMapping, error) {
+	data, err := ioutil.ReadFile(path.Join(dir, idMapFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var m idMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid ID mapping %s: %v", path.Join(dir, idMapFile), err)
+	}
+	return idtools.NewIDMappingsFromMaps(m.UIDMaps, m.GIDMaps), nil
+}
+
+func sysProcIDMaps(maps []idtools.IDMap) []syscall.SysProcIDMap {
+	out := make([]syscall.SysProcIDMap, len(maps))
+	for i, m := range maps {
+		out[i] = syscall.SysProcIDMap{ContainerID: m.ContainerID, HostID: m.HostID, Size: m.Size}
+	}
+	return out
+}
+
+// openUserns returns a user namespace with the ID mapping m.
+func openUserns(m *idtools.IdentityMapping) (*os.File, error) {
+	cmd := reexec.Command("docker-overlay-userns")
+	cmd.SysProcAttr.Cloneflags = unix.CLONE_NEWUSER
+	cmd.SysProcAttr.UidMappings = sysProcIDMaps(m.UIDs())
+	cmd.SysProcAttr.GidMappings = sysProcIDMaps(m.GIDs())
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		w.Close()
+		return nil, fmt.Errorf("error creating user namespace: %v", err)
+	}
+	defer func() {
+		w.Close()
+		cmd.Wait()
+	}()
+	return os.Open(fmt.Sprintf("/proc/%d/ns/user", cmd.Process.Pid))
+}
+
+// mountIDMapped mounts source at target through an idmapped mount, in which
+// the IDs of the files are those userns maps them to.
+func mountIDMapped(source, target string, userns *os.File) error {
+	src, err := unix.BytePtrFromString(source)
+	if err != nil {
+		return err
+	}
+	dst, err := unix.BytePtrFromString(target)
+	if err != nil {
+		return err
+	}
+	empty, _ := unix.BytePtrFromString("")
+	cwd := unix.AT_FDCWD
+
+	r, _, errno := unix.Syscall(sysOpenTree, uintptr(cwd), uintptr(unsafe.Pointer(src)), openTreeClone|unix.O_CLOEXEC)
+	if errno != 0 {
+		return fmt.Errorf("error cloning mount of %s: %v", source, errno)
+	}
+	fd := int(r)
+	defer unix.Close(fd)
+
+	attr := mountAttr{attrSet: mountAttrIDMap, usernsFd: uint64(userns.Fd())}
+	if _, _, errno := unix.Syscall6(sysMountSetattr, uintptr(fd), uintptr(unsafe.Pointer(empty)), unix.AT_EMPTY_PATH, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0); errno != 0 {
+		return fmt.Errorf("error creating idmapped mount of %s (idmapped mounts require Linux 5.12 or later): %v", source, errno)
+	}
+	if _, _, errno := unix.Syscall6(sysMoveMount, uintptr(fd), uintptr(unsafe.Pointer(empty)), uintptr(cwd), uintptr(unsafe.Pointer(dst)), moveMountFEmptyPath, 0); errno != 0 {
+		return fmt.Errorf("error mounting %s at %s: %v", source, target, errno)
+	}
+	return nil
+}
+
+// mountIDMappedLowers mounts the lower directories of a layer, given relative
+// to the home of the driver, through idmapped mounts in the mapped directory
+// of the layer, and returns the paths of these mounts relative to the home of
+// the driver.
+func (d *Driver) mountIDMappedLowers(id string, lowers []string, m *idtools.IdentityMapping) (_ []string, retErr error) {
+	userns, err := openUserns(m)
+	if err != nil {
+		return nil, err
+	}
+	defer userns.Close()
+
+	mappedDir := path.Join(d.dir(id), mappedDirName)
+	if err := os.Mkdir(mappedDir, 0700); err != nil && !os.IsExist(err) {
+		return nil, err
+	}
+	defer func() {
+		if retErr != nil {
+			d.unmountIDMappedLowers(id)
+		}
+	}()
+
+	mapped := make([]string, len(lowers))
+	for i, lower := range lowers {
+		name := strconv.Itoa(i)
+		if err := os.Mkdir(path.Join(mappedDir, name), 0700); err != nil && !os.IsExist(err) {
+			return nil, err
+		}
+		if err := mountIDMapped(path.Join(d.home, lower), path.Join(mappedDir, name), userns); err != nil {
+			return nil, err
+		}
+		mapped[i] = path.Join(id, mappedDirName, name)
+	}
+	return mapped, nil
+}
+
+// unmountIDMappedLowers unmounts the idmapped mounts of the lower directories
+// of a layer. The directories are removed with rmdir, which fails on the
+// directories which are still mounted.
+func (d *Driver) unmountIDMappedLowers(id string) {
+	mappedDir := path.Join(d.dir(id), mappedDirName)
+	entries, err := ioutil.ReadDir(mappedDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Debugf("Failed to list the idmapped lower directories of %s: %v", id, err)
+		}
+		return
+	}
+	for _, e := range entries {
+		p := path.Join(mappedDir, e.Name())
+		if err := unix.Unmount(p, unix.MNT_DETACH); err != nil && err != unix.EINVAL {
+			logger.Debugf("Failed to unmount %s: %v", p, err)
+		}
+		if err := unix.Rmdir(p); err != nil {
+			logger.Debugf("Failed to remove %s: %v", p, err)
+		}
+	}
+	if err := unix.Rmdir(mappedDir); err != nil {
+		logger.Debugf("Failed to remove %s: %v", mappedDir, err)
+	}
+}
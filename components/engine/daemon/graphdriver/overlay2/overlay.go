@@ -456,6 +456,12 @@ func (d *Driver) create(id, parent string, opts *graphdriver.CreateOpts) (retErr
 		}
 	}
 
+	if opts != nil && opts.IDMapping != nil {
+		if err := writeIDMap(dir, opts.IDMapping); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -586,8 +592,24 @@ func (d *Driver) Get(id, mountLabel string) (_ containerfs.ContainerFS, retErr e
 		}
 	}()
 
-	workDir := path.Join(dir, workDirName)
 	splitLowers := strings.Split(string(lowers), ":")
+	idMapping, err := readIDMap(dir)
+	if err != nil {
+		return nil, err
+	}
+	if idMapping != nil {
+		splitLowers, err = d.mountIDMappedLowers(id, splitLowers, idMapping)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if retErr != nil {
+				d.unmountIDMappedLowers(id)
+			}
+		}()
+	}
+
+	workDir := path.Join(dir, workDirName)
 	absLowers := make([]string, len(splitLowers))
 	for i, s := range splitLowers {
 		absLowers[i] = path.Join(d.home, s)
@@ -612,7 +634,7 @@ func (d *Driver) Get(id, mountLabel string) (_ containerfs.ContainerFS, retErr e
 	// fit within a page and relative links make the mount data much
 	// smaller at the expense of requiring a fork exec to chroot.
 	if len(mountData) > pageSize {
-		opts = indexOff + "lowerdir=" + string(lowers) + ",upperdir=" + path.Join(id, diffDirName) + ",workdir=" + path.Join(id, workDirName)
+		opts = indexOff + "lowerdir=" + strings.Join(splitLowers, ":") + ",upperdir=" + path.Join(id, diffDirName) + ",workdir=" + path.Join(id, workDirName)
 		mountData = label.FormatMountLabel(opts, mountLabel)
 		if len(mountData) > pageSize {
 			return nil, fmt.Errorf("cannot mount layer, mount label too large %d", len(mountData))
@@ -625,6 +647,9 @@ func (d *Driver) Get(id, mountLabel string) (_ containerfs.ContainerFS, retErr e
 	}
 
 	if err := mount("overlay", mountTarget, "overlay", 0, mountData); err != nil {
+		if idMapping != nil {
+			return nil, fmt.Errorf("error creating overlay mount to %s (overlay mounts of idmapped layers require Linux 5.19 or later): %v", mergedDir, err)
+		}
 		return nil, fmt.Errorf("error creating overlay mount to %s: %v", mergedDir, err)
 	}
 
@@ -670,9 +695,15 @@ func (d *Driver) Put(id string) error {
 	if err := unix.Rmdir(mountpoint); err != nil && !os.IsNotExist(err) {
 		logger.Debugf("Failed to remove %s overlay: %v", id, err)
 	}
+	d.unmountIDMappedLowers(id)
 	return nil
 }
 
+// Capabilities returns the capabilities of the driver.
+func (d *Driver) Capabilities() graphdriver.Capabilities {
+	return graphdriver.Capabilities{IDMappedLayers: true}
+}
+
 // Exists checks to see if the id is already mounted.
 func (d *Driver) Exists(id string) bool {
 	_, err := os.Stat(d.dir(id))
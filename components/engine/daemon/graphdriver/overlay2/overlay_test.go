@@ -5,12 +5,17 @@ package overlay2 // import "github.com/docker/docker/daemon/graphdriver/overlay2
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
 
 	"github.com/docker/docker/daemon/graphdriver"
 	"github.com/docker/docker/daemon/graphdriver/graphtest"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/reexec"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 )
 
 func init() {
@@ -66,6 +71,54 @@ func TestOverlayChanges(t *testing.T) {
 	graphtest.DriverTestChanges(t, driverName)
 }
 
+func TestOverlayIDMappedLayers(t *testing.T) {
+	driver := graphtest.GetDriver(t, driverName)
+	defer graphtest.PutDriver(t)
+
+	assert.NilError(t, driver.Create("idmap-base", "", nil))
+	defer driver.Remove("idmap-base")
+	base, err := driver.Get("idmap-base", "")
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(base.Path(), "file"), nil, 0644))
+	assert.NilError(t, driver.Put("idmap-base"))
+
+	m := idtools.NewIDMappingsFromMaps(
+		[]idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+		[]idtools.IDMap{{ContainerID: 0, HostID: 200000, Size: 65536}},
+	)
+	assert.NilError(t, driver.CreateReadWrite("idmap-rw", "idmap-base", &graphdriver.CreateOpts{IDMapping: m}))
+	defer driver.Remove("idmap-rw")
+	rw, err := driver.Get("idmap-rw", "")
+	if err != nil {
+		t.Skipf("idmapped layers are not supported: %v", err)
+	}
+
+	owner := func(p string) (uint32, uint32) {
+		fi, err := os.Lstat(p)
+		assert.NilError(t, err)
+		st := fi.Sys().(*syscall.Stat_t)
+		return st.Uid, st.Gid
+	}
+	uid, gid := owner(filepath.Join(rw.Path(), "file"))
+	assert.Check(t, is.Equal(uid, uint32(100000)))
+	assert.Check(t, is.Equal(gid, uint32(200000)))
+
+	// Copying up a file keeps the IDs seen in the mount, and the files of
+	// the lower layer are not changed.
+	assert.NilError(t, os.Chmod(filepath.Join(rw.Path(), "file"), 0600))
+	md, err := driver.GetMetadata("idmap-rw")
+	assert.NilError(t, err)
+	uid, _ = owner(filepath.Join(md["UpperDir"], "file"))
+	assert.Check(t, is.Equal(uid, uint32(100000)))
+	assert.NilError(t, driver.Put("idmap-rw"))
+
+	base, err = driver.Get("idmap-base", "")
+	assert.NilError(t, err)
+	uid, _ = owner(filepath.Join(base.Path(), "file"))
+	assert.Check(t, is.Equal(uid, uint32(0)))
+	assert.NilError(t, driver.Put("idmap-base"))
+}
+
 func TestOverlayTeardown(t *testing.T) {
 	graphtest.PutDriver(t)
 }
@@ -78,6 +78,7 @@ func (p *cmdProbe) run(ctx context.Context, d *Daemon, cntr *container.Container
 	execConfig.Args = args
 	execConfig.Tty = false
 	execConfig.Privileged = false
+	execConfig.Probe = true
 	execConfig.User = cntr.Config.User
 	execConfig.WorkingDir = cntr.Config.WorkingDir
 
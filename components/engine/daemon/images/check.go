@@ -0,0 +1,179 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+// CheckReportFunc is called for every problem found by a check. repair
+// repairs the problem; it is nil if the problem cannot be repaired
+// automatically.
+type CheckReportFunc func(problem types.SystemCheckProblem, repair func() error)
+
+// CheckImages checks the image, reference and layer stores for problems
+// left behind by interrupted operations, and reports them using report.
+// If verifyLayers is set, the content of every layer is verified against
+// its DiffID. Unused container filesystems listed in keepMounts are
+// reported, but not repaired.
+// called from check.go Daemon.SystemCheck()
+func (i *ImageService) CheckImages(ctx context.Context, verifyLayers bool, keepMounts map[string]bool, report CheckReportFunc) error {
+	// Images are checked first, as removing a corrupted image may leave
+	// dangling references behind.
+	if err := i.checkImageStore(report); err != nil {
+		return err
+	}
+	i.checkReferences(report)
+
+	// Mounted images are not used by any container, but are in use.
+	imageMounts := i.imageMountNames()
+	for os, ls := range i.layerStores {
+		cs, ok := ls.(layer.CheckableStore)
+		if !ok {
+			continue
+		}
+		if err := checkLayerStore(ctx, cs, verifyLayers, keepMounts, imageMounts, report); err != nil {
+			return fmt.Errorf("error checking %s layer store: %v", os, err)
+		}
+	}
+	return nil
+}
+
+func (i *ImageService) checkImageStore(report CheckReportFunc) error {
+	corrupted, err := i.imageStore.Corrupted()
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(corrupted))
+	for id := range corrupted {
+		ids = append(ids, id.String())
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		imgID := image.ID(id)
+		report(types.SystemCheckProblem{
+			Kind:    "corrupted-image",
+			ID:      id,
+			Message: fmt.Sprintf("image could not be loaded: %v", corrupted[imgID]),
+			Repair:  "remove image",
+		}, func() error {
+			return i.imageStore.RemoveCorrupted(imgID)
+		})
+	}
+	return nil
+}
+
+func (i *ImageService) checkReferences(report CheckReportFunc) {
+	images := i.imageStore.Map()
+	for _, a := range i.referenceStore.Associations() {
+		if _, ok := images[image.ID(a.ID)]; ok {
+			continue
+		}
+		ref := a.Ref
+		report(types.SystemCheckProblem{
+			Kind:    "dangling-reference",
+			ID:      reference.FamiliarString(ref),
+			Message: fmt.Sprintf("reference points to image %s, which does not exist", a.ID),
+			Repair:  "remove reference",
+		}, func() error {
+			_, err := i.referenceStore.Delete(ref)
+			return err
+		})
+	}
+}
+
+func checkLayerStore(ctx context.Context, cs layer.CheckableStore, verifyLayers bool, keepMounts, imageMounts map[string]bool, report CheckReportFunc) error {
+	corruptedLayers, corruptedMounts, err := cs.Corrupted()
+	if err != nil {
+		return err
+	}
+	for _, name := range corruptedMounts {
+		name := name
+		report(types.SystemCheckProblem{
+			Kind:    "corrupted-mount",
+			ID:      name,
+			Message: "container filesystem could not be loaded",
+			Repair:  "remove container filesystem",
+		}, func() error {
+			_, err := cs.RemoveMount(name)
+			return err
+		})
+	}
+	for _, id := range corruptedLayers {
+		id := id
+		report(types.SystemCheckProblem{
+			Kind:    "corrupted-layer",
+			ID:      id.String(),
+			Message: "layer could not be loaded",
+			Repair:  "remove layer",
+		}, func() error {
+			metadata, err := cs.RemoveLayer(id)
+			layer.LogReleaseMetadata(metadata)
+			return err
+		})
+	}
+
+	// Mounts are checked before layers, as removing an unused mount
+	// releases the layers it was using.
+	orphanLayers, orphanMounts := cs.Unreferenced()
+	for _, name := range orphanMounts {
+		name := name
+		if imageMounts[name] {
+			continue
+		}
+		if keepMounts[name] {
+			report(types.SystemCheckProblem{
+				Kind:    "orphan-mount",
+				ID:      name,
+				Message: "container filesystem is used by a container which could not be loaded",
+			}, nil)
+			continue
+		}
+		report(types.SystemCheckProblem{
+			Kind:    "orphan-mount",
+			ID:      name,
+			Message: "container filesystem is not used by any container",
+			Repair:  "remove container filesystem",
+		}, func() error {
+			metadata, err := cs.RemoveMount(name)
+			layer.LogReleaseMetadata(metadata)
+			return err
+		})
+	}
+	for _, id := range orphanLayers {
+		id := id
+		report(types.SystemCheckProblem{
+			Kind:    "orphan-layer",
+			ID:      id.String(),
+			Message: "layer is not used by any image, layer or container",
+			Repair:  "remove layer",
+		}, func() error {
+			metadata, err := cs.RemoveLayer(id)
+			layer.LogReleaseMetadata(metadata)
+			return err
+		})
+	}
+
+	if !verifyLayers {
+		return nil
+	}
+	for id := range cs.Map() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := cs.Verify(id); err != nil && err != layer.ErrLayerDoesNotExist {
+			report(types.SystemCheckProblem{
+				Kind:    "invalid-layer",
+				ID:      id.String(),
+				Message: err.Error(),
+			}, nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+)
+
+// buildInfoMagic starts the build information which the Go linker embeds in
+// binaries.
+var buildInfoMagic = []byte("\xff Go buildinf:")
+
+// errNotGoBinary is returned for binaries which have no Go build information.
+var errNotGoBinary = errors.New("not a Go binary")
+
+// readGoBuildInfo returns the version of Go used to build an ELF binary, and
+// the information about the modules it was built from, in the format of
+// `go version -m`. Only the headers of the binary and the sections holding
+// the build information are read.
+func readGoBuildInfo(r io.ReaderAt) (goVersion, modInfo string, err error) {
+	f, err := elf.NewFile(r)
+	if err != nil {
+		return "", "", errNotGoBinary
+	}
+	data := buildInfoData(f)
+	for len(data) >= 32 && !bytes.HasPrefix(data, buildInfoMagic) {
+		// The build information is aligned on 16 bytes.
+		data = data[16:]
+	}
+	if len(data) < 32 {
+		return "", "", errNotGoBinary
+	}
+
+	ptrSize := int(data[14])
+	flags := data[15]
+	if flags&2 != 0 {
+		// Since Go 1.18, the strings follow the header, prefixed by their
+		// length.
+		var rest []byte
+		goVersion, rest = decodeBuildInfoString(data[32:])
+		modInfo, _ = decodeBuildInfoString(rest)
+	} else {
+		// Before Go 1.18, the header is followed by pointers to the strings.
+		if ptrSize != 4 && ptrSize != 8 {
+			return "", "", errNotGoBinary
+		}
+		var bo binary.ByteOrder = binary.LittleEndian
+		if flags&1 != 0 {
+			bo = binary.BigEndian
+		}
+		mem := elfMemory{f: f, ptrSize: ptrSize, bo: bo}
+		goVersion = mem.readString(mem.readPtr(data[16:]))
+		modInfo = mem.readString(mem.readPtr(data[16+ptrSize:]))
+	}
+	if goVersion == "" {
+		return "", "", errNotGoBinary
+	}
+
+	// The module information is wrapped in 16 bytes long sentinels.
+	if len(modInfo) >= 33 && modInfo[len(modInfo)-17] == '\n' {
+		modInfo = modInfo[16 : len(modInfo)-16]
+	} else {
+		modInfo = ""
+	}
+	return goVersion, modInfo, nil
+}
+
+// buildInfoData returns the start of the data in which the build information
+// is searched.
+func buildInfoData(f *elf.File) []byte {
+	const maxSize = 64 * 1024
+	if s := f.Section(".go.buildinfo"); s != nil {
+		data := make([]byte, min64(s.Size, maxSize))
+		if _, err := s.ReadAt(data, 0); err != nil {
+			return nil
+		}
+		return data
+	}
+	for _, p := range f.Progs {
+		if p.Type == elf.PT_LOAD && p.Flags&(elf.PF_X|elf.PF_W) == elf.PF_W {
+			data := make([]byte, min64(p.Filesz, maxSize))
+			if _, err := p.ReadAt(data, 0); err != nil {
+				return nil
+			}
+			return data
+		}
+	}
+	return nil
+}
+
+func decodeBuildInfoString(data []byte) (string, []byte) {
+	n, size := binary.Uvarint(data)
+	if size <= 0 || n > uint64(len(data)-size) {
+		return "", nil
+	}
+	return string(data[size : size+int(n)]), data[size+int(n):]
+}
+
+// elfMemory reads the memory of a program from its ELF binary.
+type elfMemory struct {
+	f       *elf.File
+	ptrSize int
+	bo      binary.ByteOrder
+}
+
+func (m elfMemory) readPtr(b []byte) uint64 {
+	if m.ptrSize == 4 {
+		return uint64(m.bo.Uint32(b))
+	}
+	return m.bo.Uint64(b)
+}
+
+func (m elfMemory) read(addr, size uint64) []byte {
+	for _, p := range m.f.Progs {
+		if p.Type == elf.PT_LOAD && p.Vaddr <= addr && addr-p.Vaddr+size <= p.Filesz {
+			data := make([]byte, size)
+			if _, err := p.ReadAt(data, int64(addr-p.Vaddr)); err != nil {
+				return nil
+			}
+			return data
+		}
+	}
+	return nil
+}
+
+// readString reads a Go string header at addr, and the string it points to.
+func (m elfMemory) readString(addr uint64) string {
+	hdr := m.read(addr, uint64(2*m.ptrSize))
+	if hdr == nil {
+		return ""
+	}
+	dataAddr, size := m.readPtr(hdr), m.readPtr(hdr[m.ptrSize:])
+	if size > 1024*1024 {
+		return ""
+	}
+	return string(m.read(dataAddr, size))
+}
+
+func min64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// parseGoBinary returns the packages of a Go binary, read from its build
+// information. Other binaries have no packages.
+func parseGoBinary(p string, r io.ReaderAt) ([]types.ImagePackage, error) {
+	goVersion, modInfo, err := readGoBuildInfo(r)
+	if err == errNotGoBinary {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return goPackages(p, goVersion, modInfo), nil
+}
+
+// goPackages returns the packages of a Go binary: the modules it depends on,
+// its main module, and the Go standard library.
+func goPackages(path, goVersion, modInfo string) []types.ImagePackage {
+	packages := []types.ImagePackage{{Name: "stdlib", Version: goVersion, Type: "golang", Path: path}}
+	last := -1
+	for _, line := range strings.Split(modInfo, "\n") {
+		fields := strings.Split(line, "\t")
+		switch fields[0] {
+		case "mod", "dep":
+			last = -1
+			// The version of the main module is "(devel)" when it is built
+			// from a working tree.
+			if len(fields) < 3 || fields[2] == "" || fields[2] == "(devel)" {
+				continue
+			}
+			last = len(packages)
+			packages = append(packages, types.ImagePackage{Name: fields[1], Version: fields[2], Type: "golang", Path: path})
+		case "=>":
+			// A replacement of the previous module. Replacements by local
+			// directories have no version, and are ignored.
+			if last >= 0 && len(fields) >= 3 && fields[2] != "" {
+				packages[last].Name = fields[1]
+				packages[last].Version = fields[2]
+			}
+			last = -1
+		}
+	}
+	return packages
+}
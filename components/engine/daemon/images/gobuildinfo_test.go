@@ -0,0 +1,46 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestReadGoBuildInfo(t *testing.T) {
+	skip.If(t, runtime.GOOS != "linux", "test binaries are only ELF binaries on Linux")
+	executable, err := os.Executable()
+	assert.NilError(t, err)
+	f, err := os.Open(executable)
+	assert.NilError(t, err)
+	defer f.Close()
+
+	goVersion, _, err := readGoBuildInfo(f)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(goVersion, runtime.Version()))
+
+	_, _, err = readGoBuildInfo(strings.NewReader("#!/bin/sh\n"))
+	assert.Check(t, is.Equal(err, errNotGoBinary))
+}
+
+func TestGoPackages(t *testing.T) {
+	modInfo := "path\tgithub.com/example/app\n" +
+		"mod\tgithub.com/example/app\t(devel)\t\n" +
+		"dep\tgithub.com/pkg/errors\tv0.8.1\th1:abc=\n" +
+		"dep\tgolang.org/x/sys\tv0.0.0-20200106162015-b016eb3dc98e\th1:def=\n" +
+		"=>\tgithub.com/example/sys\tv0.1.0\th1:ghi=\n" +
+		"dep\tgithub.com/example/local\tv1.0.0\t\n" +
+		"=>\t../local\t\t\n"
+	packages := goPackages("/app", "go1.13.8", modInfo)
+	assert.Check(t, is.DeepEqual(packages, []types.ImagePackage{
+		{Name: "stdlib", Version: "go1.13.8", Type: "golang", Path: "/app"},
+		{Name: "github.com/pkg/errors", Version: "v0.8.1", Type: "golang", Path: "/app"},
+		{Name: "github.com/example/sys", Version: "v0.1.0", Type: "golang", Path: "/app"},
+		{Name: "github.com/example/local", Version: "v1.0.0", Type: "golang", Path: "/app"},
+	}))
+}
@@ -1,12 +1,14 @@
 package images // import "github.com/docker/docker/daemon/images"
 
 import (
+	"archive/tar"
 	"encoding/json"
 	"io"
 
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/system"
 	"github.com/pkg/errors"
@@ -19,6 +21,9 @@ func (i *ImageService) CommitImage(c backend.CommitConfig) (image.ID, error) {
 		return "", system.ErrNotSupportedOperatingSystem
 	}
 	rwTar, err := exportContainerRw(layerStore, c.ContainerID, c.ContainerMountLabel)
+	if err == nil && c.ContainerIDMapping != nil {
+		rwTar = unmapArchiveIDs(rwTar, c.ContainerIDMapping)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -105,6 +110,44 @@ func exportContainerRw(layerStore layer.Store, id, mountLabel string) (arch io.R
 		nil
 }
 
+// unmapArchiveIDs maps the IDs of the files of a tar archive of the RW layer
+// of a container with its own user namespace mapping back to the IDs of the
+// container, which are those of its image. The IDs which m does not map, such
+// as those of the whiteouts, are left unchanged.
+func unmapArchiveIDs(rc io.ReadCloser, m *idtools.IdentityMapping) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		tr := tar.NewReader(rc)
+		tw := tar.NewWriter(pw)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if uid, gid, err := m.ToContainer(idtools.Identity{UID: hdr.Uid, GID: hdr.Gid}); err == nil {
+				hdr.Uid, hdr.Gid = uid, gid
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if _, err := io.Copy(tw, tr); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.CloseWithError(tw.Close())
+	}()
+	return ioutils.NewReadCloserWrapper(pr, func() error {
+		pr.Close()
+		return rc.Close()
+	})
+}
+
 // CommitBuildStep is used by the builder to create an image for each step in
 // the build.
 //
@@ -0,0 +1,53 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/docker/pkg/idtools"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestUnmapArchiveIDs(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	tw := tar.NewWriter(buf)
+	for _, hdr := range []*tar.Header{
+		{Name: "home/user/file", Mode: 0644, Uid: 101000, Gid: 201000, Size: 4},
+		{Name: "etc/.wh.passwd", Mode: 0600, Uid: 0, Gid: 0},
+	} {
+		assert.NilError(t, tw.WriteHeader(hdr))
+		if hdr.Size > 0 {
+			_, err := tw.Write([]byte("data"))
+			assert.NilError(t, err)
+		}
+	}
+	assert.NilError(t, tw.Close())
+
+	m := idtools.NewIDMappingsFromMaps(
+		[]idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+		[]idtools.IDMap{{ContainerID: 0, HostID: 200000, Size: 65536}},
+	)
+	rc := unmapArchiveIDs(ioutil.NopCloser(buf), m)
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	hdr, err := tr.Next()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(hdr.Uid, 1000))
+	assert.Check(t, is.Equal(hdr.Gid, 1000))
+	data, err := ioutil.ReadAll(tr)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "data"))
+
+	hdr, err = tr.Next()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(hdr.Uid, 0))
+	assert.Check(t, is.Equal(hdr.Gid, 0))
+
+	_, err = tr.Next()
+	assert.Check(t, is.Equal(err, io.EOF))
+}
@@ -22,7 +22,8 @@ const (
 	conflictRunningContainer
 	conflictActiveReference
 	conflictStoppedContainer
-	conflictHard = conflictDependentChild | conflictRunningContainer
+	conflictMounted
+	conflictHard = conflictDependentChild | conflictRunningContainer | conflictMounted
 	conflictSoft = conflictActiveReference | conflictStoppedContainer
 )
 
@@ -48,6 +49,7 @@ const (
 // 	- a pull or build using the image.
 // 	- any descendant image.
 // 	- any running container using the image.
+// 	- a mount of the image made by MountImage.
 //
 // Soft Conflict:
 // 	- any stopped container using the image.
@@ -381,6 +383,15 @@ func (i *ImageService) checkImageDeleteConflict(imgID image.ID, mask conflictTyp
 		}
 	}
 
+	if mask&conflictMounted != 0 && i.isImageMounted(imgID) {
+		return &imageDeleteConflict{
+			imgID:   imgID,
+			hard:    true,
+			used:    true,
+			message: "image is mounted",
+		}
+	}
+
 	// Check if any repository tags/digest reference this image.
 	if mask&conflictActiveReference != 0 && len(i.referenceStore.References(imgID.Digest())) > 0 {
 		return &imageDeleteConflict{
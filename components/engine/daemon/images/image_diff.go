@@ -0,0 +1,448 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/system"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// maxContentDiffSize is the maximum size of a file for which a diff of the
+// content is computed.
+const maxContentDiffSize = 1024 * 1024
+
+// DiffImages compares the filesystem of the target image against that of the
+// base image. The filesystems are computed from the layers of the images,
+// without mounting them.
+func (i *ImageService) DiffImages(ctx context.Context, base, target string, opts types.DiffOptions) ([]types.FileChange, error) {
+	start := time.Now()
+	baseImg, err := i.GetImage(base)
+	if err != nil {
+		return nil, err
+	}
+	targetImg, err := i.GetImage(target)
+	if err != nil {
+		return nil, err
+	}
+	if baseImg.OperatingSystem() != targetImg.OperatingSystem() {
+		return nil, errdefs.InvalidParameter(errors.Errorf("cannot compare a %s image against a %s image", targetImg.OperatingSystem(), baseImg.OperatingSystem()))
+	}
+
+	changes, err := i.diffLayers(ctx, targetImg.OperatingSystem(), baseImg.RootFS, targetImg.RootFS, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	imageActions.WithValues("changes").UpdateSince(start)
+	return changes, nil
+}
+
+// DiffContainer compares the filesystem of a container, made of the layers of
+// its image and of its RW layer, against that of the base image.
+func (i *ImageService) DiffContainer(ctx context.Context, base string, imgID image.ID, rwLayer layer.RWLayer, opts types.DiffOptions) ([]types.FileChange, error) {
+	baseImg, err := i.GetImage(base)
+	if err != nil {
+		return nil, err
+	}
+	img, err := i.imageStore.Get(imgID)
+	if err != nil {
+		return nil, err
+	}
+	if baseImg.OperatingSystem() != img.OperatingSystem() {
+		return nil, errdefs.InvalidParameter(errors.Errorf("cannot compare a %s container against a %s image", img.OperatingSystem(), baseImg.OperatingSystem()))
+	}
+	return i.diffLayers(ctx, img.OperatingSystem(), baseImg.RootFS, img.RootFS, rwLayer, opts)
+}
+
+func (i *ImageService) diffLayers(ctx context.Context, operatingSystem string, baseFS, targetFS *image.RootFS, rwLayer layer.RWLayer, opts types.DiffOptions) ([]types.FileChange, error) {
+	if !system.IsOSSupported(operatingSystem) {
+		return nil, errdefs.InvalidParameter(system.ErrNotSupportedOperatingSystem)
+	}
+	ls := i.layerStores[operatingSystem]
+
+	baseLayers, release, err := getLayerChain(ls, baseFS)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	targetLayers, release, err := getLayerChain(ls, targetFS)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// The layers which both filesystems have in common are only read once.
+	shared := 0
+	for shared < len(baseLayers) && shared < len(targetLayers) && baseLayers[shared].ChainID() == targetLayers[shared].ChainID() {
+		shared++
+	}
+	targetTree := fileTree{}
+	for _, l := range baseLayers[:shared] {
+		if err := targetTree.applyLayer(ctx, l); err != nil {
+			return nil, err
+		}
+	}
+	baseTree := targetTree.clone()
+	for _, l := range baseLayers[shared:] {
+		if err := baseTree.applyLayer(ctx, l); err != nil {
+			return nil, err
+		}
+	}
+	for _, l := range targetLayers[shared:] {
+		if err := targetTree.applyLayer(ctx, l); err != nil {
+			return nil, err
+		}
+	}
+	if rwLayer != nil {
+		if err := targetTree.applyLayer(ctx, rwLayer); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, pairs := compareFileTrees(baseTree, targetTree)
+	if opts.Content {
+		if err := addContentDiffs(ctx, changes, pairs); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// getLayerChain returns the layers of a root filesystem, from the bottom to
+// the top layer. The returned function releases the layers.
+func getLayerChain(ls layer.Store, rootFS *image.RootFS) ([]layer.Layer, func(), error) {
+	chainID := rootFS.ChainID()
+	if chainID == "" {
+		return nil, func() {}, nil
+	}
+	top, err := ls.Get(chainID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var layers []layer.Layer
+	for l := top; l != nil; l = l.Parent() {
+		layers = append([]layer.Layer{l}, layers...)
+	}
+	return layers, func() { layer.ReleaseAndLog(ls, top) }, nil
+}
+
+// fileEntry is a file of a filesystem computed from layers.
+type fileEntry struct {
+	typeflag byte
+	mode     os.FileMode
+	uid      int
+	gid      int
+	size     int64
+	linkname string
+	devmajor int64
+	devminor int64
+	digest   digest.Digest
+
+	// src is the layer which contains the file, and srcName the name of the
+	// file in the tar stream of that layer. It differs from the path of the
+	// file for hard links.
+	src     layer.TarStreamer
+	srcName string
+}
+
+func (e *fileEntry) equal(o *fileEntry) bool {
+	return e.typeflag == o.typeflag &&
+		e.mode == o.mode &&
+		e.uid == o.uid &&
+		e.gid == o.gid &&
+		e.size == o.size &&
+		e.linkname == o.linkname &&
+		e.devmajor == o.devmajor &&
+		e.devminor == o.devminor &&
+		e.digest == o.digest
+}
+
+func (e *fileEntry) info() *types.FileChangeInfo {
+	return &types.FileChangeInfo{
+		Size:       e.size,
+		Mode:       e.mode,
+		UID:        e.uid,
+		GID:        e.gid,
+		LinkTarget: e.linkname,
+		Digest:     e.digest.String(),
+	}
+}
+
+// fileTree is a filesystem computed from layers, indexed by the absolute
+// paths of the files. Modification times are not recorded, as they change
+// every time an image is rebuilt.
+type fileTree map[string]*fileEntry
+
+func (t fileTree) clone() fileTree {
+	c := make(fileTree, len(t))
+	for p, e := range t {
+		c[p] = e
+	}
+	return c
+}
+
+// removeChildren removes the files below the directory dir.
+func (t fileTree) removeChildren(dir string) {
+	prefix := dir + "/"
+	if dir == "/" {
+		prefix = dir
+	}
+	for p := range t {
+		if strings.HasPrefix(p, prefix) && p != dir {
+			delete(t, p)
+		}
+	}
+}
+
+// applyLayer applies the tar stream of a layer to the tree. Whiteouts only
+// apply to the files of the lower layers, so they are applied before the
+// files of the layer are added.
+func (t fileTree) applyLayer(ctx context.Context, l layer.TarStreamer) error {
+	rc, err := l.TarStream()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	var (
+		whiteouts []string
+		opaques   []string
+		adds      = fileTree{}
+		order     []string
+	)
+	tr := tar.NewReader(rc)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "error reading layer")
+		}
+		name := path.Join("/", hdr.Name)
+		dir, base := path.Split(name)
+		dir = path.Clean(dir)
+		switch {
+		case strings.HasPrefix(name, "/"+archive.WhiteoutMetaPrefix) && base != archive.WhiteoutOpaqueDir:
+			// AUFS metadata, such as the directory of hard links
+			continue
+		case base == archive.WhiteoutOpaqueDir:
+			opaques = append(opaques, dir)
+			continue
+		case strings.HasPrefix(base, archive.WhiteoutPrefix):
+			whiteouts = append(whiteouts, path.Join(dir, strings.TrimPrefix(base, archive.WhiteoutPrefix)))
+			continue
+		}
+
+		e := &fileEntry{
+			typeflag: hdr.Typeflag,
+			mode:     hdr.FileInfo().Mode(),
+			uid:      hdr.Uid,
+			gid:      hdr.Gid,
+			size:     hdr.Size,
+			devmajor: hdr.Devmajor,
+			devminor: hdr.Devminor,
+			src:      l,
+			srcName:  name,
+		}
+		switch hdr.Typeflag {
+		case tar.TypeReg, tar.TypeRegA:
+			e.typeflag = tar.TypeReg
+			if e.digest, err = digest.FromReader(tr); err != nil {
+				return errors.Wrap(err, "error reading layer")
+			}
+		case tar.TypeSymlink:
+			e.linkname = hdr.Linkname
+		case tar.TypeLink:
+			// A hard link is compared as the file it links to.
+			target := path.Join("/", hdr.Linkname)
+			linked, ok := adds[target]
+			if !ok {
+				linked, ok = t[target]
+			}
+			if ok {
+				e.typeflag = linked.typeflag
+				e.size = linked.size
+				e.digest = linked.digest
+				e.src = linked.src
+				e.srcName = linked.srcName
+			}
+		}
+		if _, ok := adds[name]; !ok {
+			order = append(order, name)
+		}
+		adds[name] = e
+	}
+
+	for _, dir := range opaques {
+		t.removeChildren(dir)
+	}
+	for _, p := range whiteouts {
+		delete(t, p)
+		t.removeChildren(p)
+	}
+	for _, p := range order {
+		e := adds[p]
+		if old, ok := t[p]; ok && old.typeflag == tar.TypeDir && e.typeflag != tar.TypeDir {
+			t.removeChildren(p)
+		}
+		t[p] = e
+	}
+	return nil
+}
+
+// filePair is a file which is modified between two trees.
+type filePair struct {
+	change   *types.FileChange
+	old, new *fileEntry
+}
+
+// compareFileTrees returns the changes of the target tree against the base
+// tree, sorted by path, and the pairs of files for the modified files.
+func compareFileTrees(base, target fileTree) ([]types.FileChange, []filePair) {
+	paths := make([]string, 0, len(target))
+	for p := range target {
+		paths = append(paths, p)
+	}
+	for p := range base {
+		if _, ok := target[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	changes := []types.FileChange{}
+	var modified []int
+	for _, p := range paths {
+		if p == "/" {
+			continue
+		}
+		old, inBase := base[p]
+		e, inTarget := target[p]
+		switch {
+		case !inBase:
+			changes = append(changes, types.FileChange{Kind: archive.ChangeAdd, Path: p, New: e.info()})
+		case !inTarget:
+			changes = append(changes, types.FileChange{Kind: archive.ChangeDelete, Path: p, Old: old.info()})
+		case !old.equal(e):
+			modified = append(modified, len(changes))
+			changes = append(changes, types.FileChange{Kind: archive.ChangeModify, Path: p, Old: old.info(), New: e.info()})
+		}
+	}
+
+	pairs := make([]filePair, 0, len(modified))
+	for _, idx := range modified {
+		c := &changes[idx]
+		pairs = append(pairs, filePair{change: c, old: base[c.Path], new: target[c.Path]})
+	}
+	return changes, pairs
+}
+
+// addContentDiffs sets the diff of the content of the modified text files.
+// The files are read from the layers which contain them, each layer being
+// read at most once.
+func addContentDiffs(ctx context.Context, changes []types.FileChange, pairs []filePair) error {
+	wanted := map[layer.TarStreamer]map[string][]byte{}
+	want := func(e *fileEntry) {
+		if wanted[e.src] == nil {
+			wanted[e.src] = map[string][]byte{}
+		}
+		wanted[e.src][e.srcName] = nil
+	}
+	var candidates []filePair
+	for _, p := range pairs {
+		if p.old.typeflag != tar.TypeReg || p.new.typeflag != tar.TypeReg || p.old.digest == p.new.digest {
+			continue
+		}
+		if p.old.size > maxContentDiffSize || p.new.size > maxContentDiffSize || p.old.src == nil || p.new.src == nil {
+			continue
+		}
+		want(p.old)
+		want(p.new)
+		candidates = append(candidates, p)
+	}
+
+	for src, files := range wanted {
+		if err := readLayerFiles(ctx, src, files); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range candidates {
+		oldContent := wanted[p.old.src][p.old.srcName]
+		newContent := wanted[p.new.src][p.new.srcName]
+		if !isText(oldContent) || !isText(newContent) {
+			continue
+		}
+		p.change.Diff = unifiedDiff("a"+p.change.Path, "b"+p.change.Path, string(oldContent), string(newContent))
+	}
+	return nil
+}
+
+// readLayerFiles reads the content of the given files from the tar stream of
+// a layer.
+func readLayerFiles(ctx context.Context, l layer.TarStreamer, files map[string][]byte) error {
+	names := make(map[string]bool, len(files))
+	for name := range files {
+		names[name] = true
+	}
+	return walkLayerFiles(ctx, l, names, func(name string, r io.Reader) error {
+		content, err := ioutil.ReadAll(io.LimitReader(r, maxContentDiffSize))
+		if err != nil {
+			return errors.Wrap(err, "error reading layer")
+		}
+		files[name] = content
+		return nil
+	})
+}
+
+// walkLayerFiles calls fn with a reader of the content of each of the given
+// files, found in the tar stream of a layer. The tar stream is only read
+// until all the files were found.
+func walkLayerFiles(ctx context.Context, l layer.TarStreamer, names map[string]bool, fn func(name string, r io.Reader) error) error {
+	rc, err := l.TarStream()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	remaining := len(names)
+	for remaining > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "error reading layer")
+		}
+		name := path.Join("/", hdr.Name)
+		if !names[name] {
+			continue
+		}
+		if err := fn(name, tr); err != nil {
+			return err
+		}
+		remaining--
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/archive"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type tarFile struct {
+	hdr     tar.Header
+	content string
+}
+
+// fakeLayer is a layer.TarStreamer which returns a tar stream of the given
+// files.
+type fakeLayer struct {
+	files []tarFile
+}
+
+func newFakeLayer(files ...tarFile) *fakeLayer {
+	return &fakeLayer{files: files}
+}
+
+func (l *fakeLayer) TarStream() (io.ReadCloser, error) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, f := range l.files {
+		hdr := f.hdr
+		hdr.Size = int64(len(f.content))
+		if err := tw.WriteHeader(&hdr); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write([]byte(f.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(buf), nil
+}
+
+func dir(name string) tarFile {
+	return tarFile{hdr: tar.Header{Name: name, Typeflag: tar.TypeDir, Mode: 0755}}
+}
+
+func file(name, content string) tarFile {
+	return tarFile{hdr: tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644}, content: content}
+}
+
+func whiteout(name string) tarFile {
+	return tarFile{hdr: tar.Header{Name: name, Typeflag: tar.TypeReg}}
+}
+
+func buildTree(t *testing.T, layers ...*fakeLayer) fileTree {
+	tree := fileTree{}
+	for _, l := range layers {
+		assert.NilError(t, tree.applyLayer(context.Background(), l))
+	}
+	return tree
+}
+
+func treePaths(tree fileTree) []string {
+	var paths []string
+	for p := range tree {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestFileTreeApplyLayerWhiteouts(t *testing.T) {
+	tree := buildTree(t,
+		newFakeLayer(dir("etc/"), file("etc/a", "a"), file("etc/b", "b"), dir("opt/"), file("opt/x", "x"), dir("var/"), file("var/y", "y")),
+		newFakeLayer(whiteout("etc/.wh.a"), dir("opt/"), whiteout("opt/"+archive.WhiteoutOpaqueDir), file("opt/z", "z"), whiteout(".wh.var")),
+	)
+	assert.Check(t, is.DeepEqual(treePaths(tree), []string{"/etc", "/etc/b", "/opt", "/opt/z"}))
+}
+
+func TestFileTreeApplyLayerReplacesDirectory(t *testing.T) {
+	tree := buildTree(t,
+		newFakeLayer(dir("etc/"), dir("etc/conf/"), file("etc/conf/a", "a")),
+		newFakeLayer(file("etc/conf", "conf")),
+	)
+	assert.Check(t, is.DeepEqual(treePaths(tree), []string{"/etc", "/etc/conf"}))
+	assert.Check(t, is.Equal(tree["/etc/conf"].typeflag, byte(tar.TypeReg)))
+}
+
+func TestCompareFileTrees(t *testing.T) {
+	base := newFakeLayer(dir("etc/"), file("etc/passwd", "root\n"), file("etc/hosts", "localhost\n"), file("etc/motd", "hello\n"))
+	chmod := file("etc/motd", "hello\n")
+	chmod.hdr.Mode = 0600
+	target := newFakeLayer(file("etc/passwd", "root\nuser\n"), whiteout("etc/.wh.hosts"), file("etc/group", "root\n"), chmod)
+
+	baseTree := buildTree(t, base)
+	targetTree := buildTree(t, base, target)
+	changes, pairs := compareFileTrees(baseTree, targetTree)
+	assert.Assert(t, is.Len(changes, 4))
+	assert.Check(t, is.Len(pairs, 2))
+
+	expected := []struct {
+		kind uint8
+		path string
+	}{
+		{archive.ChangeAdd, "/etc/group"},
+		{archive.ChangeDelete, "/etc/hosts"},
+		{archive.ChangeModify, "/etc/motd"},
+		{archive.ChangeModify, "/etc/passwd"},
+	}
+	for i, c := range changes {
+		assert.Check(t, is.Equal(c.Kind, expected[i].kind))
+		assert.Check(t, is.Equal(c.Path, expected[i].path))
+	}
+	assert.Check(t, is.Nil(changes[0].Old))
+	assert.Check(t, is.Equal(changes[0].New.Size, int64(5)))
+	assert.Check(t, is.Nil(changes[1].New))
+	assert.Check(t, is.Equal(changes[2].Old.Mode.Perm(), os.FileMode(0644)))
+	assert.Check(t, is.Equal(changes[2].New.Mode.Perm(), os.FileMode(0600)))
+	assert.Check(t, changes[3].Old.Digest != changes[3].New.Digest)
+
+	assert.NilError(t, addContentDiffs(context.Background(), changes, pairs))
+	assert.Check(t, is.Equal(changes[2].Diff, ""))
+	assert.Check(t, is.Equal(changes[3].Diff, "--- a/etc/passwd\n+++ b/etc/passwd\n@@ -1 +1,2 @@\n root\n+user\n"))
+}
+
+func TestCompareFileTreesHardLink(t *testing.T) {
+	link := tarFile{hdr: tar.Header{Name: "bin/sh", Typeflag: tar.TypeLink, Linkname: "bin/bash", Mode: 0755}}
+	bash := file("bin/bash", "#!")
+	bash.hdr.Mode = 0755
+	base := buildTree(t, newFakeLayer(dir("bin/"), bash, link))
+	target := buildTree(t, newFakeLayer(dir("bin/"), bash, file("bin/sh", "#!")))
+
+	changes, _ := compareFileTrees(base, target)
+	assert.Assert(t, is.Len(changes, 1))
+	assert.Check(t, is.Equal(changes[0].Path, "/bin/sh"))
+	assert.Check(t, is.Equal(changes[0].Old.Digest, changes[0].New.Digest))
+}
+
+func TestCompareFileTreesEqual(t *testing.T) {
+	l := newFakeLayer(dir("etc/"), file("etc/passwd", "root\n"))
+	changes, _ := compareFileTrees(buildTree(t, l), buildTree(t, l))
+	assert.Check(t, is.DeepEqual(changes, []types.FileChange{}))
+}
@@ -3,7 +3,13 @@ package images // import "github.com/docker/docker/daemon/images"
 import (
 	"io"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
 	"github.com/docker/docker/image/tarexport"
+	"github.com/docker/docker/layer"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
 )
 
 // ExportImage exports a list of images to the given output stream. The
@@ -11,9 +17,20 @@ import (
 // stream. All images with the given tag and all versions containing
 // the same tag are exported. names is the set of tags to export, and
 // outStream is the writer which the images are written to.
-func (i *ImageService) ExportImage(names []string, outStream io.Writer) error {
+func (i *ImageService) ExportImage(names []string, opts types.ImageSaveOptions, outStream io.Writer) error {
+	exportOpts := image.ExportOptions{
+		Format:      opts.Format,
+		ExcludeBase: opts.ExcludeBase,
+	}
+	for _, l := range opts.ExcludeLayers {
+		dgst, err := digest.Parse(l)
+		if err != nil {
+			return errdefs.InvalidParameter(errors.Wrapf(err, "invalid layer digest %q", l))
+		}
+		exportOpts.ExcludeLayers = append(exportOpts.ExcludeLayers, layer.DiffID(dgst))
+	}
 	imageExporter := tarexport.NewTarExporter(i.imageStore, i.layerStores, i.referenceStore, i)
-	return imageExporter.Save(names, outStream)
+	return imageExporter.Save(names, outStream, exportOpts)
 }
 
 // LoadImage uploads a set of images into the repository. This is the
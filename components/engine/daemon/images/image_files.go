@@ -0,0 +1,39 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"os"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/containerfs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ListImageFiles lists the files at the path of the filesystem of an image
+// given by opts. The image is mounted while its files are listed.
+func (i *ImageService) ListImageFiles(refOrID string, opts types.ListFilesOptions) ([]types.FileEntry, error) {
+	img, err := i.GetImage(refOrID)
+	if err != nil {
+		return nil, err
+	}
+	m, _, err := i.acquireImageMount(img)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if _, err := i.releaseImageMount(img.ID(), false); err != nil {
+			logrus.WithError(err).WithField("image", img.ID()).Warn("failed to unmount image")
+		}
+	}()
+
+	entries, err := container.ListFiles(containerfs.NewLocalContainerFS(m.path), opts)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errdefs.NotFound(errors.Errorf("Could not find the file %s in image %s", opts.Path, refOrID))
+		}
+		return nil, errdefs.System(err)
+	}
+	return entries, nil
+}
@@ -63,6 +63,16 @@ func (i *ImageService) LookupImage(name string) (*types.ImageInspect, error) {
 		return nil, err
 	}
 
+	lastUsed, err := i.imageStore.GetLastUsed(img.ID())
+	if err != nil {
+		return nil, err
+	}
+	var lastUsedTime *time.Time
+	if !lastUsed.IsZero() {
+		// the field is omitted if the image was never used
+		lastUsedTime = &lastUsed
+	}
+
 	imageInspect := &types.ImageInspect{
 		ID:              img.ID().String(),
 		RepoTags:        repoTags,
@@ -82,7 +92,8 @@ func (i *ImageService) LookupImage(name string) (*types.ImageInspect, error) {
 		VirtualSize:     size, // TODO: field unused, deprecate
 		RootFS:          rootFSToAPIType(img.RootFS),
 		Metadata: types.ImageMetadata{
-			LastTagTime: lastUpdated,
+			LastTagTime:  lastUpdated,
+			LastUsedTime: lastUsedTime,
 		},
 	}
 
@@ -0,0 +1,243 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// imageMountPrefix is the prefix of the names of the RW layers which are
+// used to mount images.
+const imageMountPrefix = "image-mount-"
+
+type imageMount struct {
+	count   int
+	os      string
+	rwLayer layer.RWLayer
+	path    string
+}
+
+// MountImage mounts the root filesystem of an image read-only on the host,
+// and returns the path at which it is mounted. Mounts are reference counted:
+// the filesystem stays mounted until UnmountImage is called as many times as
+// MountImage. A mounted image cannot be removed.
+func (i *ImageService) MountImage(refOrID string) (string, error) {
+	img, err := i.GetImage(refOrID)
+	if err != nil {
+		return "", err
+	}
+	m, created, err := i.acquireImageMount(img)
+	if err != nil {
+		return "", err
+	}
+	if created {
+		i.LogImageEvent(img.ID().String(), img.ID().String(), "mount")
+	}
+	return m.path, nil
+}
+
+// acquireImageMount increments the reference count of the mount of an image,
+// and mounts the image if it is not mounted yet.
+func (i *ImageService) acquireImageMount(img *image.Image) (_ *imageMount, created bool, _ error) {
+	operatingSystem := img.OperatingSystem()
+	if !system.IsOSSupported(operatingSystem) {
+		return nil, false, errdefs.InvalidParameter(system.ErrNotSupportedOperatingSystem)
+	}
+	id := img.ID()
+
+	i.mountsMu.Lock()
+	defer i.mountsMu.Unlock()
+	if m, ok := i.mounts[id]; ok {
+		m.count++
+		return m, false, nil
+	}
+
+	m, err := i.mountImage(id, img.RootFS.ChainID(), operatingSystem)
+	if err != nil {
+		return nil, false, err
+	}
+	i.mounts[id] = m
+	return m, true, nil
+}
+
+func (i *ImageService) mountImage(id image.ID, chainID layer.ChainID, operatingSystem string) (_ *imageMount, retErr error) {
+	// The directory is created first, so that the RW layer is released on
+	// restart even if the daemon exits while the image is being mounted.
+	target := filepath.Join(i.mountRoot, id.Digest().Hex())
+	if err := system.MkdirAll(target, 0700, ""); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if retErr != nil {
+			if err := removeImageMountTarget(target); err != nil {
+				logrus.WithError(err).WithField("image", id).Warn("failed to remove image mount point")
+			}
+		}
+	}()
+
+	ls := i.layerStores[operatingSystem]
+	rwLayer, err := ls.CreateRWLayer(imageMountPrefix+id.Digest().Hex(), chainID, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if retErr != nil {
+			metadata, err := ls.ReleaseRWLayer(rwLayer)
+			layer.LogReleaseMetadata(metadata)
+			if err != nil {
+				logrus.WithError(err).WithField("image", id).Warn("failed to release image mount layer")
+			}
+		}
+	}()
+
+	fs, err := rwLayer.Mount("")
+	if err != nil {
+		return nil, err
+	}
+	if err := bindImageMount(fs.Path(), target); err != nil {
+		if err := rwLayer.Unmount(); err != nil {
+			logrus.WithError(err).WithField("image", id).Warn("failed to unmount image mount layer")
+		}
+		return nil, err
+	}
+
+	return &imageMount{count: 1, os: operatingSystem, rwLayer: rwLayer, path: target}, nil
+}
+
+// UnmountImage releases a mount of an image made by MountImage. If force is
+// set, all mounts of the image are released.
+func (i *ImageService) UnmountImage(refOrID string, force bool) error {
+	img, err := i.GetImage(refOrID)
+	if err != nil {
+		return err
+	}
+	unmounted, err := i.releaseImageMount(img.ID(), force)
+	if err != nil {
+		return err
+	}
+	if unmounted {
+		i.LogImageEvent(img.ID().String(), img.ID().String(), "unmount")
+	}
+	return nil
+}
+
+// releaseImageMount decrements the reference count of the mount of an image,
+// or resets it if force is set, and returns whether the image was unmounted
+// because the count dropped to zero.
+func (i *ImageService) releaseImageMount(id image.ID, force bool) (bool, error) {
+	i.mountsMu.Lock()
+	defer i.mountsMu.Unlock()
+	m, ok := i.mounts[id]
+	if !ok {
+		return false, errdefs.Conflict(fmt.Errorf("image %s is not mounted", id))
+	}
+	if m.count > 1 && !force {
+		m.count--
+		return false, nil
+	}
+	if err := i.unmountImage(m); err != nil {
+		return false, err
+	}
+	delete(i.mounts, id)
+	return true, nil
+}
+
+func (i *ImageService) unmountImage(m *imageMount) error {
+	if err := unbindImageMount(m.path); err != nil {
+		return err
+	}
+	if err := m.rwLayer.Unmount(); err != nil {
+		return err
+	}
+	metadata, err := i.layerStores[m.os].ReleaseRWLayer(m.rwLayer)
+	layer.LogReleaseMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	return removeImageMountTarget(m.path)
+}
+
+// isImageMounted returns whether the image is mounted by MountImage.
+func (i *ImageService) isImageMounted(id image.ID) bool {
+	i.mountsMu.Lock()
+	defer i.mountsMu.Unlock()
+	_, ok := i.mounts[id]
+	return ok
+}
+
+// imageMountNames returns the names of the RW layers of the mounted images.
+func (i *ImageService) imageMountNames() map[string]bool {
+	i.mountsMu.Lock()
+	defer i.mountsMu.Unlock()
+	names := make(map[string]bool, len(i.mounts))
+	for _, m := range i.mounts {
+		names[m.rwLayer.Name()] = true
+	}
+	return names
+}
+
+// releaseImageMounts unmounts all images mounted by MountImage. It is
+// called on shutdown, as mounts are not restored when the daemon restarts.
+func (i *ImageService) releaseImageMounts() {
+	i.mountsMu.Lock()
+	defer i.mountsMu.Unlock()
+	for id, m := range i.mounts {
+		if err := i.unmountImage(m); err != nil {
+			logrus.WithError(err).WithField("image", id).Warn("failed to unmount image")
+		}
+		delete(i.mounts, id)
+	}
+}
+
+// cleanupImageMounts releases the mounts of images left behind when the
+// daemon exited without unmounting them.
+func (i *ImageService) cleanupImageMounts() {
+	dirs, err := ioutil.ReadDir(i.mountRoot)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.WithError(err).Warn("failed to clean up image mounts")
+		}
+		return
+	}
+	for _, d := range dirs {
+		target := filepath.Join(i.mountRoot, d.Name())
+		if err := unbindImageMount(target); err != nil {
+			logrus.WithError(err).WithField("path", target).Warn("failed to clean up image mount")
+			continue
+		}
+		for _, ls := range i.layerStores {
+			rwLayer, err := ls.GetRWLayer(imageMountPrefix + d.Name())
+			if err != nil {
+				continue
+			}
+			// The filesystem may still be mounted if the daemon did not
+			// exit cleanly.
+			if err := rwLayer.Unmount(); err != nil {
+				logrus.WithError(err).WithField("path", target).Debug("failed to unmount image mount layer")
+			}
+			metadata, err := ls.ReleaseRWLayer(rwLayer)
+			layer.LogReleaseMetadata(metadata)
+			if err != nil {
+				logrus.WithError(err).WithField("path", target).Warn("failed to clean up image mount layer")
+			}
+		}
+		if err := removeImageMountTarget(target); err != nil {
+			logrus.WithError(err).WithField("path", target).Warn("failed to clean up image mount")
+		}
+	}
+}
+
+func removeImageMountTarget(target string) error {
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "error removing image mount point")
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import "github.com/docker/docker/pkg/mount"
+
+// bindImageMount makes the filesystem mounted at source available read-only
+// at target.
+func bindImageMount(source, target string) error {
+	return mount.Mount(source, target, "bind", "bind,ro")
+}
+
+func unbindImageMount(target string) error {
+	return mount.Unmount(target)
+}
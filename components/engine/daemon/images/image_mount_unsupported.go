@@ -0,0 +1,17 @@
+// +build !linux
+
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"errors"
+
+	"github.com/docker/docker/errdefs"
+)
+
+func bindImageMount(source, target string) error {
+	return errdefs.NotImplemented(errors.New("mounting images is not supported on this platform"))
+}
+
+func unbindImageMount(target string) error {
+	return nil
+}
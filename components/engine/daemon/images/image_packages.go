@@ -0,0 +1,276 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/system"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxPackageFileSize is the maximum size of a package database or of a
+	// lockfile which is read.
+	maxPackageFileSize = 64 * 1024 * 1024
+	// maxGoBinarySize is the maximum size of a binary which is searched for
+	// Go build information.
+	maxGoBinarySize = 256 * 1024 * 1024
+)
+
+var elfMagic = []byte("\x7fELF")
+
+// ImagePackages returns the inventory of the packages of an image. The
+// inventory recorded when the image was built is returned if there is one,
+// otherwise the inventory is made from the layers of the image.
+func (i *ImageService) ImagePackages(ctx context.Context, refOrID string) (*types.ImagePackages, error) {
+	img, err := i.GetImage(refOrID)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := i.imageStore.GetPackages(img.ID())
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		var inventory types.ImagePackages
+		err := json.Unmarshal(stored, &inventory)
+		if err == nil {
+			return &inventory, nil
+		}
+		logrus.WithError(err).WithField("image", img.ID()).Warn("ignoring invalid inventory of the packages of the image")
+	}
+
+	start := time.Now()
+	inventory, err := i.listPackages(ctx, img)
+	if err != nil {
+		return nil, err
+	}
+	imageActions.WithValues("packages").UpdateSince(start)
+	return inventory, nil
+}
+
+// StoreImagePackages makes the inventory of the packages of an image, and
+// stores it with the image, to be returned by ImagePackages.
+func (i *ImageService) StoreImagePackages(ctx context.Context, id image.ID) (*types.ImagePackages, error) {
+	img, err := i.imageStore.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	inventory, err := i.listPackages(ctx, img)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(inventory)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.imageStore.SetPackages(id, data); err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// packageFile is a file of an image which lists packages.
+type packageFile struct {
+	path  string
+	parse packageParser
+	// binary is set for executable files, which are only read if they are
+	// ELF binaries, for their Go build information. parse is not used for
+	// them.
+	binary bool
+}
+
+// listPackages makes the inventory of the packages of an image, from the
+// package databases, the lockfiles, and the Go binaries found in its layers.
+// The layers are read without being mounted.
+func (i *ImageService) listPackages(ctx context.Context, img *image.Image) (*types.ImagePackages, error) {
+	if !system.IsOSSupported(img.OperatingSystem()) {
+		return nil, errdefs.InvalidParameter(system.ErrNotSupportedOperatingSystem)
+	}
+	layers, release, err := getLayerChain(i.layerStores[img.OperatingSystem()], img.RootFS)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	tree := fileTree{}
+	for _, l := range layers {
+		if err := tree.applyLayer(ctx, l); err != nil {
+			return nil, err
+		}
+	}
+
+	inventory := &types.ImagePackages{
+		ID:       img.ID().String(),
+		Created:  time.Now().UTC(),
+		Packages: []types.ImagePackage{},
+	}
+	if err := tree.readPackages(ctx, inventory); err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// readPackages adds the packages listed in the files of the tree to the
+// inventory, and reads the distribution of the tree from os-release.
+func (t fileTree) readPackages(ctx context.Context, inventory *types.ImagePackages) error {
+	// The files to read, by layer and by name in the tar stream of the layer.
+	files := map[layer.TarStreamer]map[string][]packageFile{}
+	add := func(p string, e *fileEntry, f packageFile) {
+		if files[e.src] == nil {
+			files[e.src] = map[string][]packageFile{}
+		}
+		files[e.src][e.srcName] = append(files[e.src][e.srcName], f)
+	}
+	for p, e := range t {
+		if e.typeflag != tar.TypeReg || e.src == nil {
+			continue
+		}
+		if parse := packageParserFor(p); parse != nil {
+			if e.size <= maxPackageFileSize {
+				add(p, e, packageFile{path: p, parse: parse})
+			}
+		} else if e.mode&0111 != 0 && e.size <= maxGoBinarySize {
+			add(p, e, packageFile{path: p, binary: true})
+		}
+	}
+	for _, p := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		if p, e := t.resolve(p); e != nil && e.typeflag == tar.TypeReg && e.src != nil {
+			add(p, e, packageFile{path: p, parse: func(_ string, content []byte) ([]types.ImagePackage, error) {
+				inventory.OS, inventory.OSVersion = parseOSRelease(content)
+				return nil, nil
+			}})
+			break
+		}
+	}
+
+	for src, names := range files {
+		wanted := make(map[string]bool, len(names))
+		for name := range names {
+			wanted[name] = true
+		}
+		err := walkLayerFiles(ctx, src, wanted, func(name string, r io.Reader) error {
+			packages, err := readPackageFile(r, names[name])
+			if err != nil {
+				return err
+			}
+			inventory.Packages = append(inventory.Packages, packages...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range inventory.Packages {
+		inventory.Packages[i].PURL = packageURL(inventory.Packages[i], inventory.OS)
+	}
+	sortPackages(inventory.Packages)
+	return nil
+}
+
+// readPackageFile reads a file of a layer, and returns the packages it lists
+// for each of the paths at which the file is in the image. Errors parsing the
+// file are logged, so that a malformed file does not prevent listing the
+// other packages of the image.
+func readPackageFile(r io.Reader, files []packageFile) ([]types.ImagePackage, error) {
+	onlyBinaries := true
+	for _, f := range files {
+		onlyBinaries = onlyBinaries && f.binary
+	}
+	head := make([]byte, len(elfMagic))
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+	isELF := bytes.Equal(head, elfMagic)
+	if onlyBinaries && !isELF {
+		// Most executable files are not Go binaries, and only the header of
+		// the file is read to skip scripts.
+		return nil, nil
+	}
+
+	var (
+		content []byte
+		binary  io.ReaderAt
+	)
+	if onlyBinaries {
+		// Binaries can be large, and the layer is read as a stream, so the
+		// binary is copied to a temporary file from which only the headers
+		// and the build information are read.
+		tmp, err := ioutil.TempFile("", "docker-packages-")
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}()
+		if _, err := io.Copy(tmp, io.MultiReader(bytes.NewReader(head), r)); err != nil {
+			return nil, err
+		}
+		binary = tmp
+	} else {
+		rest, err := ioutil.ReadAll(io.LimitReader(r, maxPackageFileSize))
+		if err != nil {
+			return nil, err
+		}
+		content = append(head, rest...)
+		binary = bytes.NewReader(content)
+	}
+
+	var packages []types.ImagePackage
+	for _, f := range files {
+		var (
+			found []types.ImagePackage
+			err   error
+		)
+		if f.binary {
+			if !isELF {
+				continue
+			}
+			found, err = parseGoBinary(f.path, binary)
+		} else {
+			found, err = f.parse(f.path, content)
+		}
+		if err != nil {
+			logrus.WithError(err).Debugf("failed to read the packages listed in %s", f.path)
+			continue
+		}
+		packages = append(packages, found...)
+	}
+	return packages, nil
+}
+
+// resolve follows the symbolic link at p, if it is one, and returns the path
+// and the entry of the file it points to.
+func (t fileTree) resolve(p string) (string, *fileEntry) {
+	// Limit the number of links which are followed, as in the kernel.
+	for n := 0; n < 40; n++ {
+		e, ok := t[p]
+		if !ok {
+			return "", nil
+		}
+		if e.typeflag != tar.TypeSymlink {
+			return p, e
+		}
+		target := e.linkname
+		if !path.IsAbs(target) {
+			target = path.Join(path.Dir(p), target)
+		}
+		p = path.Clean(target)
+	}
+	return "", nil
+}
@@ -0,0 +1,39 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"archive/tar"
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestFileTreeReadPackages(t *testing.T) {
+	osRelease := tarFile{hdr: tar.Header{Name: "etc/os-release", Typeflag: tar.TypeSymlink, Linkname: "../usr/lib/os-release"}}
+	script := file("usr/bin/script", "#!/bin/sh\n")
+	script.hdr.Mode = 0755
+	tree := buildTree(t,
+		newFakeLayer(
+			dir("etc/"), osRelease,
+			dir("usr/"), dir("usr/lib/"), file("usr/lib/os-release", "ID=alpine\nVERSION_ID=3.11.3\n"),
+			dir("usr/bin/"), script,
+			dir("lib/"), dir("lib/apk/"), dir("lib/apk/db/"), file("lib/apk/db/installed", "P:musl\nV:1.1.24-r1\nA:x86_64\n"),
+		),
+		newFakeLayer(
+			dir("lib/apk/db/"), file("lib/apk/db/installed", "P:musl\nV:1.1.24-r2\nA:x86_64\n\nP:busybox\nV:1.31.1-r9\nA:x86_64\n"),
+			dir("app/"), file("app/yarn.lock", "lodash@^4.17.13:\n  version \"4.17.15\"\n"),
+		),
+	)
+
+	inventory := &types.ImagePackages{}
+	assert.NilError(t, tree.readPackages(context.Background(), inventory))
+	assert.Check(t, is.Equal(inventory.OS, "alpine"))
+	assert.Check(t, is.Equal(inventory.OSVersion, "3.11.3"))
+	assert.Check(t, is.DeepEqual(inventory.Packages, []types.ImagePackage{
+		{Name: "busybox", Version: "1.31.1-r9", Type: "apk", Arch: "x86_64", Path: "/lib/apk/db/installed", PURL: "pkg:apk/alpine/busybox@1.31.1-r9?arch=x86_64"},
+		{Name: "musl", Version: "1.1.24-r2", Type: "apk", Arch: "x86_64", Path: "/lib/apk/db/installed", PURL: "pkg:apk/alpine/musl@1.1.24-r2?arch=x86_64"},
+		{Name: "lodash", Version: "4.17.15", Type: "npm", Path: "/app/yarn.lock", PURL: "pkg:npm/lodash@4.17.15"},
+	}))
+}
@@ -19,10 +19,11 @@ import (
 )
 
 var imagesAcceptedFilters = map[string]bool{
-	"dangling": true,
-	"label":    true,
-	"label!":   true,
-	"until":    true,
+	"dangling":        true,
+	"label":           true,
+	"label!":          true,
+	"until":           true,
+	"until-last-used": true,
 }
 
 // errPruneRunning is returned when a prune request is received while
@@ -58,6 +59,11 @@ func (i *ImageService) ImagesPrune(ctx context.Context, pruneFilters filters.Arg
 		return nil, err
 	}
 
+	untilLastUsed, err := getTimestampFromPruneFilters(pruneFilters, "until-last-used")
+	if err != nil {
+		return nil, err
+	}
+
 	var allImages map[image.ID]*image.Image
 	if danglingOnly {
 		allImages = i.imageStore.Heads()
@@ -85,9 +91,15 @@ func (i *ImageService) ImagesPrune(ctx context.Context, pruneFilters filters.Arg
 			if !until.IsZero() && img.Created.After(until) {
 				continue
 			}
+			if !untilLastUsed.IsZero() && i.imageLastUsed(img).After(untilLastUsed) {
+				continue
+			}
 			if img.Config != nil && !matchLabels(pruneFilters, img.Config.Labels) {
 				continue
 			}
+			if i.isImageMounted(id) {
+				continue
+			}
 			topImages[id] = img
 		}
 	}
@@ -189,14 +201,29 @@ func matchLabels(pruneFilters filters.Args, labels map[string]string) bool {
 	return true
 }
 
+// imageLastUsed returns the time at which a container was last created or
+// started from the image. Images that were never used fall back to their
+// creation time.
+func (i *ImageService) imageLastUsed(img *image.Image) time.Time {
+	lastUsed, err := i.imageStore.GetLastUsed(img.ID())
+	if err != nil || lastUsed.IsZero() {
+		return img.Created
+	}
+	return lastUsed
+}
+
 func getUntilFromPruneFilters(pruneFilters filters.Args) (time.Time, error) {
+	return getTimestampFromPruneFilters(pruneFilters, "until")
+}
+
+func getTimestampFromPruneFilters(pruneFilters filters.Args, name string) (time.Time, error) {
 	until := time.Time{}
-	if !pruneFilters.Contains("until") {
+	if !pruneFilters.Contains(name) {
 		return until, nil
 	}
-	untilFilters := pruneFilters.Get("until")
+	untilFilters := pruneFilters.Get(name)
 	if len(untilFilters) > 1 {
-		return until, fmt.Errorf("more than one until filter specified")
+		return until, fmt.Errorf("more than one %s filter specified", name)
 	}
 	ts, err := timetypes.GetTimestamp(untilFilters[0], time.Now())
 	if err != nil {
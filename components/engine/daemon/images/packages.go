@@ -0,0 +1,498 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// packageParser returns the packages listed in the content of the file at
+// path.
+type packageParser func(path string, content []byte) ([]types.ImagePackage, error)
+
+// packageParserFor returns the parser of the package database, the lockfile,
+// or the package manifest at path, or nil if the file lists no packages.
+func packageParserFor(p string) packageParser {
+	dir, base := path.Split(p)
+	dir = path.Clean(dir)
+	switch {
+	case p == "/var/lib/dpkg/status":
+		return parseDpkgStatus
+	case dir == "/var/lib/dpkg/status.d" && !strings.HasSuffix(base, ".md5sums"):
+		// Images without dpkg, such as distroless images, have a file per
+		// package, next to the checksums of the files of the package.
+		return parseDpkgStatus
+	case p == "/lib/apk/db/installed":
+		return parseApkInstalled
+	case p == "/var/lib/rpmmanifest/container-manifest-2":
+		return parseRpmManifest
+	case base == "METADATA" && strings.HasSuffix(dir, ".dist-info"),
+		base == "PKG-INFO" && strings.HasSuffix(dir, ".egg-info"):
+		return parsePythonMetadata
+	case base == "Pipfile.lock":
+		return parsePipfileLock
+	case base == "poetry.lock":
+		return parsePoetryLock
+	case strings.Contains(p, "/node_modules/"):
+		// Lockfiles of the installed node modules are ignored, as the
+		// modules themselves are listed.
+		if base == "package.json" && isNodeModuleDir(dir) {
+			return parseNodePackageJSON
+		}
+	case base == "package-lock.json", base == "npm-shrinkwrap.json":
+		return parseNpmLock
+	case base == "yarn.lock":
+		return parseYarnLock
+	}
+	return nil
+}
+
+// isNodeModuleDir returns whether dir is the directory of a module installed
+// in a node_modules directory, such as node_modules/name or
+// node_modules/@scope/name.
+func isNodeModuleDir(dir string) bool {
+	parent, name := path.Split(dir)
+	parent = path.Clean(parent)
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	if strings.HasPrefix(path.Base(parent), "@") {
+		parent = path.Dir(parent)
+	}
+	return path.Base(parent) == "node_modules"
+}
+
+// parseParagraphs parses a file made of paragraphs of fields, separated by
+// blank lines. Each field is split in a key and a value by split, and lines
+// starting with a space continue the value of the previous field.
+func parseParagraphs(content []byte, split func(line string) (key, value string, ok bool)) []map[string]string {
+	var (
+		paragraphs []map[string]string
+		current    map[string]string
+		lastKey    string
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+		if current == nil {
+			current = map[string]string{}
+			paragraphs = append(paragraphs, current)
+			lastKey = ""
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if lastKey != "" {
+				current[lastKey] += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+		if key, value, ok := split(line); ok {
+			current[key] = value
+			lastKey = key
+		}
+	}
+	return paragraphs
+}
+
+// splitHeaderField splits a field of a dpkg database or of the metadata of a
+// Python package, such as "Package: bash".
+func splitHeaderField(line string) (string, string, bool) {
+	i := strings.IndexByte(line, ':')
+	if i < 0 {
+		return "", "", false
+	}
+	return line[:i], strings.TrimSpace(line[i+1:]), true
+}
+
+// parseDpkgStatus parses the database of the packages installed by dpkg.
+func parseDpkgStatus(p string, content []byte) ([]types.ImagePackage, error) {
+	var packages []types.ImagePackage
+	for _, fields := range parseParagraphs(content, splitHeaderField) {
+		if fields["Package"] == "" {
+			continue
+		}
+		// The database also lists packages which were removed, but whose
+		// configuration files are still installed.
+		if status, ok := fields["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		source := fields["Source"]
+		if i := strings.IndexByte(source, ' '); i >= 0 {
+			// The version of the source package may follow its name.
+			source = source[:i]
+		}
+		packages = append(packages, types.ImagePackage{
+			Name:    fields["Package"],
+			Version: fields["Version"],
+			Type:    "deb",
+			Arch:    fields["Architecture"],
+			Source:  source,
+			Path:    p,
+		})
+	}
+	return packages, nil
+}
+
+// parseApkInstalled parses the database of the packages installed by apk.
+func parseApkInstalled(p string, content []byte) ([]types.ImagePackage, error) {
+	split := func(line string) (string, string, bool) {
+		if len(line) < 2 || line[1] != ':' {
+			return "", "", false
+		}
+		return line[:1], line[2:], true
+	}
+	var packages []types.ImagePackage
+	for _, fields := range parseParagraphs(content, split) {
+		if fields["P"] == "" {
+			continue
+		}
+		packages = append(packages, types.ImagePackage{
+			Name:    fields["P"],
+			Version: fields["V"],
+			Type:    "apk",
+			Arch:    fields["A"],
+			Source:  fields["o"],
+			License: fields["L"],
+			Path:    p,
+		})
+	}
+	return packages, nil
+}
+
+// parseRpmManifest parses the text manifest of the installed RPM packages
+// which some distributions, such as CBL-Mariner, include in their images
+// instead of the RPM database. Each line has tab-separated fields: the name,
+// the version and release, the install and build times, the vendor, the
+// epoch, the size, the architecture, the epoch number, and the source RPM.
+func parseRpmManifest(p string, content []byte) ([]types.ImagePackage, error) {
+	var packages []types.ImagePackage
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 || fields[0] == "" {
+			continue
+		}
+		pkg := types.ImagePackage{Name: fields[0], Version: fields[1], Type: "rpm", Path: p}
+		if len(fields) > 7 {
+			pkg.Arch = fields[7]
+		}
+		if len(fields) > 9 {
+			pkg.Source = fields[9]
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// parsePythonMetadata parses the metadata of an installed Python package.
+func parsePythonMetadata(p string, content []byte) ([]types.ImagePackage, error) {
+	// The fields are followed by the description of the package, after a
+	// blank line.
+	paragraphs := parseParagraphs(content, splitHeaderField)
+	if len(paragraphs) == 0 || paragraphs[0]["Name"] == "" {
+		return nil, nil
+	}
+	fields := paragraphs[0]
+	license := fields["License-Expression"]
+	if license == "" && !strings.Contains(fields["License"], "\n") {
+		license = fields["License"]
+	}
+	if license == "UNKNOWN" {
+		license = ""
+	}
+	return []types.ImagePackage{{
+		Name:    fields["Name"],
+		Version: fields["Version"],
+		Type:    "pypi",
+		License: license,
+		Path:    p,
+	}}, nil
+}
+
+type pipfileLockPackage struct {
+	Version string `json:"version"`
+}
+
+// parsePipfileLock parses the lockfile of Pipenv.
+func parsePipfileLock(p string, content []byte) ([]types.ImagePackage, error) {
+	var lock struct {
+		Default map[string]pipfileLockPackage `json:"default"`
+		Develop map[string]pipfileLockPackage `json:"develop"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, err
+	}
+
+	var packages []types.ImagePackage
+	for _, section := range []map[string]pipfileLockPackage{lock.Default, lock.Develop} {
+		for name, pkg := range section {
+			packages = append(packages, types.ImagePackage{
+				Name:    name,
+				Version: strings.TrimPrefix(pkg.Version, "=="),
+				Type:    "pypi",
+				Path:    p,
+			})
+		}
+	}
+	return packages, nil
+}
+
+// parsePoetryLock parses the lockfile of Poetry. Only the name and the
+// version of the packages are needed, so the TOML file is parsed line by
+// line.
+func parsePoetryLock(p string, content []byte) ([]types.ImagePackage, error) {
+	var (
+		packages  []types.ImagePackage
+		inPackage bool
+	)
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inPackage = line == "[[package]]"
+			if inPackage {
+				packages = append(packages, types.ImagePackage{Type: "pypi", Path: p})
+			}
+			continue
+		}
+		if !inPackage {
+			continue
+		}
+		key, value, ok := splitTOMLString(line)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "name":
+			packages[len(packages)-1].Name = value
+		case "version":
+			packages[len(packages)-1].Version = value
+		}
+	}
+	return packages, nil
+}
+
+// splitTOMLString splits a TOML key/value pair whose value is a string.
+func splitTOMLString(line string) (string, string, bool) {
+	i := strings.IndexByte(line, '=')
+	if i < 0 {
+		return "", "", false
+	}
+	key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return "", "", false
+	}
+	return key, value[1 : len(value)-1], true
+}
+
+// nodeLicense is the license of a node module, which is either an SPDX
+// expression or, in older modules, an object with a type.
+type nodeLicense string
+
+func (l *nodeLicense) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = nodeLicense(s)
+		return nil
+	}
+	var o struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &o); err == nil {
+		*l = nodeLicense(o.Type)
+	}
+	return nil
+}
+
+// parseNodePackageJSON parses the manifest of an installed node module.
+func parseNodePackageJSON(p string, content []byte) ([]types.ImagePackage, error) {
+	var manifest struct {
+		Name    string      `json:"name"`
+		Version string      `json:"version"`
+		License nodeLicense `json:"license"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Name == "" || manifest.Version == "" {
+		return nil, nil
+	}
+	return []types.ImagePackage{{
+		Name:    manifest.Name,
+		Version: manifest.Version,
+		Type:    "npm",
+		License: string(manifest.License),
+		Path:    p,
+	}}, nil
+}
+
+type npmLockDependency struct {
+	Version      string                       `json:"version"`
+	License      nodeLicense                  `json:"license"`
+	Link         bool                         `json:"link"`
+	Dependencies map[string]npmLockDependency `json:"dependencies"`
+}
+
+// parseNpmLock parses the lockfile of npm.
+func parseNpmLock(p string, content []byte) ([]types.ImagePackage, error) {
+	var lock struct {
+		LockfileVersion int                          `json:"lockfileVersion"`
+		Packages        map[string]npmLockDependency `json:"packages"`
+		Dependencies    map[string]npmLockDependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, err
+	}
+
+	var packages []types.ImagePackage
+	if lock.Packages != nil {
+		// Since version 2, the packages are listed by their location.
+		for location, dep := range lock.Packages {
+			i := strings.LastIndex(location, "node_modules/")
+			if i < 0 || dep.Link || dep.Version == "" {
+				continue
+			}
+			packages = append(packages, types.ImagePackage{
+				Name:    location[i+len("node_modules/"):],
+				Version: dep.Version,
+				Type:    "npm",
+				License: string(dep.License),
+				Path:    p,
+			})
+		}
+		return packages, nil
+	}
+
+	var walk func(deps map[string]npmLockDependency)
+	walk = func(deps map[string]npmLockDependency) {
+		for name, dep := range deps {
+			if !strings.HasPrefix(dep.Version, "file:") {
+				packages = append(packages, types.ImagePackage{Name: name, Version: dep.Version, Type: "npm", Path: p})
+			}
+			walk(dep.Dependencies)
+		}
+	}
+	walk(lock.Dependencies)
+	return packages, nil
+}
+
+var yarnVersionRegexp = regexp.MustCompile(`^\s+version:?\s+"?([^"]+)"?$`)
+
+// parseYarnLock parses the lockfile of Yarn, in the format of Yarn 1 or in
+// the YAML format of later versions.
+func parseYarnLock(p string, content []byte) ([]types.ImagePackage, error) {
+	var (
+		packages []types.ImagePackage
+		name     string
+	)
+	for _, line := range strings.Split(string(content), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line[0] != ' ' {
+			// The entry of a package starts with its specifications, such as
+			// `"@babel/core@^7.0.0", "@babel/core@^7.1.0":`.
+			name = ""
+			spec := strings.TrimSuffix(line, ":")
+			if i := strings.IndexByte(spec, ','); i >= 0 {
+				spec = spec[:i]
+			}
+			spec = strings.Trim(spec, `"`)
+			if i := strings.LastIndexByte(spec, '@'); i > 0 {
+				name = spec[:i]
+			}
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		if m := yarnVersionRegexp.FindStringSubmatch(line); m != nil {
+			packages = append(packages, types.ImagePackage{Name: name, Version: m[1], Type: "npm", Path: p})
+			name = ""
+		}
+	}
+	return packages, nil
+}
+
+// parseOSRelease returns the ID and the version of the distribution from the
+// content of an os-release file.
+func parseOSRelease(content []byte) (id, version string) {
+	for _, line := range strings.Split(string(content), "\n") {
+		i := strings.IndexByte(line, '=')
+		if i < 0 {
+			continue
+		}
+		value := strings.Trim(strings.TrimSpace(line[i+1:]), `"'`)
+		switch strings.TrimSpace(line[:i]) {
+		case "ID":
+			id = value
+		case "VERSION_ID":
+			version = value
+		}
+	}
+	return id, version
+}
+
+// packageURL returns the package URL of a package. OS packages are in the
+// namespace of the distribution of the image.
+func packageURL(pkg types.ImagePackage, osID string) string {
+	if pkg.Name == "" || pkg.Version == "" {
+		return ""
+	}
+	var name string
+	switch pkg.Type {
+	case "deb", "apk", "rpm":
+		name = url.PathEscape(pkg.Name)
+		if osID != "" {
+			name = url.PathEscape(osID) + "/" + name
+		}
+	case "golang":
+		segments := strings.Split(pkg.Name, "/")
+		for i, s := range segments {
+			segments[i] = url.PathEscape(s)
+		}
+		name = strings.Join(segments, "/")
+	case "pypi":
+		name = url.PathEscape(strings.Replace(strings.ToLower(pkg.Name), "_", "-", -1))
+	case "npm":
+		scope, n := "", pkg.Name
+		if i := strings.IndexByte(n, '/'); strings.HasPrefix(n, "@") && i > 0 {
+			// The "@" of the scope is escaped in package URLs.
+			scope, n = "%40"+url.PathEscape(n[1:i])+"/", n[i+1:]
+		}
+		name = scope + url.PathEscape(n)
+	default:
+		return ""
+	}
+	purl := "pkg:" + pkg.Type + "/" + name + "@" + url.PathEscape(pkg.Version)
+	if pkg.Arch != "" {
+		purl += "?arch=" + url.QueryEscape(pkg.Arch)
+	}
+	return purl
+}
+
+// sortPackages sorts packages by type, name, version, and path.
+func sortPackages(packages []types.ImagePackage) {
+	sort.Slice(packages, func(i, j int) bool {
+		a, b := packages[i], packages[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Path < b.Path
+	})
+}
@@ -0,0 +1,210 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestPackageParserFor(t *testing.T) {
+	for _, p := range []string{
+		"/var/lib/dpkg/status",
+		"/var/lib/dpkg/status.d/libssl1.1",
+		"/lib/apk/db/installed",
+		"/var/lib/rpmmanifest/container-manifest-2",
+		"/usr/lib/python3/dist-packages/six-1.16.0.dist-info/METADATA",
+		"/usr/lib/python2.7/site-packages/six-1.10.0.egg-info/PKG-INFO",
+		"/app/Pipfile.lock",
+		"/app/poetry.lock",
+		"/app/package-lock.json",
+		"/app/yarn.lock",
+		"/app/node_modules/express/package.json",
+		"/usr/local/lib/node_modules/@npmcli/arborist/package.json",
+	} {
+		assert.Check(t, packageParserFor(p) != nil, p)
+	}
+	for _, p := range []string{
+		"/var/lib/dpkg/status.d/libssl1.1.md5sums",
+		"/app/package.json",
+		"/app/node_modules/express/lib/package.json",
+		"/app/node_modules/.package-lock.json",
+		"/app/node_modules/express/package-lock.json",
+		"/etc/passwd",
+	} {
+		assert.Check(t, packageParserFor(p) == nil, p)
+	}
+}
+
+func TestParseDpkgStatus(t *testing.T) {
+	content := `Package: bash
+Status: install ok installed
+Architecture: amd64
+Version: 5.0-4
+Description: GNU Bourne Again SHell
+ Bash is an sh-compatible command language interpreter.
+
+Package: libgcc1
+Status: deinstall ok config-files
+Version: 1:8.3.0-6
+
+Package: libc6
+Status: install ok installed
+Architecture: amd64
+Source: glibc (2.28-10)
+Version: 2.28-10
+`
+	packages, err := parseDpkgStatus("/var/lib/dpkg/status", []byte(content))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(packages, []types.ImagePackage{
+		{Name: "bash", Version: "5.0-4", Type: "deb", Arch: "amd64", Path: "/var/lib/dpkg/status"},
+		{Name: "libc6", Version: "2.28-10", Type: "deb", Arch: "amd64", Source: "glibc", Path: "/var/lib/dpkg/status"},
+	}))
+}
+
+func TestParseApkInstalled(t *testing.T) {
+	content := `C:Q1Gy3ZmT4+qdz8qhlEbr7PDGoMdQ4=
+P:musl
+V:1.1.24-r2
+A:x86_64
+L:MIT
+o:musl
+
+P:busybox
+V:1.31.1-r9
+A:x86_64
+L:GPL-2.0-only
+o:busybox
+`
+	packages, err := parseApkInstalled("/lib/apk/db/installed", []byte(content))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(packages, []types.ImagePackage{
+		{Name: "musl", Version: "1.1.24-r2", Type: "apk", Arch: "x86_64", Source: "musl", License: "MIT", Path: "/lib/apk/db/installed"},
+		{Name: "busybox", Version: "1.31.1-r9", Type: "apk", Arch: "x86_64", Source: "busybox", License: "GPL-2.0-only", Path: "/lib/apk/db/installed"},
+	}))
+}
+
+func TestParseRpmManifest(t *testing.T) {
+	content := "bash\t5.1.8-1.cm2\t1650000000\t1640000000\tMicrosoft Corporation\t(none)\t7300000\tx86_64\t0\tbash-5.1.8-1.cm2.src.rpm\n"
+	packages, err := parseRpmManifest("/var/lib/rpmmanifest/container-manifest-2", []byte(content))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(packages, []types.ImagePackage{
+		{Name: "bash", Version: "5.1.8-1.cm2", Type: "rpm", Arch: "x86_64", Source: "bash-5.1.8-1.cm2.src.rpm", Path: "/var/lib/rpmmanifest/container-manifest-2"},
+	}))
+}
+
+func TestParsePythonMetadata(t *testing.T) {
+	content := `Metadata-Version: 2.1
+Name: requests
+Version: 2.22.0
+License: Apache 2.0
+
+Requests: HTTP for Humans
+Name: not a field
+`
+	packages, err := parsePythonMetadata("/METADATA", []byte(content))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(packages, []types.ImagePackage{
+		{Name: "requests", Version: "2.22.0", Type: "pypi", License: "Apache 2.0", Path: "/METADATA"},
+	}))
+}
+
+func TestParsePythonLockfiles(t *testing.T) {
+	pipfile := `{"_meta": {"hash": {"sha256": "abc"}}, "default": {"flask": {"version": "==1.1.1"}}, "develop": {"pytest": {"version": "==5.3.2"}}}`
+	packages, err := parsePipfileLock("/Pipfile.lock", []byte(pipfile))
+	assert.NilError(t, err)
+	sortPackages(packages)
+	assert.Check(t, is.DeepEqual(packages, []types.ImagePackage{
+		{Name: "flask", Version: "1.1.1", Type: "pypi", Path: "/Pipfile.lock"},
+		{Name: "pytest", Version: "5.3.2", Type: "pypi", Path: "/Pipfile.lock"},
+	}))
+
+	poetry := `[[package]]
+name = "click"
+version = "7.0"
+category = "main"
+
+[package.dependencies]
+name = "ignored"
+
+[[package]]
+name = "flask"
+version = "1.1.1"
+
+[metadata]
+content-hash = "abc"
+`
+	packages, err = parsePoetryLock("/poetry.lock", []byte(poetry))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(packages, []types.ImagePackage{
+		{Name: "click", Version: "7.0", Type: "pypi", Path: "/poetry.lock"},
+		{Name: "flask", Version: "1.1.1", Type: "pypi", Path: "/poetry.lock"},
+	}))
+}
+
+func TestParseNodePackages(t *testing.T) {
+	packages, err := parseNodePackageJSON("/package.json", []byte(`{"name": "express", "version": "4.17.1", "license": {"type": "MIT"}}`))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(packages, []types.ImagePackage{
+		{Name: "express", Version: "4.17.1", Type: "npm", License: "MIT", Path: "/package.json"},
+	}))
+
+	lockV1 := `{"lockfileVersion": 1, "dependencies": {"accepts": {"version": "1.3.7", "dependencies": {"mime-types": {"version": "2.1.26"}}}, "local": {"version": "file:../local"}}}`
+	packages, err = parseNpmLock("/package-lock.json", []byte(lockV1))
+	assert.NilError(t, err)
+	sortPackages(packages)
+	assert.Check(t, is.DeepEqual(packages, []types.ImagePackage{
+		{Name: "accepts", Version: "1.3.7", Type: "npm", Path: "/package-lock.json"},
+		{Name: "mime-types", Version: "2.1.26", Type: "npm", Path: "/package-lock.json"},
+	}))
+
+	lockV2 := `{"lockfileVersion": 2, "packages": {"": {"name": "app", "version": "1.0.0"}, "node_modules/@babel/core": {"version": "7.8.4", "license": "MIT"}, "node_modules/a/node_modules/b": {"version": "1.0.0"}, "node_modules/linked": {"link": true}}}`
+	packages, err = parseNpmLock("/package-lock.json", []byte(lockV2))
+	assert.NilError(t, err)
+	sortPackages(packages)
+	assert.Check(t, is.DeepEqual(packages, []types.ImagePackage{
+		{Name: "@babel/core", Version: "7.8.4", Type: "npm", License: "MIT", Path: "/package-lock.json"},
+		{Name: "b", Version: "1.0.0", Type: "npm", Path: "/package-lock.json"},
+	}))
+
+	yarn := `# yarn lockfile v1
+
+"@babel/core@^7.0.0", "@babel/core@^7.1.0":
+  version "7.8.4"
+  resolved "https://registry.yarnpkg.com/@babel/core/-/core-7.8.4.tgz"
+
+lodash@^4.17.13:
+  version "4.17.15"
+`
+	packages, err = parseYarnLock("/yarn.lock", []byte(yarn))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(packages, []types.ImagePackage{
+		{Name: "@babel/core", Version: "7.8.4", Type: "npm", Path: "/yarn.lock"},
+		{Name: "lodash", Version: "4.17.15", Type: "npm", Path: "/yarn.lock"},
+	}))
+}
+
+func TestParseOSRelease(t *testing.T) {
+	id, version := parseOSRelease([]byte("NAME=\"Alpine Linux\"\nID=alpine\nVERSION_ID=3.11.3\n"))
+	assert.Check(t, is.Equal(id, "alpine"))
+	assert.Check(t, is.Equal(version, "3.11.3"))
+}
+
+func TestPackageURL(t *testing.T) {
+	cases := []struct {
+		pkg      types.ImagePackage
+		osID     string
+		expected string
+	}{
+		{types.ImagePackage{Name: "bash", Version: "5.0-4", Type: "deb", Arch: "amd64"}, "debian", "pkg:deb/debian/bash@5.0-4?arch=amd64"},
+		{types.ImagePackage{Name: "musl", Version: "1.1.24-r2", Type: "apk"}, "", "pkg:apk/musl@1.1.24-r2"},
+		{types.ImagePackage{Name: "github.com/pkg/errors", Version: "v0.9.1", Type: "golang"}, "alpine", "pkg:golang/github.com/pkg/errors@v0.9.1"},
+		{types.ImagePackage{Name: "Flask_Cors", Version: "3.0.8", Type: "pypi"}, "", "pkg:pypi/flask-cors@3.0.8"},
+		{types.ImagePackage{Name: "@babel/core", Version: "7.8.4", Type: "npm"}, "", "pkg:npm/%40babel/core@7.8.4"},
+		{types.ImagePackage{Name: "bash", Type: "deb"}, "", ""},
+	}
+	for _, c := range cases {
+		assert.Check(t, is.Equal(packageURL(c.pkg, c.osID), c.expected))
+	}
+}
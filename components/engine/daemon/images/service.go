@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"runtime"
+	"sync"
 
 	"github.com/docker/docker/container"
 	daemonevents "github.com/docker/docker/daemon/events"
@@ -12,6 +13,7 @@ import (
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/idtools"
 	dockerreference "github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/opencontainers/go-digest"
@@ -37,6 +39,7 @@ type ImageServiceConfig struct {
 	LayerStores               map[string]layer.Store
 	MaxConcurrentDownloads    int
 	MaxConcurrentUploads      int
+	MountRoot                 string // directory in which images are mounted by MountImage
 	ReferenceStore            dockerreference.Store
 	RegistryService           registry.Service
 }
@@ -45,17 +48,21 @@ type ImageServiceConfig struct {
 func NewImageService(config ImageServiceConfig) *ImageService {
 	logrus.Debugf("Max Concurrent Downloads: %d", config.MaxConcurrentDownloads)
 	logrus.Debugf("Max Concurrent Uploads: %d", config.MaxConcurrentUploads)
-	return &ImageService{
+	i := &ImageService{
 		containers:                config.ContainerStore,
 		distributionMetadataStore: config.DistributionMetadataStore,
 		downloadManager:           xfer.NewLayerDownloadManager(config.LayerStores, config.MaxConcurrentDownloads),
 		eventsService:             config.EventsService,
 		imageStore:                config.ImageStore,
 		layerStores:               config.LayerStores,
+		mountRoot:                 config.MountRoot,
+		mounts:                    make(map[image.ID]*imageMount),
 		referenceStore:            config.ReferenceStore,
 		registryService:           config.RegistryService,
 		uploadManager:             xfer.NewLayerUploadManager(config.MaxConcurrentUploads),
 	}
+	i.cleanupImageMounts()
+	return i
 }
 
 // ImageService provides a backend for image management
@@ -66,6 +73,9 @@ type ImageService struct {
 	eventsService             *daemonevents.Events
 	imageStore                image.Store
 	layerStores               map[string]layer.Store // By operating system
+	mountRoot                 string
+	mountsMu                  sync.Mutex
+	mounts                    map[image.ID]*imageMount
 	pruneRunning              int32
 	referenceStore            dockerreference.Store
 	registryService           registry.Service
@@ -105,6 +115,17 @@ func (i *ImageService) Children(id image.ID) []image.ID {
 	return i.imageStore.Children(id)
 }
 
+// SetImageLastUsed records that the image was used by a container.
+// called from create.go and start.go
+func (i *ImageService) SetImageLastUsed(id image.ID) {
+	if id == "" {
+		return
+	}
+	if err := i.imageStore.SetLastUsed(id); err != nil {
+		logrus.WithError(err).WithField("image", id).Warn("failed to update image last-used time")
+	}
+}
+
 // CreateLayer creates a filesystem layer for a container.
 // called from create.go
 // TODO: accept an opt struct instead of container?
@@ -123,6 +144,9 @@ func (i *ImageService) CreateLayer(container *container.Container, initFunc laye
 		InitFunc:   initFunc,
 		StorageOpt: container.HostConfig.StorageOpt,
 	}
+	if container.UIDMaps != nil {
+		rwLayerOpts.IDMapping = idtools.NewIDMappingsFromMaps(container.UIDMaps, container.GIDMaps)
+	}
 
 	// Indexing by OS is safe here as validation of OS has already been performed in create() (the only
 	// caller), and guaranteed non-nil
@@ -156,6 +180,7 @@ func (i *ImageService) GetLayerMountID(cid string, os string) (string, error) {
 // Cleanup resources before the process is shutdown.
 // called from daemon.go Daemon.Shutdown()
 func (i *ImageService) Cleanup() {
+	i.releaseImageMounts()
 	for os, ls := range i.layerStores {
 		if ls != nil {
 			if err := ls.Cleanup(); err != nil {
@@ -0,0 +1,52 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	var lines []string
+	for _, l := range strings.Split("a b c d e f g h i j k l m n o p", " ") {
+		lines = append(lines, l+"\n")
+	}
+	old := strings.Join(lines, "")
+	lines[1] = "B\n"
+	lines = append(lines[:14], lines[15:]...)
+	new := strings.Join(lines, "") + "q"
+
+	expected := `--- a/f
++++ b/f
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -12,5 +12,5 @@
+ l
+ m
+ n
+-o
+ p
++q
+\ No newline at end of file
+`
+	assert.Check(t, is.Equal(unifiedDiff("a/f", "b/f", old, new), expected))
+}
+
+func TestUnifiedDiffEmpty(t *testing.T) {
+	assert.Check(t, is.Equal(unifiedDiff("a/f", "b/f", "a\n", "a\n"), ""))
+	assert.Check(t, is.Equal(unifiedDiff("a/f", "b/f", "", "a\n"), "--- a/f\n+++ b/f\n@@ -0,0 +1 @@\n+a\n"))
+	assert.Check(t, is.Equal(unifiedDiff("a/f", "b/f", "a\n", ""), "--- a/f\n+++ b/f\n@@ -1 +0,0 @@\n-a\n"))
+}
+
+func TestIsText(t *testing.T) {
+	assert.Check(t, isText([]byte("hello\n")))
+	assert.Check(t, !isText([]byte("hello\x00")))
+	assert.Check(t, !isText([]byte{0xff, 0xfe}))
+}
@@ -3,7 +3,10 @@ package daemon
 import (
 	"testing"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/daemon/config"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 )
 
 func TestMaskURLCredentials(t *testing.T) {
@@ -51,3 +54,30 @@ func TestMaskURLCredentials(t *testing.T) {
 		assert.Equal(t, maskedURL, test.maskedURL)
 	}
 }
+
+func TestFillAPIInfoListenerWarnings(t *testing.T) {
+	noTLS := false
+	daemon := &Daemon{
+		configStore: &config.Config{
+			CommonConfig: config.CommonConfig{
+				Hosts: []string{
+					"unix:///var/run/docker.sock",
+					"tcp://0.0.0.0:2376",
+					"tcp://127.0.0.1:2375",
+					"tcp://10.0.0.1:2376",
+				},
+				TLS:       true,
+				TLSVerify: true,
+				Listeners: map[string]config.ListenerConfig{
+					"tcp://127.0.0.1:2375": {TLS: &noTLS},
+					"tcp://10.0.0.1:2376":  {ClientAuth: config.ClientAuthVerifyIfGiven},
+				},
+			},
+		},
+	}
+	v := &types.Info{}
+	daemon.fillAPIInfo(v)
+	assert.Assert(t, is.Len(v.Warnings, 2))
+	assert.Check(t, is.Contains(v.Warnings[0], "API is accessible on http://127.0.0.1:2375 without encryption"))
+	assert.Check(t, is.Contains(v.Warnings[1], "API is accessible on https://10.0.0.1:2376 without TLS client verification"))
+}
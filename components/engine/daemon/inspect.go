@@ -176,6 +176,7 @@ func (daemon *Daemon) getInspectData(container *container.Container) (*types.Con
 		MountLabel:   container.MountLabel,
 		ProcessLabel: container.ProcessLabel,
 		ExecIDs:      container.GetExecIDs(),
+		ExecHistory:  container.GetExecHistory(),
 		HostConfig:   &hostConfig,
 	}
 
@@ -11,6 +11,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/pkg/reexec"
 	"github.com/google/uuid"
 	"github.com/opencontainers/go-digest"
 	"gotest.tools/assert"
@@ -20,6 +21,9 @@ import (
 var root string
 
 func TestMain(m *testing.M) {
+	if reexec.Init() {
+		return
+	}
 	var err error
 	root, err = ioutil.TempDir("", "docker-container-test-")
 	if err != nil {
@@ -55,6 +55,9 @@ func Init(proto, addr, socketGroup string, tlsConfig *tls.Config) ([]net.Listene
 		return nil, fmt.Errorf("invalid protocol format: %q", proto)
 	}
 
+	for i, l := range ls {
+		ls[i] = withPeerCredentials(l)
+	}
 	return ls, nil
 }
 
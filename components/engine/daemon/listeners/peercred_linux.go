@@ -0,0 +1,130 @@
+package listeners // import "github.com/docker/docker/daemon/listeners"
+
+import (
+	"net"
+	"unsafe"
+
+	"github.com/docker/docker/pkg/authorization"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// peerCredentialsListener records the credentials of the client processes
+// connecting to a Unix socket, so that the API can be authorized based on
+// them.
+type peerCredentialsListener struct {
+	net.Listener
+}
+
+// withPeerCredentials wraps the Unix socket listeners, so that the remote
+// address of their connections are the credentials of the client processes.
+func withPeerCredentials(l net.Listener) net.Listener {
+	if l.Addr().Network() != "unix" {
+		return l
+	}
+	return &peerCredentialsListener{Listener: l}
+}
+
+func (l *peerCredentialsListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return conn, nil
+	}
+	creds, err := peerCredentials(uc)
+	if err != nil {
+		logrus.WithError(err).Debug("cannot get the credentials of the API client")
+		return conn, nil
+	}
+	return &peerCredentialsConn{Conn: conn, addr: peerAddr(creds.String())}, nil
+}
+
+// peerCredentials returns the credentials of the process at the other end of
+// a Unix socket connection.
+func peerCredentials(conn *net.UnixConn) (*authorization.PeerCredentials, error) {
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ucred    *unix.Ucred
+		groups   []uint32
+		ucredErr error
+	)
+	if err := rc.Control(func(fd uintptr) {
+		ucred, ucredErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+		if ucredErr != nil {
+			return
+		}
+		// The supplementary groups are those of the client when it
+		// connected, as the other credentials. They are only available since
+		// Linux 4.13, and only the primary group is used on older kernels.
+		var err error
+		if groups, err = peerGroups(int(fd)); err != nil {
+			logrus.WithError(err).Debug("cannot get the supplementary groups of the API client")
+		}
+	}); err != nil {
+		return nil, err
+	}
+	if ucredErr != nil {
+		return nil, ucredErr
+	}
+	creds := &authorization.PeerCredentials{
+		PID:  ucred.Pid,
+		UID:  ucred.Uid,
+		GIDs: []uint32{ucred.Gid},
+	}
+	for _, gid := range groups {
+		if gid != ucred.Gid {
+			creds.GIDs = append(creds.GIDs, gid)
+		}
+	}
+	return creds, nil
+}
+
+// peerGroups returns the supplementary groups of the peer of a Unix socket
+// with SO_PEERGROUPS. unix.GetsockoptString cannot be used, as it expects a
+// NUL-terminated string, and drops the last byte of the array of groups.
+func peerGroups(fd int) ([]uint32, error) {
+	groups := make([]uint32, 16)
+	for {
+		size := uint32(len(groups) * 4)
+		_, _, errno := unix.Syscall6(unix.SYS_GETSOCKOPT, uintptr(fd), unix.SOL_SOCKET, unix.SO_PEERGROUPS,
+			uintptr(unsafe.Pointer(&groups[0])), uintptr(unsafe.Pointer(&size)), 0)
+		switch {
+		case errno == unix.ERANGE && int(size/4) > len(groups):
+			// The size of the groups is returned if the buffer is too small.
+			groups = make([]uint32, size/4)
+		case errno != 0:
+			return nil, errno
+		default:
+			return groups[:size/4], nil
+		}
+	}
+}
+
+// peerCredentialsConn is a Unix socket connection whose remote address is the
+// credentials of the client process.
+type peerCredentialsConn struct {
+	net.Conn
+	addr peerAddr
+}
+
+func (c *peerCredentialsConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// peerAddr holds the credentials of a client process in the format expected
+// by the authorization policy.
+type peerAddr string
+
+func (a peerAddr) Network() string {
+	return "unix"
+}
+
+func (a peerAddr) String() string {
+	return string(a)
+}
@@ -0,0 +1,69 @@
+package listeners // import "github.com/docker/docker/daemon/listeners"
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestPeerCredentialsListener(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peercred")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := net.Listen("unix", filepath.Join(dir, "docker.sock"))
+	assert.NilError(t, err)
+	l = withPeerCredentials(l)
+	defer l.Close()
+
+	client, err := net.Dial("unix", filepath.Join(dir, "docker.sock"))
+	assert.NilError(t, err)
+	defer client.Close()
+
+	conn, err := l.Accept()
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	addr := conn.RemoteAddr().String()
+	assert.Check(t, is.Equal(conn.RemoteAddr().Network(), "unix"))
+	assert.Check(t, strings.HasPrefix(addr, "unix-peer:pid="), addr)
+	assert.Check(t, is.Contains(addr, ",uid="+strconv.Itoa(os.Getuid())+","))
+}
+
+func TestPeerCredentialsGroups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peercred")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := net.Listen("unix", filepath.Join(dir, "docker.sock"))
+	assert.NilError(t, err)
+	defer l.Close()
+
+	client, err := net.Dial("unix", filepath.Join(dir, "docker.sock"))
+	assert.NilError(t, err)
+	defer client.Close()
+
+	conn, err := l.Accept()
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	creds, err := peerCredentials(conn.(*net.UnixConn))
+	assert.NilError(t, err)
+
+	groups, err := os.Getgroups()
+	assert.NilError(t, err)
+	expected := []uint32{uint32(os.Getgid())}
+	for _, gid := range groups {
+		if gid != os.Getgid() {
+			expected = append(expected, uint32(gid))
+		}
+	}
+	assert.Check(t, is.DeepEqual(creds.GIDs, expected))
+}
@@ -0,0 +1,32 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import "github.com/docker/docker/api/types/backend"
+
+// attributesLogger adds attributes to the messages logged to a Logger which it
+// does not own.
+type attributesLogger struct {
+	Logger
+	attrs []backend.LogAttr
+}
+
+// WithAttributes returns a Logger which adds attrs to the messages it logs to
+// l. Closing the returned Logger does not close l.
+func WithAttributes(l Logger, attrs []backend.LogAttr) Logger {
+	return &attributesLogger{Logger: l, attrs: attrs}
+}
+
+func (l *attributesLogger) Log(msg *Message) error {
+	msg.Attrs = append(msg.Attrs, l.attrs...)
+	return l.Logger.Log(msg)
+}
+
+func (l *attributesLogger) BufSize() int {
+	if sl, ok := l.Logger.(SizedLogger); ok {
+		return sl.BufSize()
+	}
+	return defaultBufSize
+}
+
+func (l *attributesLogger) Close() error {
+	return nil
+}
@@ -0,0 +1,41 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/docker/api/types/backend"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type closeCountLogger struct {
+	TestLoggerJSON
+	closed int
+}
+
+func (l *closeCountLogger) Close() error {
+	l.closed++
+	return nil
+}
+
+func TestWithAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	l := &closeCountLogger{TestLoggerJSON: TestLoggerJSON{Encoder: json.NewEncoder(&buf)}}
+	attrs := []backend.LogAttr{{Key: "exec-id", Value: "1234"}}
+
+	al := WithAttributes(l, attrs)
+	assert.NilError(t, al.Log(&Message{Line: []byte("line"), Source: "stdout", Attrs: []backend.LogAttr{{Key: "foo", Value: "bar"}}}))
+
+	var msg Message
+	assert.NilError(t, json.NewDecoder(&buf).Decode(&msg))
+	assert.Check(t, is.DeepEqual([]backend.LogAttr{{Key: "foo", Value: "bar"}, {Key: "exec-id", Value: "1234"}}, msg.Attrs))
+
+	assert.Check(t, is.Equal(defaultBufSize, al.(SizedLogger).BufSize()))
+	assert.Check(t, is.Equal(32*1024, WithAttributes(&TestSizedLoggerJSON{}, attrs).(SizedLogger).BufSize()))
+
+	// the logger is not owned by the attributes logger
+	assert.NilError(t, al.Close())
+	assert.Check(t, is.Equal(0, l.closed))
+}
@@ -21,6 +21,7 @@ type LogOptValidator func(cfg map[string]string) error
 type logdriverFactory struct {
 	registry     map[string]Creator
 	optValidator map[string]LogOptValidator
+	attrs        map[string]bool
 	m            sync.Mutex
 }
 
@@ -76,6 +77,24 @@ func (lf *logdriverFactory) registerLogOptValidator(name string, l LogOptValidat
 	return nil
 }
 
+func (lf *logdriverFactory) registerMessageAttributes(name string) error {
+	lf.m.Lock()
+	defer lf.m.Unlock()
+
+	if lf.attrs[name] {
+		return fmt.Errorf("logger: message attributes of log driver named '%s' are already registered", name)
+	}
+	lf.attrs[name] = true
+	return nil
+}
+
+func (lf *logdriverFactory) supportsMessageAttributes(name string) bool {
+	lf.m.Lock()
+	defer lf.m.Unlock()
+
+	return lf.attrs[name]
+}
+
 func (lf *logdriverFactory) get(name string) (Creator, error) {
 	lf.m.Lock()
 	defer lf.m.Unlock()
@@ -97,7 +116,7 @@ func (lf *logdriverFactory) getLogOptValidator(name string) LogOptValidator {
 	return c
 }
 
-var factory = &logdriverFactory{registry: make(map[string]Creator), optValidator: make(map[string]LogOptValidator)} // global factory instance
+var factory = &logdriverFactory{registry: make(map[string]Creator), optValidator: make(map[string]LogOptValidator), attrs: make(map[string]bool)} // global factory instance
 
 // RegisterLogDriver registers the given logging driver builder with given logging
 // driver name.
@@ -111,6 +130,19 @@ func RegisterLogOptValidator(name string, l LogOptValidator) error {
 	return factory.registerLogOptValidator(name, l)
 }
 
+// RegisterMessageAttributes records that the logging driver with the given
+// name stores the attributes of the messages it logs (Message.Attrs). The
+// other drivers drop them.
+func RegisterMessageAttributes(name string) error {
+	return factory.registerMessageAttributes(name)
+}
+
+// SupportsMessageAttributes returns whether the logging driver with the given
+// name stores the attributes of the messages it logs.
+func SupportsMessageAttributes(name string) bool {
+	return factory.supportsMessageAttributes(name)
+}
+
 // GetLogDriver provides the logging driver builder for a logging driver name.
 func GetLogDriver(name string) (Creator, error) {
 	return factory.get(name)
@@ -63,6 +63,9 @@ func init() {
 	if err := logger.RegisterLogOptValidator(name, ValidateLogOpt); err != nil {
 		logrus.Fatal(err)
 	}
+	if err := logger.RegisterMessageAttributes(name); err != nil {
+		logrus.Fatal(err)
+	}
 }
 
 // New creates a fluentd logger using the configuration passed in on
@@ -163,6 +166,9 @@ func (f *fluentd) Log(msg *logger.Message) error {
 	for k, v := range f.extra {
 		data[k] = v
 	}
+	for _, a := range msg.Attrs {
+		data[a.Key] = a.Value
+	}
 	if msg.PLogMetaData != nil {
 		data["partial_message"] = "true"
 		data["partial_id"] = msg.PLogMetaData.ID
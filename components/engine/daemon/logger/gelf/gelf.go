@@ -34,6 +34,9 @@ func init() {
 	if err := logger.RegisterLogOptValidator(name, ValidateLogOpt); err != nil {
 		logrus.Fatal(err)
 	}
+	if err := logger.RegisterMessageAttributes(name); err != nil {
+		logrus.Fatal(err)
+	}
 }
 
 // New creates a gelf logger using the configuration passed in on the
@@ -179,6 +182,12 @@ func (s *gelfLogger) Log(msg *logger.Message) error {
 		Level:    int32(level),
 		RawExtra: s.rawExtra,
 	}
+	if len(msg.Attrs) > 0 {
+		m.Extra = make(map[string]interface{}, len(msg.Attrs))
+		for _, a := range msg.Attrs {
+			m.Extra["_"+a.Key] = a.Value
+		}
+	}
 	logger.PutMessage(msg)
 
 	if err := s.writer.WriteMessage(&m); err != nil {
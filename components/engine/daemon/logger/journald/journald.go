@@ -31,6 +31,9 @@ func init() {
 	if err := logger.RegisterLogOptValidator(name, validateLogOpt); err != nil {
 		logrus.Fatal(err)
 	}
+	if err := logger.RegisterMessageAttributes(name); err != nil {
+		logrus.Fatal(err)
+	}
 }
 
 // sanitizeKeyMode returns the sanitized string so that it could be used in journald.
@@ -109,6 +112,9 @@ func (s *journald) Log(msg *logger.Message) error {
 	if msg.PLogMetaData != nil && !msg.PLogMetaData.Last {
 		vars["CONTAINER_PARTIAL_MESSAGE"] = "true"
 	}
+	for _, a := range msg.Attrs {
+		vars[sanitizeKeyMod(a.Key)] = a.Value
+	}
 
 	line := string(msg.Line)
 	source := msg.Source
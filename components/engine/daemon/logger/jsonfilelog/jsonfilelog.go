@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/logger/jsonfilelog/jsonlog"
 	"github.com/docker/docker/daemon/logger/loggerutils"
@@ -37,6 +38,9 @@ func init() {
 	if err := logger.RegisterLogOptValidator(Name, ValidateLogOpt); err != nil {
 		logrus.Fatal(err)
 	}
+	if err := logger.RegisterMessageAttributes(Name); err != nil {
+		logrus.Fatal(err)
+	}
 }
 
 // New creates new JSONFileLogger which writes to filename passed in
@@ -102,7 +106,14 @@ func New(info logger.Info) (logger.Logger, error) {
 
 	buf := bytes.NewBuffer(nil)
 	marshalFunc := func(msg *logger.Message) ([]byte, error) {
-		if err := marshalMessage(msg, extra, buf); err != nil {
+		msgExtra := extra
+		if len(msg.Attrs) > 0 {
+			var err error
+			if msgExtra, err = mergeAttrs(attrs, msg.Attrs); err != nil {
+				return nil, err
+			}
+		}
+		if err := marshalMessage(msg, msgExtra, buf); err != nil {
 			return nil, err
 		}
 		b := buf.Bytes()
@@ -148,6 +159,19 @@ func marshalMessage(msg *logger.Message, extra json.RawMessage, buf *bytes.Buffe
 	return errors.Wrap(err, "error finalizing log buffer")
 }
 
+// mergeAttrs returns the attributes of the logger, with the attributes of a
+// message, serialized to JSON.
+func mergeAttrs(attrs map[string]string, msgAttrs []backend.LogAttr) (json.RawMessage, error) {
+	merged := make(map[string]string, len(attrs)+len(msgAttrs))
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	for _, a := range msgAttrs {
+		merged[a.Key] = a.Value
+	}
+	return json.Marshal(merged)
+}
+
 // ValidateLogOpt looks for json specific log options max-file & max-size.
 func ValidateLogOpt(cfg map[string]string) error {
 	for key := range cfg {
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/logger/jsonfilelog/jsonlog"
 	"gotest.tools/assert"
@@ -318,3 +319,35 @@ func TestJSONFileLoggerWithLabelsEnv(t *testing.T) {
 		t.Fatalf("Wrong log attrs: %q, expected %q", extra, expected)
 	}
 }
+
+func TestJSONFileLoggerWithMessageAttrs(t *testing.T) {
+	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "container.log")
+	l, err := New(logger.Info{
+		ContainerID:     cid,
+		LogPath:         filename,
+		Config:          map[string]string{"labels": "rack"},
+		ContainerLabels: map[string]string{"rack": "101"},
+	})
+	assert.NilError(t, err)
+	defer l.Close()
+
+	assert.NilError(t, l.Log(&logger.Message{Line: []byte("line1"), Source: "stdout", Attrs: []backend.LogAttr{{Key: "exec-id", Value: "1234"}}}))
+	assert.NilError(t, l.Log(&logger.Message{Line: []byte("line2"), Source: "stdout"}))
+
+	f, err := os.Open(filename)
+	assert.NilError(t, err)
+	defer f.Close()
+	dec := json.NewDecoder(f)
+
+	var jsonLog jsonlog.JSONLog
+	assert.NilError(t, dec.Decode(&jsonLog))
+	assert.Check(t, is.DeepEqual(map[string]string{"rack": "101", "exec-id": "1234"}, jsonLog.Attrs))
+
+	jsonLog = jsonlog.JSONLog{}
+	assert.NilError(t, dec.Decode(&jsonLog))
+	assert.Check(t, is.DeepEqual(map[string]string{"rack": "101"}, jsonLog.Attrs))
+}
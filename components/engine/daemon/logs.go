@@ -10,6 +10,7 @@ import (
 	containertypes "github.com/docker/docker/api/types/container"
 	timetypes "github.com/docker/docker/api/types/time"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/errdefs"
 	"github.com/pkg/errors"
@@ -174,16 +175,17 @@ func (daemon *Daemon) getLogger(container *container.Container) (l logger.Logger
 
 // mergeLogConfig merges the daemon log config to the container's log config if the container's log driver is not specified.
 func (daemon *Daemon) mergeAndVerifyLogConfig(cfg *containertypes.LogConfig) error {
+	defaultLogConfig := daemon.getDefaultLogConfig()
 	if cfg.Type == "" {
-		cfg.Type = daemon.defaultLogConfig.Type
+		cfg.Type = defaultLogConfig.Type
 	}
 
 	if cfg.Config == nil {
 		cfg.Config = make(map[string]string)
 	}
 
-	if cfg.Type == daemon.defaultLogConfig.Type {
-		for k, v := range daemon.defaultLogConfig.Config {
+	if cfg.Type == defaultLogConfig.Type {
+		for k, v := range defaultLogConfig.Config {
 			if _, ok := cfg.Config[k]; !ok {
 				cfg.Config[k] = v
 			}
@@ -195,15 +197,39 @@ func (daemon *Daemon) mergeAndVerifyLogConfig(cfg *containertypes.LogConfig) err
 
 func (daemon *Daemon) setupDefaultLogConfig() error {
 	config := daemon.configStore
-	if len(config.LogConfig.Config) > 0 {
-		if err := logger.ValidateLogOpts(config.LogConfig.Type, config.LogConfig.Config); err != nil {
-			return errors.Wrap(err, "failed to set log opts")
-		}
+	if err := validateLogConfig(config.LogConfig); err != nil {
+		return err
 	}
-	daemon.defaultLogConfig = containertypes.LogConfig{
+	daemon.setDefaultLogConfig(containertypes.LogConfig{
 		Type:   config.LogConfig.Type,
 		Config: config.LogConfig.Config,
+	})
+	return nil
+}
+
+// validateLogConfig checks the default log options of the daemon against its
+// default log driver.
+func validateLogConfig(cfg config.LogConfig) error {
+	if len(cfg.Config) > 0 {
+		if err := logger.ValidateLogOpts(cfg.Type, cfg.Config); err != nil {
+			return errors.Wrap(err, "failed to set log opts")
+		}
 	}
-	logrus.Debugf("Using default logging driver %s", daemon.defaultLogConfig.Type)
 	return nil
 }
+
+// setDefaultLogConfig sets the daemon log config, which is merged to the log
+// config of the containers when they are created.
+func (daemon *Daemon) setDefaultLogConfig(cfg containertypes.LogConfig) {
+	daemon.defaultLogConfigMu.Lock()
+	daemon.defaultLogConfig = cfg
+	daemon.defaultLogConfigMu.Unlock()
+	logrus.Debugf("Using default logging driver %s", cfg.Type)
+}
+
+// getDefaultLogConfig returns the daemon log config.
+func (daemon *Daemon) getDefaultLogConfig() containertypes.LogConfig {
+	daemon.defaultLogConfigMu.RLock()
+	defer daemon.defaultLogConfigMu.RUnlock()
+	return daemon.defaultLogConfig
+}
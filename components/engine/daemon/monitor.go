@@ -80,6 +80,7 @@ func (daemon *Daemon) ProcessEvent(id string, e libcontainerdtypes.EventType, ei
 			// cancel healthcheck here, they will be automatically
 			// restarted if/when the container is started again
 			daemon.stopHealthchecks(c)
+			c.EndRunningExecHistoryEntries(ei.ExitedAt.UTC())
 			attributes := map[string]string{
 				"exitCode": strconv.Itoa(int(ei.ExitCode)),
 			}
@@ -119,6 +120,9 @@ func (daemon *Daemon) ProcessEvent(id string, e libcontainerdtypes.EventType, ei
 
 		if execConfig := c.ExecCommands.Get(ei.ProcessID); execConfig != nil {
 			ec := int(ei.ExitCode)
+			// the exit is recorded in the history of the container after
+			// the lock of the exec is released
+			defer daemon.recordExecExit(c, execConfig.ID, ec, ei.ExitedAt.UTC())
 			execConfig.Lock()
 			defer execConfig.Unlock()
 			execConfig.ExitCode = &ec
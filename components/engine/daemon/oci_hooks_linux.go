@@ -0,0 +1,104 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/containerd/containerd/containers"
+	coci "github.com/containerd/containerd/oci"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// verifyOCIHooks checks the OCI hooks of a container, and that one of the
+// allowed hooks allows their paths, arguments and environment variables.
+func verifyOCIHooks(hooks *containertypes.OCIHooks, allowed []config.AllowedOCIHook) error {
+	if hooks == nil {
+		return nil
+	}
+	for stage, hs := range map[string][]containertypes.OCIHook{
+		"prestart":  hooks.Prestart,
+		"poststart": hooks.Poststart,
+		"poststop":  hooks.Poststop,
+	} {
+		for _, h := range hs {
+			if err := verifyOCIHook(h, allowed); err != nil {
+				return fmt.Errorf("invalid %s OCI hook: %v", stage, err)
+			}
+		}
+	}
+	return nil
+}
+
+func verifyOCIHook(h containertypes.OCIHook, allowed []config.AllowedOCIHook) error {
+	if !filepath.IsAbs(h.Path) || filepath.Clean(h.Path) != h.Path {
+		return fmt.Errorf("the path %q must be a clean absolute path", h.Path)
+	}
+	if h.Timeout != nil && *h.Timeout <= 0 {
+		return fmt.Errorf("the timeout of %s must be positive", h.Path)
+	}
+	for _, e := range h.Env {
+		if !strings.Contains(e, "=") {
+			return fmt.Errorf("invalid environment variable %q for %s", e, h.Path)
+		}
+	}
+	// The first argument is the name of the program, so it cannot be set to
+	// something else than its path, as some programs change their behavior
+	// with it.
+	var args []string
+	if len(h.Args) > 0 {
+		if h.Args[0] != h.Path {
+			return fmt.Errorf("the first argument of %s must be its path", h.Path)
+		}
+		args = h.Args[1:]
+	}
+	for _, a := range allowed {
+		if a.Allows(h.Path, args, h.Env) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not allowed with these arguments and environment variables by the allowed-oci-hooks setting of the daemon", h.Path)
+}
+
+// WithOCIHooks adds the OCI hooks of the container to the spec, after the
+// hooks set by the daemon.
+func WithOCIHooks(daemon *Daemon, c *container.Container) coci.SpecOpts {
+	return func(ctx context.Context, _ coci.Client, _ *containers.Container, s *coci.Spec) error {
+		hooks := c.HostConfig.OCIHooks
+		if hooks == nil {
+			return nil
+		}
+		// The allowed hooks may have changed since the container was created.
+		if err := verifyOCIHooks(hooks, daemon.configStore.AllowedOCIHooks); err != nil {
+			return err
+		}
+		if s.Hooks == nil {
+			s.Hooks = &specs.Hooks{}
+		}
+		s.Hooks.Prestart = append(s.Hooks.Prestart, toSpecHooks(hooks.Prestart)...)
+		s.Hooks.Poststart = append(s.Hooks.Poststart, toSpecHooks(hooks.Poststart)...)
+		s.Hooks.Poststop = append(s.Hooks.Poststop, toSpecHooks(hooks.Poststop)...)
+		return nil
+	}
+}
+
+func toSpecHooks(hooks []containertypes.OCIHook) []specs.Hook {
+	var out []specs.Hook
+	for _, h := range hooks {
+		args := h.Args
+		if len(args) == 0 {
+			args = []string{h.Path}
+		}
+		out = append(out, specs.Hook{
+			Path:    h.Path,
+			Args:    args,
+			Env:     h.Env,
+			Timeout: h.Timeout,
+		})
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestVerifyOCIHooks(t *testing.T) {
+	allowed := []config.AllowedOCIHook{
+		{Path: "/usr/local/lib/hooks/*"},
+		{Path: "/usr/local/lib/monitoring/register", Args: []string{"--verbose", "--name=*"}, Env: []string{"MONITORING_URL"}},
+	}
+	timeout := 0
+
+	assert.Check(t, verifyOCIHooks(nil, nil))
+	assert.Check(t, verifyOCIHooks(&containertypes.OCIHooks{
+		Prestart: []containertypes.OCIHook{{Path: "/usr/local/lib/hooks/register"}},
+	}, allowed))
+	assert.Check(t, is.ErrorContains(verifyOCIHooks(&containertypes.OCIHooks{
+		Prestart: []containertypes.OCIHook{{Path: "/usr/local/lib/hooks/register"}},
+	}, nil), "invalid prestart OCI hook: /usr/local/lib/hooks/register is not allowed"))
+	assert.Check(t, is.ErrorContains(verifyOCIHooks(&containertypes.OCIHooks{
+		Poststop: []containertypes.OCIHook{{Path: "/usr/local/lib/hooks/../../bin/sh"}},
+	}, allowed), "must be a clean absolute path"))
+	assert.Check(t, is.ErrorContains(verifyOCIHooks(&containertypes.OCIHooks{
+		Poststart: []containertypes.OCIHook{{Path: "/usr/local/lib/hooks/register", Timeout: &timeout}},
+	}, allowed), "timeout of /usr/local/lib/hooks/register must be positive"))
+	assert.Check(t, is.ErrorContains(verifyOCIHooks(&containertypes.OCIHooks{
+		Poststart: []containertypes.OCIHook{{Path: "/usr/local/lib/hooks/register", Env: []string{"FOO"}}},
+	}, allowed), `invalid environment variable "FOO"`))
+
+	// Only the declared arguments and environment variables can be set.
+	assert.Check(t, verifyOCIHooks(&containertypes.OCIHooks{
+		Prestart: []containertypes.OCIHook{{
+			Path: "/usr/local/lib/monitoring/register",
+			Args: []string{"/usr/local/lib/monitoring/register", "--name=web", "--verbose"},
+			Env:  []string{"MONITORING_URL=http://localhost"},
+		}},
+	}, allowed))
+	assert.Check(t, is.ErrorContains(verifyOCIHooks(&containertypes.OCIHooks{
+		Prestart: []containertypes.OCIHook{{Path: "/usr/local/lib/hooks/register", Env: []string{"LD_PRELOAD=/tmp/evil.so"}}},
+	}, allowed), "is not allowed"))
+	assert.Check(t, is.ErrorContains(verifyOCIHooks(&containertypes.OCIHooks{
+		Prestart: []containertypes.OCIHook{{Path: "/usr/local/lib/monitoring/register", Env: []string{"LD_PRELOAD=/tmp/evil.so"}}},
+	}, allowed), "is not allowed"))
+	assert.Check(t, is.ErrorContains(verifyOCIHooks(&containertypes.OCIHooks{
+		Prestart: []containertypes.OCIHook{{Path: "/usr/local/lib/hooks/register", Args: []string{"/usr/local/lib/hooks/register", "--config=/tmp/evil"}}},
+	}, allowed), "is not allowed"))
+	assert.Check(t, is.ErrorContains(verifyOCIHooks(&containertypes.OCIHooks{
+		Prestart: []containertypes.OCIHook{{Path: "/usr/local/lib/monitoring/register", Args: []string{"/usr/local/lib/monitoring/register", "--config=/tmp/evil"}}},
+	}, allowed), "is not allowed"))
+	assert.Check(t, is.ErrorContains(verifyOCIHooks(&containertypes.OCIHooks{
+		Prestart: []containertypes.OCIHook{{Path: "/usr/local/lib/monitoring/register", Args: []string{"sh", "--verbose"}}},
+	}, allowed), "the first argument of /usr/local/lib/monitoring/register must be its path"))
+}
+
+func TestWithOCIHooks(t *testing.T) {
+	d := &Daemon{configStore: &config.Config{}}
+	d.configStore.AllowedOCIHooks = []config.AllowedOCIHook{{Path: "/usr/local/lib/hooks/*", Args: []string{"--all"}}}
+	c := &container.Container{HostConfig: &containertypes.HostConfig{
+		OCIHooks: &containertypes.OCIHooks{
+			Prestart: []containertypes.OCIHook{{Path: "/usr/local/lib/hooks/register"}},
+			Poststop: []containertypes.OCIHook{{Path: "/usr/local/lib/hooks/unregister", Args: []string{"/usr/local/lib/hooks/unregister", "--all"}}},
+		},
+	}}
+
+	s := oci.DefaultSpec()
+	s.Hooks = &specs.Hooks{Prestart: []specs.Hook{{Path: "/proc/1/exe"}}}
+	assert.NilError(t, WithOCIHooks(d, c)(context.Background(), nil, nil, &s))
+	assert.Check(t, is.DeepEqual(s.Hooks.Prestart, []specs.Hook{
+		{Path: "/proc/1/exe"},
+		{Path: "/usr/local/lib/hooks/register", Args: []string{"/usr/local/lib/hooks/register"}},
+	}))
+	assert.Check(t, is.DeepEqual(s.Hooks.Poststop, []specs.Hook{
+		{Path: "/usr/local/lib/hooks/unregister", Args: []string{"/usr/local/lib/hooks/unregister", "--all"}},
+	}))
+
+	// The hooks are verified again when the container is started.
+	d.configStore.AllowedOCIHooks = nil
+	s = oci.DefaultSpec()
+	assert.Check(t, is.ErrorContains(WithOCIHooks(d, c)(context.Background(), nil, nil, &s), "is not allowed"))
+}
@@ -219,13 +219,13 @@ func WithNamespaces(daemon *Daemon, c *container.Container) coci.SpecOpts {
 		userNS := false
 		// user
 		if c.HostConfig.UsernsMode.IsPrivate() {
-			uidMap := daemon.idMapping.UIDs()
+			uidMap := daemon.containerIDMapping(c).UIDs()
 			if uidMap != nil {
 				userNS = true
 				ns := specs.LinuxNamespace{Type: "user"}
 				setNamespace(s, ns)
 				s.Linux.UIDMappings = specMapping(uidMap)
-				s.Linux.GIDMappings = specMapping(daemon.idMapping.GIDs())
+				s.Linux.GIDMappings = specMapping(daemon.containerIDMapping(c).GIDs())
 			}
 		}
 		// network
@@ -302,7 +302,24 @@ func WithNamespaces(daemon *Daemon, c *container.Container) coci.SpecOpts {
 			setNamespace(s, ns)
 		}
 		// uts
-		if c.HostConfig.UTSMode.IsHost() {
+		if c.HostConfig.UTSMode.IsContainer() {
+			ns := specs.LinuxNamespace{Type: "uts"}
+			uc, err := daemon.getUTSContainer(c)
+			if err != nil {
+				return err
+			}
+			ns.Path = fmt.Sprintf("/proc/%d/ns/uts", uc.State.GetPID())
+			setNamespace(s, ns)
+			if userNS {
+				// to share a UTS namespace, they must also share a user namespace
+				nsUser := specs.LinuxNamespace{Type: "user"}
+				nsUser.Path = fmt.Sprintf("/proc/%d/ns/user", uc.State.GetPID())
+				setNamespace(s, nsUser)
+			}
+			// the hostname and the domain name are the ones of the container
+			s.Hostname = ""
+			delete(s.Linux.Sysctl, "kernel.domainname")
+		} else if c.HostConfig.UTSMode.IsHost() {
 			oci.RemoveNamespace(s, specs.LinuxNamespaceType("uts"))
 			s.Hostname = ""
 		}
@@ -639,7 +656,7 @@ func WithMounts(daemon *Daemon, c *container.Container) coci.SpecOpts {
 			// "mount" when we bind-mount. The reason for this is that at the point
 			// when runc sets up the root filesystem, it is already inside a user
 			// namespace, and thus cannot change any flags that are locked.
-			if daemon.configStore.RemappedRoot != "" {
+			if daemon.configStore.RemappedRoot != "" || c.UIDMaps != nil {
 				unprivOpts, err := getUnprivilegedMountFlags(m.Source)
 				if err != nil {
 					return err
@@ -678,7 +695,7 @@ func WithMounts(daemon *Daemon, c *container.Container) coci.SpecOpts {
 
 		// TODO: until a kernel/mount solution exists for handling remount in a user namespace,
 		// we must clear the readonly flag for the cgroups mount (@mrunalp concurs)
-		if uidMap := daemon.idMapping.UIDs(); uidMap != nil || c.HostConfig.Privileged {
+		if uidMap := daemon.containerIDMapping(c).UIDs(); uidMap != nil || c.HostConfig.Privileged {
 			for i, m := range s.Mounts {
 				if m.Type == "cgroup" {
 					clearReadOnly(&s.Mounts[i])
@@ -705,7 +722,7 @@ func WithCommonOptions(daemon *Daemon, c *container.Container) coci.SpecOpts {
 			Path:     c.BaseFS.Path(),
 			Readonly: c.HostConfig.ReadonlyRootfs,
 		}
-		if err := c.SetupWorkingDirectory(daemon.idMapping.RootPair()); err != nil {
+		if err := c.SetupWorkingDirectory(daemon.containerIDMapping(c).RootPair()); err != nil {
 			return err
 		}
 		cwd := c.Config.WorkingDir
@@ -963,11 +980,28 @@ func (daemon *Daemon) createSpec(c *container.Container) (retSpec *specs.Spec, e
 	if daemon.configStore.Rootless {
 		opts = append(opts, WithRootless)
 	}
+	// The hooks and the patches of the spec are applied last, so that the
+	// patches can change any field of the spec.
+	opts = append(opts,
+		WithOCIHooks(daemon, c),
+		WithSpecPatches(daemon, c),
+		WithUsernsMounts(daemon, c),
+	)
 	return &s, coci.ApplyOpts(context.Background(), nil, &containers.Container{
 		ID: c.ID,
 	}, &s, opts...)
 }
 
+// WithUsernsMounts replaces the rootfs and the bind mount sources in the
+// daemon directory by mounts which the user namespace of a container with its
+// own mapping can reach. It is applied last, once the paths of the spec are
+// final.
+func WithUsernsMounts(daemon *Daemon, c *container.Container) coci.SpecOpts {
+	return func(ctx context.Context, _ coci.Client, _ *containers.Container, s *coci.Spec) error {
+		return daemon.setupUsernsMounts(c, s)
+	}
+}
+
 func clearReadOnly(m *specs.Mount) {
 	var opt []string
 	for _, o := range m.Options {
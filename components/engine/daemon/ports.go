@@ -0,0 +1,213 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+)
+
+// ContainerUpdatePorts publishes ports of a container, and unpublishes
+// others, and returns the ports published by the container. The bindings are
+// saved to the HostConfig of the container. If the container is running, its
+// port mappings are updated without restarting it.
+func (daemon *Daemon) ContainerUpdatePorts(name string, config *containertypes.PortsUpdateConfig) (nat.PortMap, error) {
+	if runtime.GOOS == "windows" {
+		return nil, errdefs.NotImplemented(errors.New("updating the published ports of a container is not supported on Windows"))
+	}
+
+	ctr, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyPortBindings(config.Add); err != nil {
+		return nil, errdefs.InvalidParameter(err)
+	}
+
+	ctr.Lock()
+	defer ctr.Unlock()
+
+	if ctr.RemovalInProgress || ctr.Dead {
+		return nil, errCannotUpdate(ctr.ID, errors.New("container is marked for removal and cannot be \"update\""))
+	}
+	if mode := ctr.HostConfig.NetworkMode; mode.IsHost() || mode.IsContainer() || mode.IsNone() {
+		return nil, errdefs.InvalidParameter(fmt.Errorf("cannot publish ports of a container using the %s network mode", mode))
+	}
+
+	bindings, err := updatePortBindings(ctr.HostConfig.PortBindings, config.Add, config.Remove)
+	if err != nil {
+		return nil, err
+	}
+
+	running := ctr.Running && !ctr.Restarting
+	if running {
+		if err := daemon.checkPortBindingConflicts(ctr.ID, config.Add); err != nil {
+			return nil, err
+		}
+	}
+
+	backupBindings := ctr.HostConfig.PortBindings
+	backupExposedPorts := ctr.Config.ExposedPorts
+	ctr.HostConfig.PortBindings = bindings
+	// The ports are only published if they are exposed.
+	exposedPorts := make(nat.PortSet, len(backupExposedPorts)+len(config.Add))
+	for p := range backupExposedPorts {
+		exposedPorts[p] = struct{}{}
+	}
+	for p := range config.Add {
+		exposedPorts[p] = struct{}{}
+	}
+	ctr.Config.ExposedPorts = exposedPorts
+
+	if running {
+		if err := daemon.updatePortMappings(ctr); err != nil {
+			ctr.HostConfig.PortBindings = backupBindings
+			ctr.Config.ExposedPorts = backupExposedPorts
+			return nil, errCannotUpdate(ctr.ID, err)
+		}
+	}
+
+	if err := ctr.CheckpointTo(daemon.containersReplica); err != nil {
+		return nil, errCannotUpdate(ctr.ID, err)
+	}
+	daemon.LogContainerEvent(ctr, "update")
+
+	ports := nat.PortMap{}
+	if ctr.NetworkSettings != nil {
+		for p, b := range ctr.NetworkSettings.Ports {
+			ports[p] = append([]nat.PortBinding(nil), b...)
+		}
+	}
+	return ports, nil
+}
+
+// verifyPortBindings validates the port bindings to publish.
+func verifyPortBindings(bindings nat.PortMap) error {
+	for p, b := range bindings {
+		if _, err := nat.ParsePort(p.Port()); err != nil || p.Int() == 0 {
+			return fmt.Errorf("invalid port %s", p)
+		}
+		switch p.Proto() {
+		case "tcp", "udp", "sctp":
+		default:
+			return fmt.Errorf("invalid protocol %s for port %s", p.Proto(), p)
+		}
+		for _, binding := range b {
+			if binding.HostIP != "" && net.ParseIP(binding.HostIP) == nil {
+				return fmt.Errorf("invalid host IP %s for port %s", binding.HostIP, p)
+			}
+			if binding.HostPort != "" {
+				if _, _, err := nat.ParsePortRange(binding.HostPort); err != nil {
+					return fmt.Errorf("invalid host port %s for port %s", binding.HostPort, p)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// updatePortBindings returns a copy of the port bindings current, without the
+// bindings of the ports in remove, and with the bindings in add. A port of add
+// without bindings is published on a port allocated by the daemon.
+func updatePortBindings(current, add nat.PortMap, remove []nat.Port) (nat.PortMap, error) {
+	bindings := make(nat.PortMap, len(current)+len(add))
+	for p, b := range current {
+		bindings[p] = append([]nat.PortBinding(nil), b...)
+	}
+	for _, p := range remove {
+		if _, ok := bindings[p]; !ok {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("port %s is not published", p))
+		}
+		delete(bindings, p)
+	}
+	for p, b := range add {
+		if len(b) == 0 {
+			b = []nat.PortBinding{{}}
+		}
+		for _, binding := range b {
+			for _, existing := range bindings[p] {
+				if existing == binding && binding.HostPort != "" {
+					return nil, errdefs.InvalidParameter(fmt.Errorf("port %s is already published on %s", p, formatHostPort(binding)))
+				}
+			}
+			bindings[p] = append(bindings[p], binding)
+		}
+	}
+	return bindings, nil
+}
+
+// checkPortBindingConflicts checks that the host ports of the bindings are not
+// published by another running container.
+func (daemon *Daemon) checkPortBindingConflicts(containerID string, bindings nat.PortMap) error {
+	all, err := daemon.containersReplica.Snapshot().All()
+	if err != nil {
+		return err
+	}
+	for p, b := range bindings {
+		for _, binding := range b {
+			if binding.HostPort == "" {
+				continue
+			}
+			start, end, err := nat.ParsePortRange(binding.HostPort)
+			if err != nil {
+				return errdefs.InvalidParameter(err)
+			}
+			for _, other := range all {
+				if other.ID == containerID || !other.Running {
+					continue
+				}
+				for _, published := range other.Ports {
+					if published.Type != p.Proto() || uint64(published.PublicPort) < start || uint64(published.PublicPort) > end {
+						continue
+					}
+					if hostIPsOverlap(binding.HostIP, published.IP) {
+						return errdefs.Conflict(fmt.Errorf("host port %s/%s is already published by container %s", formatHostPort(binding), p.Proto(), other.Name))
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// updatePortMappings updates the port mappings of a running container to the
+// port bindings of its HostConfig. The ports which are kept stay mapped, and
+// the interfaces of the container are not changed. On error, the port
+// mappings are left unchanged.
+func (daemon *Daemon) updatePortMappings(ctr *container.Container) error {
+	sb, err := daemon.netController.SandboxByID(ctr.NetworkSettings.SandboxID)
+	if err != nil {
+		return errdefs.System(fmt.Errorf("error locating sandbox id %s: %v", ctr.NetworkSettings.SandboxID, err))
+	}
+	exposedPorts, portBindings, err := buildPortMapping(ctr)
+	if err != nil {
+		return err
+	}
+	if err := sb.UpdatePortMapping(exposedPorts, portBindings); err != nil {
+		return errdefs.System(err)
+	}
+	ctr.NetworkSettings.Ports = getPortMapInfo(sb)
+	return nil
+}
+
+func hostIPsOverlap(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil || ipA.IsUnspecified() || ipB.IsUnspecified() {
+		return true
+	}
+	return ipA.Equal(ipB)
+}
+
+func formatHostPort(binding nat.PortBinding) string {
+	hostIP := binding.HostIP
+	if hostIP == "" {
+		hostIP = "0.0.0.0"
+	}
+	return net.JoinHostPort(hostIP, binding.HostPort)
+}
@@ -0,0 +1,102 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestVerifyPortBindings(t *testing.T) {
+	assert.NilError(t, verifyPortBindings(nat.PortMap{
+		"80/tcp":   {{HostPort: "8080"}, {HostIP: "127.0.0.1", HostPort: "9000-9010"}},
+		"53/udp":   {{}},
+		"9000/tcp": nil,
+	}))
+
+	testCases := []struct {
+		bindings nat.PortMap
+		expected string
+	}{
+		{nat.PortMap{"http/tcp": nil}, "invalid port http/tcp"},
+		{nat.PortMap{"80/icmp": nil}, "invalid protocol icmp for port 80/icmp"},
+		{nat.PortMap{"80/tcp": {{HostIP: "localhost"}}}, "invalid host IP localhost for port 80/tcp"},
+		{nat.PortMap{"80/tcp": {{HostPort: "80a"}}}, "invalid host port 80a for port 80/tcp"},
+	}
+	for _, tc := range testCases {
+		assert.Check(t, is.Error(verifyPortBindings(tc.bindings), tc.expected))
+	}
+}
+
+func TestUpdatePortBindings(t *testing.T) {
+	current := nat.PortMap{
+		"80/tcp":  {{HostPort: "8080"}},
+		"443/tcp": {{HostPort: "8443"}},
+	}
+
+	bindings, err := updatePortBindings(current, nat.PortMap{
+		"80/tcp": {{HostIP: "127.0.0.1", HostPort: "9080"}},
+		"53/udp": nil,
+	}, []nat.Port{"443/tcp"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(bindings, nat.PortMap{
+		"80/tcp": {{HostPort: "8080"}, {HostIP: "127.0.0.1", HostPort: "9080"}},
+		"53/udp": {{}},
+	}))
+	// the current bindings are not modified
+	assert.Check(t, is.DeepEqual(current, nat.PortMap{
+		"80/tcp":  {{HostPort: "8080"}},
+		"443/tcp": {{HostPort: "8443"}},
+	}))
+
+	_, err = updatePortBindings(current, nil, []nat.Port{"22/tcp"})
+	assert.Check(t, is.Error(err, "port 22/tcp is not published"))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	_, err = updatePortBindings(current, nat.PortMap{"80/tcp": {{HostPort: "8080"}}}, nil)
+	assert.Check(t, is.Error(err, "port 80/tcp is already published on 0.0.0.0:8080"))
+}
+
+func TestCheckPortBindingConflicts(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{containersReplica: db}
+
+	web := setupContainerWithName(t, "web", d)
+	web.NetworkSettings = &network.Settings{Ports: nat.PortMap{
+		"80/tcp": {{HostIP: "127.0.0.1", HostPort: "8080"}},
+		"53/udp": {{HostIP: "0.0.0.0", HostPort: "5353"}},
+	}}
+	assert.NilError(t, db.Save(web))
+	stopped := setupContainerWithName(t, "stopped", d)
+	stopped.Running = false
+	stopped.NetworkSettings = &network.Settings{Ports: nat.PortMap{
+		"80/tcp": {{HostIP: "0.0.0.0", HostPort: "9090"}},
+	}}
+	assert.NilError(t, db.Save(stopped))
+	self := setupContainerWithName(t, "self", d)
+
+	for _, bindings := range []nat.PortMap{
+		{"80/tcp": {{HostPort: "8080"}}},
+		{"8000/tcp": {{HostIP: "127.0.0.1", HostPort: "8000-8100"}}},
+		{"53/udp": {{HostIP: "10.0.0.1", HostPort: "5353"}}},
+	} {
+		err := d.checkPortBindingConflicts(self.ID, bindings)
+		assert.Check(t, is.ErrorContains(err, "is already published by container /web"), bindings)
+		assert.Check(t, errdefs.IsConflict(err))
+	}
+
+	for _, bindings := range []nat.PortMap{
+		{"80/tcp": {{HostIP: "10.0.0.1", HostPort: "8080"}}},
+		{"80/udp": {{HostPort: "8080"}}},
+		{"80/tcp": {{HostPort: "9090"}}},
+		{"80/tcp": {{}}},
+	} {
+		assert.Check(t, d.checkPortBindingConflicts(self.ID, bindings), bindings)
+	}
+	assert.Check(t, d.checkPortBindingConflicts(web.ID, nat.PortMap{"80/tcp": {{HostPort: "8080"}}}))
+}
@@ -3,12 +3,50 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
+	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/daemon/config"
 	"github.com/docker/docker/daemon/discovery"
+	"github.com/docker/docker/pkg/authorization"
 	"github.com/sirupsen/logrus"
 )
 
+// reloadableOptions contains the configuration keys which are applied by
+// Reload without restarting the daemon, on all the platforms. The keys which
+// are only applied on some platforms are in platformReloadableOptions.
+var reloadableOptions = map[string]bool{
+	"allow-nondistributable-artifacts": true,
+	"authorization-plugins":            true,
+	"authorization-policy":             true,
+	"cluster-advertise":                true,
+	"cluster-store":                    true,
+	"cluster-store-opts":               true,
+	"debug":                            true,
+	"default-address-pools":            true,
+	"exec-log":                         true,
+	"features":                         true,
+	"hosts":                            true,
+	"insecure-registries":              true,
+	"labels":                           true,
+	"listeners":                        true,
+	"live-restore":                     true,
+	"log-driver":                       true,
+	"log-opts":                         true,
+	"max-concurrent-downloads":         true,
+	"max-concurrent-uploads":           true,
+	"network-diagnostic-port":          true,
+	"registry-mirrors":                 true,
+	"shutdown-timeout":                 true,
+	"tls":                              true,
+	"tlscacert":                        true,
+	"tlscert":                          true,
+	"tlskey":                           true,
+	"tlsverify":                        true,
+}
+
 // Reload reads configuration changes and modifies the
 // daemon according to those changes.
 // These are the settings that Reload changes:
@@ -22,6 +60,15 @@ import (
 // - Insecure registries
 // - Registry mirrors
 // - Daemon live restore
+// - Authorization policy
+// - API hosts, TLS settings and listener settings
+// - Default log driver and log options
+// - Exec log
+// - Default address pools, for the networks created after the reload
+//
+// The attributes of the reload event list the options of the configuration
+// file which were applied, and the ones which are only applied when the daemon
+// is restarted.
 func (daemon *Daemon) Reload(conf *config.Config) (err error) {
 	daemon.configStore.Lock()
 	attributes := map[string]string{}
@@ -39,13 +86,20 @@ func (daemon *Daemon) Reload(conf *config.Config) (err error) {
 		}
 	}()
 
+	if err := daemon.validateReload(conf); err != nil {
+		return err
+	}
 	if err := daemon.reloadPlatform(conf, attributes); err != nil {
 		return err
 	}
+	if err := daemon.reloadLogConfig(conf, attributes); err != nil {
+		return err
+	}
 	daemon.reloadDebug(conf, attributes)
 	daemon.reloadMaxConcurrentDownloadsAndUploads(conf, attributes)
 	daemon.reloadShutdownTimeout(conf, attributes)
 	daemon.reloadFeatures(conf, attributes)
+	daemon.reloadExecLog(conf, attributes)
 
 	if err := daemon.reloadClusterDiscovery(conf, attributes); err != nil {
 		return err
@@ -65,7 +119,166 @@ func (daemon *Daemon) Reload(conf *config.Config) (err error) {
 	if err := daemon.reloadLiveRestore(conf, attributes); err != nil {
 		return err
 	}
-	return daemon.reloadNetworkDiagnosticPort(conf, attributes)
+	if err := daemon.reloadAuthorizationPolicy(conf, attributes); err != nil {
+		return err
+	}
+	if err := daemon.reloadAPIListeners(conf, attributes); err != nil {
+		return err
+	}
+	if err := daemon.reloadNetworkDiagnosticPort(conf, attributes); err != nil {
+		return err
+	}
+	if err := daemon.reloadDefaultAddressPools(conf, attributes); err != nil {
+		return err
+	}
+	daemon.reloadValuesSet(conf, attributes)
+	return nil
+}
+
+// validateReload validates the options of conf which are applied by Reload,
+// merged with the options in effect which are not set in conf, with the
+// validation of the platform settings, of the default address pools and of
+// ValidateConfig. The options are all validated before any of them is
+// applied, so that an invalid configuration leaves the daemon untouched.
+func (daemon *Daemon) validateReload(conf *config.Config) error {
+	if err := conf.ValidatePlatformConfig(); err != nil {
+		return err
+	}
+	if conf.IsValueSet("runtimes") {
+		if err := validateRuntimes(conf); err != nil {
+			return err
+		}
+	}
+
+	reloaded := &config.Config{}
+	reloaded.LogConfig = daemon.reloadedLogConfig(conf)
+	reloaded.ServiceOptions = daemon.configStore.ServiceOptions
+	if conf.IsValueSet("allow-nondistributable-artifacts") {
+		reloaded.AllowNondistributableArtifacts = conf.AllowNondistributableArtifacts
+	}
+	if conf.IsValueSet("insecure-registries") {
+		reloaded.InsecureRegistries = conf.InsecureRegistries
+	}
+	if conf.IsValueSet("registry-mirrors") {
+		reloaded.Mirrors = conf.Mirrors
+	}
+	reloaded.AuthorizationPolicy = daemon.configStore.AuthorizationPolicy
+	if conf.IsValueSet("authorization-policy") {
+		reloaded.AuthorizationPolicy = conf.AuthorizationPolicy
+	}
+	if err := config.ValidateDefaultAddressPools(conf.NetworkConfig.DefaultAddressPools); err != nil {
+		return err
+	}
+	return ValidateConfig(reloaded)
+}
+
+// isReloadableOption returns whether the option with the given configuration
+// key is applied by Reload.
+func isReloadableOption(name string) bool {
+	return reloadableOptions[name] || platformReloadableOptions[name]
+}
+
+// reloadValuesSet updates configuration with the options explicitly set in
+// the configuration file, and updates the passed attributes with the options
+// which were applied, and the ones which were changed but are only applied
+// when the daemon is restarted.
+func (daemon *Daemon) reloadValuesSet(conf *config.Config, attributes map[string]string) {
+	names := make(map[string]bool)
+	for name := range daemon.configStore.ValuesSet {
+		names[name] = true
+	}
+	for name := range conf.ValuesSet {
+		names[name] = true
+	}
+
+	var applied, restartRequired []string
+	valuesSet := make(map[string]interface{})
+	for name := range names {
+		oldValue, wasSet := daemon.configStore.ValuesSet[name]
+		newValue, set := conf.ValuesSet[name]
+		changed := set != wasSet || !reflect.DeepEqual(oldValue, newValue)
+		switch {
+		case isReloadableOption(name) && set:
+			valuesSet[name] = newValue
+			if changed {
+				applied = append(applied, name)
+			}
+		case wasSet:
+			// the value which is in effect is kept, as the option was
+			// removed from the file, or it is not applied before a restart
+			valuesSet[name] = oldValue
+		}
+		if changed && !isReloadableOption(name) {
+			restartRequired = append(restartRequired, name)
+		}
+	}
+	daemon.configStore.ValuesSet = valuesSet
+
+	sort.Strings(applied)
+	sort.Strings(restartRequired)
+	if len(restartRequired) > 0 {
+		logrus.Warnf("The following options changed in the configuration file are not applied until the daemon is restarted: %s", strings.Join(restartRequired, ", "))
+	}
+
+	// prepare reload event attributes with the changed options
+	attributes["applied"] = strings.Join(applied, ",")
+	attributes["restart-required"] = strings.Join(restartRequired, ",")
+}
+
+// reloadedLogConfig returns the default log driver and log options of the
+// daemon once conf is applied.
+func (daemon *Daemon) reloadedLogConfig(conf *config.Config) config.LogConfig {
+	logConfig := daemon.configStore.LogConfig
+	if conf.IsValueSet("log-driver") {
+		if conf.LogConfig.Type != logConfig.Type {
+			// the options of the previous log driver are not kept, as they
+			// may not be supported by the new one
+			logConfig.Config = make(map[string]string)
+		}
+		logConfig.Type = conf.LogConfig.Type
+	}
+	if conf.IsValueSet("log-opts") {
+		logConfig.Config = conf.LogConfig.Config
+	}
+	return logConfig
+}
+
+// reloadLogConfig updates configuration with the default log driver and log
+// options, which are used by the containers created after the reload, and
+// updates the passed attributes
+func (daemon *Daemon) reloadLogConfig(conf *config.Config, attributes map[string]string) error {
+	if conf.IsValueSet("log-driver") || conf.IsValueSet("log-opts") {
+		logConfig := daemon.reloadedLogConfig(conf)
+		daemon.configStore.LogConfig = logConfig
+		daemon.setDefaultLogConfig(containertypes.LogConfig{
+			Type:   logConfig.Type,
+			Config: logConfig.Config,
+		})
+	}
+
+	// prepare reload event attributes with updatable configurations
+	attributes["log-driver"] = daemon.configStore.LogConfig.Type
+	attributes["log-opts"] = "{}"
+	if len(daemon.configStore.LogConfig.Config) > 0 {
+		logOpts, err := json.Marshal(daemon.configStore.LogConfig.Config)
+		if err != nil {
+			return err
+		}
+		attributes["log-opts"] = string(logOpts)
+	}
+	return nil
+}
+
+// reloadExecLog updates configuration with the exec-log option, which is used
+// by the exec processes created after the reload, and updates the passed
+// attributes
+func (daemon *Daemon) reloadExecLog(conf *config.Config, attributes map[string]string) {
+	if conf.IsValueSet("exec-log") {
+		daemon.configStore.ExecLog = conf.ExecLog
+	}
+
+	// prepare reload event attributes with updatable configurations
+	attributes["exec-log"] = fmt.Sprintf("%t", daemon.configStore.ExecLog)
 }
 
 // reloadDebug updates configuration with Debug option
@@ -308,6 +521,108 @@ func (daemon *Daemon) reloadLiveRestore(conf *config.Config, attributes map[stri
 	return nil
 }
 
+// reloadAuthorizationPolicy reads the authorization policy file again, so
+// that its changes are applied without restarting the daemon, and updates the
+// passed attributes. The current policy is kept if the file is invalid.
+func (daemon *Daemon) reloadAuthorizationPolicy(conf *config.Config, attributes map[string]string) error {
+	policyPath := daemon.configStore.AuthorizationPolicy
+	if conf.IsValueSet("authorization-policy") {
+		policyPath = conf.AuthorizationPolicy
+	}
+
+	var policy *authorization.Policy
+	if policyPath != "" {
+		var err error
+		if policy, err = authorization.LoadPolicy(policyPath); err != nil {
+			return err
+		}
+	}
+	daemon.configStore.AuthorizationPolicy = policyPath
+	if daemon.configStore.AuthzMiddleware != nil {
+		daemon.configStore.AuthzMiddleware.SetPolicy(policy)
+	}
+
+	// prepare reload event attributes with updatable configurations
+	attributes["authorization-policy"] = daemon.configStore.AuthorizationPolicy
+	return nil
+}
+
+// reloadAPIListeners updates the configuration with the hosts, the TLS
+// settings and the listener settings of the API, which are applied to the
+// listeners by the API server before the daemon is reloaded.
+func (daemon *Daemon) reloadAPIListeners(conf *config.Config, attributes map[string]string) error {
+	if conf.IsValueSet("hosts") {
+		daemon.configStore.Hosts = conf.Hosts
+		daemon.hosts = nil
+		daemon.StoreHosts(hostAddresses(conf.Hosts))
+	}
+	if conf.IsValueSet("tls") {
+		daemon.configStore.TLS = conf.TLS
+	}
+	if conf.IsValueSet("tlsverify") {
+		// setting tlsverify at all turns on TLS
+		daemon.configStore.TLS = true
+		daemon.configStore.TLSVerify = conf.TLSVerify
+	}
+	if conf.IsValueSet("tlscacert") {
+		daemon.configStore.CAFile = conf.CAFile
+	}
+	if conf.IsValueSet("tlscert") {
+		daemon.configStore.CertFile = conf.CertFile
+	}
+	if conf.IsValueSet("tlskey") {
+		daemon.configStore.KeyFile = conf.KeyFile
+	}
+	if conf.IsValueSet("listeners") {
+		daemon.configStore.Listeners = conf.Listeners
+	}
+
+	// prepare reload event attributes with updatable configurations
+	hosts, err := json.Marshal(daemon.configStore.Hosts)
+	if err != nil {
+		return err
+	}
+	attributes["hosts"] = string(hosts)
+	attributes["tls"] = fmt.Sprintf("%t", daemon.configStore.TLS)
+	attributes["tlsverify"] = fmt.Sprintf("%t", daemon.configStore.TLSVerify)
+	return nil
+}
+
+// reloadDefaultAddressPools updates the configuration and the built-in IPAM
+// driver with the default address pools, and updates the passed attributes.
+// The subnets of the existing networks are kept, and the new pools are used
+// for the networks which are created afterwards.
+func (daemon *Daemon) reloadDefaultAddressPools(conf *config.Config, attributes map[string]string) error {
+	if conf.IsValueSet("default-address-pools") {
+		pools := conf.NetworkConfig.DefaultAddressPools.Value()
+		if len(pools) == 0 {
+			// the IPAM driver uses its own pools if none is configured
+			pools = nil
+		}
+		if daemon.netController != nil {
+			if err := daemon.netController.SetDefaultAddressPools(pools); err != nil {
+				return err
+			}
+		}
+		daemon.configStore.NetworkConfig.DefaultAddressPools = conf.NetworkConfig.DefaultAddressPools
+	}
+
+	// prepare reload event attributes with updatable configurations
+	attributes["default-address-pools"] = daemon.configStore.NetworkConfig.DefaultAddressPools.String()
+	return nil
+}
+
+// hostAddresses returns the addresses of the normalized hosts.
+func hostAddresses(hosts []string) []string {
+	var addrs []string
+	for _, h := range hosts {
+		if parts := strings.SplitN(h, "://", 2); len(parts) == 2 {
+			addrs = append(addrs, parts[1])
+		}
+	}
+	return addrs
+}
+
 // reloadNetworkDiagnosticPort updates the network controller starting the diagnostic if the config is valid
 func (daemon *Daemon) reloadNetworkDiagnosticPort(conf *config.Config, attributes map[string]string) error {
 	if conf == nil || daemon.netController == nil || !conf.IsValueSet("network-diagnostic-port") ||
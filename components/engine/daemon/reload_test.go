@@ -1,14 +1,18 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"testing"
 	"time"
 
+	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/daemon/config"
 	"github.com/docker/docker/daemon/images"
+	"github.com/docker/docker/pkg/authorization"
 	"github.com/docker/docker/pkg/discovery"
 	_ "github.com/docker/docker/pkg/discovery/memory"
 	"github.com/docker/docker/registry"
@@ -571,3 +575,227 @@ func TestDaemonReloadNetworkDiagnosticPort(t *testing.T) {
 	}
 
 }
+
+func TestDaemonReloadDefaultAddressPools(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("root required")
+	}
+	root, err := ioutil.TempDir("", "reload-address-pools")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	daemon := &Daemon{
+		imageService: images.NewImageService(images.ImageServiceConfig{}),
+	}
+	daemon.configStore = &config.Config{
+		CommonConfig: config.CommonConfig{Root: root},
+	}
+	netOptions, err := daemon.networkOptions(daemon.configStore, nil, nil)
+	assert.NilError(t, err)
+	controller, err := libnetwork.New(netOptions...)
+	assert.NilError(t, err)
+	defer controller.Stop()
+	daemon.netController = controller
+
+	newConfig := &config.Config{
+		CommonConfig: config.CommonConfig{
+			ValuesSet: map[string]interface{}{"default-address-pools": nil},
+		},
+	}
+	assert.NilError(t, newConfig.NetworkConfig.DefaultAddressPools.Set("base=10.123.0.0/16,size=24"))
+	assert.NilError(t, daemon.Reload(newConfig))
+	// the default pools of the IPAM driver are restored for the other tests
+	defer daemon.Reload(&config.Config{
+		CommonConfig: config.CommonConfig{
+			ValuesSet: map[string]interface{}{"default-address-pools": nil},
+		},
+	})
+	assert.Check(t, is.Len(daemon.configStore.NetworkConfig.DefaultAddressPools.Value(), 1))
+
+	// the networks created after the reload use the new pools
+	n, err := controller.NewNetwork("bridge", "reload-address-pools", "")
+	assert.NilError(t, err)
+	defer n.Delete()
+	ipv4, _ := n.Info().IpamInfo()
+	assert.Assert(t, is.Len(ipv4, 1))
+	assert.Check(t, is.Equal(ipv4[0].Pool.String(), "10.123.0.0/24"))
+
+	// invalid pools are not applied
+	invalidConfig := &config.Config{
+		CommonConfig: config.CommonConfig{
+			ValuesSet: map[string]interface{}{"default-address-pools": nil},
+		},
+	}
+	assert.NilError(t, invalidConfig.NetworkConfig.DefaultAddressPools.Set("base=10.124.0.0/16,size=8"))
+	assert.Check(t, is.ErrorContains(daemon.Reload(invalidConfig), "invalid default address pool size"))
+	assert.Check(t, is.Equal(daemon.configStore.NetworkConfig.DefaultAddressPools.String(), "10.123.0.0/16 24"))
+}
+
+func TestDaemonReloadAuthorizationPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "authz-policy")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	policyPath := filepath.Join(dir, "policy.json")
+	assert.NilError(t, ioutil.WriteFile(policyPath, []byte(`{"rules":[{"uids":[0]}]}`), 0600))
+
+	daemon := &Daemon{
+		configStore: &config.Config{
+			CommonConfig: config.CommonConfig{
+				AuthzMiddleware: authorization.NewMiddleware(nil, nil),
+			},
+		},
+		imageService: images.NewImageService(images.ImageServiceConfig{}),
+	}
+
+	newConfig := &config.Config{
+		CommonConfig: config.CommonConfig{
+			AuthorizationPolicy: policyPath,
+			ValuesSet:           map[string]interface{}{"authorization-policy": policyPath},
+		},
+	}
+	assert.NilError(t, daemon.Reload(newConfig))
+	assert.Check(t, is.Equal(daemon.configStore.AuthorizationPolicy, policyPath))
+
+	// An invalid policy is not applied.
+	assert.NilError(t, ioutil.WriteFile(policyPath, []byte(`{"rules":[{"endpoints":["info"]}]}`), 0600))
+	err = daemon.Reload(&config.Config{})
+	assert.Check(t, is.ErrorContains(err, "invalid authorization policy"))
+}
+
+func TestDaemonReloadAPIListeners(t *testing.T) {
+	daemon := &Daemon{
+		configStore: &config.Config{
+			CommonConfig: config.CommonConfig{
+				Hosts: []string{"unix:///var/run/docker.sock"},
+			},
+		},
+		imageService: images.NewImageService(images.ImageServiceConfig{}),
+	}
+	daemon.StoreHosts([]string{"/var/run/docker.sock"})
+
+	verify := true
+	newConfig := &config.Config{
+		CommonConfig: config.CommonConfig{
+			Hosts:     []string{"tcp://0.0.0.0:2376"},
+			TLSVerify: true,
+			Listeners: map[string]config.ListenerConfig{
+				"tcp://0.0.0.0:2376": {TLS: &verify, MaxAPIVersion: "1.40"},
+			},
+			ValuesSet: map[string]interface{}{
+				"hosts":     []string{"tcp://0.0.0.0:2376"},
+				"tlsverify": true,
+				"listeners": nil,
+			},
+		},
+	}
+	assert.NilError(t, daemon.Reload(newConfig))
+	assert.Check(t, is.DeepEqual(daemon.configStore.Hosts, []string{"tcp://0.0.0.0:2376"}))
+	assert.Check(t, daemon.configStore.TLS)
+	assert.Check(t, daemon.configStore.TLSVerify)
+	assert.Check(t, is.Equal(daemon.configStore.Listeners["tcp://0.0.0.0:2376"].MaxAPIVersion, "1.40"))
+	assert.Check(t, is.DeepEqual(daemon.hosts, map[string]bool{"0.0.0.0:2376": true}))
+
+	// The settings which are not set are kept.
+	assert.NilError(t, daemon.Reload(&config.Config{}))
+	assert.Check(t, is.DeepEqual(daemon.configStore.Hosts, []string{"tcp://0.0.0.0:2376"}))
+	assert.Check(t, daemon.configStore.TLSVerify)
+}
+
+func TestDaemonReloadLogConfig(t *testing.T) {
+	daemon := &Daemon{
+		configStore: &config.Config{
+			CommonConfig: config.CommonConfig{
+				LogConfig: config.LogConfig{Type: "json-file", Config: map[string]string{"max-size": "10m"}},
+			},
+		},
+		imageService: images.NewImageService(images.ImageServiceConfig{}),
+	}
+	assert.NilError(t, daemon.setupDefaultLogConfig())
+
+	// The options of the previous log driver are not kept.
+	newConfig := &config.Config{
+		CommonConfig: config.CommonConfig{
+			LogConfig: config.LogConfig{Type: "local"},
+			ValuesSet: map[string]interface{}{"log-driver": "local"},
+		},
+	}
+	assert.NilError(t, daemon.Reload(newConfig))
+	assert.Check(t, is.Equal(daemon.configStore.LogConfig.Type, "local"))
+	assert.Check(t, is.Len(daemon.configStore.LogConfig.Config, 0))
+	assert.Check(t, is.Equal(daemon.getDefaultLogConfig().Type, "local"))
+
+	newConfig = &config.Config{
+		CommonConfig: config.CommonConfig{
+			LogConfig: config.LogConfig{Type: "json-file", Config: map[string]string{"max-file": "3", "max-size": "20m"}},
+			ValuesSet: map[string]interface{}{"log-driver": "json-file", "log-opts": nil},
+		},
+	}
+	assert.NilError(t, daemon.Reload(newConfig))
+	assert.Check(t, is.DeepEqual(daemon.getDefaultLogConfig(), containertypes.LogConfig{
+		Type:   "json-file",
+		Config: map[string]string{"max-file": "3", "max-size": "20m"},
+	}))
+
+	// Invalid log options are not applied.
+	newConfig = &config.Config{
+		CommonConfig: config.CommonConfig{
+			LogConfig: config.LogConfig{Config: map[string]string{"unknown": "value"}},
+			ValuesSet: map[string]interface{}{"log-opts": nil},
+		},
+	}
+	assert.Check(t, is.ErrorContains(daemon.Reload(newConfig), "failed to set log opts"))
+	assert.Check(t, is.Equal(daemon.getDefaultLogConfig().Config["max-size"], "20m"))
+
+	// Valid log options are not applied if another option is invalid.
+	newConfig = &config.Config{
+		CommonConfig: config.CommonConfig{
+			LogConfig: config.LogConfig{Config: map[string]string{"max-size": "30m"}},
+			ServiceOptions: registry.ServiceOptions{
+				Mirrors: []string{"not a mirror"},
+			},
+			ValuesSet: map[string]interface{}{"log-opts": nil, "registry-mirrors": nil},
+		},
+	}
+	assert.Check(t, is.ErrorContains(daemon.Reload(newConfig), "invalid mirror"))
+	assert.Check(t, is.Equal(daemon.getDefaultLogConfig().Config["max-size"], "20m"))
+	assert.Check(t, is.Equal(daemon.configStore.LogConfig.Config["max-size"], "20m"))
+}
+
+func TestDaemonReloadValuesSet(t *testing.T) {
+	daemon := &Daemon{
+		configStore: &config.Config{
+			CommonConfig: config.CommonConfig{
+				ValuesSet: map[string]interface{}{
+					"data-root":             "/var/lib/docker",
+					"debug":                 false,
+					"default-address-pools": []interface{}{map[string]interface{}{"base": "10.10.0.0/16", "size": 24.0}},
+					"shutdown-timeout":      15.0,
+				},
+			},
+		},
+	}
+
+	newConfig := &config.Config{
+		CommonConfig: config.CommonConfig{
+			ValuesSet: map[string]interface{}{
+				"data-root":             "/data/docker",
+				"debug":                 true,
+				"default-address-pools": []interface{}{map[string]interface{}{"base": "10.20.0.0/16", "size": 24.0}},
+				"labels":                []interface{}{"foo=bar"},
+			},
+		},
+	}
+	attributes := map[string]string{}
+	daemon.reloadValuesSet(newConfig, attributes)
+	assert.Check(t, is.Equal(attributes["applied"], "debug,default-address-pools,labels"))
+	assert.Check(t, is.Equal(attributes["restart-required"], "data-root"))
+	assert.Check(t, is.Equal(daemon.configStore.ValuesSet["data-root"], "/var/lib/docker"))
+	assert.Check(t, is.Equal(daemon.configStore.ValuesSet["debug"], true))
+	assert.Check(t, is.Equal(daemon.configStore.ValuesSet["shutdown-timeout"], 15.0))
+
+	// The options which are not applied are reported until the daemon is restarted.
+	attributes = map[string]string{}
+	daemon.reloadValuesSet(newConfig, attributes)
+	assert.Check(t, is.Equal(attributes["applied"], ""))
+	assert.Check(t, is.Equal(attributes["restart-required"], "data-root"))
+}
@@ -5,18 +5,31 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"bytes"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/daemon/config"
 )
 
+// platformReloadableOptions contains the configuration keys which are
+// applied by Reload without restarting the daemon, in addition to the ones in
+// reloadableOptions.
+var platformReloadableOptions = map[string]bool{
+	"allowed-oci-hooks":     true,
+	"default-cgroupns-mode": true,
+	"default-ipc-mode":      true,
+	"default-runtime":       true,
+	"default-shm-size":      true,
+	"default-ulimits":       true,
+	"runtimes":              true,
+	"spec-patches":          true,
+}
+
 // reloadPlatform updates configuration with platform specific options
 // and updates the passed attributes
 func (daemon *Daemon) reloadPlatform(conf *config.Config, attributes map[string]string) error {
-	if err := conf.ValidatePlatformConfig(); err != nil {
-		return err
-	}
-
 	if conf.IsValueSet("runtimes") {
 		// Always set the default one
 		conf.Runtimes[config.StockRuntimeName] = types.Runtime{Path: DefaultRuntimeBinary}
@@ -34,6 +47,10 @@ func (daemon *Daemon) reloadPlatform(conf *config.Config, attributes map[string]
 		daemon.configStore.ShmSize = conf.ShmSize
 	}
 
+	if conf.IsValueSet("default-ulimits") {
+		daemon.configStore.Ulimits = conf.Ulimits
+	}
+
 	if conf.CgroupNamespaceMode != "" {
 		daemon.configStore.CgroupNamespaceMode = conf.CgroupNamespaceMode
 	}
@@ -42,20 +59,45 @@ func (daemon *Daemon) reloadPlatform(conf *config.Config, attributes map[string]
 		daemon.configStore.IpcMode = conf.IpcMode
 	}
 
+	if conf.IsValueSet("allowed-oci-hooks") {
+		daemon.configStore.AllowedOCIHooks = conf.AllowedOCIHooks
+	}
+
+	if conf.IsValueSet("spec-patches") {
+		daemon.configStore.SpecPatches = conf.SpecPatches
+	}
+
 	// Update attributes
 	var runtimeList bytes.Buffer
 	for name, rt := range daemon.configStore.Runtimes {
 		if runtimeList.Len() > 0 {
 			runtimeList.WriteRune(' ')
 		}
-		runtimeList.WriteString(fmt.Sprintf("%s:%s", name, rt))
+		if rt.Type != "" {
+			runtimeList.WriteString(fmt.Sprintf("%s:%s", name, rt.Type))
+			continue
+		}
+		runtimeList.WriteString(fmt.Sprintf("%s:{%s %s}", name, rt.Path, rt.Args))
 	}
 
+	var ulimits []string
+	for _, ul := range daemon.configStore.Ulimits {
+		ulimits = append(ulimits, ul.String())
+	}
+	sort.Strings(ulimits)
+
 	attributes["runtimes"] = runtimeList.String()
 	attributes["default-runtime"] = daemon.configStore.DefaultRuntime
 	attributes["default-shm-size"] = fmt.Sprintf("%d", daemon.configStore.ShmSize)
+	attributes["default-ulimits"] = strings.Join(ulimits, ",")
 	attributes["default-ipc-mode"] = daemon.configStore.IpcMode
 	attributes["default-cgroupns-mode"] = daemon.configStore.CgroupNamespaceMode
+	var hooks []string
+	for _, h := range daemon.configStore.AllowedOCIHooks {
+		hooks = append(hooks, h.String())
+	}
+	attributes["allowed-oci-hooks"] = strings.Join(hooks, ",")
+	attributes["spec-patches"] = strconv.Itoa(len(daemon.configStore.SpecPatches))
 
 	return nil
 }
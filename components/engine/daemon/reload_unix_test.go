@@ -0,0 +1,46 @@
+// +build linux freebsd
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/daemon/images"
+	"github.com/docker/go-units"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestDaemonReloadDefaultUlimits(t *testing.T) {
+	daemon := &Daemon{
+		configStore: &config.Config{
+			Ulimits: map[string]*units.Ulimit{"nofile": {Name: "nofile", Soft: 1024, Hard: 2048}},
+		},
+		imageService: images.NewImageService(images.ImageServiceConfig{}),
+	}
+
+	newConfig := &config.Config{
+		CommonConfig: config.CommonConfig{
+			ValuesSet: map[string]interface{}{"default-ulimits": nil},
+		},
+		Ulimits: map[string]*units.Ulimit{
+			"nofile": {Name: "nofile", Soft: 4096, Hard: 8192},
+			"nproc":  {Name: "nproc", Soft: 512, Hard: 512},
+		},
+	}
+	assert.NilError(t, daemon.Reload(newConfig))
+
+	// The new defaults are merged to the ulimits of the new containers.
+	hostConfig := &containertypes.HostConfig{
+		Resources: containertypes.Resources{
+			Ulimits: []*units.Ulimit{{Name: "nproc", Soft: 256, Hard: 256}},
+		},
+	}
+	daemon.mergeUlimits(hostConfig)
+	assert.Check(t, is.DeepEqual(hostConfig.Ulimits, []*units.Ulimit{
+		{Name: "nproc", Soft: 256, Hard: 256},
+		{Name: "nofile", Soft: 4096, Hard: 8192},
+	}))
+}
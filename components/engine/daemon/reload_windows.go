@@ -2,6 +2,11 @@ package daemon // import "github.com/docker/docker/daemon"
 
 import "github.com/docker/docker/daemon/config"
 
+// platformReloadableOptions contains the configuration keys which are
+// applied by Reload without restarting the daemon, in addition to the ones in
+// reloadableOptions.
+var platformReloadableOptions = map[string]bool{}
+
 // reloadPlatform updates configuration with platform specific options
 // and updates the passed attributes
 func (daemon *Daemon) reloadPlatform(config *config.Config, attributes map[string]string) error {
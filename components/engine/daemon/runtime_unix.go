@@ -0,0 +1,108 @@
+// +build !windows
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/containerd/containerd/plugin"
+	runcoptions "github.com/containerd/containerd/runtime/v2/runc/options"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/daemon/config"
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+)
+
+// validateRuntimes checks the options of the runtimes with a runtime type,
+// which are decoded when the runtimes are initialized.
+func validateRuntimes(conf *config.Config) error {
+	for name, rt := range conf.Runtimes {
+		if rt.Type == "" {
+			continue
+		}
+		if _, err := getShimConfig(name, rt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getShimConfig returns the configuration of the v2 containerd shim of a
+// runtime with a runtime type. The options of the runc shims are decoded to
+// the options of these shims, and the options of the other shims are passed
+// to them as a google.protobuf.Struct.
+func getShimConfig(name string, rt types.Runtime) (*types.ShimConfig, error) {
+	shim := &types.ShimConfig{Binary: rt.Type}
+	switch rt.Type {
+	case plugin.RuntimeRuncV1, plugin.RuntimeRuncV2:
+		opts := &runcoptions.Options{}
+		if len(rt.Options) > 0 {
+			b, err := json.Marshal(rt.Options)
+			if err != nil {
+				return nil, errors.Wrapf(err, "runtime %s: invalid options", name)
+			}
+			dec := json.NewDecoder(bytes.NewReader(b))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(opts); err != nil {
+				return nil, errors.Wrapf(err, "runtime %s: invalid options", name)
+			}
+		}
+		shim.Opts = opts
+	default:
+		if len(rt.Options) > 0 {
+			opts, err := structOptions(rt.Options)
+			if err != nil {
+				return nil, errors.Wrapf(err, "runtime %s: invalid options", name)
+			}
+			shim.Opts = opts
+		}
+	}
+	return shim, nil
+}
+
+// structOptions converts the options of a runtime, as decoded from the
+// daemon configuration, to a google.protobuf.Struct.
+func structOptions(options map[string]interface{}) (*ptypes.Struct, error) {
+	s := &ptypes.Struct{Fields: make(map[string]*ptypes.Value, len(options))}
+	for k, o := range options {
+		v, err := structValue(o)
+		if err != nil {
+			return nil, errors.Wrapf(err, "option %s", k)
+		}
+		s.Fields[k] = v
+	}
+	return s, nil
+}
+
+func structValue(v interface{}) (*ptypes.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return &ptypes.Value{Kind: &ptypes.Value_NullValue{NullValue: ptypes.NullValue_NULL_VALUE}}, nil
+	case bool:
+		return &ptypes.Value{Kind: &ptypes.Value_BoolValue{BoolValue: v}}, nil
+	case float64:
+		return &ptypes.Value{Kind: &ptypes.Value_NumberValue{NumberValue: v}}, nil
+	case string:
+		return &ptypes.Value{Kind: &ptypes.Value_StringValue{StringValue: v}}, nil
+	case []interface{}:
+		l := &ptypes.ListValue{Values: make([]*ptypes.Value, 0, len(v))}
+		for _, e := range v {
+			ev, err := structValue(e)
+			if err != nil {
+				return nil, err
+			}
+			l.Values = append(l.Values, ev)
+		}
+		return &ptypes.Value{Kind: &ptypes.Value_ListValue{ListValue: l}}, nil
+	case map[string]interface{}:
+		s, err := structOptions(v)
+		if err != nil {
+			return nil, err
+		}
+		return &ptypes.Value{Kind: &ptypes.Value_StructValue{StructValue: s}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value of type %T", v)
+	}
+}
@@ -0,0 +1,91 @@
+// +build !windows
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	runcoptions "github.com/containerd/containerd/runtime/v2/runc/options"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/daemon/config"
+	ptypes "github.com/gogo/protobuf/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestGetShimConfig(t *testing.T) {
+	shim, err := getShimConfig("runc-v2", types.Runtime{
+		Type:    "io.containerd.runc.v2",
+		Options: map[string]interface{}{"binary_name": "/usr/local/bin/crun", "systemd_cgroup": true},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(shim.Binary, "io.containerd.runc.v2"))
+	opts, ok := shim.Opts.(*runcoptions.Options)
+	assert.Assert(t, ok)
+	assert.Check(t, is.Equal(opts.BinaryName, "/usr/local/bin/crun"))
+	assert.Check(t, opts.SystemdCgroup)
+
+	_, err = getShimConfig("runc-v2", types.Runtime{
+		Type:    "io.containerd.runc.v2",
+		Options: map[string]interface{}{"BinaryName": "/usr/local/bin/crun"},
+	})
+	assert.Check(t, is.ErrorContains(err, "runtime runc-v2: invalid options"))
+
+	shim, err = getShimConfig("kata", types.Runtime{Type: "io.containerd.kata.v2"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(shim.Binary, "io.containerd.kata.v2"))
+	assert.Check(t, is.Nil(shim.Opts))
+
+	shim, err = getShimConfig("kata", types.Runtime{
+		Type: "io.containerd.kata.v2",
+		Options: map[string]interface{}{
+			"config": "/etc/kata/configuration.toml",
+			"debug":  true,
+			"annotations": map[string]interface{}{
+				"cpus": float64(2),
+			},
+		},
+	})
+	assert.NilError(t, err)
+	s, ok := shim.Opts.(*ptypes.Struct)
+	assert.Assert(t, ok)
+	assert.Check(t, is.Equal(s.Fields["config"].GetStringValue(), "/etc/kata/configuration.toml"))
+	assert.Check(t, s.Fields["debug"].GetBoolValue())
+	assert.Check(t, is.Equal(s.Fields["annotations"].GetStructValue().Fields["cpus"].GetNumberValue(), float64(2)))
+}
+
+func TestInitRuntimesShimConfig(t *testing.T) {
+	root, err := ioutil.TempDir("", "test-init-runtimes")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+	assert.NilError(t, os.Mkdir(filepath.Join(root, "runtimes"), 0700))
+
+	d := &Daemon{configStore: &config.Config{}}
+	d.configStore.Root = root
+	runtimes := map[string]types.Runtime{
+		"legacy":  {Path: "/usr/local/bin/crun", Args: []string{"--debug"}},
+		"runc-v2": {Type: "io.containerd.runc.v2"},
+	}
+	assert.NilError(t, d.initRuntimes(runtimes))
+	assert.Check(t, is.Nil(runtimes["legacy"].ShimConfig))
+	assert.Assert(t, runtimes["runc-v2"].ShimConfig != nil)
+	assert.Check(t, is.Equal(runtimes["runc-v2"].ShimConfig.Binary, "io.containerd.runc.v2"))
+
+	_, err = os.Stat(filepath.Join(root, "runtimes", "legacy"))
+	assert.Check(t, err)
+}
+
+func TestValidateConfigRuntimes(t *testing.T) {
+	conf := &config.Config{}
+	conf.Runtimes = map[string]types.Runtime{
+		"legacy":  {Path: "/usr/local/bin/crun", Args: []string{"--debug"}},
+		"runc-v2": {Type: "io.containerd.runc.v2", Options: map[string]interface{}{"binary_name": "crun"}},
+	}
+	assert.NilError(t, ValidateConfig(conf))
+
+	conf.Runtimes["runc-v2"] = types.Runtime{Type: "io.containerd.runc.v2", Options: map[string]interface{}{"Unknown": true}}
+	assert.Check(t, is.ErrorContains(ValidateConfig(conf), "runtime runc-v2: invalid options"))
+}
@@ -22,3 +22,6 @@ func WithSeccomp(daemon *Daemon, c *container.Container) coci.SpecOpts {
 		return nil
 	}
 }
+
+func (daemon *Daemon) saveSeccompRecording(c *container.Container) {
+}
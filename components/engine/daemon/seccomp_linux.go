@@ -36,7 +36,18 @@ func WithSeccomp(daemon *Daemon, c *container.Container) coci.SpecOpts {
 		if c.SeccompProfile == "unconfined" {
 			return nil
 		}
-		if c.SeccompProfile != "" {
+		if name, ok := seccompRecordName(c); ok {
+			if _, err := daemon.seccompRecordPath(name); err != nil {
+				return err
+			}
+			profile, err = seccomp.GetRecordingProfile(s)
+			if err != nil {
+				return err
+			}
+			if err := defaultSeccompRecorder.start(c.ID); err != nil {
+				return err
+			}
+		} else if c.SeccompProfile != "" {
 			profile, err = seccomp.LoadProfile(c.SeccompProfile, s)
 			if err != nil {
 				return err
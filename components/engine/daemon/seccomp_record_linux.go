@@ -0,0 +1,380 @@
+// +build linux,seccomp
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/profiles/seccomp"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// seccompRecordPrefix is the prefix of the seccomp security option of
+	// the containers whose system calls are recorded to a profile.
+	seccompRecordPrefix = "record:"
+
+	// seccompRecordDir is the directory of the daemon, under its root, in
+	// which the recorded profiles are written.
+	seccompRecordDir = "seccomp"
+
+	// auditSeccomp is the type of the audit records of the system calls
+	// logged by seccomp filters (AUDIT_SECCOMP).
+	auditSeccomp = "1326"
+
+	// seccompRecordFlushDelay is the time given to the kernel to log the
+	// last system calls of a container after it exited.
+	seccompRecordFlushDelay = 250 * time.Millisecond
+
+	// maxCachedPids is the number of processes whose container is cached
+	// while reading the kernel log.
+	maxCachedPids = 4096
+
+	// seccompIncompleteComment is the comment of the rule of a recorded
+	// profile for which records of the kernel log may have been dropped.
+	seccompIncompleteComment = "incomplete: records of the kernel log may have been dropped while recording"
+)
+
+// recordedSyscall is a system call logged by the kernel.
+type recordedSyscall struct {
+	arch uint32
+	nr   int
+}
+
+// seccompRecording is the recording of the system calls of a container.
+type seccompRecording struct {
+	syscalls map[recordedSyscall]struct{}
+	// lost is set when records of the kernel log may have been dropped
+	// while the container was recorded.
+	lost bool
+}
+
+// seccompRecorder collects the system calls of the containers run with
+// --security-opt seccomp=record:<name> from the kernel log, in which they are
+// logged by the SCMP_ACT_LOG action of their seccomp filter.
+type seccompRecorder struct {
+	mu      sync.Mutex
+	started bool
+	// recordings are the recordings of the containers which are being
+	// recorded, by container ID.
+	recordings map[string]*seccompRecording
+	// generation changes each time a recording starts or stops, so that
+	// the reader of the kernel log does not use the containers it cached
+	// for processes whose ID may have been reused.
+	generation uint64
+}
+
+var defaultSeccompRecorder = &seccompRecorder{
+	recordings: make(map[string]*seccompRecording),
+}
+
+// seccompRecordName returns the name of the profile to which the system calls
+// of a container are recorded, if they are.
+func seccompRecordName(c *container.Container) (string, bool) {
+	if !strings.HasPrefix(c.SeccompProfile, seccompRecordPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(c.SeccompProfile, seccompRecordPrefix), true
+}
+
+// seccompRecordPath returns the path of a recorded profile in the seccomp
+// directory of the daemon. Only plain file names are accepted, so that
+// profiles are not written elsewhere.
+func (daemon *Daemon) seccompRecordPath(name string) (string, error) {
+	if !isValidSeccompRecordName(name) {
+		return "", errors.Errorf("invalid name of recorded seccomp profile %q", name)
+	}
+	return filepath.Join(daemon.root, seccompRecordDir, name), nil
+}
+
+// start starts recording the system calls of a container.
+func (r *seccompRecorder) start(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.started {
+		f, err := os.Open("/dev/kmsg")
+		if err != nil {
+			return errors.Wrap(err, "cannot read the kernel log to record system calls")
+		}
+		// Only read the records logged from now on.
+		if _, err := f.Seek(0, io.SeekEnd); err != nil {
+			f.Close()
+			return errors.Wrap(err, "cannot read the kernel log to record system calls")
+		}
+		go r.read(f)
+		r.started = true
+	}
+	r.recordings[id] = &seccompRecording{syscalls: make(map[recordedSyscall]struct{})}
+	atomic.AddUint64(&r.generation, 1)
+	return nil
+}
+
+// get returns the current recording of a container.
+func (r *seccompRecorder) get(id string) *seccompRecording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recordings[id]
+}
+
+// stop stops a recording of a container. The container may have been
+// restarted, with a new recording, which is kept.
+func (r *seccompRecorder) stop(id string, rec *seccompRecording) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.recordings[id] == rec {
+		delete(r.recordings, id)
+		atomic.AddUint64(&r.generation, 1)
+	}
+}
+
+// read reads the kernel log until it cannot be read anymore. The kernel log
+// is shared by all the containers, so it is never closed.
+func (r *seccompRecorder) read(f io.Reader) {
+	// The containers of the processes which logged system calls. Only this
+	// goroutine uses the cache, so that the cgroups of the processes are not
+	// read for each record, and not with the recorder locked.
+	var (
+		pids       = make(map[int]string)
+		generation uint64
+	)
+	buf := make([]byte, 8192)
+	for {
+		n, err := f.Read(buf)
+		if err != nil {
+			if pe, ok := err.(*os.PathError); ok && pe.Err == syscall.EPIPE {
+				// Records were overwritten before being read.
+				r.markLost()
+				continue
+			}
+			logrus.WithError(err).Error("Stopped recording system calls: error reading the kernel log")
+			return
+		}
+		record := string(buf[:n])
+		if isLostRecordsLog(record) {
+			r.markLost()
+			continue
+		}
+		pid, sc, ok := parseSeccompLog(record)
+		if !ok {
+			continue
+		}
+		if g := atomic.LoadUint64(&r.generation); g != generation || len(pids) >= maxCachedPids {
+			pids = make(map[int]string)
+			generation = g
+		}
+		id, ok := pids[pid]
+		if !ok {
+			cgroups, err := ioutil.ReadFile("/proc/" + strconv.Itoa(pid) + "/cgroup")
+			if err != nil {
+				// The process exited before its system call was read,
+				// so the system call may be one of a recorded container.
+				r.markLost()
+				continue
+			}
+			id = r.containerOf(cgroups)
+			pids[pid] = id
+		}
+		if id != "" {
+			r.record(id, sc)
+		}
+	}
+}
+
+// containerOf returns the ID of the recorded container to which the cgroups
+// of a process belong, or an empty string.
+func (r *seccompRecorder) containerOf(cgroups []byte) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id := range r.recordings {
+		if bytes.Contains(cgroups, []byte(id)) {
+			return id
+		}
+	}
+	return ""
+}
+
+// record adds a system call to the recording of a container, if it is being
+// recorded.
+func (r *seccompRecorder) record(id string, sc recordedSyscall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rec, ok := r.recordings[id]; ok {
+		rec.syscalls[sc] = struct{}{}
+	}
+}
+
+// markLost marks the current recordings as possibly incomplete.
+func (r *seccompRecorder) markLost() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rec := range r.recordings {
+		rec.lost = true
+	}
+}
+
+// isLostRecordsLog returns whether a record of the kernel log reports that
+// records were dropped: audit records are dropped when the kernel log is
+// rate limited, or when the audit backlog is full.
+func isLostRecordsLog(record string) bool {
+	if i := strings.IndexByte(record, ';'); i >= 0 {
+		record = record[i+1:]
+	}
+	return strings.Contains(record, "callbacks suppressed") ||
+		strings.HasPrefix(record, "audit: audit_lost=") ||
+		strings.HasPrefix(record, "audit: backlog limit exceeded") ||
+		strings.HasPrefix(record, "audit: rate limit exceeded")
+}
+
+// parseSeccompLog parses a record of the kernel log, and returns the process
+// ID and the system call of the records logged by seccomp filters, such as:
+//
+//	6,1234,5678,-;audit: type=1326 audit(1585000000.123:42): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=4242 comm="sh" exe="/bin/busybox" sig=0 arch=c000003e syscall=59 compat=0 ip=0x7f0000000000 code=0x7ffc0000
+func parseSeccompLog(record string) (int, recordedSyscall, bool) {
+	var (
+		pid                       int
+		sc                        recordedSyscall
+		isSeccomp, hasArch, hasNr bool
+	)
+	if i := strings.IndexByte(record, ';'); i >= 0 {
+		record = record[i+1:]
+	}
+	for _, field := range strings.Fields(record) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "type":
+			isSeccomp = kv[1] == auditSeccomp
+		case "pid":
+			v, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return 0, sc, false
+			}
+			pid = v
+		case "arch":
+			v, err := strconv.ParseUint(kv[1], 16, 32)
+			if err != nil {
+				return 0, sc, false
+			}
+			sc.arch, hasArch = uint32(v), true
+		case "syscall":
+			v, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return 0, sc, false
+			}
+			sc.nr, hasNr = v, true
+		}
+	}
+	if !isSeccomp || pid == 0 || !hasArch || !hasNr {
+		return 0, sc, false
+	}
+	return pid, sc, true
+}
+
+// seccompProfilesMu serializes the writes of the recorded profiles, which
+// are merged with the profiles previously written to the same path.
+var seccompProfilesMu sync.Mutex
+
+// saveSeccompRecording writes the profile allowing the system calls recorded
+// for a container which exited. The profile is written once the kernel had
+// time to log the last system calls of the container, without delaying its
+// cleanup.
+func (daemon *Daemon) saveSeccompRecording(c *container.Container) {
+	name, ok := seccompRecordName(c)
+	if !ok {
+		return
+	}
+	rec := defaultSeccompRecorder.get(c.ID)
+	if rec == nil {
+		return
+	}
+	logger := logrus.WithField("container", c.ID).WithField("profile", name)
+	path, err := daemon.seccompRecordPath(name)
+	if err != nil {
+		defaultSeccompRecorder.stop(c.ID, rec)
+		logger.WithError(err).Error("Failed to write the recorded seccomp profile")
+		return
+	}
+	time.AfterFunc(seccompRecordFlushDelay, func() {
+		defaultSeccompRecorder.stop(c.ID, rec)
+		writeSeccompRecording(logger, path, rec)
+	})
+}
+
+// writeSeccompRecording writes the profile allowing the system calls of a
+// recording. The system calls of the profile previously written to the same
+// path are kept, so that a profile can be recorded over several runs. The
+// profile is marked as incomplete if records may have been lost.
+func writeSeccompRecording(logger *logrus.Entry, path string, rec *seccompRecording) {
+	if len(rec.syscalls) == 0 {
+		logger.Warn("No system call was recorded for the container: check that the kernel logs the system calls allowed by seccomp filters in /proc/sys/kernel/seccomp/actions_logged and that auditd is not running")
+		return
+	}
+
+	names := make(map[string]struct{})
+	for sc := range rec.syscalls {
+		name, err := seccomp.SyscallName(sc.arch, sc.nr)
+		if err != nil {
+			logger.WithError(err).Warnf("Cannot resolve the name of recorded system call %d", sc.nr)
+			continue
+		}
+		names[name] = struct{}{}
+	}
+
+	seccompProfilesMu.Lock()
+	defer seccompProfilesMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		logger.WithError(err).Error("Failed to write the recorded seccomp profile")
+		return
+	}
+	if b, err := ioutil.ReadFile(path); err == nil {
+		var previous types.Seccomp
+		if err := json.Unmarshal(b, &previous); err != nil {
+			logger.WithError(err).Warn("Overwriting invalid seccomp profile")
+		} else {
+			for _, name := range seccomp.AllowedSyscalls(&previous) {
+				names[name] = struct{}{}
+			}
+		}
+	}
+
+	list := make([]string, 0, len(names))
+	for name := range names {
+		list = append(list, name)
+	}
+	profile := seccomp.ProfileFromSyscalls(list)
+	if rec.lost {
+		profile.Syscalls[0].Comment = seccompIncompleteComment
+	}
+	b, err := json.MarshalIndent(profile, "", "\t")
+	if err != nil {
+		logger.WithError(err).Error("Failed to encode the recorded seccomp profile")
+		return
+	}
+	if err := ioutils.AtomicWriteFile(path, append(b, '\n'), 0644); err != nil {
+		logger.WithError(err).Error("Failed to write the recorded seccomp profile")
+		return
+	}
+	if rec.lost {
+		logger.Warn("Records of the kernel log may have been dropped while recording system calls: the seccomp profile is marked as incomplete")
+	}
+	logger.Infof("Recorded a seccomp profile allowing %d system calls to %s", len(list), path)
+}
@@ -0,0 +1,52 @@
+// +build linux,seccomp
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestParseSeccompLog(t *testing.T) {
+	pid, sc, ok := parseSeccompLog(`6,1234,5678,-;audit: type=1326 audit(1585000000.123:42): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=4242 comm="sh" exe="/bin/busybox" sig=0 arch=c000003e syscall=59 compat=0 ip=0x7f0000000000 code=0x7ffc0000`)
+	assert.Assert(t, ok)
+	assert.Check(t, is.Equal(pid, 4242))
+	assert.Check(t, is.Equal(sc, recordedSyscall{arch: 0xc000003e, nr: 59}))
+
+	_, _, ok = parseSeccompLog(`6,1235,5679,-;audit: type=1400 audit(1585000000.123:43): apparmor="DENIED" pid=4242`)
+	assert.Check(t, !ok, "records which are not logged by seccomp filters must be ignored")
+
+	_, _, ok = parseSeccompLog(`6,1236,5680,-;eth0: link up`)
+	assert.Check(t, !ok)
+}
+
+func TestIsLostRecordsLog(t *testing.T) {
+	assert.Check(t, isLostRecordsLog(`4,1237,5681,-;kauditd_printk_skb: 12 callbacks suppressed`))
+	assert.Check(t, isLostRecordsLog(`4,1238,5682,-;audit: audit_lost=3 audit_rate_limit=0 audit_backlog_limit=64`))
+	assert.Check(t, isLostRecordsLog(`4,1239,5683,-;audit: backlog limit exceeded`))
+	assert.Check(t, !isLostRecordsLog(`6,1234,5678,-;audit: type=1326 audit(1585000000.123:42): pid=4242 arch=c000003e syscall=59`))
+}
+
+// kmsgReader returns a record of the kernel log on each read.
+type kmsgReader []string
+
+func (r *kmsgReader) Read(p []byte) (int, error) {
+	if len(*r) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, (*r)[0])
+	*r = (*r)[1:]
+	return n, nil
+}
+
+func TestSeccompRecorderMarksLostRecords(t *testing.T) {
+	r := &seccompRecorder{recordings: make(map[string]*seccompRecording)}
+	rec := &seccompRecording{syscalls: make(map[recordedSyscall]struct{})}
+	r.recordings["abc"] = rec
+
+	r.read(&kmsgReader{`6,1234,5678,-;audit: type=1326 audit(1585000000.123:42): pid=2147483647 arch=c000003e syscall=59`})
+	assert.Check(t, rec.lost, "the system calls of processes whose cgroups cannot be read must mark the recordings as lost")
+}
@@ -1,7 +1,15 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"path/filepath"
+	"strings"
+
+	containertypes "github.com/docker/docker/api/types/container"
 	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/secretstore"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -21,3 +29,110 @@ func (daemon *Daemon) SetContainerSecretReferences(name string, refs []*swarmtyp
 
 	return nil
 }
+
+// SecretStore returns the store of the secrets and the configs which are
+// used when the daemon is not part of a swarm.
+func (daemon *Daemon) SecretStore() *secretstore.Store {
+	return daemon.secretStore
+}
+
+// setLocalSecretReferences resolves the secrets and the configs of the host
+// config in the local store of the daemon, and sets the references of the
+// container to them. The file targets default to the name of the secret or
+// the config, owned by root, and readable by everyone.
+func (daemon *Daemon) setLocalSecretReferences(c *container.Container, hostConfig *containertypes.HostConfig) error {
+	if len(hostConfig.Secrets) > 0 && !secretsSupported() {
+		return errdefs.InvalidParameter(errors.New("secrets are not supported on this platform"))
+	}
+	if len(hostConfig.Configs) > 0 && !configsSupported() {
+		return errdefs.InvalidParameter(errors.New("configs are not supported on this platform"))
+	}
+
+	for _, ref := range hostConfig.Secrets {
+		input := ref.SecretID
+		if input == "" {
+			input = ref.SecretName
+		}
+		secret, err := daemon.secretStore.GetSecret(input)
+		if err != nil {
+			return err
+		}
+		file := fileTarget(ref.File, secret.Spec.Name)
+		c.SecretReferences = append(c.SecretReferences, &swarmtypes.SecretReference{
+			File: &swarmtypes.SecretReferenceFileTarget{
+				Name: file.Name,
+				UID:  file.UID,
+				GID:  file.GID,
+				Mode: file.Mode,
+			},
+			SecretID:   secret.ID,
+			SecretName: secret.Spec.Name,
+		})
+	}
+
+	for _, ref := range hostConfig.Configs {
+		input := ref.ConfigID
+		if input == "" {
+			input = ref.ConfigName
+		}
+		config, err := daemon.secretStore.GetConfig(input)
+		if err != nil {
+			return err
+		}
+		file := fileTarget(ref.File, config.Spec.Name)
+		if !filepath.IsAbs(file.Name) {
+			// Configs are mounted relative to the root of the container,
+			// as in swarm.
+			file.Name = filepath.Join(string(filepath.Separator), file.Name)
+		}
+		c.ConfigReferences = append(c.ConfigReferences, &swarmtypes.ConfigReference{
+			File: &swarmtypes.ConfigReferenceFileTarget{
+				Name: file.Name,
+				UID:  file.UID,
+				GID:  file.GID,
+				Mode: file.Mode,
+			},
+			ConfigID:   config.ID,
+			ConfigName: config.Spec.Name,
+		})
+	}
+	return nil
+}
+
+func fileTarget(file *containertypes.FileTarget, name string) containertypes.FileTarget {
+	target := containertypes.FileTarget{Name: name, UID: "0", GID: "0", Mode: 0444}
+	if file != nil {
+		if file.Name != "" {
+			target.Name = file.Name
+		}
+		if file.UID != "" {
+			target.UID = file.UID
+		}
+		if file.GID != "" {
+			target.GID = file.GID
+		}
+		if file.Mode != 0 {
+			target.Mode = file.Mode
+		}
+	}
+	return target
+}
+
+// secretUsers returns the names of the containers which use the secret or the
+// config with the given ID.
+func (daemon *Daemon) secretUsers(id string) []string {
+	var names []string
+	for _, c := range daemon.containers.List() {
+		used := false
+		for _, ref := range c.SecretReferences {
+			used = used || ref.SecretID == id
+		}
+		for _, ref := range c.ConfigReferences {
+			used = used || ref.ConfigID == id
+		}
+		if used {
+			names = append(names, strings.TrimPrefix(c.Name, "/"))
+		}
+	}
+	return names
+}
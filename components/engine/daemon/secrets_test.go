@@ -0,0 +1,70 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/secretstore"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestSetLocalSecretReferences(t *testing.T) {
+	skip.If(t, !secretsSupported() || !configsSupported(), "secrets and configs are not supported on this platform")
+
+	root, err := ioutil.TempDir("", "secrets-test-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+	store, err := secretstore.New(root, nil, nil)
+	assert.NilError(t, err)
+	d := &Daemon{secretStore: store}
+
+	secretID, err := store.CreateSecret(swarmtypes.SecretSpec{
+		Annotations: swarmtypes.Annotations{Name: "password"},
+		Data:        []byte("s3cr3t"),
+	})
+	assert.NilError(t, err)
+	configID, err := store.CreateConfig(swarmtypes.ConfigSpec{
+		Annotations: swarmtypes.Annotations{Name: "app.conf"},
+		Data:        []byte("debug = true"),
+	})
+	assert.NilError(t, err)
+
+	c := &container.Container{}
+	err = d.setLocalSecretReferences(c, &containertypes.HostConfig{
+		Secrets: []*containertypes.SecretReference{
+			{SecretName: "password"},
+			{SecretName: "password", File: &containertypes.FileTarget{Name: "db", UID: "1000", Mode: 0400}},
+		},
+		Configs: []*containertypes.ConfigReference{
+			{ConfigName: "app.conf"},
+		},
+	})
+	assert.NilError(t, err)
+
+	assert.Check(t, is.DeepEqual(c.SecretReferences, []*swarmtypes.SecretReference{
+		{
+			File:       &swarmtypes.SecretReferenceFileTarget{Name: "password", UID: "0", GID: "0", Mode: 0444},
+			SecretID:   secretID,
+			SecretName: "password",
+		},
+		{
+			File:       &swarmtypes.SecretReferenceFileTarget{Name: "db", UID: "1000", GID: "0", Mode: 0400},
+			SecretID:   secretID,
+			SecretName: "password",
+		},
+	}))
+	assert.Check(t, is.Len(c.ConfigReferences, 1))
+	assert.Check(t, is.Equal(c.ConfigReferences[0].ConfigID, configID))
+
+	err = d.setLocalSecretReferences(&container.Container{}, &containertypes.HostConfig{
+		Secrets: []*containertypes.SecretReference{{SecretName: "unknown"}},
+	})
+	assert.Check(t, errdefs.IsNotFound(err))
+}
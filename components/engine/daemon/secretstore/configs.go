@@ -0,0 +1,137 @@
+package secretstore // import "github.com/docker/docker/daemon/secretstore"
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	apitypes "github.com/docker/docker/api/types"
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+)
+
+// GetConfig returns the config whose ID, name, or ID prefix is input.
+func (s *Store) GetConfig(input string) (swarmtypes.Config, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, err := s.lookupConfig(input)
+	if err != nil {
+		return swarmtypes.Config{}, err
+	}
+	return *s.configs[id], nil
+}
+
+// GetConfigs returns the configs which match the filters of the options.
+func (s *Store) GetConfigs(options apitypes.ConfigListOptions) ([]swarmtypes.Config, error) {
+	if err := validateFilters(options.Filters); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	configs := make([]swarmtypes.Config, 0, len(s.configs))
+	for id, config := range s.configs {
+		if matches(options.Filters, id, config.Spec.Annotations) {
+			configs = append(configs, *config)
+		}
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].CreatedAt.Before(configs[j].CreatedAt)
+	})
+	return configs, nil
+}
+
+// CreateConfig creates a config, and returns its ID.
+func (s *Store) CreateConfig(spec swarmtypes.ConfigSpec) (string, error) {
+	if spec.Templating != nil {
+		return "", errdefs.NotImplemented(errors.New("config templating is only supported in swarm mode"))
+	}
+	if err := validateName("config", spec.Name); err != nil {
+		return "", err
+	}
+	if err := validateData("config", spec.Data); err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, config := range s.configs {
+		if config.Spec.Name == spec.Name {
+			return "", errdefs.Conflict(fmt.Errorf("config %s already exists", spec.Name))
+		}
+	}
+	now := time.Now().UTC()
+	config := &swarmtypes.Config{
+		ID: newID(),
+		Meta: swarmtypes.Meta{
+			Version:   swarmtypes.Version{Index: 1},
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Spec: spec,
+	}
+	if err := s.write(configsDir, config.ID, config); err != nil {
+		return "", err
+	}
+	s.configs[config.ID] = config
+	return config.ID, nil
+}
+
+// RemoveConfig removes the config whose ID, name, or ID prefix is input. A
+// config which is used by a container cannot be removed.
+func (s *Store) RemoveConfig(input string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := s.lookupConfig(input)
+	if err != nil {
+		return err
+	}
+	if err := s.checkNotInUse("config", id, s.configs[id].Spec.Name); err != nil {
+		return err
+	}
+	if err := s.remove(configsDir, id); err != nil {
+		return err
+	}
+	delete(s.configs, id)
+	return nil
+}
+
+// UpdateConfig updates the config whose ID, name, or ID prefix is input. As in
+// swarm, only the labels of a config can be updated.
+func (s *Store) UpdateConfig(input string, version uint64, spec swarmtypes.ConfigSpec) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := s.lookupConfig(input)
+	if err != nil {
+		return err
+	}
+	config := *s.configs[id]
+	if config.Version.Index != version {
+		return errdefs.Conflict(errors.New("update out of sequence"))
+	}
+	if spec.Name != config.Spec.Name || !reflect.DeepEqual(spec.Templating, config.Spec.Templating) || (len(spec.Data) > 0 && string(spec.Data) != string(config.Spec.Data)) {
+		return errdefs.InvalidParameter(errors.New("only updates to Labels are allowed"))
+	}
+	config.Spec.Labels = spec.Labels
+	config.Version.Index++
+	config.UpdatedAt = time.Now().UTC()
+	if err := s.write(configsDir, id, &config); err != nil {
+		return err
+	}
+	s.configs[id] = &config
+	return nil
+}
+
+func (s *Store) lookupConfig(input string) (string, error) {
+	names := make(map[string]string, len(s.configs))
+	for id, config := range s.configs {
+		names[id] = config.Spec.Name
+	}
+	return lookup("config", input, names)
+}
@@ -0,0 +1,143 @@
+package secretstore // import "github.com/docker/docker/daemon/secretstore"
+
+import (
+	"fmt"
+
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/secretprovider"
+	"github.com/docker/swarmkit/agent/exec"
+	swarmapi "github.com/docker/swarmkit/api"
+	"github.com/pkg/errors"
+)
+
+// Container is the container in which secrets and configs are mounted. It is
+// passed to the secret provider plugins.
+type Container struct {
+	ID       string
+	Name     string
+	Hostname string
+	Image    string
+	Labels   map[string]string
+}
+
+// DependencyGetter returns a getter of the secrets and the configs of the
+// store, with their data, to mount them in a container. The values of the
+// secrets which have a driver are fetched from their secret provider plugin,
+// at most once per getter.
+func (s *Store) DependencyGetter(c Container) exec.DependencyGetter {
+	return &dependencyGetter{
+		s:      s,
+		c:      c,
+		values: make(map[string][]byte),
+	}
+}
+
+type dependencyGetter struct {
+	s *Store
+	c Container
+	// values caches the values fetched from secret provider plugins for
+	// the container, by secret ID.
+	values map[string][]byte
+}
+
+func (g *dependencyGetter) Secrets() exec.SecretGetter {
+	return secretGetter{g}
+}
+
+func (g *dependencyGetter) Configs() exec.ConfigGetter {
+	return configGetter{g.s}
+}
+
+type secretGetter struct {
+	g *dependencyGetter
+}
+
+func (sg secretGetter) Get(id string) (*swarmapi.Secret, error) {
+	s := sg.g.s
+	s.mu.RLock()
+	secret, ok := s.secrets[id]
+	value, cached := s.values[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, errdefs.NotFound(fmt.Errorf("secret %s not found", id))
+	}
+
+	data := secret.Spec.Data
+	if secret.Spec.Driver != nil {
+		if !cached {
+			value, cached = sg.g.values[id]
+		}
+		if !cached {
+			var err error
+			value, err = sg.g.fetch(secret)
+			if err != nil {
+				return nil, err
+			}
+		}
+		data = value
+	}
+	return &swarmapi.Secret{
+		ID: secret.ID,
+		Spec: swarmapi.SecretSpec{
+			Annotations: swarmapi.Annotations{Name: secret.Spec.Name, Labels: secret.Spec.Labels},
+			Data:        data,
+		},
+	}, nil
+}
+
+// fetch fetches the value of a secret from its secret provider plugin, and
+// caches it for the container, or for all containers if the plugin allows it.
+func (g *dependencyGetter) fetch(secret *swarmtypes.Secret) ([]byte, error) {
+	driver := secret.Spec.Driver
+	p, err := g.s.getProvider(driver.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting secret driver %s", driver.Name)
+	}
+	value, reuse, err := p.GetSecret(&secretprovider.Request{
+		SecretName:        secret.Spec.Name,
+		SecretLabels:      secret.Spec.Labels,
+		ContainerID:       g.c.ID,
+		ContainerName:     g.c.Name,
+		ContainerHostname: g.c.Hostname,
+		ContainerImage:    g.c.Image,
+		ContainerLabels:   g.c.Labels,
+		DriverOptions:     driver.Options,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting secret %s from secret driver %s", secret.Spec.Name, driver.Name)
+	}
+
+	g.values[secret.ID] = value
+	if reuse {
+		g.s.mu.Lock()
+		// The secret may have been removed or updated while its value was
+		// fetched.
+		if g.s.secrets[secret.ID] == secret {
+			g.s.values[secret.ID] = value
+		}
+		g.s.mu.Unlock()
+	}
+	return value, nil
+}
+
+type configGetter struct {
+	s *Store
+}
+
+func (cg configGetter) Get(id string) (*swarmapi.Config, error) {
+	cg.s.mu.RLock()
+	defer cg.s.mu.RUnlock()
+
+	config, ok := cg.s.configs[id]
+	if !ok {
+		return nil, errdefs.NotFound(fmt.Errorf("config %s not found", id))
+	}
+	return &swarmapi.Config{
+		ID: config.ID,
+		Spec: swarmapi.ConfigSpec{
+			Annotations: swarmapi.Annotations{Name: config.Spec.Name, Labels: config.Spec.Labels},
+			Data:        config.Spec.Data,
+		},
+	}, nil
+}
@@ -0,0 +1,136 @@
+package secretstore // import "github.com/docker/docker/daemon/secretstore"
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/secretprovider"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type fakeProvider struct {
+	requests []secretprovider.Request
+	reuse    bool
+	err      error
+}
+
+func (p *fakeProvider) Name() string {
+	return "vault"
+}
+
+func (p *fakeProvider) GetSecret(req *secretprovider.Request) ([]byte, bool, error) {
+	p.requests = append(p.requests, *req)
+	if p.err != nil {
+		return nil, false, p.err
+	}
+	return []byte("value of " + req.SecretName + " for " + req.ContainerName), p.reuse, nil
+}
+
+func newDriverTestStore(t *testing.T, p *fakeProvider) (*Store, string) {
+	s, root := newTestStore(t, nil)
+	s.getProvider = func(name string) (secretprovider.Provider, error) {
+		if name != p.Name() {
+			return nil, errdefs.NotFound(errors.New("plugin not found"))
+		}
+		return p, nil
+	}
+	return s, root
+}
+
+func driverSecretSpec(name, driver string) swarmtypes.SecretSpec {
+	spec := secretSpec(name, "", map[string]string{"path": "secret/db"})
+	spec.Driver = &swarmtypes.Driver{Name: driver, Options: map[string]string{"role": "app"}}
+	return spec
+}
+
+func TestSecretDriverCreate(t *testing.T) {
+	s, root := newDriverTestStore(t, &fakeProvider{})
+	defer os.RemoveAll(root)
+
+	_, err := s.CreateSecret(driverSecretSpec("unknown-driver", "keyring"))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	spec := driverSecretSpec("with-data", "vault")
+	spec.Data = []byte("data")
+	_, err = s.CreateSecret(spec)
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	id, err := s.CreateSecret(driverSecretSpec("db-password", "vault"))
+	assert.NilError(t, err)
+	secret, err := s.GetSecret(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(secret.Spec.Driver.Name, "vault"))
+}
+
+func TestSecretDriverGet(t *testing.T) {
+	p := &fakeProvider{}
+	s, root := newDriverTestStore(t, p)
+	defer os.RemoveAll(root)
+
+	id, err := s.CreateSecret(driverSecretSpec("db-password", "vault"))
+	assert.NilError(t, err)
+
+	web := s.DependencyGetter(Container{ID: "abc", Name: "web", Image: "nginx", Labels: map[string]string{"env": "prod"}})
+	secret, err := web.Secrets().Get(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(secret.Spec.Data), "value of db-password for web"))
+	assert.Check(t, is.DeepEqual(p.requests, []secretprovider.Request{{
+		SecretName:      "db-password",
+		SecretLabels:    map[string]string{"path": "secret/db"},
+		ContainerID:     "abc",
+		ContainerName:   "web",
+		ContainerImage:  "nginx",
+		ContainerLabels: map[string]string{"env": "prod"},
+		DriverOptions:   map[string]string{"role": "app"},
+	}}))
+
+	// The value is fetched once for the container.
+	_, err = web.Secrets().Get(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(p.requests, 1))
+
+	// The plugin did not allow reusing the value for other containers.
+	secret, err = s.DependencyGetter(Container{Name: "worker"}).Secrets().Get(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(secret.Spec.Data), "value of db-password for worker"))
+	assert.Check(t, is.Len(p.requests, 2))
+}
+
+func TestSecretDriverReuse(t *testing.T) {
+	p := &fakeProvider{reuse: true}
+	s, root := newDriverTestStore(t, p)
+	defer os.RemoveAll(root)
+
+	id, err := s.CreateSecret(driverSecretSpec("db-password", "vault"))
+	assert.NilError(t, err)
+
+	_, err = s.DependencyGetter(Container{Name: "web"}).Secrets().Get(id)
+	assert.NilError(t, err)
+	secret, err := s.DependencyGetter(Container{Name: "worker"}).Secrets().Get(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(secret.Spec.Data), "value of db-password for web"))
+	assert.Check(t, is.Len(p.requests, 1))
+
+	// Updating the secret invalidates the cached value.
+	spec := driverSecretSpec("db-password", "vault")
+	spec.Labels["path"] = "secret/other"
+	assert.NilError(t, s.UpdateSecret(id, 1, spec))
+	_, err = s.DependencyGetter(Container{Name: "worker"}).Secrets().Get(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(p.requests, 2))
+}
+
+func TestSecretDriverError(t *testing.T) {
+	p := &fakeProvider{err: errors.New("permission denied")}
+	s, root := newDriverTestStore(t, p)
+	defer os.RemoveAll(root)
+
+	id, err := s.CreateSecret(driverSecretSpec("db-password", "vault"))
+	assert.NilError(t, err)
+	_, err = s.DependencyGetter(Container{Name: "web"}).Secrets().Get(id)
+	assert.Check(t, is.ErrorContains(err, "permission denied"))
+}
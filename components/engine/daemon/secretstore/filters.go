@@ -0,0 +1,47 @@
+package secretstore // import "github.com/docker/docker/daemon/secretstore"
+
+import (
+	"strings"
+
+	"github.com/docker/docker/api/types/filters"
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+)
+
+var acceptedFilters = map[string]bool{
+	"names": true,
+	"name":  true,
+	"id":    true,
+	"label": true,
+}
+
+func validateFilters(filter filters.Args) error {
+	if err := filter.Validate(acceptedFilters); err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+	return nil
+}
+
+// matches returns whether an object matches the filters, as they are applied
+// by swarm: names are matched exactly, and names and IDs by prefix.
+func matches(filter filters.Args, id string, annotations swarmtypes.Annotations) bool {
+	if filter.Contains("names") && !filter.ExactMatch("names", annotations.Name) {
+		return false
+	}
+	if filter.Contains("name") && !matchPrefix(filter.Get("name"), annotations.Name) {
+		return false
+	}
+	if filter.Contains("id") && !matchPrefix(filter.Get("id"), id) {
+		return false
+	}
+	return filter.MatchKVList("label", annotations.Labels)
+}
+
+func matchPrefix(prefixes []string, s string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
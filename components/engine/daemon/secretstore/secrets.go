@@ -0,0 +1,157 @@
+package secretstore // import "github.com/docker/docker/daemon/secretstore"
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	apitypes "github.com/docker/docker/api/types"
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// GetSecret returns the secret whose ID, name, or ID prefix is input.
+func (s *Store) GetSecret(input string) (swarmtypes.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, err := s.lookupSecret(input)
+	if err != nil {
+		return swarmtypes.Secret{}, err
+	}
+	return withoutData(s.secrets[id]), nil
+}
+
+// GetSecrets returns the secrets which match the filters of the options.
+func (s *Store) GetSecrets(options apitypes.SecretListOptions) ([]swarmtypes.Secret, error) {
+	if err := validateFilters(options.Filters); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	secrets := make([]swarmtypes.Secret, 0, len(s.secrets))
+	for id, secret := range s.secrets {
+		if matches(options.Filters, id, secret.Spec.Annotations) {
+			secrets = append(secrets, withoutData(secret))
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].CreatedAt.Before(secrets[j].CreatedAt)
+	})
+	return secrets, nil
+}
+
+// CreateSecret creates a secret, and returns its ID.
+func (s *Store) CreateSecret(spec swarmtypes.SecretSpec) (string, error) {
+	if spec.Templating != nil {
+		return "", errdefs.NotImplemented(errors.New("secret templating is only supported in swarm mode"))
+	}
+	if err := validateName("secret", spec.Name); err != nil {
+		return "", err
+	}
+	if spec.Driver != nil {
+		// The value of the secret is fetched from the secret provider plugin
+		// when it is mounted in a container.
+		if len(spec.Data) > 0 {
+			return "", errdefs.InvalidParameter(errors.New("secret data must be empty when a secret driver is used"))
+		}
+		if _, err := s.getProvider(spec.Driver.Name); err != nil {
+			return "", errdefs.InvalidParameter(errors.Wrapf(err, "invalid secret driver %s", spec.Driver.Name))
+		}
+	} else if err := validateData("secret", spec.Data); err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, secret := range s.secrets {
+		if secret.Spec.Name == spec.Name {
+			return "", errdefs.Conflict(fmt.Errorf("secret %s already exists", spec.Name))
+		}
+	}
+	now := time.Now().UTC()
+	secret := &swarmtypes.Secret{
+		ID: newID(),
+		Meta: swarmtypes.Meta{
+			Version:   swarmtypes.Version{Index: 1},
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Spec: spec,
+	}
+	if err := s.write(secretsDir, secret.ID, secret); err != nil {
+		return "", err
+	}
+	s.secrets[secret.ID] = secret
+	return secret.ID, nil
+}
+
+// RemoveSecret removes the secret whose ID, name, or ID prefix is input. A
+// secret which is used by a container cannot be removed.
+func (s *Store) RemoveSecret(input string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := s.lookupSecret(input)
+	if err != nil {
+		return err
+	}
+	if err := s.checkNotInUse("secret", id, s.secrets[id].Spec.Name); err != nil {
+		return err
+	}
+	if err := s.remove(secretsDir, id); err != nil {
+		return err
+	}
+	delete(s.secrets, id)
+	delete(s.values, id)
+	return nil
+}
+
+// UpdateSecret updates the secret whose ID, name, or ID prefix is input. As in
+// swarm, only the labels of a secret can be updated.
+func (s *Store) UpdateSecret(input string, version uint64, spec swarmtypes.SecretSpec) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := s.lookupSecret(input)
+	if err != nil {
+		return err
+	}
+	secret := *s.secrets[id]
+	if secret.Version.Index != version {
+		return errdefs.Conflict(errors.New("update out of sequence"))
+	}
+	if spec.Name != secret.Spec.Name || !reflect.DeepEqual(spec.Driver, secret.Spec.Driver) || !reflect.DeepEqual(spec.Templating, secret.Spec.Templating) || (len(spec.Data) > 0 && string(spec.Data) != string(secret.Spec.Data)) {
+		return errdefs.InvalidParameter(errors.New("only updates to Labels are allowed"))
+	}
+	secret.Spec.Labels = spec.Labels
+	secret.Version.Index++
+	secret.UpdatedAt = time.Now().UTC()
+	if err := s.write(secretsDir, id, &secret); err != nil {
+		return err
+	}
+	s.secrets[id] = &secret
+	// The labels of the secret are passed to its secret provider plugin.
+	delete(s.values, id)
+	return nil
+}
+
+func (s *Store) lookupSecret(input string) (string, error) {
+	names := make(map[string]string, len(s.secrets))
+	for id, secret := range s.secrets {
+		names[id] = secret.Spec.Name
+	}
+	return lookup("secret", input, names)
+}
+
+// withoutData returns a copy of a secret without its data, which is never
+// returned by the API.
+func withoutData(secret *swarmtypes.Secret) swarmtypes.Secret {
+	s := *secret
+	s.Spec.Data = nil
+	return s
+}
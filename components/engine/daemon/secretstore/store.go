@@ -0,0 +1,271 @@
+// Package secretstore stores the secrets and the configs of a daemon which is
+// not part of a swarm, so that they can be mounted in its containers.
+//
+// The secrets and the configs are encrypted at rest with a key which is
+// stored in the directory of the store.
+package secretstore // import "github.com/docker/docker/daemon/secretstore"
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/plugingetter"
+	"github.com/docker/docker/pkg/secretprovider"
+	"github.com/docker/swarmkit/identity"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	keyFile    = "key"
+	secretsDir = "secrets"
+	configsDir = "configs"
+	keySize    = 32
+
+	// maxDataSize is the maximum size of the data of a secret or a config,
+	// as in swarm.
+	maxDataSize = 500 * 1024
+)
+
+// validName matches the names of secrets and configs which are accepted by
+// swarm.
+var validName = regexp.MustCompile(`^[a-zA-Z0-9]+(?:[a-zA-Z0-9-_.]*[a-zA-Z0-9])?$`)
+
+// UsedByFunc returns the names of the containers which use the secret or the
+// config with the given ID.
+type UsedByFunc func(id string) []string
+
+// Store stores secrets and configs, encrypted, in a directory.
+type Store struct {
+	mu      sync.RWMutex
+	root    string
+	aead    cipher.AEAD
+	usedBy  UsedByFunc
+	secrets map[string]*swarmtypes.Secret
+	configs map[string]*swarmtypes.Config
+
+	// getProvider returns the secret provider plugin of the secrets which
+	// have a driver.
+	getProvider func(name string) (secretprovider.Provider, error)
+	// values caches the values fetched from secret provider plugins which
+	// can be reused for all containers, by secret ID.
+	values map[string][]byte
+}
+
+// New returns a store of secrets and configs in the root directory, and loads
+// the secrets and configs stored in it. The key used to encrypt them is
+// created if it does not exist. usedBy is used to refuse to remove the
+// secrets and the configs which are used by containers, and pg to get the
+// secret provider plugins of the secrets which have a driver.
+func New(root string, usedBy UsedByFunc, pg plugingetter.PluginGetter) (*Store, error) {
+	for _, dir := range []string{secretsDir, configsDir} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0700); err != nil {
+			return nil, err
+		}
+	}
+	key, err := loadOrCreateKey(filepath.Join(root, keyFile))
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		root:    root,
+		aead:    aead,
+		usedBy:  usedBy,
+		secrets: make(map[string]*swarmtypes.Secret),
+		configs: make(map[string]*swarmtypes.Config),
+		getProvider: func(name string) (secretprovider.Provider, error) {
+			return secretprovider.Get(pg, name)
+		},
+		values: make(map[string][]byte),
+	}
+	err = s.load(secretsDir, func(id string, data []byte) error {
+		secret := &swarmtypes.Secret{}
+		if err := json.Unmarshal(data, secret); err != nil {
+			return err
+		}
+		s.secrets[id] = secret
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = s.load(configsDir, func(id string, data []byte) error {
+		config := &swarmtypes.Config{}
+		if err := json.Unmarshal(data, config); err != nil {
+			return err
+		}
+		s.configs[id] = config
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func loadOrCreateKey(p string) ([]byte, error) {
+	key, err := ioutil.ReadFile(p)
+	if err == nil {
+		if len(key) != keySize {
+			return nil, fmt.Errorf("invalid key in %s", p)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	key = make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := ioutils.AtomicWriteFile(p, key, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// load decrypts the objects stored in dir, and passes them to addFn, which
+// decodes and adds them to the store. Objects which cannot be read or decoded
+// are logged and skipped.
+func (s *Store) load(dir string, addFn func(id string, data []byte) error) error {
+	files, err := ioutil.ReadDir(filepath.Join(s.root, dir))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		id := f.Name()
+		if f.IsDir() || strings.HasPrefix(id, ".") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(s.root, dir, id))
+		if err == nil {
+			data, err = s.decrypt(id, data)
+		}
+		if err == nil {
+			err = addFn(id, data)
+		}
+		if err != nil {
+			logrus.WithError(err).WithField("id", id).Errorf("failed to load %s", strings.TrimSuffix(dir, "s"))
+		}
+	}
+	return nil
+}
+
+// write encrypts v and stores it in dir, under its ID.
+func (s *Store) write(dir, id string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data, err = s.encrypt(id, data)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(filepath.Join(s.root, dir, id), data, 0600)
+}
+
+func (s *Store) remove(dir, id string) error {
+	if err := os.Remove(filepath.Join(s.root, dir, id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// encrypt seals data, which is bound to the ID of its object, and prefixes it
+// with the nonce used.
+func (s *Store) encrypt(id string, data []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(nonce, nonce, data, []byte(id)), nil
+}
+
+func (s *Store) decrypt(id string, data []byte) ([]byte, error) {
+	n := s.aead.NonceSize()
+	if len(data) < n {
+		return nil, errors.New("invalid encrypted data")
+	}
+	return s.aead.Open(nil, data[:n], data[n:], []byte(id))
+}
+
+// checkNotInUse returns an error if the object of the given kind is used by
+// containers.
+func (s *Store) checkNotInUse(kind, id, name string) error {
+	if s.usedBy == nil {
+		return nil
+	}
+	if containers := s.usedBy(id); len(containers) > 0 {
+		return errdefs.InvalidParameter(fmt.Errorf("%s '%s' is in use by the following containers: %s", kind, name, strings.Join(containers, ", ")))
+	}
+	return nil
+}
+
+func validateName(kind string, name string) error {
+	if name == "" {
+		return errdefs.InvalidParameter(fmt.Errorf("%s name must be provided", kind))
+	}
+	if len(name) > 64 || !validName.MatchString(name) {
+		return errdefs.InvalidParameter(fmt.Errorf("invalid %s name %q: names must be 64 characters or less, start and end with a letter or a digit, and only contain letters, digits, '-', '_' and '.'", kind, name))
+	}
+	return nil
+}
+
+func validateData(kind string, data []byte) error {
+	if len(data) == 0 || len(data) > maxDataSize {
+		return errdefs.InvalidParameter(fmt.Errorf("%s data must be larger than 0 and less than %d bytes", kind, maxDataSize))
+	}
+	return nil
+}
+
+// lookup returns the ID of the object whose ID, name or ID prefix is input.
+func lookup(kind, input string, ids map[string]string) (string, error) {
+	if _, ok := ids[input]; ok {
+		return input, nil
+	}
+	for id, name := range ids {
+		if name == input {
+			return id, nil
+		}
+	}
+	var found []string
+	for id := range ids {
+		if strings.HasPrefix(id, input) {
+			found = append(found, id)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", errdefs.NotFound(fmt.Errorf("%s %s not found", kind, input))
+	case 1:
+		return found[0], nil
+	default:
+		return "", errdefs.InvalidParameter(fmt.Errorf("%s %s is ambiguous (%d matches found)", kind, input, len(found)))
+	}
+}
+
+func newID() string {
+	return identity.NewID()
+}
@@ -0,0 +1,227 @@
+package secretstore // import "github.com/docker/docker/daemon/secretstore"
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	apitypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newTestStore(t *testing.T, usedBy UsedByFunc) (*Store, string) {
+	root, err := ioutil.TempDir("", "secretstore-")
+	assert.NilError(t, err)
+	s, err := New(root, usedBy, nil)
+	assert.NilError(t, err)
+	return s, root
+}
+
+func secretSpec(name, data string, labels map[string]string) swarmtypes.SecretSpec {
+	return swarmtypes.SecretSpec{
+		Annotations: swarmtypes.Annotations{Name: name, Labels: labels},
+		Data:        []byte(data),
+	}
+}
+
+func TestSecretCreateAndGet(t *testing.T) {
+	s, root := newTestStore(t, nil)
+	defer os.RemoveAll(root)
+
+	id, err := s.CreateSecret(secretSpec("db-password", "s3cr3t", map[string]string{"env": "dev"}))
+	assert.NilError(t, err)
+
+	for _, input := range []string{id, "db-password", id[:5]} {
+		secret, err := s.GetSecret(input)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(secret.ID, id))
+		assert.Check(t, is.Equal(secret.Spec.Name, "db-password"))
+		assert.Check(t, is.Len(secret.Spec.Data, 0), "the data of secrets must not be returned")
+	}
+
+	secret, err := s.DependencyGetter(Container{}).Secrets().Get(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(secret.Spec.Data), "s3cr3t"))
+
+	data, err := ioutil.ReadFile(filepath.Join(root, secretsDir, id))
+	assert.NilError(t, err)
+	assert.Check(t, !bytes.Contains(data, []byte("s3cr3t")), "secrets must be encrypted at rest")
+
+	_, err = s.CreateSecret(secretSpec("db-password", "other", nil))
+	assert.Check(t, errdefs.IsConflict(err))
+
+	_, err = s.GetSecret("unknown")
+	assert.Check(t, errdefs.IsNotFound(err))
+}
+
+func TestSecretValidation(t *testing.T) {
+	s, root := newTestStore(t, nil)
+	defer os.RemoveAll(root)
+
+	_, err := s.CreateSecret(secretSpec("", "data", nil))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	_, err = s.CreateSecret(secretSpec("-invalid", "data", nil))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	_, err = s.CreateSecret(secretSpec("empty", "", nil))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	spec := secretSpec("template", "data", nil)
+	spec.Templating = &swarmtypes.Driver{Name: "golang"}
+	_, err = s.CreateSecret(spec)
+	assert.Check(t, errdefs.IsNotImplemented(err))
+
+	spec = secretSpec("driver", "", nil)
+	spec.Driver = &swarmtypes.Driver{Name: "vault"}
+	_, err = s.CreateSecret(spec)
+	assert.Check(t, errdefs.IsInvalidParameter(err), "secret provider plugins are not supported without a plugin getter")
+}
+
+func TestSecretList(t *testing.T) {
+	s, root := newTestStore(t, nil)
+	defer os.RemoveAll(root)
+
+	_, err := s.CreateSecret(secretSpec("foo", "data", map[string]string{"env": "dev"}))
+	assert.NilError(t, err)
+	_, err = s.CreateSecret(secretSpec("foobar", "data", map[string]string{"env": "prod"}))
+	assert.NilError(t, err)
+	_, err = s.CreateSecret(secretSpec("bar", "data", nil))
+	assert.NilError(t, err)
+
+	names := func(f filters.Args) []string {
+		secrets, err := s.GetSecrets(apitypes.SecretListOptions{Filters: f})
+		assert.NilError(t, err)
+		var names []string
+		for _, secret := range secrets {
+			names = append(names, secret.Spec.Name)
+		}
+		return names
+	}
+	assert.Check(t, is.DeepEqual(names(filters.NewArgs()), []string{"foo", "foobar", "bar"}))
+	assert.Check(t, is.DeepEqual(names(filters.NewArgs(filters.Arg("name", "foo"))), []string{"foo", "foobar"}))
+	assert.Check(t, is.DeepEqual(names(filters.NewArgs(filters.Arg("names", "foo"))), []string{"foo"}))
+	assert.Check(t, is.DeepEqual(names(filters.NewArgs(filters.Arg("label", "env=prod"))), []string{"foobar"}))
+	assert.Check(t, is.DeepEqual(names(filters.NewArgs(filters.Arg("label", "env"))), []string{"foo", "foobar"}))
+
+	_, err = s.GetSecrets(apitypes.SecretListOptions{Filters: filters.NewArgs(filters.Arg("driver", "vault"))})
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+}
+
+func TestSecretUpdate(t *testing.T) {
+	s, root := newTestStore(t, nil)
+	defer os.RemoveAll(root)
+
+	id, err := s.CreateSecret(secretSpec("foo", "data", nil))
+	assert.NilError(t, err)
+
+	err = s.UpdateSecret(id, 0, secretSpec("foo", "", map[string]string{"a": "b"}))
+	assert.Check(t, errdefs.IsConflict(err))
+	err = s.UpdateSecret(id, 1, secretSpec("foo", "other data", nil))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	assert.NilError(t, s.UpdateSecret(id, 1, secretSpec("foo", "", map[string]string{"a": "b"})))
+	secret, err := s.GetSecret(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(secret.Spec.Labels, map[string]string{"a": "b"}))
+	assert.Check(t, is.Equal(secret.Version.Index, uint64(2)))
+
+	data, err := s.DependencyGetter(Container{}).Secrets().Get(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data.Spec.Data), "data"))
+}
+
+func TestSecretRemove(t *testing.T) {
+	var inUse bool
+	s, root := newTestStore(t, func(id string) []string {
+		if inUse {
+			return []string{"web"}
+		}
+		return nil
+	})
+	defer os.RemoveAll(root)
+
+	id, err := s.CreateSecret(secretSpec("foo", "data", nil))
+	assert.NilError(t, err)
+
+	inUse = true
+	err = s.RemoveSecret("foo")
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	assert.Check(t, is.ErrorContains(err, "web"))
+
+	inUse = false
+	assert.NilError(t, s.RemoveSecret("foo"))
+	_, err = s.DependencyGetter(Container{}).Secrets().Get(id)
+	assert.Check(t, errdefs.IsNotFound(err))
+}
+
+func TestConfigs(t *testing.T) {
+	s, root := newTestStore(t, nil)
+	defer os.RemoveAll(root)
+
+	id, err := s.CreateConfig(swarmtypes.ConfigSpec{
+		Annotations: swarmtypes.Annotations{Name: "nginx.conf"},
+		Data:        []byte("server {}"),
+	})
+	assert.NilError(t, err)
+
+	config, err := s.GetConfig("nginx.conf")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(config.ID, id))
+	assert.Check(t, is.Equal(string(config.Spec.Data), "server {}"))
+
+	configs, err := s.GetConfigs(apitypes.ConfigListOptions{Filters: filters.NewArgs()})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(configs, 1))
+
+	c, err := s.DependencyGetter(Container{}).Configs().Get(id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(c.Spec.Data), "server {}"))
+
+	assert.NilError(t, s.RemoveConfig(id))
+	_, err = s.GetConfig(id)
+	assert.Check(t, errdefs.IsNotFound(err))
+}
+
+func TestReload(t *testing.T) {
+	s, root := newTestStore(t, nil)
+	defer os.RemoveAll(root)
+
+	secretID, err := s.CreateSecret(secretSpec("foo", "data", nil))
+	assert.NilError(t, err)
+	configID, err := s.CreateConfig(swarmtypes.ConfigSpec{
+		Annotations: swarmtypes.Annotations{Name: "bar"},
+		Data:        []byte("config"),
+	})
+	assert.NilError(t, err)
+
+	// Objects which cannot be decoded are skipped.
+	data, err := s.encrypt("invalid", []byte("{"))
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(root, secretsDir, "invalid"), data, 0600))
+
+	s, err = New(root, nil, nil)
+	assert.NilError(t, err)
+	_, err = s.GetSecret("invalid")
+	assert.Check(t, errdefs.IsNotFound(err))
+	secrets, err := s.GetSecrets(apitypes.SecretListOptions{})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(secrets, 1))
+	secret, err := s.DependencyGetter(Container{}).Secrets().Get(secretID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(secret.Spec.Data), "data"))
+	config, err := s.GetConfig(configID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(config.Spec.Name, "bar"))
+
+	// Objects which cannot be decrypted are skipped.
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(root, keyFile), bytes.Repeat([]byte{1}, keySize), 0600))
+	s, err = New(root, nil, nil)
+	assert.NilError(t, err)
+	_, err = s.GetSecret(secretID)
+	assert.Check(t, errdefs.IsNotFound(err))
+}
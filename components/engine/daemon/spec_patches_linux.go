@@ -0,0 +1,42 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/containerd/containerd/containers"
+	coci "github.com/containerd/containerd/oci"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// WithSpecPatches applies the spec patches of the daemon configuration which
+// select the container to the spec. The patched spec must still be a valid
+// spec, with no unknown fields.
+func WithSpecPatches(daemon *Daemon, c *container.Container) coci.SpecOpts {
+	return func(ctx context.Context, _ coci.Client, _ *containers.Container, s *coci.Spec) error {
+		for i, p := range daemon.configStore.SpecPatches {
+			if !p.Selects(c.Config.Labels) {
+				continue
+			}
+			b, err := json.Marshal(s)
+			if err != nil {
+				return err
+			}
+			b, err = p.Patch.Apply(b)
+			if err != nil {
+				return errdefs.System(errors.Wrapf(err, "failed to apply spec patch %d", i))
+			}
+			var patched coci.Spec
+			dec := json.NewDecoder(bytes.NewReader(b))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&patched); err != nil {
+				return errdefs.System(errors.Wrapf(err, "spec patch %d produced an invalid spec", i))
+			}
+			*s = patched
+		}
+		return nil
+	}
+}
@@ -0,0 +1,47 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/oci"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestWithSpecPatches(t *testing.T) {
+	var patches []config.SpecPatch
+	assert.NilError(t, json.Unmarshal([]byte(`[
+		{"selector": {"com.example.monitor": ""}, "patch": [
+			{"op": "add", "path": "/annotations", "value": {"com.example.monitor": "enabled"}},
+			{"op": "add", "path": "/process/env/-", "value": "MONITOR=1"}
+		]},
+		{"selector": {"com.example.tier": "db"}, "patch": [
+			{"op": "replace", "path": "/process/noNewPrivileges", "value": true}
+		]}
+	]`), &patches))
+	d := &Daemon{configStore: &config.Config{}}
+	d.configStore.SpecPatches = patches
+
+	c := &container.Container{Config: &containertypes.Config{
+		Labels: map[string]string{"com.example.monitor": "yes", "com.example.tier": "web"},
+	}}
+	s := oci.DefaultSpec()
+	s.Process.Env = []string{"PATH=/usr/bin"}
+	assert.NilError(t, WithSpecPatches(d, c)(context.Background(), nil, nil, &s))
+	assert.Check(t, is.DeepEqual(s.Annotations, map[string]string{"com.example.monitor": "enabled"}))
+	assert.Check(t, is.DeepEqual(s.Process.Env, []string{"PATH=/usr/bin", "MONITOR=1"}))
+	assert.Check(t, !s.Process.NoNewPrivileges)
+
+	// The patched spec must be a valid spec.
+	assert.NilError(t, json.Unmarshal([]byte(`[
+		{"patch": [{"op": "add", "path": "/linux/unknown", "value": 1}]}
+	]`), &d.configStore.SpecPatches))
+	s = oci.DefaultSpec()
+	err := WithSpecPatches(d, c)(context.Background(), nil, nil, &s)
+	assert.Check(t, is.ErrorContains(err, "spec patch 0 produced an invalid spec"))
+}
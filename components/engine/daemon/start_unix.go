@@ -7,7 +7,9 @@ import (
 	"os/exec"
 	"path/filepath"
 
+	"github.com/containerd/containerd/plugin"
 	"github.com/containerd/containerd/runtime/linux/runctypes"
+	runcoptions "github.com/containerd/containerd/runtime/v2/runc/options"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/errdefs"
 	"github.com/pkg/errors"
@@ -31,27 +33,46 @@ func (daemon *Daemon) getRuntimeScript(container *container.Container) (string,
 	return rt.Path, nil
 }
 
-// getLibcontainerdCreateOptions callers must hold a lock on the container
-func (daemon *Daemon) getLibcontainerdCreateOptions(container *container.Container) (interface{}, error) {
+// getLibcontainerdCreateOptions returns the containerd shim of the runtime of
+// the container, and its options. Callers must hold a lock on the container.
+func (daemon *Daemon) getLibcontainerdCreateOptions(container *container.Container) (string, interface{}, error) {
 	// Ensure a runtime has been assigned to this container
 	if container.HostConfig.Runtime == "" {
 		container.HostConfig.Runtime = daemon.configStore.GetDefaultRuntimeName()
 		container.CheckpointTo(daemon.containersReplica)
 	}
 
+	runtimeRoot := filepath.Join(daemon.configStore.ExecRoot,
+		fmt.Sprintf("runtime-%s", container.HostConfig.Runtime))
+
+	rt := daemon.configStore.GetRuntime(container.HostConfig.Runtime)
+	if rt != nil && rt.ShimConfig != nil {
+		opts := rt.ShimConfig.Opts
+		if runcOpts, ok := opts.(*runcoptions.Options); ok {
+			o := *runcOpts
+			if o.Root == "" {
+				o.Root = runtimeRoot
+			}
+			if UsingSystemd(daemon.configStore) {
+				o.SystemdCgroup = true
+			}
+			opts = &o
+		}
+		return rt.ShimConfig.Binary, opts, nil
+	}
+
 	path, err := daemon.getRuntimeScript(container)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 	opts := &runctypes.RuncOptions{
-		Runtime: path,
-		RuntimeRoot: filepath.Join(daemon.configStore.ExecRoot,
-			fmt.Sprintf("runtime-%s", container.HostConfig.Runtime)),
+		Runtime:     path,
+		RuntimeRoot: runtimeRoot,
 	}
 
 	if UsingSystemd(daemon.configStore) {
 		opts.SystemdCgroup = true
 	}
 
-	return opts, nil
+	return plugin.RuntimeLinuxV1, opts, nil
 }
@@ -0,0 +1,213 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/unixproxy"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/runconfig"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultUnixSocketMode is the file mode of the Unix sockets on which ports
+// are published, when the binding does not specify it.
+const defaultUnixSocketMode os.FileMode = 0660
+
+// unixSocketsDir returns the directory of the Unix sockets on which ports are
+// published. Only the daemon can write in this directory, so that the sockets
+// it replaces or changes the owner of are the ones it created.
+func (daemon *Daemon) unixSocketsDir() string {
+	return filepath.Join(daemon.configStore.GetExecRoot(), "sockets")
+}
+
+// validateUnixSocketBindings validates the Unix sockets on which the ports of
+// a container are published, which must be in the directory dir.
+func validateUnixSocketBindings(hostConfig *containertypes.HostConfig, platform, dir string) error {
+	if len(hostConfig.UnixSocketBindings) == 0 {
+		return nil
+	}
+	if platform == "windows" {
+		return errors.New("publishing ports on Unix sockets is not supported on Windows")
+	}
+	if mode := hostConfig.NetworkMode; mode.IsContainer() || mode.IsNone() {
+		return errors.Errorf("cannot publish ports on Unix sockets with the %s network mode", mode.NetworkName())
+	}
+	paths := make(map[string]bool)
+	for port, bindings := range hostConfig.UnixSocketBindings {
+		if _, err := nat.ParsePort(port.Port()); err != nil || port.Int() == 0 {
+			return errors.Errorf("invalid port specification: %q", port.Port())
+		}
+		if port.Proto() != "tcp" {
+			return errors.Errorf("cannot publish port %s on a Unix socket: only TCP ports can be published on Unix sockets", port)
+		}
+		for _, b := range bindings {
+			if !filepath.IsAbs(b.Path) {
+				return errors.Errorf("invalid Unix socket path %q: the path must be absolute", b.Path)
+			}
+			path := filepath.Clean(b.Path)
+			if filepath.Dir(path) != dir {
+				return errors.Errorf("invalid Unix socket path %s: the socket must be in %s", path, dir)
+			}
+			if paths[path] {
+				return errors.Errorf("duplicate Unix socket path %s", path)
+			}
+			paths[path] = true
+			if _, err := parseSocketOwnerID(b.UID); err != nil {
+				return errors.Errorf("invalid UID %q for Unix socket %s", b.UID, path)
+			}
+			if _, err := parseSocketOwnerID(b.GID); err != nil {
+				return errors.Errorf("invalid GID %q for Unix socket %s", b.GID, path)
+			}
+			if b.Mode&^os.ModePerm != 0 {
+				return errors.Errorf("invalid mode %o for Unix socket %s", b.Mode, path)
+			}
+		}
+	}
+	return nil
+}
+
+// startUnixSocketProxies creates the Unix sockets on which the ports of a
+// container are published, and forwards their connections to the container.
+// It must be called once the networking of the container is initialized.
+func (daemon *Daemon) startUnixSocketProxies(ctr *container.Container) (retErr error) {
+	if len(ctr.HostConfig.UnixSocketBindings) == 0 {
+		return nil
+	}
+	ip, err := unixSocketBackendIP(ctr)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(daemon.unixSocketsDir(), 0755); err != nil {
+		return errdefs.System(err)
+	}
+
+	daemon.unixSocketProxiesMu.Lock()
+	defer daemon.unixSocketProxiesMu.Unlock()
+
+	inUse := make(map[string]string)
+	for id, proxies := range daemon.unixSocketProxies {
+		for _, p := range proxies {
+			inUse[p.Path()] = id
+		}
+	}
+
+	var proxies []*unixproxy.Proxy
+	defer func() {
+		if retErr != nil {
+			for _, p := range proxies {
+				p.Close()
+			}
+		}
+	}()
+
+	ports := make([]nat.Port, 0, len(ctr.HostConfig.UnixSocketBindings))
+	for port := range ctr.HostConfig.UnixSocketBindings {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	for _, port := range ports {
+		for _, b := range ctr.HostConfig.UnixSocketBindings[port] {
+			path := filepath.Clean(b.Path)
+			if id, ok := inUse[path]; ok && id != ctr.ID {
+				return errdefs.Conflict(fmt.Errorf("cannot publish port %s on Unix socket %s: the socket is used by container %s", port, path, id))
+			}
+			uid, err := parseSocketOwnerID(b.UID)
+			if err != nil {
+				return errdefs.InvalidParameter(err)
+			}
+			gid, err := parseSocketOwnerID(b.GID)
+			if err != nil {
+				return errdefs.InvalidParameter(err)
+			}
+			mode := b.Mode
+			if mode == 0 {
+				mode = defaultUnixSocketMode
+			}
+			p, err := unixproxy.New(path, uid, gid, mode, &net.TCPAddr{IP: ip, Port: port.Int()})
+			if err != nil {
+				return errdefs.System(errors.Wrapf(err, "failed to publish port %s on Unix socket", port))
+			}
+			proxies = append(proxies, p)
+		}
+	}
+
+	daemon.unixSocketProxies[ctr.ID] = proxies
+	return nil
+}
+
+// stopUnixSocketProxies removes the Unix sockets on which the ports of a
+// container are published.
+func (daemon *Daemon) stopUnixSocketProxies(ctr *container.Container) {
+	daemon.unixSocketProxiesMu.Lock()
+	proxies := daemon.unixSocketProxies[ctr.ID]
+	delete(daemon.unixSocketProxies, ctr.ID)
+	daemon.unixSocketProxiesMu.Unlock()
+
+	for _, p := range proxies {
+		if err := p.Close(); err != nil {
+			logrus.WithError(err).WithField("container", ctr.ID).Warnf("Failed to remove Unix socket %s", p.Path())
+		}
+	}
+}
+
+// unixSocketBackendIP returns the IP address to which the connections to the
+// Unix sockets of a container are forwarded: the loopback address for the
+// containers using the network of the host, otherwise the address of the
+// container on the network of its network mode, or on the first network
+// with an IP address.
+func unixSocketBackendIP(ctr *container.Container) (net.IP, error) {
+	mode := ctr.HostConfig.NetworkMode
+	if mode.IsHost() {
+		return net.IPv4(127, 0, 0, 1), nil
+	}
+	if ctr.NetworkSettings != nil {
+		name := mode.NetworkName()
+		if mode.IsDefault() {
+			name = runconfig.DefaultDaemonNetworkMode().NetworkName()
+		}
+		if ep, ok := ctr.NetworkSettings.Networks[name]; ok && ep.EndpointSettings != nil {
+			if ip := net.ParseIP(ep.IPAddress); ip != nil {
+				return ip, nil
+			}
+		}
+		names := make([]string, 0, len(ctr.NetworkSettings.Networks))
+		for n := range ctr.NetworkSettings.Networks {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			ep := ctr.NetworkSettings.Networks[n]
+			if ep.EndpointSettings == nil {
+				continue
+			}
+			if ip := net.ParseIP(ep.IPAddress); ip != nil {
+				return ip, nil
+			}
+		}
+	}
+	return nil, errdefs.InvalidParameter(errors.New("cannot publish ports on Unix sockets: the container has no IP address"))
+}
+
+// parseSocketOwnerID parses the UID or GID of the owner of a Unix socket.
+// The owner defaults to root.
+func parseSocketOwnerID(id string) (int, error) {
+	if id == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(id)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid ID %q", id)
+	}
+	return n, nil
+}
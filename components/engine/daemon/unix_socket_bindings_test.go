@@ -0,0 +1,117 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	networktypes "github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/network"
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestValidateUnixSocketBindings(t *testing.T) {
+	valid := &containertypes.HostConfig{
+		NetworkMode: "bridge",
+		UnixSocketBindings: map[nat.Port][]containertypes.UnixSocketBinding{
+			"80/tcp":   {{Path: "/run/app.sock"}, {Path: "/run/app-admin.sock", UID: "1000", GID: "1000", Mode: 0600}},
+			"8080/tcp": {{Path: "/run/other.sock"}},
+		},
+	}
+	assert.NilError(t, validateUnixSocketBindings(valid, "linux", "/run"))
+	assert.Check(t, is.Error(validateUnixSocketBindings(valid, "windows", "/run"), "publishing ports on Unix sockets is not supported on Windows"))
+
+	testCases := []struct {
+		networkMode containertypes.NetworkMode
+		bindings    map[nat.Port][]containertypes.UnixSocketBinding
+		expected    string
+	}{
+		{
+			networkMode: "none",
+			bindings:    map[nat.Port][]containertypes.UnixSocketBinding{"80/tcp": {{Path: "/run/app.sock"}}},
+			expected:    "cannot publish ports on Unix sockets with the none network mode",
+		},
+		{
+			networkMode: "container:web",
+			bindings:    map[nat.Port][]containertypes.UnixSocketBinding{"80/tcp": {{Path: "/run/app.sock"}}},
+			expected:    "cannot publish ports on Unix sockets with the container network mode",
+		},
+		{
+			bindings: map[nat.Port][]containertypes.UnixSocketBinding{"http/tcp": {{Path: "/run/app.sock"}}},
+			expected: `invalid port specification: "http"`,
+		},
+		{
+			bindings: map[nat.Port][]containertypes.UnixSocketBinding{"53/udp": {{Path: "/run/app.sock"}}},
+			expected: "cannot publish port 53/udp on a Unix socket: only TCP ports can be published on Unix sockets",
+		},
+		{
+			bindings: map[nat.Port][]containertypes.UnixSocketBinding{"80/tcp": {{Path: "run/app.sock"}}},
+			expected: `invalid Unix socket path "run/app.sock": the path must be absolute`,
+		},
+		{
+			bindings: map[nat.Port][]containertypes.UnixSocketBinding{"80/tcp": {{Path: "/tmp/app.sock"}}},
+			expected: "invalid Unix socket path /tmp/app.sock: the socket must be in /run",
+		},
+		{
+			bindings: map[nat.Port][]containertypes.UnixSocketBinding{"80/tcp": {{Path: "/run/app/app.sock"}}},
+			expected: "invalid Unix socket path /run/app/app.sock: the socket must be in /run",
+		},
+		{
+			bindings: map[nat.Port][]containertypes.UnixSocketBinding{"80/tcp": {{Path: "/run/../etc/app.sock"}}},
+			expected: "invalid Unix socket path /etc/app.sock: the socket must be in /run",
+		},
+		{
+			bindings: map[nat.Port][]containertypes.UnixSocketBinding{"80/tcp": {{Path: "/run/app.sock"}, {Path: "/run//app.sock"}}},
+			expected: "duplicate Unix socket path /run/app.sock",
+		},
+		{
+			bindings: map[nat.Port][]containertypes.UnixSocketBinding{"80/tcp": {{Path: "/run/app.sock", UID: "www-data"}}},
+			expected: `invalid UID "www-data" for Unix socket /run/app.sock`,
+		},
+		{
+			bindings: map[nat.Port][]containertypes.UnixSocketBinding{"80/tcp": {{Path: "/run/app.sock", GID: "-1"}}},
+			expected: `invalid GID "-1" for Unix socket /run/app.sock`,
+		},
+		{
+			bindings: map[nat.Port][]containertypes.UnixSocketBinding{"80/tcp": {{Path: "/run/app.sock", Mode: 04755}}},
+			expected: "invalid mode 4755 for Unix socket /run/app.sock",
+		},
+	}
+	for _, tc := range testCases {
+		hostConfig := &containertypes.HostConfig{NetworkMode: tc.networkMode, UnixSocketBindings: tc.bindings}
+		assert.Check(t, is.Error(validateUnixSocketBindings(hostConfig, "linux", "/run"), tc.expected))
+	}
+}
+
+func TestUnixSocketBackendIP(t *testing.T) {
+	endpoint := func(ip string) *network.EndpointSettings {
+		return &network.EndpointSettings{EndpointSettings: &networktypes.EndpointSettings{IPAddress: ip}}
+	}
+	ctr := &container.Container{
+		HostConfig: &containertypes.HostConfig{NetworkMode: "default"},
+		NetworkSettings: &network.Settings{Networks: map[string]*network.EndpointSettings{
+			"bridge":  endpoint("172.17.0.2"),
+			"backend": endpoint("172.20.0.2"),
+		}},
+	}
+	ip, err := unixSocketBackendIP(ctr)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ip.String(), "172.17.0.2"))
+
+	ctr.HostConfig.NetworkMode = "frontend"
+	ip, err = unixSocketBackendIP(ctr)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ip.String(), "172.20.0.2"))
+
+	ctr.HostConfig.NetworkMode = "host"
+	ip, err = unixSocketBackendIP(ctr)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ip.String(), "127.0.0.1"))
+
+	ctr.HostConfig.NetworkMode = "bridge"
+	ctr.NetworkSettings.Networks = nil
+	_, err = unixSocketBackendIP(ctr)
+	assert.Check(t, is.ErrorContains(err, "the container has no IP address"))
+}
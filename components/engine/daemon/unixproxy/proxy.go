@@ -0,0 +1,138 @@
+// Package unixproxy implements a proxy forwarding the connections to a Unix
+// socket of the host to a TCP port of a container. It works like the TCP
+// proxy of the userland proxy (docker-proxy), but runs in the daemon.
+package unixproxy // import "github.com/docker/docker/daemon/unixproxy"
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Proxy forwards the connections to a Unix socket to a TCP address.
+type Proxy struct {
+	listener    *net.UnixListener
+	backendAddr *net.TCPAddr
+	quit        chan struct{}
+	wg          sync.WaitGroup
+}
+
+// New creates the Unix socket at path, owned by uid and gid with the given
+// mode, and returns a proxy forwarding the connections to the socket to
+// backendAddr. A socket left at path, for example by a daemon which did not
+// shut down cleanly, is replaced, unless it still accepts connections. The
+// directory of path must exist, and must only be writable by the caller,
+// which is trusted to have created the sockets it contains. The proxy accepts
+// connections until it is closed.
+func New(path string, uid, gid int, mode os.FileMode, backendAddr *net.TCPAddr) (*Proxy, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("cannot create socket %s: a file already exists at this path", path)
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("cannot create socket %s: the socket is already in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Lchown(path, uid, gid); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	proxy := &Proxy{
+		listener:    listener,
+		backendAddr: backendAddr,
+		quit:        make(chan struct{}),
+	}
+	proxy.wg.Add(1)
+	go proxy.acceptLoop()
+	return proxy, nil
+}
+
+func (proxy *Proxy) acceptLoop() {
+	defer proxy.wg.Done()
+	for {
+		client, err := proxy.listener.AcceptUnix()
+		if err != nil {
+			select {
+			case <-proxy.quit:
+			default:
+				logrus.WithError(err).WithField("socket", proxy.listener.Addr()).Error("Stopping the Unix socket proxy")
+			}
+			return
+		}
+		proxy.wg.Add(1)
+		go proxy.clientLoop(client)
+	}
+}
+
+func (proxy *Proxy) clientLoop(client *net.UnixConn) {
+	defer proxy.wg.Done()
+
+	backend, err := net.DialTCP("tcp", nil, proxy.backendAddr)
+	if err != nil {
+		logrus.WithError(err).Warnf("Can't forward traffic from %s to backend tcp/%v", proxy.listener.Addr(), proxy.backendAddr)
+		client.Close()
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		io.Copy(client, backend)
+		backend.CloseRead()
+		client.CloseWrite()
+		wg.Done()
+	}()
+	go func() {
+		io.Copy(backend, client)
+		client.CloseRead()
+		backend.CloseWrite()
+		wg.Done()
+	}()
+
+	finish := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finish)
+	}()
+
+	select {
+	case <-proxy.quit:
+	case <-finish:
+	}
+	client.Close()
+	backend.Close()
+}
+
+// Path returns the path of the socket.
+func (proxy *Proxy) Path() string {
+	return proxy.listener.Addr().String()
+}
+
+// Close stops the proxy, closes the connections it forwards, and removes
+// the socket.
+func (proxy *Proxy) Close() error {
+	close(proxy.quit)
+	err := proxy.listener.Close()
+	proxy.wg.Wait()
+	return err
+}
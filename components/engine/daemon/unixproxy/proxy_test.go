@@ -0,0 +1,109 @@
+package unixproxy // import "github.com/docker/docker/daemon/unixproxy"
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newEchoServer(t *testing.T) *net.TCPListener {
+	t.Helper()
+	l, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.NilError(t, err)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+	return l
+}
+
+func TestProxy(t *testing.T) {
+	backend := newEchoServer(t)
+	defer backend.Close()
+
+	dir, err := ioutil.TempDir("", "unixproxy")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.sock")
+
+	proxy, err := New(path, os.Getuid(), os.Getgid(), 0600, backend.Addr().(*net.TCPAddr))
+	assert.NilError(t, err)
+
+	fi, err := os.Stat(path)
+	assert.NilError(t, err)
+	assert.Check(t, fi.Mode()&os.ModeSocket != 0)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0600)))
+
+	conn, err := net.Dial("unix", path)
+	assert.NilError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("hello\n"))
+	assert.NilError(t, err)
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(line, "hello\n"))
+
+	assert.NilError(t, proxy.Close())
+	_, err = os.Stat(path)
+	assert.Check(t, os.IsNotExist(err))
+}
+
+func TestProxyReplacesStaleSocket(t *testing.T) {
+	backend := newEchoServer(t)
+	defer backend.Close()
+
+	dir, err := ioutil.TempDir("", "unixproxy")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.sock")
+
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	assert.NilError(t, err)
+	l.SetUnlinkOnClose(false)
+	l.Close()
+
+	proxy, err := New(path, os.Getuid(), os.Getgid(), 0660, backend.Addr().(*net.TCPAddr))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(proxy.Path(), path))
+	assert.NilError(t, proxy.Close())
+}
+
+func TestProxyDoesNotReplaceSocketInUse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unixproxy")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.sock")
+
+	l, err := net.Listen("unix", path)
+	assert.NilError(t, err)
+	defer l.Close()
+
+	_, err = New(path, os.Getuid(), os.Getgid(), 0660, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 80})
+	assert.Check(t, is.ErrorContains(err, "the socket is already in use"))
+}
+
+func TestProxyDoesNotReplaceFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unixproxy")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.sock")
+	assert.NilError(t, ioutil.WriteFile(path, nil, 0644))
+
+	_, err = New(path, os.Getuid(), os.Getgid(), 0660, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 80})
+	assert.Check(t, is.ErrorContains(err, "a file already exists at this path"))
+}
@@ -10,7 +10,7 @@ import (
 )
 
 // ContainerUpdate updates configuration of the container
-func (daemon *Daemon) ContainerUpdate(name string, hostConfig *container.HostConfig) (container.ContainerUpdateOKBody, error) {
+func (daemon *Daemon) ContainerUpdate(name string, updateConfig *container.UpdateConfig) (container.ContainerUpdateOKBody, error) {
 	var warnings []string
 
 	c, err := daemon.GetContainer(name)
@@ -18,19 +18,28 @@ func (daemon *Daemon) ContainerUpdate(name string, hostConfig *container.HostCon
 		return container.ContainerUpdateOKBody{Warnings: warnings}, err
 	}
 
+	hostConfig := &container.HostConfig{
+		Resources:     updateConfig.Resources,
+		RestartPolicy: updateConfig.RestartPolicy,
+	}
 	warnings, err = daemon.verifyContainerSettings(c.OS, hostConfig, nil, true)
 	if err != nil {
 		return container.ContainerUpdateOKBody{Warnings: warnings}, errdefs.InvalidParameter(err)
 	}
 
-	if err := daemon.update(name, hostConfig); err != nil {
+	devicesAdd, err := verifyDevicesAdd(updateConfig.DevicesAdd)
+	if err != nil {
+		return container.ContainerUpdateOKBody{Warnings: warnings}, errdefs.InvalidParameter(err)
+	}
+
+	if err := daemon.update(name, hostConfig, devicesAdd, updateConfig.DevicesRemove); err != nil {
 		return container.ContainerUpdateOKBody{Warnings: warnings}, err
 	}
 
 	return container.ContainerUpdateOKBody{Warnings: warnings}, nil
 }
 
-func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) error {
+func (daemon *Daemon) update(name string, hostConfig *container.HostConfig, devicesAdd []container.DeviceMapping, devicesRemove []string) error {
 	if hostConfig == nil {
 		return nil
 	}
@@ -61,6 +70,12 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 		container.Unlock()
 		return errCannotUpdate(container.ID, err)
 	}
+	devicesRemoved, err := updateDeviceMappings(container.HostConfig, devicesAdd, devicesRemove)
+	if err != nil {
+		restoreConfig = true
+		container.Unlock()
+		return errCannotUpdate(container.ID, err)
+	}
 	if err := container.CheckpointTo(daemon.containersReplica); err != nil {
 		restoreConfig = true
 		container.Unlock()
@@ -83,6 +98,10 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 			// TODO: it would be nice if containerd responded with better errors here so we can classify this better.
 			return errCannotUpdate(container.ID, errdefs.System(err))
 		}
+		if err := daemon.updateDevices(container, devicesAdd, devicesRemoved); err != nil {
+			restoreConfig = true
+			return errCannotUpdate(container.ID, err)
+		}
 	}
 
 	daemon.LogContainerEvent(container, "update")
@@ -93,3 +112,55 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 func errCannotUpdate(containerID string, err error) error {
 	return errors.Wrap(err, "Cannot update container "+containerID)
 }
+
+// updateDeviceMappings removes the devices mapped to the paths in remove from
+// the devices of hostConfig, then adds the devices in add. It returns the
+// mappings of the removed devices. The devices of hostConfig are replaced,
+// not modified in place, so that a copy of hostConfig can be restored.
+func updateDeviceMappings(hostConfig *container.HostConfig, add []container.DeviceMapping, remove []string) ([]container.DeviceMapping, error) {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, nil
+	}
+
+	devices := make([]container.DeviceMapping, 0, len(hostConfig.Devices)+len(add))
+	var removed []container.DeviceMapping
+	for _, d := range hostConfig.Devices {
+		if containsString(remove, d.PathInContainer) {
+			removed = append(removed, d)
+			continue
+		}
+		devices = append(devices, d)
+	}
+	for _, p := range remove {
+		if !containsDevice(removed, p) {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("no device is mapped to %s in the container", p))
+		}
+	}
+	for _, d := range add {
+		if containsDevice(devices, d.PathInContainer) {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("a device is already mapped to %s in the container", d.PathInContainer))
+		}
+		devices = append(devices, d)
+	}
+
+	hostConfig.Devices = devices
+	return removed, nil
+}
+
+func containsDevice(devices []container.DeviceMapping, pathInContainer string) bool {
+	for _, d := range devices {
+		if d.PathInContainer == pathInContainer {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
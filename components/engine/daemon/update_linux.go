@@ -1,11 +1,29 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+	containerpkg "github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
 	libcontainerdtypes "github.com/docker/docker/libcontainerd/types"
+	"github.com/docker/docker/oci"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
 func toContainerdResources(resources container.Resources) *libcontainerdtypes.Resources {
@@ -53,3 +71,450 @@ func toContainerdResources(resources container.Resources) *libcontainerdtypes.Re
 	r.Pids = getPidsLimit(resources)
 	return &r
 }
+
+// verifyDevicesAdd validates the devices to add to a container, and returns
+// them with the defaults of the path in the container and of the cgroup
+// permissions applied.
+func verifyDevicesAdd(devices []container.DeviceMapping) ([]container.DeviceMapping, error) {
+	var verified []container.DeviceMapping
+	for _, d := range devices {
+		if d.PathOnHost == "" {
+			return nil, errors.New("device path on the host cannot be empty")
+		}
+		if d.PathInContainer == "" {
+			d.PathInContainer = d.PathOnHost
+		}
+		if d.CgroupPermissions == "" {
+			d.CgroupPermissions = "rwm"
+		}
+		if !filepath.IsAbs(d.PathInContainer) {
+			return nil, fmt.Errorf("device path in the container %s must be absolute", d.PathInContainer)
+		}
+		if !validDeviceMode(d.CgroupPermissions) {
+			return nil, fmt.Errorf("invalid cgroup permissions %q for device %s", d.CgroupPermissions, d.PathOnHost)
+		}
+		if _, _, err := oci.DevicesFromPath(d.PathOnHost, d.PathInContainer, d.CgroupPermissions); err != nil {
+			return nil, err
+		}
+		verified = append(verified, d)
+	}
+	return verified, nil
+}
+
+// validDeviceMode checks that mode is a combination of the "r", "w" and "m"
+// cgroup permissions.
+func validDeviceMode(mode string) bool {
+	if mode == "" || len(mode) > 3 {
+		return false
+	}
+	seen := map[rune]bool{}
+	for _, c := range mode {
+		if !strings.ContainsRune("rwm", c) || seen[c] {
+			return false
+		}
+		seen[c] = true
+	}
+	return true
+}
+
+// updateDevices makes the devices added to a running container available in
+// the container, and the removed ones unavailable: it updates the device
+// cgroup of the container, and creates or removes the device nodes in its
+// mount namespace. The devices of the container's HostConfig must already be
+// updated.
+func (daemon *Daemon) updateDevices(c *containerpkg.Container, add, remove []container.DeviceMapping) error {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+	if c.HostConfig.Privileged {
+		return errdefs.InvalidParameter(errors.New("cannot update the devices of a running privileged container"))
+	}
+	if (daemon.configStore.RemappedRoot != "" && c.HostConfig.UsernsMode.IsPrivate()) || c.HostConfig.UsernsMode.IsAuto() {
+		return errdefs.InvalidParameter(errors.New("cannot update the devices of a running container with user namespaces enabled"))
+	}
+
+	pid := c.GetPID()
+	cgroupPath, err := deviceCgroupPath(pid)
+	if err != nil {
+		return errdefs.System(err)
+	}
+
+	// Devices are only added at paths which do not exist in the container,
+	// so that a rollback never removes a file the container already had. The
+	// paths of the removed devices are freed before the devices are added.
+	var paths []string
+	for _, d := range add {
+		if !containsDevice(remove, d.PathInContainer) {
+			paths = append(paths, d.PathInContainer)
+		}
+	}
+	if len(paths) > 0 {
+		resp, err := updateDeviceNodes(pid, deviceNodesRequest{Check: paths})
+		if err != nil {
+			return errdefs.System(err)
+		}
+		if len(resp.Existing) > 0 {
+			return errdefs.InvalidParameter(fmt.Errorf("cannot add devices at paths which already exist in the container: %s", strings.Join(resp.Existing, ", ")))
+		}
+	}
+
+	// The device cgroup rules the container keeps once the removed devices
+	// are gone, and without the added ones. Removing a device must not deny
+	// access to devices that remain allowed, like /dev/null when it is also
+	// mapped to another path.
+	var kept []container.DeviceMapping
+	for _, d := range c.HostConfig.Devices {
+		if !containsDevice(add, d.PathInContainer) {
+			kept = append(kept, d)
+		}
+	}
+	rules, err := deviceCgroupRules(kept, c.HostConfig.DeviceCgroupRules)
+	if err != nil {
+		return errdefs.System(err)
+	}
+
+	for _, d := range remove {
+		if err := removeDevice(cgroupPath, pid, d.PathInContainer, rules); err != nil {
+			return errdefs.System(errors.Wrapf(err, "failed to remove device %s", d.PathInContainer))
+		}
+	}
+
+	var (
+		allowed []specs.LinuxDeviceCgroup
+		created []string
+	)
+	for _, d := range add {
+		a, cr, err := addDevice(cgroupPath, pid, d)
+		allowed = append(allowed, a...)
+		created = append(created, cr...)
+		if err != nil {
+			rollbackAddedDevices(c, cgroupPath, pid, allowed, created, rules)
+			return errdefs.System(errors.Wrapf(err, "failed to add device %s", d.PathOnHost))
+		}
+	}
+	return nil
+}
+
+// rollbackAddedDevices removes the device nodes created while adding devices
+// to a container, and denies the devices allowed in its device cgroup which
+// rules, the rules the container keeps, do not allow.
+func rollbackAddedDevices(c *containerpkg.Container, cgroupPath string, pid int, allowed []specs.LinuxDeviceCgroup, created []string, rules []specs.LinuxDeviceCgroup) {
+	for _, p := range created {
+		if _, err := updateDeviceNodes(pid, deviceNodesRequest{Remove: p}); err != nil {
+			logrus.WithError(err).WithField("container", c.ID).Warnf("failed to remove device %s", p)
+		}
+	}
+	for _, r := range allowed {
+		if r.Major != nil && r.Minor != nil && deviceAllowed(rules, r.Type, *r.Major, *r.Minor) {
+			continue
+		}
+		if err := writeDeviceRule(cgroupPath, "devices.deny", r); err != nil {
+			logrus.WithError(err).WithField("container", c.ID).Warnf("failed to deny device %s", formatDeviceRule(r))
+		}
+	}
+}
+
+// deviceCgroupPath returns the path of the device cgroup of the process pid.
+// Only the cgroup v1 devices controller is supported.
+func deviceCgroupPath(pid int) (string, error) {
+	paths, err := cgroups.ParseCgroupFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+	path, ok := paths["devices"]
+	if !ok {
+		return "", errors.New("updating the devices of a running container requires the cgroup v1 devices controller")
+	}
+	mnt, root, err := cgroups.FindCgroupMountpointAndRoot("", "devices")
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(mnt, rel), nil
+}
+
+// deviceCgroupRules returns the device cgroup rules of a non-privileged
+// container, as set by WithDevices. Devices no longer present on the host are
+// skipped.
+func deviceCgroupRules(devices []container.DeviceMapping, cgroupRules []string) ([]specs.LinuxDeviceCgroup, error) {
+	rules := oci.DefaultLinuxSpec().Linux.Resources.Devices
+	for _, d := range devices {
+		_, perms, err := oci.DevicesFromPath(d.PathOnHost, d.PathInContainer, d.CgroupPermissions)
+		if err != nil {
+			continue
+		}
+		rules = append(rules, perms...)
+	}
+	return oci.AppendDevicePermissionsFromCgroupRules(rules, cgroupRules)
+}
+
+// deviceAllowed returns whether the device of type typ and numbers major and
+// minor is allowed by the device cgroup rules, applied in order.
+func deviceAllowed(rules []specs.LinuxDeviceCgroup, typ string, major, minor int64) bool {
+	matches := func(n *int64, v int64) bool {
+		return n == nil || *n == -1 || *n == v
+	}
+	allowed := false
+	for _, r := range rules {
+		if (r.Type == "" || r.Type == "a" || r.Type == typ) && matches(r.Major, major) && matches(r.Minor, minor) {
+			allowed = r.Allow
+		}
+	}
+	return allowed
+}
+
+// formatDeviceRule formats a device cgroup rule as written to the
+// devices.allow and devices.deny files.
+func formatDeviceRule(r specs.LinuxDeviceCgroup) string {
+	number := func(n *int64) string {
+		if n == nil || *n == -1 {
+			return "*"
+		}
+		return strconv.FormatInt(*n, 10)
+	}
+	typ := r.Type
+	if typ == "" {
+		typ = "a"
+	}
+	return fmt.Sprintf("%s %s:%s %s", typ, number(r.Major), number(r.Minor), r.Access)
+}
+
+func writeDeviceRule(cgroupPath, file string, r specs.LinuxDeviceCgroup) error {
+	return ioutil.WriteFile(filepath.Join(cgroupPath, file), []byte(formatDeviceRule(r)), 0)
+}
+
+func init() {
+	reexec.Register("docker-device-nodes", deviceNodesMain)
+}
+
+// deviceNodesRequest is the request of the docker-device-nodes re-exec
+// helper, which checks whether the paths Check exist, creates the device
+// nodes Create and removes the device nodes at the path Remove.
+type deviceNodesRequest struct {
+	Check  []string
+	Create []specs.LinuxDevice
+	Remove string
+}
+
+// deviceNodesResponse is the response of the docker-device-nodes re-exec
+// helper, which is returned including on error.
+type deviceNodesResponse struct {
+	// Existing are the paths of the request which exist.
+	Existing []string
+	// Created are the paths of the device nodes created.
+	Created []string
+	// Removed are the cgroup rules denying the removed devices.
+	Removed []specs.LinuxDeviceCgroup
+}
+
+// updateDeviceNodes checks paths, and creates and removes device nodes in the
+// mount namespace of the process pid, in which the paths are resolved in the
+// root of the container, as seen by the processes of the container.
+func updateDeviceNodes(pid int, req deviceNodesRequest) (deviceNodesResponse, error) {
+	var resp deviceNodesResponse
+	cmd := reexec.Command("docker-device-nodes", strconv.Itoa(pid))
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return resp, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		w.Close()
+		return resp, err
+	}
+	err = json.NewEncoder(w).Encode(req)
+	w.Close()
+	if waitErr := cmd.Wait(); waitErr != nil {
+		err = waitErr
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = errors.New(msg)
+		}
+	}
+	if stdout.Len() > 0 {
+		if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+			return resp, err
+		}
+	}
+	return resp, err
+}
+
+// deviceNodesMain is the entry-point for docker-device-nodes on re-exec. It
+// joins the mount namespace of the process given as argument, with a umask
+// of 0, and writes its response to stdout, including on error.
+func deviceNodesMain() {
+	runtime.LockOSThread()
+	fatal := func(err error) {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) != 2 {
+		fatal(errors.New("usage: docker-device-nodes PID"))
+	}
+	var req deviceNodesRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fatal(err)
+	}
+	ns, err := os.Open(filepath.Join("/proc", os.Args[1], "ns", "mnt"))
+	if err != nil {
+		fatal(err)
+	}
+	if err := unix.Unshare(unix.CLONE_FS); err != nil {
+		fatal(errors.Wrap(err, "failed to unshare the filesystem attributes"))
+	}
+	if err := unix.Setns(int(ns.Fd()), unix.CLONE_NEWNS); err != nil {
+		fatal(errors.Wrap(err, "failed to join the mount namespace of the container"))
+	}
+	ns.Close()
+	unix.Umask(0)
+
+	var resp deviceNodesResponse
+	err = func() error {
+		for _, p := range req.Check {
+			if _, err := os.Lstat(p); err == nil {
+				resp.Existing = append(resp.Existing, p)
+			} else if !os.IsNotExist(err) {
+				return err
+			}
+		}
+		for _, dev := range req.Create {
+			if err := createDeviceNode(dev); err != nil {
+				return err
+			}
+			resp.Created = append(resp.Created, dev.Path)
+		}
+		if req.Remove != "" {
+			// The devices removed before an error are denied as well.
+			var err error
+			resp.Removed, err = removeDeviceNodes(req.Remove)
+			return err
+		}
+		return nil
+	}()
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		fatal(err)
+	}
+	if err != nil {
+		fatal(err)
+	}
+	os.Exit(0)
+}
+
+// addDevice allows the devices of the mapping d in the device cgroup, and
+// creates their nodes in the mount namespace of the process pid. It returns
+// the cgroup rules written to devices.allow and the paths of the nodes
+// created, including on error.
+func addDevice(cgroupPath string, pid int, d container.DeviceMapping) ([]specs.LinuxDeviceCgroup, []string, error) {
+	devs, perms, err := oci.DevicesFromPath(d.PathOnHost, d.PathInContainer, d.CgroupPermissions)
+	if err != nil {
+		return nil, nil, err
+	}
+	var allowed []specs.LinuxDeviceCgroup
+	for _, r := range perms {
+		if err := writeDeviceRule(cgroupPath, "devices.allow", r); err != nil {
+			return allowed, nil, err
+		}
+		allowed = append(allowed, r)
+	}
+	resp, err := updateDeviceNodes(pid, deviceNodesRequest{Create: devs})
+	return allowed, resp.Created, err
+}
+
+// createDeviceNode creates the node of a device. It runs in the
+// docker-device-nodes helper, in the mount namespace of the container. An existing file at the
+// path of the device, including a symlink, is not replaced.
+func createDeviceNode(d specs.LinuxDevice) error {
+	if err := os.MkdirAll(filepath.Dir(d.Path), 0755); err != nil {
+		return err
+	}
+
+	var mode uint32
+	switch d.Type {
+	case "c", "u":
+		mode = unix.S_IFCHR
+	case "b":
+		mode = unix.S_IFBLK
+	case "p":
+		mode = unix.S_IFIFO
+	default:
+		return fmt.Errorf("unsupported device type %q", d.Type)
+	}
+	perm := os.FileMode(0666)
+	if d.FileMode != nil {
+		perm = d.FileMode.Perm()
+	}
+	if err := unix.Mknod(d.Path, mode|uint32(perm), int(unix.Mkdev(uint32(d.Major), uint32(d.Minor)))); err != nil {
+		return &os.PathError{Op: "mknod", Path: d.Path, Err: err}
+	}
+	var uid, gid int
+	if d.UID != nil {
+		uid = int(*d.UID)
+	}
+	if d.GID != nil {
+		gid = int(*d.GID)
+	}
+	return os.Lchown(d.Path, uid, gid)
+}
+
+// removeDevice removes the device nodes at pathInContainer, which may be a
+// directory of devices, from the mount namespace of the process pid, and
+// denies them in the device cgroup unless they are still allowed by rules.
+func removeDevice(cgroupPath string, pid int, pathInContainer string, rules []specs.LinuxDeviceCgroup) error {
+	resp, err := updateDeviceNodes(pid, deviceNodesRequest{Remove: pathInContainer})
+	for _, r := range resp.Removed {
+		if deviceAllowed(rules, r.Type, *r.Major, *r.Minor) {
+			continue
+		}
+		if err := writeDeviceRule(cgroupPath, "devices.deny", r); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// removeDeviceNodes removes the device nodes at path, which may be a
+// directory of devices, and returns the cgroup rules denying the removed
+// devices. It runs in the docker-device-nodes helper, in the mount namespace
+// of the container. Symlinks, including one at path, are not followed.
+func removeDeviceNodes(path string) ([]specs.LinuxDeviceCgroup, error) {
+	var removed []specs.LinuxDeviceCgroup
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		var typ string
+		switch {
+		case info.Mode()&os.ModeCharDevice != 0:
+			typ = "c"
+		case info.Mode()&os.ModeDevice != 0:
+			typ = "b"
+		default:
+			return nil
+		}
+		st, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			return nil
+		}
+		major := int64(unix.Major(uint64(st.Rdev)))
+		minor := int64(unix.Minor(uint64(st.Rdev)))
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+		removed = append(removed, specs.LinuxDeviceCgroup{
+			Type:   typ,
+			Major:  &major,
+			Minor:  &minor,
+			Access: "rwm",
+		})
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return removed, err
+}
@@ -0,0 +1,220 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	containerpkg "github.com/docker/docker/container"
+	"github.com/docker/docker/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestVerifyDevicesAdd(t *testing.T) {
+	devices, err := verifyDevicesAdd([]container.DeviceMapping{
+		{PathOnHost: "/dev/null"},
+		{PathOnHost: "/dev/zero", PathInContainer: "/dev/myzero", CgroupPermissions: "r"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(devices, []container.DeviceMapping{
+		{PathOnHost: "/dev/null", PathInContainer: "/dev/null", CgroupPermissions: "rwm"},
+		{PathOnHost: "/dev/zero", PathInContainer: "/dev/myzero", CgroupPermissions: "r"},
+	}))
+
+	testCases := []struct {
+		device   container.DeviceMapping
+		expected string
+	}{
+		{container.DeviceMapping{PathInContainer: "/dev/null"}, "device path on the host cannot be empty"},
+		{container.DeviceMapping{PathOnHost: "/dev/null", PathInContainer: "dev/null"}, "device path in the container dev/null must be absolute"},
+		{container.DeviceMapping{PathOnHost: "/dev/null", CgroupPermissions: "rwx"}, `invalid cgroup permissions "rwx" for device /dev/null`},
+		{container.DeviceMapping{PathOnHost: "/dev/null", CgroupPermissions: "rr"}, `invalid cgroup permissions "rr" for device /dev/null`},
+		{container.DeviceMapping{PathOnHost: "/dev/no-such-device"}, "error gathering device information"},
+	}
+	for _, tc := range testCases {
+		_, err := verifyDevicesAdd([]container.DeviceMapping{tc.device})
+		assert.Check(t, is.ErrorContains(err, tc.expected), tc.device)
+	}
+}
+
+func TestDeviceAllowed(t *testing.T) {
+	rules, err := deviceCgroupRules(nil, []string{"c 189:* rmw"})
+	assert.NilError(t, err)
+
+	// /dev/null is allowed by default
+	assert.Check(t, deviceAllowed(rules, "c", 1, 3))
+	assert.Check(t, deviceAllowed(rules, "c", 189, 12))
+	assert.Check(t, !deviceAllowed(rules, "b", 189, 12))
+	assert.Check(t, !deviceAllowed(rules, "c", 1, 7))
+
+	rules, err = deviceCgroupRules([]container.DeviceMapping{
+		{PathOnHost: "/dev/full", PathInContainer: "/dev/myfull", CgroupPermissions: "rwm"},
+		{PathOnHost: "/dev/no-such-device", PathInContainer: "/dev/gone", CgroupPermissions: "rwm"},
+	}, nil)
+	assert.NilError(t, err)
+	assert.Check(t, deviceAllowed(rules, "c", 1, 7))
+}
+
+func TestFormatDeviceRule(t *testing.T) {
+	rules := oci.DefaultLinuxSpec().Linux.Resources.Devices
+	assert.Check(t, is.Equal(formatDeviceRule(rules[0]), "a *:* rwm"))
+
+	rules, err := oci.AppendDevicePermissionsFromCgroupRules(nil, []string{"c 189:* rw"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(formatDeviceRule(rules[0]), "c 189:* rw"))
+}
+
+// startInRootfs starts a process in a new mount namespace, in which rootfs is
+// the root, and returns its pid.
+func startInRootfs(t *testing.T, rootfs string) (int, func()) {
+	for _, c := range []string{"unshare", "pivot_root"} {
+		if _, err := exec.LookPath(c); err != nil {
+			t.Skipf("skipping test that requires %s", c)
+		}
+	}
+	assert.NilError(t, os.MkdirAll(filepath.Join(rootfs, ".old"), 0755))
+	// The shell keeps running once the root is changed, and waits for its
+	// stdin to be closed.
+	cmd := exec.Command("unshare", "--mount", "--propagation", "private", "sh", "-c",
+		`mount --bind "$0" "$0" && cd "$0" && pivot_root . .old && cd / && echo ready && read x`, rootfs)
+	stdin, err := cmd.StdinPipe()
+	assert.NilError(t, err)
+	stdout, err := cmd.StdoutPipe()
+	assert.NilError(t, err)
+	assert.NilError(t, cmd.Start())
+	stop := func() {
+		stdin.Close()
+		cmd.Wait()
+	}
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil || line != "ready\n" {
+		stop()
+		t.Fatalf("failed to start a process in %s: %q %v", rootfs, line, err)
+	}
+	return cmd.Process.Pid, stop
+}
+
+func TestAddRemoveDevice(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "skipping test that requires root")
+
+	root, err := ioutil.TempDir("", "test-add-remove-device")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+	cgroupPath := filepath.Join(root, "cgroup")
+	assert.NilError(t, os.Mkdir(cgroupPath, 0755))
+	rootfs := filepath.Join(root, "rootfs")
+	pid, stop := startInRootfs(t, rootfs)
+	defer stop()
+
+	device := container.DeviceMapping{PathOnHost: "/dev/full", PathInContainer: "/dev/serial/myfull", CgroupPermissions: "rw"}
+	_, _, err = addDevice(cgroupPath, pid, device)
+	assert.NilError(t, err)
+	fi, err := os.Stat(filepath.Join(rootfs, "dev/serial/myfull"))
+	assert.NilError(t, err)
+	assert.Check(t, fi.Mode()&os.ModeCharDevice != 0)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0666)))
+	b, err := ioutil.ReadFile(filepath.Join(cgroupPath, "devices.allow"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), "c 1:7 rw"))
+
+	rules, err := deviceCgroupRules(nil, nil)
+	assert.NilError(t, err)
+	assert.NilError(t, removeDevice(cgroupPath, pid, device.PathInContainer, rules))
+	_, err = os.Stat(filepath.Join(rootfs, "dev/serial/myfull"))
+	assert.Check(t, os.IsNotExist(err))
+	b, err = ioutil.ReadFile(filepath.Join(cgroupPath, "devices.deny"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), "c 1:7 rwm"))
+
+	// devices that remain allowed are not denied
+	assert.NilError(t, os.Remove(filepath.Join(cgroupPath, "devices.deny")))
+	device = container.DeviceMapping{PathOnHost: "/dev/null", PathInContainer: "/dev/mynull", CgroupPermissions: "rwm"}
+	_, _, err = addDevice(cgroupPath, pid, device)
+	assert.NilError(t, err)
+	assert.NilError(t, removeDevice(cgroupPath, pid, device.PathInContainer, rules))
+	_, err = os.Stat(filepath.Join(cgroupPath, "devices.deny"))
+	assert.Check(t, os.IsNotExist(err))
+
+	// removing a device that is already gone is not an error
+	assert.NilError(t, removeDevice(cgroupPath, pid, "/dev/gone", rules))
+
+	// symlinks are not followed, even to paths in the container
+	hostDir := filepath.Join(root, "host")
+	assert.NilError(t, os.Mkdir(hostDir, 0755))
+	assert.NilError(t, os.Symlink(hostDir, filepath.Join(rootfs, "dev/escape")))
+	assert.NilError(t, os.Symlink("/dev/serial", filepath.Join(rootfs, "dev/link")))
+	device = container.DeviceMapping{PathOnHost: "/dev/full", PathInContainer: "/dev/serial/myfull", CgroupPermissions: "rw"}
+	_, _, err = addDevice(cgroupPath, pid, device)
+	assert.NilError(t, err)
+	device = container.DeviceMapping{PathOnHost: "/dev/full", PathInContainer: "/dev/escape", CgroupPermissions: "rw"}
+	_, created, err := addDevice(cgroupPath, pid, device)
+	assert.Check(t, is.ErrorContains(err, "file exists"))
+	assert.Check(t, is.Len(created, 0))
+	assert.NilError(t, removeDevice(cgroupPath, pid, "/dev/link", rules))
+	_, err = os.Stat(filepath.Join(rootfs, "dev/serial/myfull"))
+	assert.NilError(t, err)
+	// the symlink is resolved in the root of the container
+	device = container.DeviceMapping{PathOnHost: "/dev/full", PathInContainer: "/dev/escape/myfull", CgroupPermissions: "rw"}
+	_, _, err = addDevice(cgroupPath, pid, device)
+	assert.Check(t, err != nil)
+	_, err = os.Stat(filepath.Join(hostDir, "myfull"))
+	assert.Check(t, os.IsNotExist(err))
+}
+
+func TestAddDeviceRollback(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "skipping test that requires root")
+
+	root, err := ioutil.TempDir("", "test-add-device-rollback")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+	cgroupPath := filepath.Join(root, "cgroup")
+	assert.NilError(t, os.Mkdir(cgroupPath, 0755))
+	rootfs := filepath.Join(root, "rootfs")
+	pid, stop := startInRootfs(t, rootfs)
+	defer stop()
+	assert.NilError(t, os.MkdirAll(filepath.Join(rootfs, "dev"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(rootfs, "dev/existing"), nil, 0644))
+
+	// paths which exist in the container are reported
+	resp, err := updateDeviceNodes(pid, deviceNodesRequest{Check: []string{"/dev/existing", "/dev/missing"}})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(resp.Existing, []string{"/dev/existing"}))
+
+	rules, err := deviceCgroupRules(nil, nil)
+	assert.NilError(t, err)
+	var (
+		allowed []specs.LinuxDeviceCgroup
+		created []string
+	)
+	for _, device := range []container.DeviceMapping{
+		{PathOnHost: "/dev/full", PathInContainer: "/dev/myfull", CgroupPermissions: "rw"},
+		{PathOnHost: "/dev/null", PathInContainer: "/dev/existing", CgroupPermissions: "rwm"},
+	} {
+		a, c, err := addDevice(cgroupPath, pid, device)
+		allowed = append(allowed, a...)
+		created = append(created, c...)
+		if err != nil {
+			break
+		}
+	}
+	assert.Check(t, is.DeepEqual(created, []string{"/dev/myfull"}))
+	assert.Check(t, is.Len(allowed, 2))
+
+	assert.NilError(t, os.Remove(filepath.Join(cgroupPath, "devices.allow")))
+	rollbackAddedDevices(&containerpkg.Container{}, cgroupPath, pid, allowed, created, rules)
+	_, err = os.Stat(filepath.Join(rootfs, "dev/myfull"))
+	assert.Check(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(rootfs, "dev/existing"))
+	assert.Check(t, err, "the nodes which were not created must be kept")
+	// /dev/null is still allowed, so only /dev/full is denied
+	b, err := ioutil.ReadFile(filepath.Join(cgroupPath, "devices.deny"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), "c 1:7 rw"))
+}
@@ -0,0 +1,36 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestUpdateDeviceMappings(t *testing.T) {
+	null := container.DeviceMapping{PathOnHost: "/dev/null", PathInContainer: "/dev/mynull", CgroupPermissions: "rwm"}
+	zero := container.DeviceMapping{PathOnHost: "/dev/zero", PathInContainer: "/dev/zero", CgroupPermissions: "r"}
+	hostConfig := &container.HostConfig{}
+	hostConfig.Devices = []container.DeviceMapping{null}
+	backup := *hostConfig
+
+	removed, err := updateDeviceMappings(hostConfig, []container.DeviceMapping{zero}, []string{"/dev/mynull"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(removed, []container.DeviceMapping{null}))
+	assert.Check(t, is.DeepEqual(hostConfig.Devices, []container.DeviceMapping{zero}))
+	assert.Check(t, is.DeepEqual(backup.Devices, []container.DeviceMapping{null}))
+
+	// a device can be replaced in a single update
+	replacement := container.DeviceMapping{PathOnHost: "/dev/full", PathInContainer: "/dev/zero", CgroupPermissions: "rw"}
+	removed, err = updateDeviceMappings(hostConfig, []container.DeviceMapping{replacement}, []string{"/dev/zero"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(removed, []container.DeviceMapping{zero}))
+	assert.Check(t, is.DeepEqual(hostConfig.Devices, []container.DeviceMapping{replacement}))
+
+	_, err = updateDeviceMappings(hostConfig, nil, []string{"/dev/mynull"})
+	assert.Check(t, is.Error(err, "no device is mapped to /dev/mynull in the container"))
+	_, err = updateDeviceMappings(hostConfig, []container.DeviceMapping{zero}, nil)
+	assert.Check(t, is.Error(err, "a device is already mapped to /dev/zero in the container"))
+	assert.Check(t, is.DeepEqual(hostConfig.Devices, []container.DeviceMapping{replacement}))
+}
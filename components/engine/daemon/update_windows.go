@@ -1,7 +1,10 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"errors"
+
 	"github.com/docker/docker/api/types/container"
+	containerpkg "github.com/docker/docker/container"
 	libcontainerdtypes "github.com/docker/docker/libcontainerd/types"
 )
 
@@ -9,3 +12,14 @@ func toContainerdResources(resources container.Resources) *libcontainerdtypes.Re
 	// We don't support update, so do nothing
 	return nil
 }
+
+func verifyDevicesAdd(devices []container.DeviceMapping) ([]container.DeviceMapping, error) {
+	if len(devices) > 0 {
+		return nil, errors.New("adding devices to a container is not supported on Windows")
+	}
+	return nil, nil
+}
+
+func (daemon *Daemon) updateDevices(c *containerpkg.Container, add, remove []container.DeviceMapping) error {
+	return nil
+}
@@ -0,0 +1,80 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/sirupsen/logrus"
+)
+
+// containerIDMapping returns the user namespace mapping of a container: its
+// own if it was created with --userns=auto, or the one of the daemon.
+func (daemon *Daemon) containerIDMapping(c *container.Container) *idtools.IdentityMapping {
+	if c.UIDMaps != nil {
+		return idtools.NewIDMappingsFromMaps(c.UIDMaps, c.GIDMaps)
+	}
+	return daemon.idMapping
+}
+
+// idRangeAllocator returns the allocator of the ranges of subordinate IDs of
+// the containers created with --userns=auto. It is created when it is first
+// needed, as the user owning the ranges may have to be created.
+func (daemon *Daemon) idRangeAllocator() (*idtools.RangeAllocator, error) {
+	daemon.idRangesMu.Lock()
+	defer daemon.idRangesMu.Unlock()
+
+	if daemon.idRanges == nil {
+		a, err := daemon.newIDRangeAllocator()
+		if err != nil {
+			return nil, err
+		}
+		daemon.idRanges = a
+	}
+	return daemon.idRanges, nil
+}
+
+// allocateIDMapping gives a container created with --userns=auto its own
+// range of subordinate IDs.
+func (daemon *Daemon) allocateIDMapping(c *container.Container) error {
+	if !c.HostConfig.UsernsMode.IsAuto() {
+		return nil
+	}
+	a, err := daemon.idRangeAllocator()
+	if err != nil {
+		return err
+	}
+	m, err := a.Allocate()
+	if err != nil {
+		return err
+	}
+	c.UIDMaps = m.UIDs()
+	c.GIDMaps = m.GIDs()
+	return nil
+}
+
+// reserveIDMapping marks the range of subordinate IDs of a container which
+// was allocated before the daemon restarted as in use.
+func (daemon *Daemon) reserveIDMapping(c *container.Container) {
+	if c.UIDMaps == nil {
+		return
+	}
+	a, err := daemon.idRangeAllocator()
+	if err == nil {
+		err = a.Reserve(daemon.containerIDMapping(c))
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Error("Failed to reserve the user namespace mapping of container")
+	}
+}
+
+// releaseIDMapping frees the range of subordinate IDs of a removed container.
+func (daemon *Daemon) releaseIDMapping(c *container.Container) {
+	if c.UIDMaps == nil {
+		return
+	}
+	daemon.idRangesMu.Lock()
+	a := daemon.idRanges
+	daemon.idRangesMu.Unlock()
+	if a != nil {
+		a.Release(daemon.containerIDMapping(c))
+	}
+}
@@ -0,0 +1,196 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/volume"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+func (daemon *Daemon) newIDRangeAllocator() (*idtools.RangeAllocator, error) {
+	pool := daemon.configStore.UsernsAutoPool
+	if pool == "" {
+		pool = defaultIDSpecifier
+	}
+	username, groupname, err := parseRemappedRoot(pool)
+	if err != nil {
+		return nil, err
+	}
+	size := daemon.configStore.UsernsAutoSize
+	if size == 0 {
+		size = config.DefaultUsernsAutoSize
+	}
+	a, err := idtools.NewRangeAllocator(username, groupname, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "Can't create ID ranges for --userns=auto")
+	}
+	logrus.Infof("User namespaces: containers with --userns=auto get ranges of %d IDs from the subuid/subgid ranges of: %s:%s", size, username, groupname)
+	return a, nil
+}
+
+// chownVolumeRoot gives the root directory of a volume mounted in a container
+// with its own user namespace mapping to the root of the container, if it is
+// owned by the root of the daemon, so that new volumes are writable in the
+// container. Volumes populated from the rootfs of the container already have
+// the IDs of its mapping. The volume is reached by the container through the
+// mounts set up by setupUsernsMounts.
+func (daemon *Daemon) chownVolumeRoot(c *container.Container, v volume.Volume) error {
+	if c.UIDMaps == nil {
+		return nil
+	}
+	id := stringid.GenerateNonCryptoID()
+	path, err := v.Mount(id)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := v.Unmount(id); err != nil {
+			logrus.Warnf("error while unmounting volume %s: %v", v.Name(), err)
+		}
+	}()
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	daemonRoot := daemon.idMapping.RootPair()
+	if st, ok := fi.Sys().(*syscall.Stat_t); !ok || int(st.Uid) != daemonRoot.UID || int(st.Gid) != daemonRoot.GID {
+		return nil
+	}
+	rootIDs := daemon.containerIDMapping(c).RootPair()
+	return os.Chown(path, rootIDs.UID, rootIDs.GID)
+}
+
+// usernsMountsDir returns the directory in which the rootfs and the bind
+// mount sources of a container with its own user namespace mapping are
+// mounted for the container to reach them.
+func (daemon *Daemon) usernsMountsDir(c *container.Container) string {
+	return filepath.Join(daemon.root, "userns", c.ID)
+}
+
+// setupUsernsMounts makes the rootfs and the bind mount sources of the
+// daemon directory reachable from the user namespace of a container which
+// has its own mapping, as the runtime mounts them from the user namespace of
+// the container. The directories of the daemon are private to the root of
+// the daemon, so instead of changing their modes, the rootfs and the sources
+// are bind-mounted in a directory of the container owned by the group of its
+// root, as the daemon root is with --userns-remap, and the paths of the spec
+// are replaced by the paths of these mounts.
+func (daemon *Daemon) setupUsernsMounts(c *container.Container, s *specs.Spec) (retErr error) {
+	if c.UIDMaps == nil {
+		return nil
+	}
+	// Mounts left by a daemon which did not clean up the container are
+	// removed first.
+	if err := daemon.removeUsernsMounts(c); err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			if err := daemon.removeUsernsMounts(c); err != nil {
+				logrus.WithError(err).WithField("container", c.ID).Warn("Error removing the user namespace mounts of container")
+			}
+		}
+	}()
+
+	rootGID := daemon.containerIDMapping(c).RootPair().GID
+	dir := daemon.usernsMountsDir(c)
+	if err := os.MkdirAll(filepath.Dir(dir), 0711); err != nil {
+		return err
+	}
+	mountsDir := filepath.Join(dir, "mounts")
+	for _, d := range []string{dir, mountsDir} {
+		if err := os.Mkdir(d, 0710); err != nil && !os.IsExist(err) {
+			return err
+		}
+		if err := os.Chown(d, 0, rootGID); err != nil {
+			return err
+		}
+		if err := os.Chmod(d, 0710); err != nil {
+			return err
+		}
+	}
+
+	if s.Root != nil && s.Root.Path != "" {
+		rootfs := filepath.Join(dir, "rootfs")
+		if err := bindMountTarget(s.Root.Path, rootfs); err != nil {
+			return errors.Wrap(err, "error mounting the rootfs in the user namespace mounts")
+		}
+		s.Root.Path = rootfs
+	}
+	root := filepath.Clean(daemon.root) + string(filepath.Separator)
+	for i, m := range s.Mounts {
+		if m.Type != "bind" || !strings.HasPrefix(filepath.Clean(m.Source), root) {
+			continue
+		}
+		target := filepath.Join(mountsDir, strconv.Itoa(i))
+		if err := bindMountTarget(m.Source, target); err != nil {
+			return errors.Wrapf(err, "error mounting %s in the user namespace mounts", m.Source)
+		}
+		s.Mounts[i].Source = target
+	}
+	return nil
+}
+
+// bindMountTarget recursively bind-mounts source on target, which is created
+// as a directory or as a file as source is.
+func bindMountTarget(source, target string) error {
+	fi, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		err = os.Mkdir(target, 0700)
+	} else {
+		var f *os.File
+		f, err = os.OpenFile(target, os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			f.Close()
+		}
+	}
+	if err != nil && !os.IsExist(err) {
+		return err
+	}
+	return mount.Mount(source, target, "none", "rbind")
+}
+
+// removeUsernsMounts removes the mounts set up by setupUsernsMounts. The
+// targets of the mounts are removed with os.Remove only, so that the content
+// of a source which could not be unmounted is never removed.
+func (daemon *Daemon) removeUsernsMounts(c *container.Container) error {
+	dir := daemon.usernsMountsDir(c)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	if err := mount.RecursiveUnmount(dir); err != nil {
+		return err
+	}
+	mountsDir := filepath.Join(dir, "mounts")
+	targets, err := ioutil.ReadDir(mountsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, t := range targets {
+		if err := os.Remove(filepath.Join(mountsDir, t.Name())); err != nil {
+			return err
+		}
+	}
+	for _, p := range []string{mountsDir, filepath.Join(dir, "rootfs"), dir} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
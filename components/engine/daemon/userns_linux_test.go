@@ -0,0 +1,81 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/idtools"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestUsernsMounts(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "skipping test that requires root")
+
+	root, err := ioutil.TempDir("", "userns-mounts-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	rootfs := filepath.Join(root, "overlay2", "abc", "merged")
+	volume := filepath.Join(root, "volumes", "v", "_data")
+	hostname := filepath.Join(root, "containers", "abc", "hostname")
+	for _, p := range []string{rootfs, volume, filepath.Dir(hostname)} {
+		assert.NilError(t, os.MkdirAll(p, 0700))
+	}
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(volume, "data"), []byte("data"), 0600))
+	assert.NilError(t, ioutil.WriteFile(hostname, []byte("abc"), 0644))
+
+	d := &Daemon{root: root}
+	c := &container.Container{
+		ID:      "abc",
+		UIDMaps: []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+		GIDMaps: []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+	}
+	s := &specs.Spec{
+		Root: &specs.Root{Path: rootfs},
+		Mounts: []specs.Mount{
+			{Destination: "/proc", Type: "proc", Source: "proc"},
+			{Destination: "/data", Type: "bind", Source: volume},
+			{Destination: "/etc/hostname", Type: "bind", Source: hostname},
+			{Destination: "/host", Type: "bind", Source: "/tmp"},
+		},
+	}
+	assert.NilError(t, d.setupUsernsMounts(c, s))
+
+	dir := filepath.Join(root, "userns", "abc")
+	fi, err := os.Stat(dir)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0710)))
+	assert.Check(t, is.Equal(fi.Sys().(*syscall.Stat_t).Gid, uint32(100000)))
+
+	assert.Check(t, is.Equal(s.Root.Path, filepath.Join(dir, "rootfs")))
+	assert.Check(t, is.Equal(s.Mounts[0].Source, "proc"))
+	assert.Check(t, is.Equal(s.Mounts[3].Source, "/tmp"), "sources outside of the daemon directory must not be changed")
+	data, err := ioutil.ReadFile(filepath.Join(s.Mounts[1].Source, "data"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "data"))
+	data, err = ioutil.ReadFile(s.Mounts[2].Source)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "abc"))
+
+	// The directories of the daemon keep their permissions.
+	for _, p := range []string{filepath.Join(root, "overlay2"), filepath.Join(root, "volumes"), filepath.Join(root, "containers")} {
+		fi, err := os.Stat(p)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0700)), p)
+	}
+
+	assert.NilError(t, d.removeUsernsMounts(c))
+	_, err = os.Stat(dir)
+	assert.Check(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(volume, "data"))
+	assert.Check(t, err, "the content of the sources must be kept")
+	_, err = os.Stat(hostname)
+	assert.Check(t, err)
+}
@@ -0,0 +1,23 @@
+// +build !linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"errors"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/volume"
+)
+
+func (daemon *Daemon) newIDRangeAllocator() (*idtools.RangeAllocator, error) {
+	return nil, errors.New("--userns=auto is only supported on Linux")
+}
+
+func (daemon *Daemon) removeUsernsMounts(c *container.Container) error {
+	return nil
+}
+
+func (daemon *Daemon) chownVolumeRoot(c *container.Container, v volume.Volume) error {
+	return nil
+}
@@ -475,6 +475,9 @@ func (s *mockReferenceStore) References(id digest.Digest) []reference.Named {
 func (s *mockReferenceStore) ReferencesByName(ref reference.Named) []refstore.Association {
 	return []refstore.Association{}
 }
+func (s *mockReferenceStore) Associations() []refstore.Association {
+	return []refstore.Association{}
+}
 func (s *mockReferenceStore) AddTag(ref reference.Named, id digest.Digest, force bool) error {
 	return nil
 }
@@ -208,11 +208,32 @@ func NewHistory(author, comment, createdBy string, isEmptyLayer bool) History {
 	}
 }
 
+// Export formats supported by an Exporter
+const (
+	// ExportFormatDocker is the format written by docker save
+	ExportFormatDocker = "docker"
+	// ExportFormatOCI is the OCI image layout format
+	ExportFormatOCI = "oci"
+)
+
+// ExportOptions holds parameters to save images
+type ExportOptions struct {
+	// Format is the format in which images are saved; an empty format
+	// is the same as ExportFormatDocker.
+	Format string
+	// ExcludeBase lists images whose layers are omitted from the archive.
+	// The archive can only be loaded where these layers are present.
+	ExcludeBase []string
+	// ExcludeLayers lists layers, by DiffID, which are omitted from the
+	// archive.
+	ExcludeLayers []layer.DiffID
+}
+
 // Exporter provides interface for loading and saving images
 type Exporter interface {
 	Load(io.ReadCloser, io.Writer, bool) error
 	// TODO: Load(net.Context, io.ReadCloser, <- chan StatusMessage) error
-	Save([]string, io.Writer) error
+	Save([]string, io.Writer, ExportOptions) error
 }
 
 // NewFromJSON creates an Image configuration from json.
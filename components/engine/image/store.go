@@ -3,6 +3,7 @@ package image // import "github.com/docker/docker/image"
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -24,7 +25,13 @@ type Store interface {
 	GetParent(id ID) (ID, error)
 	SetLastUpdated(id ID) error
 	GetLastUpdated(id ID) (time.Time, error)
+	SetLastUsed(id ID) error
+	GetLastUsed(id ID) (time.Time, error)
+	SetPackages(id ID, packages []byte) error
+	GetPackages(id ID) ([]byte, error)
 	Children(id ID) []ID
+	Corrupted() (map[ID]error, error)
+	RemoveCorrupted(id ID) error
 	Map() map[ID]*Image
 	Heads() map[ID]*Image
 	Len() int
@@ -294,6 +301,104 @@ func (is *store) GetLastUpdated(id ID) (time.Time, error) {
 	return time.Parse(time.RFC3339Nano, string(bytes))
 }
 
+// SetLastUsed time for the image ID to the current time. The last-used time
+// is updated whenever a container is created or started from the image.
+func (is *store) SetLastUsed(id ID) error {
+	lastUsed := []byte(time.Now().Format(time.RFC3339Nano))
+	return is.fs.SetMetadata(id.Digest(), "lastUsed", lastUsed)
+}
+
+// GetLastUsed time for the image ID
+func (is *store) GetLastUsed(id ID) (time.Time, error) {
+	bytes, err := is.fs.GetMetadata(id.Digest(), "lastUsed")
+	if err != nil || len(bytes) == 0 {
+		// Image was never used
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, string(bytes))
+}
+
+// SetPackages stores the inventory of the packages of the image ID, as
+// produced by the daemon.
+func (is *store) SetPackages(id ID, packages []byte) error {
+	return is.fs.SetMetadata(id.Digest(), "packages", packages)
+}
+
+// GetPackages returns the inventory of the packages of the image ID, or nil
+// if no inventory was stored.
+func (is *store) GetPackages(id ID) ([]byte, error) {
+	packages, err := is.fs.GetMetadata(id.Digest(), "packages")
+	if err != nil {
+		if os.IsNotExist(errors.Cause(err)) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return packages, nil
+}
+
+// Corrupted returns the images which are present in the storage backend but
+// could not be loaded, along with the reason they could not be loaded.
+func (is *store) Corrupted() (map[ID]error, error) {
+	is.RLock()
+	defer is.RUnlock()
+
+	corrupted := make(map[ID]error)
+	err := is.fs.Walk(func(dgst digest.Digest) error {
+		id := IDFromDigest(dgst)
+		if is.images[id] != nil {
+			return nil
+		}
+		corrupted[id] = is.checkImage(id)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return corrupted, nil
+}
+
+// RemoveCorrupted removes an image which could not be loaded from the
+// storage backend.
+func (is *store) RemoveCorrupted(id ID) error {
+	is.Lock()
+	defer is.Unlock()
+
+	if is.images[id] != nil {
+		return fmt.Errorf("image %s is not corrupted", id)
+	}
+	if is.checkImage(id) == nil {
+		return fmt.Errorf("image %s is not corrupted", id)
+	}
+	return is.fs.Delete(id.Digest())
+}
+
+// checkImage returns why an image which is not in the store could not be
+// loaded, or nil if it can be loaded now.
+func (is *store) checkImage(id ID) error {
+	img, err := is.Get(id)
+	if err != nil {
+		return err
+	}
+	chainID := img.RootFS.ChainID()
+	if chainID == "" {
+		return nil
+	}
+	if !system.IsOSSupported(img.OperatingSystem()) {
+		return system.ErrNotSupportedOperatingSystem
+	}
+	lgr, ok := is.lss[img.OperatingSystem()]
+	if !ok {
+		return fmt.Errorf("no layer store for operating system %q", img.OperatingSystem())
+	}
+	l, err := lgr.Get(chainID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get layer %s", chainID)
+	}
+	lgr.Release(l)
+	return nil
+}
+
 func (is *store) Children(id ID) []ID {
 	is.RLock()
 	defer is.RUnlock()
@@ -172,6 +172,56 @@ func TestGetAndSetLastUpdated(t *testing.T) {
 	assert.Check(t, cmp.Equal(updated.IsZero(), false))
 }
 
+func TestGetAndSetLastUsed(t *testing.T) {
+	store, cleanup := defaultImageStore(t)
+	defer cleanup()
+
+	id, err := store.Create([]byte(`{"comment": "abc1", "rootfs": {"type": "layers"}}`))
+	assert.NilError(t, err)
+
+	used, err := store.GetLastUsed(id)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(used.IsZero(), true))
+
+	assert.Check(t, store.SetLastUsed(id))
+
+	used, err = store.GetLastUsed(id)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(used.IsZero(), false))
+
+	updated, err := store.GetLastUpdated(id)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(updated.IsZero(), true))
+}
+
+func TestCorrupted(t *testing.T) {
+	fs, cleanup := defaultFSStoreBackend(t)
+	defer cleanup()
+
+	id1, err := fs.Set([]byte(`{"comment": "abc", "rootfs": {"type": "layers"}}`))
+	assert.NilError(t, err)
+	id2, err := fs.Set([]byte(`invalid`))
+	assert.NilError(t, err)
+
+	mlgrMap := make(map[string]LayerGetReleaser)
+	mlgrMap[runtime.GOOS] = &mockLayerGetReleaser{}
+	is, err := NewImageStore(fs, mlgrMap)
+	assert.NilError(t, err)
+
+	corrupted, err := is.Corrupted()
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Len(corrupted, 1))
+	assert.Check(t, corrupted[ID(id2)] != nil)
+
+	assert.Check(t, cmp.ErrorContains(is.RemoveCorrupted(ID(id1)), "not corrupted"))
+	assert.NilError(t, is.RemoveCorrupted(ID(id2)))
+
+	corrupted, err = is.Corrupted()
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Len(corrupted, 0))
+	assert.Check(t, cmp.Len(is.Map(), 1))
+}
+
 func TestStoreLen(t *testing.T) {
 	store, cleanup := defaultImageStore(t)
 	defer cleanup()
@@ -195,3 +245,21 @@ func (ls *mockLayerGetReleaser) Get(layer.ChainID) (layer.Layer, error) {
 func (ls *mockLayerGetReleaser) Release(layer.Layer) ([]layer.Metadata, error) {
 	return nil, nil
 }
+
+func TestGetAndSetPackages(t *testing.T) {
+	store, cleanup := defaultImageStore(t)
+	defer cleanup()
+
+	id, err := store.Create([]byte(`{"comment": "abc1", "rootfs": {"type": "layers"}}`))
+	assert.NilError(t, err)
+
+	packages, err := store.GetPackages(id)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Nil(packages))
+
+	assert.Check(t, store.SetPackages(id, []byte(`{"Packages":[]}`)))
+
+	packages, err = store.GetPackages(id)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(string(packages), `{"Packages":[]}`))
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"strings"
 
 	"github.com/containerd/containerd/platforms"
 	"github.com/docker/distribution"
@@ -25,6 +26,7 @@ import (
 	"github.com/docker/docker/pkg/symlink"
 	"github.com/docker/docker/pkg/system"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
 )
 
@@ -52,6 +54,9 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	manifestFile, err := os.Open(manifestPath)
 	if err != nil {
 		if os.IsNotExist(err) {
+			if _, err := os.Stat(filepath.Join(tmpDir, ocispec.ImageLayoutFile)); err == nil {
+				return l.ociLoad(tmpDir, outStream, progressOutput)
+			}
 			return l.legacyLoad(tmpDir, outStream, progressOutput)
 		}
 		return err
@@ -62,6 +67,9 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	if err := json.NewDecoder(manifestFile).Decode(&manifest); err != nil {
 		return err
 	}
+	if err := l.checkManifestLayers(tmpDir, manifest); err != nil {
+		return err
+	}
 
 	var parentLinks []parentLink
 	var imageIDsStr string
@@ -162,6 +170,78 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	return nil
 }
 
+// checkManifestLayers verifies that the layers of every image in manifest
+// are either included in the archive, or present locally, before any image
+// is loaded.
+func (l *tarexporter) checkManifestLayers(tmpDir string, manifest []manifestItem) error {
+	for _, m := range manifest {
+		configPath, err := safePath(tmpDir, m.Config)
+		if err != nil {
+			return err
+		}
+		config, err := ioutil.ReadFile(configPath)
+		if err != nil {
+			return err
+		}
+		img, err := image.NewFromJSON(config)
+		if err != nil {
+			return err
+		}
+		if err := checkCompatibleOS(img.OS); err != nil {
+			return err
+		}
+		if expected, actual := len(m.Layers), len(img.RootFS.DiffIDs); expected != actual {
+			return fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
+		}
+		var layerPaths []string
+		for _, p := range m.Layers {
+			layerPath, err := safePath(tmpDir, p)
+			if err != nil {
+				return err
+			}
+			layerPaths = append(layerPaths, layerPath)
+		}
+		if err := l.checkLayersAvailable(image.ID(digest.FromBytes(config)), img, layerPaths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLayersAvailable returns an error listing the layers of img which are
+// neither in the archive at layerPaths, nor present in the layer store.
+// Archives saved without the layers of a base image can only be loaded once
+// the base image has been loaded.
+func (l *tarexporter) checkLayersAvailable(id image.ID, img *image.Image, layerPaths []string) error {
+	operatingSystem := img.OS
+	if operatingSystem == "" {
+		operatingSystem = runtime.GOOS
+	}
+	ls, ok := l.lss[operatingSystem]
+	if !ok {
+		return fmt.Errorf("no layer store for operating system %q", operatingSystem)
+	}
+
+	var missing []string
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for i, diffID := range img.RootFS.DiffIDs {
+		rootFS.Append(diffID)
+		if _, err := system.Lstat(layerPaths[i]); err == nil {
+			continue
+		}
+		if existing, err := ls.Get(rootFS.ChainID()); err == nil {
+			layer.ReleaseAndLog(ls, existing)
+			continue
+		}
+		missing = append(missing, diffID.String())
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("cannot load image %s: the archive does not include layers %s, and they do not exist locally; load the base image first", id, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 func (l *tarexporter) setParentID(id, parentID image.ID) error {
 	img, err := l.is.Get(id)
 	if err != nil {
@@ -0,0 +1,472 @@
+package tarexport // import "github.com/docker/docker/image/tarexport"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/system"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	ociIndexFileName = "index.json"
+	ociBlobsDirName  = "blobs"
+
+	// annotationImageName is the annotation used by containerd for the
+	// full name of an image in an OCI image layout.
+	annotationImageName = "io.containerd.image.name"
+	// annotationPartialLayout is the annotation of the manifests which
+	// reference layers that are not included in the OCI image layout.
+	annotationPartialLayout = "com.docker.image.layout.partial"
+)
+
+// saveOCI writes the images of the session as an OCI image layout. Every
+// tag of an image is added to the index as a separate descriptor, annotated
+// with the name of the image.
+func (s *saveSession) saveOCI(outStream io.Writer) error {
+	tempDir, err := ioutil.TempDir("", "docker-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	s.outDir = tempDir
+	s.diffIDPaths = make(map[layer.DiffID]string)
+	if err := os.MkdirAll(filepath.Join(tempDir, ociBlobsDirName, string(digest.Canonical)), 0755); err != nil {
+		return err
+	}
+
+	// Sort the images so that the index is reproducible
+	ids := make([]image.ID, 0, len(s.images))
+	for id := range s.images {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+	}
+	for _, id := range ids {
+		desc, err := s.saveOCIImage(id)
+		if err != nil {
+			return err
+		}
+
+		refs := s.images[id].refs
+		sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+		if len(refs) == 0 {
+			index.Manifests = append(index.Manifests, desc)
+		}
+		for _, ref := range refs {
+			d := desc
+			d.Annotations = map[string]string{
+				ocispec.AnnotationRefName: ref.Tag(),
+				annotationImageName:       ref.String(),
+			}
+			index.Manifests = append(index.Manifests, d)
+		}
+		s.tarexporter.loggerImgEvent.LogImageEvent(id.String(), id.String(), "save")
+	}
+
+	if err := writeOCIFile(filepath.Join(tempDir, ocispec.ImageLayoutFile), ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion}); err != nil {
+		return err
+	}
+	if err := writeOCIFile(filepath.Join(tempDir, ociIndexFileName), index); err != nil {
+		return err
+	}
+
+	fs, err := archive.Tar(tempDir, archive.Uncompressed)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	_, err = io.Copy(outStream, fs)
+	return err
+}
+
+// saveOCIImage writes the config, layers and manifest of an image as blobs,
+// and returns the descriptor of the manifest.
+func (s *saveSession) saveOCIImage(id image.ID) (ocispec.Descriptor, error) {
+	img := s.images[id].image
+	operatingSystem := img.OS
+	if operatingSystem == "" {
+		operatingSystem = runtime.GOOS
+	}
+
+	config, err := s.writeOCIBlob(img.RawJSON(), img.Created)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	config.MediaType = ocispec.MediaTypeImageConfig
+
+	manifest := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    config,
+		Layers:    []ocispec.Descriptor{},
+	}
+	for i, diffID := range img.RootFS.DiffIDs {
+		rootFS := *img.RootFS
+		rootFS.DiffIDs = rootFS.DiffIDs[:i+1]
+		if s.isExcluded(rootFS.ChainID(), diffID) {
+			desc, err := s.excludedOCILayer(rootFS.ChainID(), operatingSystem)
+			if err != nil {
+				return ocispec.Descriptor{}, err
+			}
+			manifest.Layers = append(manifest.Layers, desc)
+			manifest.Annotations = map[string]string{annotationPartialLayout: "true"}
+			continue
+		}
+		desc, err := s.saveOCILayer(rootFS.ChainID(), operatingSystem, img.Created)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		manifest.Layers = append(manifest.Layers, desc)
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc, err := s.writeOCIBlob(data, img.Created)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc.MediaType = ocispec.MediaTypeImageManifest
+	desc.Annotations = manifest.Annotations
+	desc.Platform = &ocispec.Platform{
+		Architecture: img.Architecture,
+		OS:           operatingSystem,
+		OSVersion:    img.OSVersion,
+		OSFeatures:   img.OSFeatures,
+	}
+	return desc, nil
+}
+
+// saveOCILayer writes the uncompressed content of a layer as a blob. As the
+// digest of the uncompressed content is the DiffID of the layer, layers
+// shared between images are only written once.
+func (s *saveSession) saveOCILayer(id layer.ChainID, operatingSystem string, createdTime time.Time) (ocispec.Descriptor, error) {
+	l, err := s.lss[operatingSystem].Get(id)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.lss[operatingSystem], l)
+
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayer,
+		Digest:    digest.Digest(l.DiffID()),
+	}
+	if err := desc.Digest.Validate(); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	blobPath, exists := s.diffIDPaths[l.DiffID()]
+	if !exists {
+		blobPath = s.ociBlobPath(desc.Digest)
+
+		// Use system.CreateSequential rather than os.Create. This ensures sequential
+		// file access on Windows to avoid eating into MM standby list.
+		// On Linux, this equates to a regular os.Create.
+		blobFile, err := system.CreateSequential(blobPath)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		defer blobFile.Close()
+
+		arch, err := l.TarStream()
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		defer arch.Close()
+
+		if _, err := io.Copy(blobFile, arch); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		if err := system.Chtimes(blobPath, createdTime, createdTime); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		s.diffIDPaths[l.DiffID()] = blobPath
+	}
+
+	fi, err := os.Stat(blobPath)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc.Size = fi.Size()
+	return desc, nil
+}
+
+// excludedOCILayer returns the descriptor of a layer which is not included
+// in the layout. The descriptor has the digest of the uncompressed archive of
+// the layer, so that it can be verified where the layer is present, and the
+// non-distributable media type, which tells consumers that the blob may be
+// absent from the layout. The size of the archive is not recorded by the
+// layer store, and computing it requires reading the whole layer, so the size
+// is the size of the content of the layer.
+func (s *saveSession) excludedOCILayer(id layer.ChainID, operatingSystem string) (ocispec.Descriptor, error) {
+	l, err := s.lss[operatingSystem].Get(id)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.lss[operatingSystem], l)
+
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayerNonDistributable,
+		Digest:    digest.Digest(l.DiffID()),
+	}
+	if err := desc.Digest.Validate(); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	if desc.Size, err = l.DiffSize(); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return desc, nil
+}
+
+func (s *saveSession) writeOCIBlob(data []byte, createdTime time.Time) (ocispec.Descriptor, error) {
+	dgst := digest.FromBytes(data)
+	blobPath := s.ociBlobPath(dgst)
+	if err := ioutil.WriteFile(blobPath, data, 0644); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := system.Chtimes(blobPath, createdTime, createdTime); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return ocispec.Descriptor{Digest: dgst, Size: int64(len(data))}, nil
+}
+
+func (s *saveSession) ociBlobPath(dgst digest.Digest) string {
+	return filepath.Join(s.outDir, ociBlobsDirName, dgst.Algorithm().String(), dgst.Hex())
+}
+
+func writeOCIFile(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	return system.Chtimes(path, time.Unix(0, 0), time.Unix(0, 0))
+}
+
+// ociLoad loads the images referenced by the index of an OCI image layout.
+// Images are tagged using the name annotations of the index.
+func (l *tarexporter) ociLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
+	var layout ocispec.ImageLayout
+	if err := readOCIFile(tmpDir, ocispec.ImageLayoutFile, &layout); err != nil {
+		return err
+	}
+	if layout.Version != ocispec.ImageLayoutVersion {
+		return fmt.Errorf("unsupported OCI image layout version %q", layout.Version)
+	}
+
+	var index ocispec.Index
+	if err := readOCIFile(tmpDir, ociIndexFileName, &index); err != nil {
+		return err
+	}
+
+	loaded := make(map[digest.Digest]image.ID)
+	for _, desc := range index.Manifests {
+		manifestDesc, err := resolveOCIManifest(tmpDir, desc)
+		if err != nil {
+			return err
+		}
+
+		imgID, ok := loaded[manifestDesc.Digest]
+		if !ok {
+			imgID, err = l.ociLoadImage(tmpDir, manifestDesc, progressOutput)
+			if err != nil {
+				return err
+			}
+			loaded[manifestDesc.Digest] = imgID
+			l.loggerImgEvent.LogImageEvent(imgID.String(), imgID.String(), "load")
+		}
+
+		ref, ok := ociImageName(desc.Annotations)
+		if !ok {
+			fmt.Fprintf(outStream, "Loaded image ID: %s\n", imgID)
+			continue
+		}
+		l.setLoadedTag(ref, imgID.Digest(), outStream)
+		fmt.Fprintf(outStream, "Loaded image: %s\n", reference.FamiliarString(ref))
+	}
+	return nil
+}
+
+// resolveOCIManifest returns the descriptor of the image manifest for the
+// current platform referenced by desc, which is either the descriptor of an
+// image manifest, or of an index of manifests for multiple platforms.
+func resolveOCIManifest(tmpDir string, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageManifest, schema2.MediaTypeManifest:
+		return desc, nil
+	case ocispec.MediaTypeImageIndex, manifestlist.MediaTypeManifestList:
+	default:
+		return ocispec.Descriptor{}, fmt.Errorf("unsupported media type %q for %s", desc.MediaType, desc.Digest)
+	}
+
+	var index ocispec.Index
+	if err := readOCIBlob(tmpDir, desc, &index); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	matcher := platforms.Default()
+	var candidates []ocispec.Descriptor
+	for _, m := range index.Manifests {
+		if m.Platform == nil || matcher.Match(*m.Platform) {
+			candidates = append(candidates, m)
+		}
+	}
+	if len(candidates) == 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("no image for platform %s found in %s", platforms.DefaultString(), desc.Digest)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Platform == nil || candidates[j].Platform == nil {
+			return candidates[j].Platform == nil && candidates[i].Platform != nil
+		}
+		return matcher.Less(*candidates[i].Platform, *candidates[j].Platform)
+	})
+	return resolveOCIManifest(tmpDir, candidates[0])
+}
+
+func (l *tarexporter) ociLoadImage(tmpDir string, desc ocispec.Descriptor, progressOutput progress.Output) (image.ID, error) {
+	var manifest ocispec.Manifest
+	if err := readOCIBlob(tmpDir, desc, &manifest); err != nil {
+		return "", err
+	}
+	config, err := readOCIBlobData(tmpDir, manifest.Config)
+	if err != nil {
+		return "", err
+	}
+	img, err := image.NewFromJSON(config)
+	if err != nil {
+		return "", err
+	}
+	if err := checkCompatibleOS(img.OS); err != nil {
+		return "", err
+	}
+	if expected, actual := len(manifest.Layers), len(img.RootFS.DiffIDs); expected != actual {
+		return "", fmt.Errorf("invalid manifest %s, layers length mismatch: expected %d, got %d", desc.Digest, expected, actual)
+	}
+
+	var layerPaths []string
+	for _, desc := range manifest.Layers {
+		layerPath, err := ociBlobPath(tmpDir, desc.Digest)
+		if err != nil {
+			return "", err
+		}
+		layerPaths = append(layerPaths, layerPath)
+	}
+	// The layers omitted from partial layouts must exist locally.
+	if err := l.checkLayersAvailable(image.ID(digest.FromBytes(config)), img, layerPaths); err != nil {
+		return "", err
+	}
+
+	operatingSystem := img.OS
+	if operatingSystem == "" {
+		operatingSystem = runtime.GOOS
+	}
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for i, diffID := range img.RootFS.DiffIDs {
+		r := rootFS
+		r.Append(diffID)
+		newLayer, err := l.lss[operatingSystem].Get(r.ChainID())
+		if err != nil {
+			newLayer, err = l.loadLayer(layerPaths[i], rootFS, diffID.String(), operatingSystem, distribution.Descriptor{}, progressOutput)
+			if err != nil {
+				return "", err
+			}
+		}
+		defer layer.ReleaseAndLog(l.lss[operatingSystem], newLayer)
+		if expected, actual := diffID, newLayer.DiffID(); expected != actual {
+			return "", fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
+		}
+		rootFS.Append(diffID)
+	}
+
+	return l.is.Create(config)
+}
+
+// ociImageName returns the name of an image from the annotations of its
+// descriptor. The containerd image name annotation is preferred, as the OCI
+// reference name annotation is commonly only a tag.
+func ociImageName(annotations map[string]string) (reference.NamedTagged, bool) {
+	for _, key := range []string{annotationImageName, ocispec.AnnotationRefName} {
+		name, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		named, err := reference.ParseNormalizedNamed(name)
+		if err != nil {
+			continue
+		}
+		if tagged, ok := named.(reference.NamedTagged); ok {
+			return tagged, true
+		}
+	}
+	return nil, false
+}
+
+func ociBlobPath(tmpDir string, dgst digest.Digest) (string, error) {
+	if err := dgst.Validate(); err != nil {
+		return "", err
+	}
+	return safePath(tmpDir, filepath.Join(ociBlobsDirName, dgst.Algorithm().String(), dgst.Hex()))
+}
+
+func readOCIBlobData(tmpDir string, desc ocispec.Descriptor) ([]byte, error) {
+	blobPath, err := ociBlobPath(tmpDir, desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(blobPath)
+	if err != nil {
+		return nil, err
+	}
+	if actual := digest.FromBytes(data); actual != desc.Digest {
+		return nil, fmt.Errorf("invalid blob: expected digest %s, got %s", desc.Digest, actual)
+	}
+	return data, nil
+}
+
+func readOCIBlob(tmpDir string, desc ocispec.Descriptor, v interface{}) error {
+	data, err := readOCIBlobData(tmpDir, desc)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(json.Unmarshal(data, v), "invalid blob %s", desc.Digest)
+}
+
+func readOCIFile(tmpDir, name string, v interface{}) error {
+	p, err := safePath(tmpDir, name)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(json.Unmarshal(data, v), "invalid %s", name)
+}
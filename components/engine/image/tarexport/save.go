@@ -13,6 +13,7 @@ import (
 
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/image/v1"
 	"github.com/docker/docker/layer"
@@ -35,9 +36,19 @@ type saveSession struct {
 	images      map[image.ID]*imageDescriptor
 	savedLayers map[string]struct{}
 	diffIDPaths map[layer.DiffID]string // cache every diffID blob to avoid duplicates
+
+	// layers which are omitted from the archive
+	excludeChainIDs map[layer.ChainID]struct{}
+	excludeDiffIDs  map[layer.DiffID]struct{}
 }
 
-func (l *tarexporter) Save(names []string, outStream io.Writer) error {
+func (l *tarexporter) Save(names []string, outStream io.Writer, opts image.ExportOptions) error {
+	switch opts.Format {
+	case "", image.ExportFormatDocker, image.ExportFormatOCI:
+	default:
+		return errdefs.InvalidParameter(errors.Errorf("invalid export format %q", opts.Format))
+	}
+
 	images, err := l.parseNames(names)
 	if err != nil {
 		return err
@@ -45,7 +56,53 @@ func (l *tarexporter) Save(names []string, outStream io.Writer) error {
 
 	// Release all the image top layer references
 	defer l.releaseLayerReferences(images)
-	return (&saveSession{tarexporter: l, images: images}).save(outStream)
+	s := &saveSession{tarexporter: l, images: images}
+	if err := s.setExcludedLayers(opts); err != nil {
+		return err
+	}
+	if opts.Format == image.ExportFormatOCI {
+		return s.saveOCI(outStream)
+	}
+	return s.save(outStream)
+}
+
+// setExcludedLayers collects the layers which are omitted from the archive.
+// The layers of a base image are excluded by ChainID, so that a layer is
+// only omitted if it has the same parent layers as in the base image.
+func (s *saveSession) setExcludedLayers(opts image.ExportOptions) error {
+	s.excludeChainIDs = make(map[layer.ChainID]struct{})
+	s.excludeDiffIDs = make(map[layer.DiffID]struct{})
+	for _, diffID := range opts.ExcludeLayers {
+		s.excludeDiffIDs[diffID] = struct{}{}
+	}
+	if len(opts.ExcludeBase) == 0 {
+		return nil
+	}
+
+	bases, err := s.parseNames(opts.ExcludeBase)
+	if err != nil {
+		return errors.Wrap(err, "invalid base image")
+	}
+	defer s.releaseLayerReferences(bases)
+	for _, base := range bases {
+		rootFS := *base.image.RootFS
+		rootFS.DiffIDs = nil
+		for _, diffID := range base.image.RootFS.DiffIDs {
+			rootFS.Append(diffID)
+			s.excludeChainIDs[rootFS.ChainID()] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// isExcluded returns whether the layer with the given ChainID and DiffID is
+// omitted from the archive.
+func (s *saveSession) isExcluded(chainID layer.ChainID, diffID layer.DiffID) bool {
+	if _, ok := s.excludeChainIDs[chainID]; ok {
+		return true
+	}
+	_, ok := s.excludeDiffIDs[diffID]
+	return ok
 }
 
 // parseNames will parse the image names to a map which contains image.ID to *imageDescriptor.
@@ -322,12 +379,15 @@ func (s *saveSession) saveImage(id image.ID) (map[layer.DiffID]distribution.Desc
 		}
 
 		v1Img.OS = img.OS
+		layers = append(layers, v1Img.ID)
+		parent = v1ID
+		if s.isExcluded(rootFS.ChainID(), img.RootFS.DiffIDs[i]) {
+			continue
+		}
 		src, err := s.saveLayer(rootFS.ChainID(), v1Img, img.Created)
 		if err != nil {
 			return nil, err
 		}
-		layers = append(layers, v1Img.ID)
-		parent = v1ID
 		if src.Digest != "" {
 			if foreignSrcs == nil {
 				foreignSrcs = make(map[layer.DiffID]distribution.Descriptor)
@@ -448,7 +448,7 @@ func (s *DockerDaemonSuite) TestDaemonEvents(c *check.C) {
 		" name=" + daemonName,
 		" registry-mirrors=[",
 		" runtimes=",
-		" shutdown-timeout=10)",
+		" shutdown-timeout=10, ",
 	}
 
 	for _, s := range expectedSubstrings {
@@ -308,3 +308,39 @@ func (s *DockerDaemonSuite) TestPruneImageLabel(c *check.C) {
 	c.Assert(strings.TrimSpace(out), checker.Not(checker.Contains), id1)
 	c.Assert(strings.TrimSpace(out), checker.Contains, id2)
 }
+
+func (s *DockerDaemonSuite) TestPruneImageUntilLastUsed(c *check.C) {
+	s.d.StartWithBusybox(c)
+
+	result := cli.BuildCmd(c, "test1", cli.Daemon(s.d),
+		build.WithDockerfile(`FROM busybox
+                 LABEL foo=bar`),
+		cli.WithFlags("-q"),
+	)
+	result.Assert(c, icmd.Success)
+	id1 := strings.TrimSpace(result.Combined())
+
+	result = cli.BuildCmd(c, "test2", cli.Daemon(s.d),
+		build.WithDockerfile(`FROM busybox
+                 LABEL bar=foo`),
+		cli.WithFlags("-q"),
+	)
+	result.Assert(c, icmd.Success)
+	id2 := strings.TrimSpace(result.Combined())
+
+	until := daemonUnixTime(c)
+
+	// test1 is used after the until-last-used timestamp, test2 is not
+	_, err := s.d.Cmd("run", "--rm", "test1", "true")
+	assert.NilError(c, err)
+
+	out, err := s.d.Cmd("image", "prune", "--force", "--all", "--filter", "until-last-used="+until)
+	assert.NilError(c, err)
+	c.Assert(strings.TrimSpace(out), checker.Not(checker.Contains), id1)
+	c.Assert(strings.TrimSpace(out), checker.Contains, id2)
+
+	out, err = s.d.Cmd("images", "-q", "--no-trunc")
+	assert.NilError(c, err)
+	c.Assert(strings.TrimSpace(out), checker.Contains, id1)
+	c.Assert(strings.TrimSpace(out), checker.Not(checker.Contains), id2)
+}
@@ -332,6 +332,49 @@ RUN cat somefile`
 	assert.Check(t, is.Contains(image.Config.Env, "bar=baz"))
 }
 
+func TestBuildSBOM(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "sbom was added in API v1.41")
+	skip.If(t, testEnv.DaemonInfo.OSType == "windows", "FIXME")
+	defer setupTest(t)()
+
+	dockerfile := `FROM busybox
+COPY installed /lib/apk/db/installed`
+	ctx := context.Background()
+	source := fakecontext.New(t, "",
+		fakecontext.WithDockerfile(dockerfile),
+		fakecontext.WithFile("installed", "P:musl\nV:1.1.24-r2\nA:x86_64\n"))
+	defer source.Close()
+
+	apiclient := testEnv.APIClient()
+	resp, err := apiclient.ImageBuild(ctx,
+		source.AsTarReader(t),
+		types.ImageBuildOptions{
+			Remove:      true,
+			ForceRemove: true,
+			SBOM:        true,
+		})
+	assert.NilError(t, err)
+	out := bytes.NewBuffer(nil)
+	_, err = io.Copy(out, resp.Body)
+	resp.Body.Close()
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(out.String(), "Recorded 1 packages"))
+
+	imageIDs, err := getImageIDsFromBuild(out.Bytes())
+	assert.NilError(t, err)
+	assert.Assert(t, len(imageIDs) > 0)
+
+	inventory, err := apiclient.ImagePackages(ctx, imageIDs[len(imageIDs)-1])
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(inventory.Packages, 1))
+	assert.Check(t, is.Equal(inventory.Packages[0].Name, "musl"))
+
+	// The inventory recorded at build time is returned.
+	again, err := apiclient.ImagePackages(ctx, imageIDs[len(imageIDs)-1])
+	assert.NilError(t, err)
+	assert.Check(t, again.Created.Equal(inventory.Created))
+}
+
 // #35403 #36122
 func TestBuildUncleanTarFilenames(t *testing.T) {
 	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.37"), "broken in earlier versions")
@@ -0,0 +1,101 @@
+package container // import "github.com/docker/docker/integration/container"
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/integration/internal/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestContainerDebug(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "debug containers were added in API v1.41")
+
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(t, ctx, client, container.WithCmd("sh", "-c", "touch /debug-me && top"), func(c *container.TestContainerConfig) {
+		c.HostConfig.IpcMode = "shareable"
+	})
+	poll.WaitOn(t, container.IsInState(ctx, client, cID, "running"), poll.WithDelay(100*time.Millisecond))
+
+	// the debug container sees the processes, the hostname, and the root
+	// filesystem of the container
+	check := `ps | grep -q "[t]op" && [ "$(hostname)" = "` + cID[:12] + `" ] && test -f /debug/debug-me && exec sleep 60`
+	resp, err := client.ContainerDebug(ctx, cID, containertypes.DebugConfig{
+		Cmd:        []string{"sh", "-c", check},
+		RootfsPath: "/debug",
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(resp.Warnings, 0))
+
+	inspect, err := client.ContainerInspect(ctx, resp.ID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(inspect.Config.Labels["com.docker.debug.target"], cID))
+	assert.Check(t, inspect.HostConfig.AutoRemove)
+
+	waitC, errC := client.ContainerWait(ctx, resp.ID, containertypes.WaitConditionRemoved)
+	assert.NilError(t, client.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}))
+
+	// the debugged container cannot be removed while it is debugged
+	err = client.ContainerRemove(ctx, cID, types.ContainerRemoveOptions{Force: true})
+	assert.Check(t, is.ErrorContains(err, "while its filesystem is mounted"))
+
+	// the checks passed if the debug container is still running
+	time.Sleep(time.Second)
+	assert.NilError(t, client.ContainerKill(ctx, resp.ID, "SIGKILL"))
+	select {
+	case err := <-errC:
+		t.Fatal(err)
+	case wait := <-waitC:
+		assert.Check(t, is.Equal(wait.StatusCode, int64(137)))
+	}
+
+	// the root filesystem of the container is released with the debug container
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if err := client.ContainerRemove(ctx, cID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return poll.Continue("container is not removed: %v", err)
+		}
+		return poll.Success()
+	}, poll.WithDelay(100*time.Millisecond))
+}
+
+func TestContainerDebugNotRunning(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "debug containers were added in API v1.41")
+
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Create(t, ctx, client)
+	_, err := client.ContainerDebug(ctx, cID, containertypes.DebugConfig{})
+	assert.Check(t, is.ErrorContains(err, "is not running"))
+}
+
+func TestContainerDebugNotStarted(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "debug containers were added in API v1.41")
+
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(t, ctx, client)
+	poll.WaitOn(t, container.IsInState(ctx, client, cID, "running"), poll.WithDelay(100*time.Millisecond))
+	resp, err := client.ContainerDebug(ctx, cID, containertypes.DebugConfig{})
+	assert.NilError(t, err)
+
+	// the root filesystem of the container is only mounted once the debug
+	// container starts
+	err = client.ContainerRemove(ctx, cID, types.ContainerRemoveOptions{Force: true})
+	assert.NilError(t, err)
+	err = client.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{})
+	assert.Check(t, is.ErrorContains(err, ""))
+}
@@ -2,13 +2,17 @@ package container // import "github.com/docker/docker/integration/container"
 
 import (
 	"context"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/integration/internal/container"
 	"github.com/docker/docker/pkg/archive"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/poll"
 	"gotest.tools/skip"
 )
@@ -41,3 +45,24 @@ func TestDiff(t *testing.T) {
 	assert.NilError(t, err)
 	assert.DeepEqual(t, expected, items)
 }
+
+func TestDiffAgainstImage(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "diff against an image was added in API v1.41")
+	skip.If(t, testEnv.OSType == "windows", "FIXME")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(t, ctx, client, container.WithCmd("sh", "-c", `mkdir /foo; echo xyzzy > /foo/bar; chmod 600 /etc/passwd`))
+	poll.WaitOn(t, container.IsInState(ctx, client, cID, "exited"), poll.WithDelay(100*time.Millisecond))
+
+	items, err := client.ContainerDiffAgainst(ctx, cID, "busybox", types.DiffOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(items, 3))
+	assert.Check(t, is.Equal(items[0].Path, "/etc/passwd"))
+	assert.Check(t, is.Equal(items[0].Kind, uint8(archive.ChangeModify)))
+	assert.Check(t, is.Equal(items[0].New.Mode.Perm(), os.FileMode(0600)))
+	assert.Check(t, is.Equal(items[1].Path, "/foo"))
+	assert.Check(t, is.Equal(items[2].Path, "/foo/bar"))
+	assert.Check(t, is.Equal(items[2].Kind, uint8(archive.ChangeAdd)))
+}
@@ -1,17 +1,22 @@
 package container // import "github.com/docker/docker/integration/container"
 
 import (
+	"bytes"
 	"context"
 	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/docker/pkg/stdcopy"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
 	"gotest.tools/skip"
 )
 
@@ -132,3 +137,88 @@ func TestExecUser(t *testing.T) {
 
 	assert.Assert(t, is.Contains(result.Stdout(), "uid=1(daemon) gid=1(daemon)"), "exec command not running as uid/gid 1")
 }
+
+func TestExecLogAndHistory(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "exec logs and history were added in API v1.41")
+	skip.If(t, testEnv.OSType == "windows", "FIXME. Probably needs to wait for container to be in running state.")
+	defer setupTest(t)()
+	ctx := context.Background()
+	client := testEnv.APIClient()
+
+	cID := container.Run(t, ctx, client, container.WithCmd("top"))
+
+	logged := true
+	id, err := client.ContainerExecCreate(ctx, cID, types.ExecConfig{
+		User:         "1",
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          strslice.StrSlice([]string{"sh", "-c", "echo hello; echo oops >&2; exit 3"}),
+		Log:          &logged,
+	})
+	assert.NilError(t, err)
+	resp, err := client.ContainerExecAttach(ctx, id.ID, types.ExecStartCheck{})
+	assert.NilError(t, err)
+	_, err = ioutil.ReadAll(resp.Reader)
+	resp.Close()
+	assert.NilError(t, err)
+
+	// the output of the exec is recorded in the logs of the container, with
+	// the ID of the exec
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		body, err := client.ContainerLogs(ctx, cID, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Details: true})
+		if err != nil {
+			return poll.Error(err)
+		}
+		defer body.Close()
+		var stdout, stderr bytes.Buffer
+		if _, err := stdcopy.StdCopy(&stdout, &stderr, body); err != nil {
+			return poll.Error(err)
+		}
+		if !strings.Contains(stdout.String(), "exec-id="+id.ID+" hello") || !strings.Contains(stderr.String(), "exec-id="+id.ID+" oops") {
+			return poll.Continue("exec output not in the logs: stdout %q, stderr %q", stdout.String(), stderr.String())
+		}
+		return poll.Success()
+	}, poll.WithDelay(100*time.Millisecond))
+
+	// the exec is in the exec history of the container
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		inspect, err := client.ContainerInspect(ctx, cID)
+		if err != nil {
+			return poll.Error(err)
+		}
+		if len(inspect.ExecHistory) != 1 || inspect.ExecHistory[0].ExitCode == nil {
+			return poll.Continue("exec not ended in the exec history: %v", inspect.ExecHistory)
+		}
+		return poll.Success()
+	}, poll.WithDelay(100*time.Millisecond))
+
+	inspect, err := client.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	entry := inspect.ExecHistory[0]
+	assert.Check(t, is.Equal(id.ID, entry.ID))
+	assert.Check(t, is.DeepEqual([]string{"sh", "-c", "echo hello; echo oops >&2; exit 3"}, entry.Cmd))
+	assert.Check(t, is.Equal("1", entry.User))
+	assert.Check(t, entry.Caller != "", "no caller in the exec history")
+	assert.Check(t, entry.Logged)
+	assert.Check(t, is.Equal(3, *entry.ExitCode))
+	assert.Check(t, !entry.End.Before(entry.Start))
+}
+
+func TestExecLogUnsupportedDriver(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "exec logs and history were added in API v1.41")
+	skip.If(t, testEnv.OSType == "windows", "FIXME. Probably needs to wait for container to be in running state.")
+	defer setupTest(t)()
+	ctx := context.Background()
+	client := testEnv.APIClient()
+
+	// the local driver does not record the ID of the exec in the logs
+	cID := container.Run(t, ctx, client, container.WithCmd("top"), container.WithLogDriver("local"))
+
+	logged := true
+	_, err := client.ContainerExecCreate(ctx, cID, types.ExecConfig{
+		Cmd: strslice.StrSlice([]string{"true"}),
+		Log: &logged,
+	})
+	assert.Check(t, is.ErrorContains(err, "cannot be recorded with the local logging driver"))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+}
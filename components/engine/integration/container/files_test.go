@@ -0,0 +1,43 @@
+package container // import "github.com/docker/docker/integration/container"
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/integration/internal/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestContainerListFiles(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "listing files was added in API v1.41")
+	skip.If(t, testEnv.OSType == "windows", "FIXME")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(t, ctx, client, container.WithCmd("sh", "-c", `mkdir -p /foo/bar/baz && echo xyzzy > /foo/bar/file && ln -s /foo/bar /foo/link && chown 1000:1001 /foo/bar/file && sleep 600`))
+
+	entries, err := client.ContainerListFiles(ctx, cID, types.ListFilesOptions{Path: "/foo", Recursive: true})
+	assert.NilError(t, err)
+	var paths []string
+	for _, e := range entries {
+		paths = append(paths, e.Path)
+	}
+	assert.Check(t, is.DeepEqual(paths, []string{"/foo/bar", "/foo/bar/baz", "/foo/bar/file", "/foo/link"}))
+	assert.Check(t, is.Equal(entries[2].Size, int64(6)))
+	assert.Check(t, is.Equal(entries[2].UID, 1000))
+	assert.Check(t, is.Equal(entries[2].GID, 1001))
+	assert.Check(t, is.Equal(entries[3].LinkTarget, "/foo/bar"))
+
+	entries, err = client.ContainerListFiles(ctx, cID, types.ListFilesOptions{Path: "/foo", Recursive: true, Depth: 1})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(entries, 2))
+
+	_, err = client.ContainerListFiles(ctx, cID, types.ListFilesOptions{Path: "/no/such/file"})
+	assert.Check(t, errdefs.IsNotFound(err), "expected a not found error, got %v", err)
+}
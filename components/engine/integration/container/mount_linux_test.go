@@ -0,0 +1,50 @@
+package container // import "github.com/docker/docker/integration/container"
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/integration/internal/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestContainerMount(t *testing.T) {
+	skip.If(t, testEnv.IsRemoteDaemon, "cannot access the filesystem of a remote daemon")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Create(t, ctx, client, container.WithCmd("sh", "-c", "echo hello > /hello"))
+	err := client.ContainerStart(ctx, cID, types.ContainerStartOptions{})
+	assert.NilError(t, err)
+	status, errC := client.ContainerWait(ctx, cID, "")
+	select {
+	case err := <-errC:
+		assert.NilError(t, err)
+	case <-status:
+	}
+
+	path, err := client.ContainerMount(ctx, cID)
+	assert.NilError(t, err)
+	content, err := ioutil.ReadFile(filepath.Join(path, "hello"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("hello\n", string(content)))
+
+	// A mounted container cannot be removed, even by force
+	err = client.ContainerRemove(ctx, cID, types.ContainerRemoveOptions{Force: true})
+	assert.Check(t, errdefs.IsConflict(err), "expected a conflict, got %v", err)
+
+	err = client.ContainerUnmount(ctx, cID, types.UnmountOptions{})
+	assert.NilError(t, err)
+	err = client.ContainerUnmount(ctx, cID, types.UnmountOptions{})
+	assert.Check(t, errdefs.IsConflict(err), "expected a conflict, got %v", err)
+
+	err = client.ContainerRemove(ctx, cID, types.ContainerRemoveOptions{})
+	assert.NilError(t, err)
+}
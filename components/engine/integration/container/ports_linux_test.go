@@ -0,0 +1,78 @@
+package container // import "github.com/docker/docker/integration/container"
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestUpdatePorts(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "updating ports was added in API v1.41")
+
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(t, ctx, client)
+	poll.WaitOn(t, container.IsInState(ctx, client, cID, "running"), poll.WithDelay(100*time.Millisecond))
+	inspect, err := client.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	endpointID := inspect.NetworkSettings.Networks["bridge"].EndpointID
+
+	ports, err := client.ContainerUpdatePorts(ctx, cID, containertypes.PortsUpdateConfig{
+		Add: nat.PortMap{"80/tcp": {{HostIP: "127.0.0.1"}}},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(ports["80/tcp"], 1))
+	hostPort := ports["80/tcp"][0].HostPort
+	assert.Check(t, hostPort != "")
+
+	inspect, err = client.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(inspect.HostConfig.PortBindings, nat.PortMap{"80/tcp": {{HostIP: "127.0.0.1"}}}))
+	assert.Check(t, is.DeepEqual(inspect.NetworkSettings.Ports["80/tcp"], []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: hostPort}}))
+	// the container is not disconnected from its network
+	assert.Check(t, is.Equal(inspect.NetworkSettings.Networks["bridge"].EndpointID, endpointID))
+
+	// the ports which are kept stay mapped on the same host ports
+	ports, err = client.ContainerUpdatePorts(ctx, cID, containertypes.PortsUpdateConfig{
+		Add: nat.PortMap{"81/tcp": {{HostIP: "127.0.0.1"}}},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(ports["80/tcp"], []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: hostPort}}))
+	assert.Check(t, is.Len(ports["81/tcp"], 1))
+	ports, err = client.ContainerUpdatePorts(ctx, cID, containertypes.PortsUpdateConfig{
+		Remove: []nat.Port{"81/tcp"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(ports["80/tcp"], []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: hostPort}}))
+	assert.Check(t, is.Len(ports["81/tcp"], 0))
+
+	// the host port of the container cannot be published by another container
+	other := container.Run(t, ctx, client)
+	poll.WaitOn(t, container.IsInState(ctx, client, other, "running"), poll.WithDelay(100*time.Millisecond))
+	_, err = client.ContainerUpdatePorts(ctx, other, containertypes.PortsUpdateConfig{
+		Add: nat.PortMap{"8080/tcp": {{HostPort: hostPort}}},
+	})
+	assert.Check(t, errdefs.IsConflict(err))
+
+	ports, err = client.ContainerUpdatePorts(ctx, cID, containertypes.PortsUpdateConfig{
+		Remove: []nat.Port{"80/tcp"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(ports["80/tcp"], 0))
+
+	inspect, err = client.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(inspect.HostConfig.PortBindings, 0))
+}
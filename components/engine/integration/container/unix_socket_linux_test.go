@@ -0,0 +1,77 @@
+package container // import "github.com/docker/docker/integration/container"
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/docker/internal/test/daemon"
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestUnixSocketBindings(t *testing.T) {
+	skip.If(t, testEnv.IsRemoteDaemon, "cannot start daemon on remote test run")
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "Unix socket bindings were added in API v1.41")
+
+	d := daemon.New(t)
+	d.StartWithBusybox(t, "--iptables=false")
+	defer d.Stop(t)
+	client := d.NewClientT(t)
+	ctx := context.Background()
+
+	socket := filepath.Join(d.ExecRoot(), "sockets", "app.sock")
+
+	cID := container.Run(t, ctx, client, container.WithCmd("sh", "-c", "while true; do echo hello | nc -lp 8080; done"), func(c *container.TestContainerConfig) {
+		c.HostConfig.UnixSocketBindings = map[nat.Port][]containertypes.UnixSocketBinding{
+			"8080/tcp": {{Path: socket, Mode: 0600}},
+		}
+	})
+	poll.WaitOn(t, container.IsInState(ctx, client, cID, "running"), poll.WithDelay(100*time.Millisecond))
+
+	fi, err := os.Stat(socket)
+	assert.NilError(t, err)
+	assert.Check(t, fi.Mode()&os.ModeSocket != 0)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0600)))
+
+	conn, err := net.Dial("unix", socket)
+	assert.NilError(t, err)
+	defer conn.Close()
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(line, "hello\n"))
+
+	// the socket cannot be used by another container
+	other := container.Create(t, ctx, client, func(c *container.TestContainerConfig) {
+		c.HostConfig.UnixSocketBindings = map[nat.Port][]containertypes.UnixSocketBinding{
+			"80/tcp": {{Path: socket}},
+		}
+	})
+	err = client.ContainerStart(ctx, other, types.ContainerStartOptions{})
+	assert.Check(t, is.ErrorContains(err, "the socket is used by container"))
+
+	// sockets can only be created in the directory of the daemon
+	_, err = client.ContainerCreate(ctx, &containertypes.Config{Image: "busybox"}, &containertypes.HostConfig{
+		UnixSocketBindings: map[nat.Port][]containertypes.UnixSocketBinding{
+			"80/tcp": {{Path: "/tmp/app.sock"}},
+		},
+	}, nil, "")
+	assert.Check(t, is.ErrorContains(err, "the socket must be in"))
+
+	// the socket is removed when the container stops
+	assert.NilError(t, client.ContainerKill(ctx, cID, "SIGKILL"))
+	poll.WaitOn(t, container.IsInState(ctx, client, cID, "exited"), poll.WithDelay(100*time.Millisecond))
+	_, err = os.Stat(socket)
+	assert.Check(t, os.IsNotExist(err))
+}
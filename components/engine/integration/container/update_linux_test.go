@@ -8,6 +8,7 @@ import (
 	"time"
 
 	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/integration/internal/container"
 	"github.com/docker/docker/internal/test/request"
@@ -166,3 +167,52 @@ func TestUpdatePidsLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateDevices(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "device updates are not supported before API 1.41")
+	skip.If(t, testEnv.IsRemoteDaemon, "the device must exist on the daemon host")
+	skip.If(t, testEnv.IsUserNamespace())
+
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(t, ctx, client)
+	poll.WaitOn(t, container.IsInState(ctx, client, cID, "running"), poll.WithDelay(100*time.Millisecond))
+
+	_, err := client.ContainerUpdate(ctx, cID, containertypes.UpdateConfig{
+		DevicesAdd: []containertypes.DeviceMapping{
+			{PathOnHost: "/dev/full", PathInContainer: "/dev/myfull", CgroupPermissions: "rwm"},
+		},
+	})
+	assert.NilError(t, err)
+
+	inspect, err := client.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(inspect.HostConfig.Devices, []containertypes.DeviceMapping{
+		{PathOnHost: "/dev/full", PathInContainer: "/dev/myfull", CgroupPermissions: "rwm"},
+	}))
+
+	// writing to /dev/full fails with ENOSPC once the device is allowed
+	res, err := container.Exec(ctx, client, cID, []string{"sh", "-c", "echo > /dev/myfull"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(res.Stderr(), "No space left on device"))
+
+	_, err = client.ContainerUpdate(ctx, cID, containertypes.UpdateConfig{
+		DevicesRemove: []string{"/dev/myfull"},
+	})
+	assert.NilError(t, err)
+
+	inspect, err = client.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(inspect.HostConfig.Devices, 0))
+
+	res, err = container.Exec(ctx, client, cID, []string{"test", "-e", "/dev/myfull"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(res.ExitCode, 1))
+
+	_, err = client.ContainerUpdate(ctx, cID, containertypes.UpdateConfig{
+		DevicesRemove: []string{"/dev/myfull"},
+	})
+	assert.Check(t, is.ErrorContains(err, "no device is mapped to /dev/myfull in the container"))
+}
@@ -0,0 +1,59 @@
+package image // import "github.com/docker/docker/integration/image"
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/docker/pkg/archive"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestImageDiff(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "image diff was added in API v1.41")
+	skip.If(t, testEnv.OSType == "windows", "FIXME")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(t, ctx, client, container.WithCmd("sh", "-c", `mkdir /foo; echo xyzzy > /foo/bar; rm /etc/group; echo "user:x:1000:1000::/home/user:/bin/sh" >> /etc/passwd`))
+	poll.WaitOn(t, container.IsInState(ctx, client, cID, "exited"), poll.WithDelay(100*time.Millisecond))
+
+	commitResp, err := client.ContainerCommit(ctx, cID, types.ContainerCommitOptions{})
+	assert.NilError(t, err)
+
+	changes, err := client.ImageDiff(ctx, "busybox", commitResp.ID, types.DiffOptions{Content: true})
+	assert.NilError(t, err)
+
+	byPath := map[string]types.FileChange{}
+	for _, c := range changes {
+		byPath[c.Path] = c
+	}
+	assert.Check(t, is.Equal(byPath["/foo"].Kind, uint8(archive.ChangeAdd)))
+	assert.Check(t, is.Equal(byPath["/foo/bar"].Kind, uint8(archive.ChangeAdd)))
+	assert.Check(t, is.Equal(byPath["/foo/bar"].New.Size, int64(6)))
+	assert.Check(t, is.Equal(byPath["/etc/group"].Kind, uint8(archive.ChangeDelete)))
+	passwd := byPath["/etc/passwd"]
+	assert.Check(t, is.Equal(passwd.Kind, uint8(archive.ChangeModify)))
+	assert.Check(t, strings.Contains(passwd.Diff, "\n+user:x:1000:1000::/home/user:/bin/sh\n"), passwd.Diff)
+
+	// Comparing in the other direction reverses the changes.
+	changes, err = client.ImageDiff(ctx, commitResp.ID, "busybox", types.DiffOptions{})
+	assert.NilError(t, err)
+	for _, c := range changes {
+		if c.Path == "/etc/group" {
+			assert.Check(t, is.Equal(c.Kind, uint8(archive.ChangeAdd)))
+		}
+	}
+
+	changes, err = client.ImageDiff(ctx, "busybox", "busybox", types.DiffOptions{})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(changes, 0))
+}
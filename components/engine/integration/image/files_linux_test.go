@@ -0,0 +1,46 @@
+package image // import "github.com/docker/docker/integration/image"
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestImageListFiles(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "listing files was added in API v1.41")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	entries, err := client.ImageListFiles(ctx, "busybox:latest", types.ListFilesOptions{Path: "/etc"})
+	assert.NilError(t, err)
+	var passwd *types.FileEntry
+	for i, e := range entries {
+		if e.Path == "/etc/passwd" {
+			passwd = &entries[i]
+		}
+	}
+	assert.Assert(t, passwd != nil, "expected /etc/passwd in %v", entries)
+	assert.Check(t, is.Equal(passwd.Name, "passwd"))
+	assert.Check(t, passwd.Size > 0)
+	assert.Check(t, passwd.Mode.IsRegular())
+
+	// A file is listed by itself
+	entries, err = client.ImageListFiles(ctx, "busybox:latest", types.ListFilesOptions{Path: "/bin/busybox"})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(entries, 1))
+	assert.Check(t, is.Equal(entries[0].Path, "/bin/busybox"))
+
+	_, err = client.ImageListFiles(ctx, "busybox:latest", types.ListFilesOptions{Path: "/no/such/file"})
+	assert.Check(t, errdefs.IsNotFound(err), "expected a not found error, got %v", err)
+
+	// Listing files does not leave the image mounted
+	err = client.ImageUnmount(ctx, "busybox:latest", types.UnmountOptions{})
+	assert.Check(t, errdefs.IsConflict(err), "expected the image not to be mounted, got %v", err)
+}
@@ -0,0 +1,51 @@
+package image // import "github.com/docker/docker/integration/image"
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestImageMount(t *testing.T) {
+	skip.If(t, testEnv.IsRemoteDaemon, "cannot access the filesystem of a remote daemon")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	path, err := client.ImageMount(ctx, "busybox:latest")
+	assert.NilError(t, err)
+	_, err = os.Stat(filepath.Join(path, "bin", "busybox"))
+	assert.NilError(t, err)
+
+	// The mount is read-only
+	err = ioutil.WriteFile(filepath.Join(path, "hello"), []byte("hello"), 0644)
+	assert.Check(t, err != nil, "expected the mount to be read-only")
+
+	// Mounts are reference counted
+	path2, err := client.ImageMount(ctx, "busybox:latest")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(path, path2))
+
+	insp, _, err := client.ImageInspectWithRaw(ctx, "busybox:latest")
+	assert.NilError(t, err)
+	_, err = client.ImageRemove(ctx, insp.ID, types.ImageRemoveOptions{Force: true})
+	assert.Check(t, errdefs.IsConflict(err), "expected a conflict, got %v", err)
+
+	err = client.ImageUnmount(ctx, "busybox:latest", types.UnmountOptions{})
+	assert.NilError(t, err)
+	_, err = os.Stat(filepath.Join(path, "bin", "busybox"))
+	assert.NilError(t, err)
+
+	err = client.ImageUnmount(ctx, "busybox:latest", types.UnmountOptions{})
+	assert.NilError(t, err)
+	_, err = os.Stat(filepath.Join(path, "bin", "busybox"))
+	assert.Check(t, os.IsNotExist(err), "expected the image to be unmounted, got %v", err)
+}
@@ -0,0 +1,39 @@
+package image // import "github.com/docker/docker/integration/image"
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/integration/internal/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestImagePackages(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "image packages was added in API v1.41")
+	skip.If(t, testEnv.OSType == "windows", "FIXME")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(t, ctx, client, container.WithCmd("sh", "-c", `mkdir -p /lib/apk/db && printf 'P:musl\nV:1.1.24-r2\nA:x86_64\n\nP:busybox\nV:1.31.1-r9\nA:x86_64\n' > /lib/apk/db/installed && printf 'ID=alpine\nVERSION_ID=3.11.3\n' > /etc/os-release`))
+	poll.WaitOn(t, container.IsInState(ctx, client, cID, "exited"), poll.WithDelay(100*time.Millisecond))
+
+	commitResp, err := client.ContainerCommit(ctx, cID, types.ContainerCommitOptions{})
+	assert.NilError(t, err)
+
+	inventory, err := client.ImagePackages(ctx, commitResp.ID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(inventory.ID, commitResp.ID))
+	assert.Check(t, is.Equal(inventory.OS, "alpine"))
+	assert.Check(t, is.Equal(inventory.OSVersion, "3.11.3"))
+	assert.Assert(t, is.Len(inventory.Packages, 2))
+	assert.Check(t, is.Equal(inventory.Packages[0].Name, "busybox"))
+	assert.Check(t, is.Equal(inventory.Packages[0].PURL, "pkg:apk/alpine/busybox@1.31.1-r9?arch=x86_64"))
+	assert.Check(t, is.Equal(inventory.Packages[1].Name, "musl"))
+}
@@ -0,0 +1,181 @@
+package image // import "github.com/docker/docker/integration/image"
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestSaveLoadOCILayout(t *testing.T) {
+	skip.If(t, testEnv.OSType == "windows", "TODO enable on windows")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	err := client.ImageTag(ctx, "busybox:latest", "test-oci-save:v1")
+	assert.NilError(t, err)
+	insp, _, err := client.ImageInspectWithRaw(ctx, "test-oci-save:v1")
+	assert.NilError(t, err)
+
+	rdr, err := client.ImageSave(ctx, []string{"test-oci-save:v1"}, types.ImageSaveOptions{Format: "oci"})
+	assert.NilError(t, err)
+	archive, err := ioutil.ReadAll(rdr)
+	rdr.Close()
+	assert.NilError(t, err)
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		if hdr.Name == ocispec.ImageLayoutFile || hdr.Name == "index.json" {
+			files[hdr.Name], err = ioutil.ReadAll(tr)
+			assert.NilError(t, err)
+		}
+	}
+	assert.Check(t, is.Contains(files, ocispec.ImageLayoutFile))
+
+	var index ocispec.Index
+	err = json.Unmarshal(files["index.json"], &index)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(index.Manifests, 1))
+	assert.Check(t, is.Equal(index.Manifests[0].MediaType, ocispec.MediaTypeImageManifest))
+	assert.Check(t, is.Equal(index.Manifests[0].Annotations[ocispec.AnnotationRefName], "v1"))
+
+	_, err = client.ImageRemove(ctx, "test-oci-save:v1", types.ImageRemoveOptions{})
+	assert.NilError(t, err)
+
+	resp, err := client.ImageLoad(ctx, ioutil.NopCloser(bytes.NewReader(archive)), true)
+	assert.NilError(t, err)
+	_, err = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NilError(t, err)
+
+	loaded, _, err := client.ImageInspectWithRaw(ctx, "test-oci-save:v1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(loaded.ID, insp.ID))
+}
+
+func TestSaveInvalidFormat(t *testing.T) {
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+
+	rdr, err := client.ImageSave(context.Background(), []string{"busybox:latest"}, types.ImageSaveOptions{Format: "invalid"})
+	if err == nil {
+		rdr.Close()
+	}
+	assert.Check(t, is.ErrorContains(err, "invalid export format"))
+}
+
+func TestSaveExcludeBase(t *testing.T) {
+	skip.If(t, testEnv.OSType == "windows", "TODO enable on windows")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	err := client.ImageTag(ctx, "busybox:latest", "test-exclude-base:v1")
+	assert.NilError(t, err)
+
+	rdr, err := client.ImageSave(ctx, []string{"test-exclude-base:v1"}, types.ImageSaveOptions{ExcludeBase: []string{"busybox:latest"}})
+	assert.NilError(t, err)
+	archive, err := ioutil.ReadAll(rdr)
+	rdr.Close()
+	assert.NilError(t, err)
+
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		assert.Check(t, !strings.HasSuffix(hdr.Name, "/layer.tar"), "unexpected layer %s in archive", hdr.Name)
+	}
+
+	_, err = client.ImageRemove(ctx, "test-exclude-base:v1", types.ImageRemoveOptions{})
+	assert.NilError(t, err)
+
+	// The layers of busybox are present, so the image can be loaded
+	resp, err := client.ImageLoad(ctx, ioutil.NopCloser(bytes.NewReader(archive)), true)
+	assert.NilError(t, err)
+	_, err = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NilError(t, err)
+
+	_, _, err = client.ImageInspectWithRaw(ctx, "test-exclude-base:v1")
+	assert.NilError(t, err)
+}
+
+func TestSaveExcludeBaseOCILayout(t *testing.T) {
+	skip.If(t, testEnv.OSType == "windows", "TODO enable on windows")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	err := client.ImageTag(ctx, "busybox:latest", "test-exclude-base-oci:v1")
+	assert.NilError(t, err)
+
+	rdr, err := client.ImageSave(ctx, []string{"test-exclude-base-oci:v1"}, types.ImageSaveOptions{Format: "oci", ExcludeBase: []string{"busybox:latest"}})
+	assert.NilError(t, err)
+	archive, err := ioutil.ReadAll(rdr)
+	rdr.Close()
+	assert.NilError(t, err)
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		data, err := ioutil.ReadAll(tr)
+		assert.NilError(t, err)
+		files[hdr.Name] = data
+	}
+
+	var index ocispec.Index
+	assert.NilError(t, json.Unmarshal(files["index.json"], &index))
+	assert.Assert(t, is.Len(index.Manifests, 1))
+	desc := index.Manifests[0]
+	assert.Check(t, is.Equal(desc.Annotations["com.docker.image.layout.partial"], "true"))
+
+	var manifest ocispec.Manifest
+	assert.NilError(t, json.Unmarshal(files["blobs/sha256/"+desc.Digest.Hex()], &manifest))
+	assert.Assert(t, len(manifest.Layers) > 0)
+	for _, l := range manifest.Layers {
+		// the excluded layers keep their digest and size, and are marked as
+		// absent from the layout
+		assert.Check(t, is.Equal(l.MediaType, ocispec.MediaTypeImageLayerNonDistributable))
+		assert.Check(t, l.Size > 0)
+		_, ok := files["blobs/sha256/"+l.Digest.Hex()]
+		assert.Check(t, !ok, "unexpected layer %s in archive", l.Digest)
+	}
+
+	_, err = client.ImageRemove(ctx, "test-exclude-base-oci:v1", types.ImageRemoveOptions{})
+	assert.NilError(t, err)
+
+	// The layers of busybox are present, so the image can be loaded
+	resp, err := client.ImageLoad(ctx, ioutil.NopCloser(bytes.NewReader(archive)), true)
+	assert.NilError(t, err)
+	_, err = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NilError(t, err)
+
+	_, _, err = client.ImageInspectWithRaw(ctx, "test-exclude-base-oci:v1")
+	assert.NilError(t, err)
+}
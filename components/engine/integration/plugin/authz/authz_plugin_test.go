@@ -414,7 +414,7 @@ func TestAuthzPluginEnsureContainerCopyToFrom(t *testing.T) {
 
 func imageSave(client client.APIClient, path, image string) error {
 	ctx := context.Background()
-	responseReader, err := client.ImageSave(ctx, []string{image})
+	responseReader, err := client.ImageSave(ctx, []string{image}, types.ImageSaveOptions{})
 	if err != nil {
 		return err
 	}
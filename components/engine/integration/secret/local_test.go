@@ -0,0 +1,76 @@
+package secret // import "github.com/docker/docker/integration/secret"
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestLocalSecretAndConfig(t *testing.T) {
+	skip.If(t, testEnv.DaemonInfo.OSType == "windows")
+	skip.If(t, testEnv.IsRemoteDaemon)
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "local secrets were added in API v1.41")
+	skip.If(t, testEnv.DaemonInfo.Swarm.LocalNodeState != swarmtypes.LocalNodeStateInactive, "the daemon is part of a swarm")
+
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	secretName := "test_secret_" + t.Name()
+	secretID := createSecret(ctx, t, client, secretName, []byte("s3cr3t"), nil)
+	defer client.SecretRemove(ctx, secretID)
+
+	config, err := client.ConfigCreate(ctx, swarmtypes.ConfigSpec{
+		Annotations: swarmtypes.Annotations{Name: "test_config_" + t.Name()},
+		Data:        []byte("debug = true"),
+	})
+	assert.NilError(t, err)
+	defer client.ConfigRemove(ctx, config.ID)
+
+	secrets, err := client.SecretList(ctx, types.SecretListOptions{
+		Filters: filters.NewArgs(filters.Arg("names", secretName)),
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(secrets, 1))
+
+	cID := container.Run(t, ctx, client,
+		container.WithCmd("cat", "/run/secrets/"+secretName, "/etc/app.conf"),
+		func(c *container.TestContainerConfig) {
+			c.HostConfig.Secrets = []*containertypes.SecretReference{{SecretName: secretName}}
+			c.HostConfig.Configs = []*containertypes.ConfigReference{{
+				ConfigID: config.ID,
+				File:     &containertypes.FileTarget{Name: "/etc/app.conf"},
+			}}
+		},
+	)
+	poll.WaitOn(t, container.IsSuccessful(ctx, client, cID))
+
+	out, err := client.ContainerLogs(ctx, cID, types.ContainerLogsOptions{ShowStdout: true})
+	assert.NilError(t, err)
+	defer out.Close()
+	var stdout bytes.Buffer
+	_, err = stdcopy.StdCopy(&stdout, ioutil.Discard, out)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(stdout.String(), "s3cr3tdebug = true"))
+
+	err = client.SecretRemove(ctx, secretName)
+	assert.Check(t, errdefs.IsInvalidParameter(err), "removing a secret used by a container must fail")
+
+	err = client.ContainerRemove(ctx, cID, types.ContainerRemoveOptions{Force: true})
+	assert.NilError(t, err)
+	assert.NilError(t, client.SecretRemove(ctx, secretName))
+}
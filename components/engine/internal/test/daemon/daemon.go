@@ -140,6 +140,11 @@ func (d *Daemon) RootDir() string {
 	return d.Root
 }
 
+// ExecRoot returns the exec root directory of the daemon.
+func (d *Daemon) ExecRoot() string {
+	return d.execRoot
+}
+
 // ID returns the generated id of the daemon
 func (d *Daemon) ID() string {
 	return d.id
@@ -578,7 +583,7 @@ func (d *Daemon) LoadBusybox(t assert.TestingT) {
 	defer clientHost.Close()
 
 	ctx := context.Background()
-	reader, err := clientHost.ImageSave(ctx, []string{"busybox:latest"})
+	reader, err := clientHost.ImageSave(ctx, []string{"busybox:latest"}, types.ImageSaveOptions{})
 	assert.NilError(t, err, "failed to download busybox")
 	defer reader.Close()
 
@@ -0,0 +1,160 @@
+package layer // import "github.com/docker/docker/layer"
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CheckableStore represents a layer store which can report and remove layers
+// and mounts left behind by interrupted operations.
+type CheckableStore interface {
+	Store
+
+	// Unreferenced returns the layers which are not referenced by an image,
+	// a child layer or a mount, and the mounts which are not in use.
+	Unreferenced() ([]ChainID, []string)
+	// Corrupted returns the layers and mounts which are present on disk but
+	// could not be loaded.
+	Corrupted() ([]ChainID, []string, error)
+	// RemoveLayer removes an unreferenced or corrupted layer.
+	RemoveLayer(ChainID) ([]Metadata, error)
+	// RemoveMount removes an unused or corrupted mount.
+	RemoveMount(name string) ([]Metadata, error)
+	// Verify checks that the content of a layer matches its DiffID.
+	Verify(ChainID) error
+}
+
+func (ls *layerStore) Unreferenced() ([]ChainID, []string) {
+	var layers []ChainID
+	ls.layerL.Lock()
+	for id, l := range ls.layerMap {
+		if l.referenceCount == 0 {
+			layers = append(layers, id)
+		}
+	}
+	ls.layerL.Unlock()
+
+	var mounts []string
+	ls.mountL.Lock()
+	for name, m := range ls.mounts {
+		if !m.hasReferences() {
+			mounts = append(mounts, name)
+		}
+	}
+	ls.mountL.Unlock()
+
+	return layers, mounts
+}
+
+func (ls *layerStore) Corrupted() ([]ChainID, []string, error) {
+	ids, mountNames, err := ls.store.List()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var layers []ChainID
+	ls.layerL.Lock()
+	for _, id := range ids {
+		if _, ok := ls.layerMap[id]; !ok {
+			layers = append(layers, id)
+		}
+	}
+	ls.layerL.Unlock()
+
+	var mounts []string
+	ls.mountL.Lock()
+	for _, name := range mountNames {
+		if _, ok := ls.mounts[name]; !ok {
+			mounts = append(mounts, name)
+		}
+	}
+	ls.mountL.Unlock()
+
+	return layers, mounts, nil
+}
+
+func (ls *layerStore) RemoveLayer(id ChainID) ([]Metadata, error) {
+	ls.layerL.Lock()
+	defer ls.layerL.Unlock()
+
+	l, ok := ls.layerMap[id]
+	if !ok {
+		// The layer could not be loaded; make sure it still cannot be loaded
+		// before removing what is left of it.
+		if l, err := ls.loadLayer(id); err == nil {
+			if l.parent != nil {
+				l.parent.referenceCount++
+			}
+			return nil, fmt.Errorf("layer %s is not corrupted", id)
+		}
+		if cacheID, err := ls.store.GetCacheID(id); err == nil {
+			if err := ls.driver.Remove(cacheID); err != nil {
+				logrus.WithError(err).WithField("layer", id).Warn("failed to remove layer content")
+			}
+		}
+		return []Metadata{{ChainID: id}}, ls.store.Remove(id)
+	}
+
+	if l.referenceCount != 0 {
+		return nil, fmt.Errorf("layer %s is in use", id)
+	}
+	// releaseLayer drops the reference we take here, removing the layer and
+	// any of its parents which are no longer referenced.
+	l.referenceCount++
+	return ls.releaseLayer(l)
+}
+
+func (ls *layerStore) RemoveMount(name string) ([]Metadata, error) {
+	ls.locker.Lock(name)
+	ls.mountL.Lock()
+	m, ok := ls.mounts[name]
+	ls.mountL.Unlock()
+	if !ok {
+		defer ls.locker.Unlock(name)
+
+		if err := ls.loadMount(name); err == nil {
+			return nil, fmt.Errorf("mount %s is not corrupted", name)
+		}
+		for _, get := range []func(string) (string, error){ls.store.GetMountID, ls.store.GetInitID} {
+			if id, err := get(name); err == nil && id != "" {
+				if err := ls.driver.Remove(id); err != nil {
+					logrus.WithError(err).WithField("mount", name).Warn("failed to remove mount content")
+				}
+			}
+		}
+		return []Metadata{}, ls.store.RemoveMount(name)
+	}
+	if m.hasReferences() {
+		ls.locker.Unlock(name)
+		return nil, fmt.Errorf("mount %s is in use", name)
+	}
+	ls.locker.Unlock(name)
+
+	rl, err := ls.GetRWLayer(name)
+	if err != nil {
+		return nil, err
+	}
+	return ls.ReleaseRWLayer(rl)
+}
+
+func (ls *layerStore) Verify(id ChainID) error {
+	l, err := ls.Get(id)
+	if err != nil {
+		return err
+	}
+	defer ReleaseAndLog(ls, l)
+
+	// TarStream verifies the reassembled content against the DiffID once
+	// the stream has been read completely.
+	ts, err := l.TarStream()
+	if err != nil {
+		return err
+	}
+	defer ts.Close()
+
+	_, err = io.Copy(ioutil.Discard, ts)
+	return err
+}
@@ -0,0 +1,156 @@
+package layer // import "github.com/docker/docker/layer"
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestCheckUnreferenced(t *testing.T) {
+	// TODO Windows: Figure out why this is failing
+	if runtime.GOOS == "windows" {
+		t.Skip("Failing on Windows")
+	}
+	ls, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	layer1, err := createLayer(ls, "", initWithFiles(newTestFile("layer1.txt", []byte("layer 1 file"), 0644)))
+	assert.NilError(t, err)
+	layer2, err := createLayer(ls, layer1.ChainID(), initWithFiles(newTestFile("layer2.txt", []byte("layer 2 file"), 0644)))
+	assert.NilError(t, err)
+	_, err = ls.Release(layer1)
+	assert.NilError(t, err)
+
+	_, err = ls.CreateRWLayer("some-mount_name", layer2.ChainID(), nil)
+	assert.NilError(t, err)
+
+	cs := ls.(CheckableStore)
+	layers, mounts := cs.Unreferenced()
+	assert.Check(t, is.Len(layers, 0))
+	assert.Check(t, is.Len(mounts, 0))
+
+	// A restored store holds no references until images and containers
+	// are loaded.
+	ls2, err := newStoreFromGraphDriver(ls.(*layerStore).store.root, ls.(*layerStore).driver, runtime.GOOS)
+	assert.NilError(t, err)
+	cs = ls2.(CheckableStore)
+
+	layers, mounts = cs.Unreferenced()
+	assert.Check(t, is.Len(layers, 0))
+	assert.Check(t, is.DeepEqual([]string{"some-mount_name"}, mounts))
+
+	// Removing the mount releases the layers it was the last user of
+	metadata, err := cs.RemoveMount("some-mount_name")
+	assert.NilError(t, err)
+	assertMetadata(t, metadata, createMetadata(layer2, layer1))
+	assert.Check(t, is.Len(ls2.Map(), 0))
+}
+
+func TestCheckRemoveUnreferencedLayer(t *testing.T) {
+	// TODO Windows: Figure out why this is failing
+	if runtime.GOOS == "windows" {
+		t.Skip("Failing on Windows")
+	}
+	ls, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	layer1, err := createLayer(ls, "", initWithFiles(newTestFile("layer1.txt", []byte("layer 1 file"), 0644)))
+	assert.NilError(t, err)
+	layer2, err := createLayer(ls, layer1.ChainID(), initWithFiles(newTestFile("layer2.txt", []byte("layer 2 file"), 0644)))
+	assert.NilError(t, err)
+
+	ls2, err := newStoreFromGraphDriver(ls.(*layerStore).store.root, ls.(*layerStore).driver, runtime.GOOS)
+	assert.NilError(t, err)
+	cs := ls2.(CheckableStore)
+
+	// layer1 is referenced by its child
+	layers, mounts := cs.Unreferenced()
+	assert.Check(t, is.DeepEqual([]ChainID{layer2.ChainID()}, layers))
+	assert.Check(t, is.Len(mounts, 0))
+
+	_, err = cs.RemoveLayer(layer1.ChainID())
+	assert.Check(t, is.ErrorContains(err, "in use"))
+
+	metadata, err := cs.RemoveLayer(layer2.ChainID())
+	assert.NilError(t, err)
+	assertMetadata(t, metadata, createMetadata(layer2, layer1))
+	assert.Check(t, is.Len(ls2.Map(), 0))
+}
+
+func TestCheckCorrupted(t *testing.T) {
+	// TODO Windows: Figure out why this is failing
+	if runtime.GOOS == "windows" {
+		t.Skip("Failing on Windows")
+	}
+	ls, tmpdir, cleanup := newTestStore(t)
+	defer cleanup()
+
+	layer1, err := createLayer(ls, "", initWithFiles(newTestFile("layer1.txt", []byte("layer 1 file"), 0644)))
+	assert.NilError(t, err)
+
+	id := digest.Digest(layer1.ChainID())
+	layerDir := filepath.Join(tmpdir, id.Algorithm().String(), id.Hex())
+	assert.NilError(t, os.Remove(filepath.Join(layerDir, "diff")))
+
+	ls2, err := newStoreFromGraphDriver(ls.(*layerStore).store.root, ls.(*layerStore).driver, runtime.GOOS)
+	assert.NilError(t, err)
+	cs := ls2.(CheckableStore)
+
+	layers, mounts, err := cs.Corrupted()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]ChainID{layer1.ChainID()}, layers))
+	assert.Check(t, is.Len(mounts, 0))
+
+	_, err = cs.RemoveLayer(layer1.ChainID())
+	assert.NilError(t, err)
+	_, err = os.Stat(layerDir)
+	assert.Check(t, os.IsNotExist(err))
+
+	layers, _, err = cs.Corrupted()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(layers, 0))
+}
+
+func TestCheckVerify(t *testing.T) {
+	// TODO Windows: Figure out why this is failing
+	if runtime.GOOS == "windows" {
+		t.Skip("Failing on Windows")
+	}
+	ls, tmpdir, cleanup := newTestStore(t)
+	defer cleanup()
+
+	tar1, err := tarFromFiles(newTestFile("/foo", []byte("abc"), 0644))
+	assert.NilError(t, err)
+	tar2, err := tarFromFiles(newTestFile("/foo", []byte("abc"), 0600))
+	assert.NilError(t, err)
+
+	layer1, err := ls.Register(bytes.NewReader(tar1), "")
+	assert.NilError(t, err)
+	layer2, err := ls.Register(bytes.NewReader(tar2), "")
+	assert.NilError(t, err)
+
+	cs := ls.(CheckableStore)
+	assert.Check(t, cs.Verify(layer1.ChainID()))
+	assert.Check(t, cs.Verify(layer2.ChainID()))
+
+	// Replace the tar-split data of the second layer with the first one
+	id1 := digest.Digest(layer1.ChainID())
+	id2 := digest.Digest(layer2.ChainID())
+	src, err := os.Open(filepath.Join(tmpdir, id1.Algorithm().String(), id1.Hex(), "tar-split.json.gz"))
+	assert.NilError(t, err)
+	defer src.Close()
+	dst, err := os.Create(filepath.Join(tmpdir, id2.Algorithm().String(), id2.Hex(), "tar-split.json.gz"))
+	assert.NilError(t, err)
+	defer dst.Close()
+	_, err = io.Copy(dst, src)
+	assert.NilError(t, err)
+
+	assert.Check(t, is.ErrorContains(cs.Verify(layer2.ChainID()), "could not verify layer data"))
+}
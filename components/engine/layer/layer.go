@@ -16,6 +16,7 @@ import (
 	"github.com/docker/distribution"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/containerfs"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/opencontainers/go-digest"
 	"github.com/sirupsen/logrus"
 )
@@ -178,6 +179,11 @@ type CreateRWLayerOpts struct {
 	MountLabel string
 	InitFunc   MountInit
 	StorageOpt map[string]string
+	// IDMapping is the user namespace mapping through which the parent
+	// layers are seen in the mounted read-write layer. The files of the
+	// parent layers, including those of the init layer, are stored with
+	// the IDs of the daemon.
+	IDMapping *idtools.IdentityMapping
 }
 
 // Store represents a backend for managing both
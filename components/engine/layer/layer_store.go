@@ -31,6 +31,9 @@ type layerStore struct {
 	store       *fileMetadataStore
 	driver      graphdriver.Driver
 	useTarSplit bool
+	// idMappedLayers is set when the driver supports mounting read-write
+	// layers with an IDMapping.
+	idMappedLayers bool
 
 	layerMap map[ChainID]*roLayer
 	layerL   sync.Mutex
@@ -100,6 +103,8 @@ func newStoreFromGraphDriver(root string, driver graphdriver.Driver, os string)
 		locker:      locker.New(),
 		useTarSplit: !caps.ReproducesExactDiffs,
 		os:          os,
+
+		idMappedLayers: caps.IDMappedLayers,
 	}
 
 	ids, mounts, err := ms.List()
@@ -490,12 +495,17 @@ func (ls *layerStore) CreateRWLayer(name string, parent ChainID, opts *CreateRWL
 		storageOpt map[string]string
 		initFunc   MountInit
 		mountLabel string
+		idMapping  *idtools.IdentityMapping
 	)
 
 	if opts != nil {
 		mountLabel = opts.MountLabel
 		storageOpt = opts.StorageOpt
 		initFunc = opts.InitFunc
+		idMapping = opts.IDMapping
+	}
+	if idMapping != nil && !ls.idMappedLayers {
+		return nil, fmt.Errorf("the %s storage driver does not support user namespace mappings of containers", ls.driver)
 	}
 
 	ls.locker.Lock(name)
@@ -545,6 +555,7 @@ func (ls *layerStore) CreateRWLayer(name string, parent ChainID, opts *CreateRWL
 
 	createOpts := &graphdriver.CreateOpts{
 		StorageOpt: storageOpt,
+		IDMapping:  idMapping,
 	}
 
 	if err = ls.driver.CreateReadWrite(m.mountID, pid, createOpts); err != nil {
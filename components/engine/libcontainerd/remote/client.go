@@ -23,7 +23,9 @@ import (
 	containerderrors "github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/events"
 	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/plugin"
 	"github.com/containerd/containerd/runtime/linux/runctypes"
+	v2runcoptions "github.com/containerd/containerd/runtime/v2/runc/options"
 	"github.com/containerd/typeurl"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/libcontainerd/queue"
@@ -124,13 +126,13 @@ func (c *client) Restore(ctx context.Context, id string, attachStdio libcontaine
 	}, nil
 }
 
-func (c *client) Create(ctx context.Context, id string, ociSpec *specs.Spec, runtimeOptions interface{}) error {
+func (c *client) Create(ctx context.Context, id string, ociSpec *specs.Spec, shim string, runtimeOptions interface{}) error {
 	bdir := c.bundleDir(id)
 	c.logger.WithField("bundle", bdir).WithField("root", ociSpec.Root.Path).Debug("bundle dir created")
 
 	_, err := c.client.NewContainer(ctx, id,
 		containerd.WithSpec(ociSpec),
-		containerd.WithRuntime(runtimeName, runtimeOptions),
+		containerd.WithRuntime(shim, runtimeOptions),
 		WithBundle(bdir, ociSpec),
 	)
 	if err != nil {
@@ -199,10 +201,28 @@ func (c *client) Start(ctx context.Context, id, checkpointDir string, withStdin
 		func(_ context.Context, _ *containerd.Client, info *containerd.TaskInfo) error {
 			info.Checkpoint = cp
 			if runtime.GOOS != "windows" {
-				info.Options = &runctypes.CreateOptions{
-					IoUid:       uint32(uid),
-					IoGid:       uint32(gid),
-					NoPivotRoot: os.Getenv("DOCKER_RAMDISK") != "",
+				switch info.Runtime() {
+				case plugin.RuntimeRuncV1, plugin.RuntimeRuncV2:
+					// The task options replace the options of the runtime
+					// for the v2 runc shim, so they are merged.
+					opts := &v2runcoptions.Options{}
+					if ctrInfo, err := ctr.Info(ctx); err == nil && ctrInfo.Runtime.Options != nil {
+						if v, err := typeurl.UnmarshalAny(ctrInfo.Runtime.Options); err == nil {
+							if runtimeOpts, ok := v.(*v2runcoptions.Options); ok {
+								*opts = *runtimeOpts
+							}
+						}
+					}
+					opts.IoUid = uint32(uid)
+					opts.IoGid = uint32(gid)
+					opts.NoPivotRoot = os.Getenv("DOCKER_RAMDISK") != ""
+					info.Options = opts
+				case plugin.RuntimeLinuxV1:
+					info.Options = &runctypes.CreateOptions{
+						IoUid:       uint32(uid),
+						IoGid:       uint32(gid),
+						NoPivotRoot: os.Getenv("DOCKER_RAMDISK") != "",
+					}
 				}
 			} else {
 				// Make sure we set the runhcs options to debug if we are at debug level.
@@ -491,12 +511,23 @@ func (c *client) CreateCheckpoint(ctx context.Context, containerID, checkpointDi
 	if exit {
 		opts = append(opts, func(r *containerd.CheckpointTaskInfo) error {
 			if r.Options == nil {
-				r.Options = &runctypes.CheckpointOptions{
-					Exit: true,
+				switch r.Runtime() {
+				case plugin.RuntimeRuncV1, plugin.RuntimeRuncV2:
+					r.Options = &v2runcoptions.CheckpointOptions{
+						Exit: true,
+					}
+				default:
+					r.Options = &runctypes.CheckpointOptions{
+						Exit: true,
+					}
 				}
 			} else {
-				opts, _ := r.Options.(*runctypes.CheckpointOptions)
-				opts.Exit = true
+				switch opts := r.Options.(type) {
+				case *v2runcoptions.CheckpointOptions:
+					opts.Exit = true
+				case *runctypes.CheckpointOptions:
+					opts.Exit = true
+				}
 			}
 			return nil
 		})
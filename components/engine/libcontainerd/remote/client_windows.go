@@ -16,8 +16,6 @@ import (
 	"github.com/pkg/errors"
 )
 
-const runtimeName = "io.containerd.runhcs.v1"
-
 func summaryFromInterface(i interface{}) (*libcontainerdtypes.Summary, error) {
 	switch pd := i.(type) {
 	case *options.ProcessDetails:
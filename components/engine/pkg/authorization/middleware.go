@@ -9,11 +9,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// Middleware uses a list of plugins to
+// Middleware uses a policy and a list of plugins to
 // handle authorization in the API requests.
 type Middleware struct {
-	mu      sync.Mutex
-	plugins []Plugin
+	mu          sync.Mutex
+	plugins     []Plugin
+	policy      *Policy
+	labelGetter LabelGetter
 }
 
 // NewMiddleware creates a new Middleware
@@ -31,6 +33,28 @@ func (m *Middleware) getAuthzPlugins() []Plugin {
 	return m.plugins
 }
 
+func (m *Middleware) getPolicy() (*Policy, LabelGetter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.policy, m.labelGetter
+}
+
+// SetPolicy sets the built-in policy evaluated before the authorization
+// plugins. A nil policy allows all the requests.
+func (m *Middleware) SetPolicy(p *Policy) {
+	m.mu.Lock()
+	m.policy = p
+	m.mu.Unlock()
+}
+
+// SetLabelGetter sets the getter of the labels of the resources, for the
+// rules of the policy which only allow the resources with some labels.
+func (m *Middleware) SetLabelGetter(g LabelGetter) {
+	m.mu.Lock()
+	m.labelGetter = g
+	m.mu.Unlock()
+}
+
 // SetPlugins sets the plugin used for authorization
 func (m *Middleware) SetPlugins(names []string) {
 	m.mu.Lock()
@@ -54,6 +78,13 @@ func (m *Middleware) RemovePlugin(name string) {
 // WrapHandler returns a new handler function wrapping the previous one in the request chain.
 func (m *Middleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		if policy, labelGetter := m.getPolicy(); policy != nil {
+			if err := policy.authorize(newPolicyRequest(r, labelGetter)); err != nil {
+				logrus.Errorf("Authorization policy denied %s %s: %s", r.Method, r.RequestURI, err)
+				return err
+			}
+		}
+
 		plugins := m.getAuthzPlugins()
 		if len(plugins) == 0 {
 			return handler(ctx, w, r, vars)
@@ -66,8 +97,8 @@ func (m *Middleware) WrapHandler(handler func(ctx context.Context, w http.Respon
 		// FIXME: Non trivial authorization mechanisms (such as advanced certificate validations, kerberos support
 		// and ldap) will be extracted using AuthN feature, which is tracked under:
 		// https://github.com/docker/docker/pull/20883
-		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
-			user = r.TLS.PeerCertificates[0].Subject.CommonName
+		if cert := VerifiedClientCertificate(r); cert != nil {
+			user = cert.Subject.CommonName
 			userAuthNMethod = "TLS"
 		}
 
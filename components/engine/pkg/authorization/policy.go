@@ -0,0 +1,515 @@
+package authorization // import "github.com/docker/docker/pkg/authorization"
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// peerCredentialsPrefix is the prefix of the remote address of the requests
+// received on the Unix sockets of the API, which holds the credentials of the
+// client process.
+const peerCredentialsPrefix = "unix-peer:"
+
+// versionPrefix matches the API version prefix of the request paths.
+var versionPrefix = regexp.MustCompile(`^/v[0-9.]+/`)
+
+// labeledResources are the kinds of resources which can be restricted by
+// their labels in the policy rules.
+var labeledResources = map[string]bool{
+	"containers": true,
+	"exec":       true,
+	"networks":   true,
+	"volumes":    true,
+}
+
+// PeerCredentials are the credentials of a client process connected to a Unix
+// socket of the API.
+type PeerCredentials struct {
+	PID  int32
+	UID  uint32
+	GIDs []uint32 // the primary group first, then the supplementary groups
+}
+
+// String returns the credentials in the format of the remote address of the
+// requests received on Unix sockets, such as "unix-peer:pid=42,uid=1000,gids=1000:999".
+func (c PeerCredentials) String() string {
+	gids := make([]string, 0, len(c.GIDs))
+	for _, gid := range c.GIDs {
+		gids = append(gids, strconv.FormatUint(uint64(gid), 10))
+	}
+	return fmt.Sprintf("%spid=%d,uid=%d,gids=%s", peerCredentialsPrefix, c.PID, c.UID, strings.Join(gids, ":"))
+}
+
+// VerifiedClientCertificate returns the TLS client certificate of a request,
+// if it was verified against the CAs of the daemon. The certificates of the
+// clients are not verified by all the client authentication modes of the
+// TLS listeners, so the other peer certificates do not identify the client.
+func VerifiedClientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
+// ParsePeerCredentials parses the credentials of the client from the remote
+// address of a request, if it was received on a Unix socket.
+func ParsePeerCredentials(remoteAddr string) (*PeerCredentials, bool) {
+	if !strings.HasPrefix(remoteAddr, peerCredentialsPrefix) {
+		return nil, false
+	}
+	var (
+		c      PeerCredentials
+		hasUID bool
+	)
+	for _, field := range strings.Split(strings.TrimPrefix(remoteAddr, peerCredentialsPrefix), ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, false
+		}
+		switch kv[0] {
+		case "pid":
+			v, err := strconv.ParseInt(kv[1], 10, 32)
+			if err != nil {
+				return nil, false
+			}
+			c.PID = int32(v)
+		case "uid":
+			v, err := strconv.ParseUint(kv[1], 10, 32)
+			if err != nil {
+				return nil, false
+			}
+			c.UID, hasUID = uint32(v), true
+		case "gids":
+			for _, s := range strings.Split(kv[1], ":") {
+				if s == "" {
+					continue
+				}
+				v, err := strconv.ParseUint(s, 10, 32)
+				if err != nil {
+					return nil, false
+				}
+				c.GIDs = append(c.GIDs, uint32(v))
+			}
+		}
+	}
+	return &c, hasUID
+}
+
+// callerKey is the context key of the identity of the client of an API
+// request.
+type callerKey struct{}
+
+// Caller returns the identity of the client of a request: "cn=" and the
+// common name of its verified TLS client certificate, the credentials of the
+// client process for the requests received on Unix sockets, such as
+// "pid=42,uid=1000,gids=1000:999", or else its remote address.
+func Caller(r *http.Request) string {
+	if cert := VerifiedClientCertificate(r); cert != nil {
+		return "cn=" + cert.Subject.CommonName
+	}
+	if strings.HasPrefix(r.RemoteAddr, peerCredentialsPrefix) {
+		return strings.TrimPrefix(r.RemoteAddr, peerCredentialsPrefix)
+	}
+	if r.RemoteAddr == "@" {
+		return ""
+	}
+	return r.RemoteAddr
+}
+
+// WithCaller returns a copy of ctx which holds the identity of the client of
+// r, as returned by Caller.
+func WithCaller(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, callerKey{}, Caller(r))
+}
+
+// CallerFromContext returns the identity of the client of the API request of
+// ctx, or an empty string if ctx is not the context of an API request.
+func CallerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+// LabelGetter returns the labels of the resources targeted by the API
+// requests, for the policy rules which only allow the resources with some
+// labels. The kinds of resources are "containers", "exec" (for which the
+// labels of the container of the exec instance are returned), "networks" and
+// "volumes".
+type LabelGetter interface {
+	ResourceLabels(kind, name string) (map[string]string, error)
+}
+
+// Policy is the built-in authorization policy of the daemon. A request is
+// allowed when it matches at least one of the rules of the policy, and is
+// denied otherwise.
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule allows some clients to make some requests.
+type PolicyRule struct {
+	// Name identifies the rule in the logs.
+	Name string `json:"name,omitempty"`
+
+	// Users are the common names, or the subject alternative names (DNS
+	// names, email addresses and URIs), of the TLS client certificates
+	// allowed by the rule.
+	Users []string `json:"users,omitempty"`
+	// UIDs are the user IDs of the processes allowed by the rule, when they
+	// connect to a Unix socket.
+	UIDs []uint32 `json:"uids,omitempty"`
+	// GIDs are the group IDs, primary or supplementary, of the processes
+	// allowed by the rule, when they connect to a Unix socket.
+	GIDs []uint32 `json:"gids,omitempty"`
+
+	// Methods are the allowed HTTP methods. All the methods are allowed when
+	// none is set.
+	Methods []string `json:"methods,omitempty"`
+	// Endpoints are the patterns of the allowed request paths, without the
+	// API version prefix, such as "/containers/*/logs". A "*" matches a
+	// single element of the path, and a final "**" matches any number of
+	// elements. All the endpoints are allowed when none is set.
+	Endpoints []string `json:"endpoints,omitempty"`
+	// Labels restricts the rule to the requests targeting a single
+	// container, exec instance, network or volume which has all these
+	// labels, or creating one with all these labels.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// LoadPolicy reads and validates the policy file at path.
+func LoadPolicy(path string) (*Policy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization policy: %v", err)
+	}
+	var p Policy
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse authorization policy %s: %v", path, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid authorization policy %s: %v", path, err)
+	}
+	return &p, nil
+}
+
+// Validate checks the rules of the policy.
+func (p *Policy) Validate() error {
+	for i, rule := range p.Rules {
+		name := rule.Name
+		if name == "" {
+			name = "#" + strconv.Itoa(i)
+		}
+		for _, m := range rule.Methods {
+			if m == "" || m != strings.ToUpper(m) {
+				return fmt.Errorf("rule %s: invalid method %q", name, m)
+			}
+		}
+		for _, e := range rule.Endpoints {
+			if !strings.HasPrefix(e, "/") {
+				return fmt.Errorf("rule %s: endpoint %q must start with /", name, e)
+			}
+			if _, err := path.Match(e, ""); err != nil {
+				return fmt.Errorf("rule %s: invalid endpoint %q: %v", name, e, err)
+			}
+		}
+	}
+	return nil
+}
+
+// policyRequest holds the details of a request needed to evaluate the rules.
+type policyRequest struct {
+	r           *http.Request
+	method      string
+	path        string
+	user        string
+	altNames    []string
+	peer        *PeerCredentials
+	labelGetter LabelGetter
+
+	// labels are the labels of the targeted resource, looked up when the
+	// first rule restricted to some labels is evaluated.
+	labels       map[string]string
+	labelsLoaded bool
+	// joined are the containers whose namespaces or volumes are used by the
+	// created container, which must have the labels of the rule as well.
+	joined       []string
+	joinedLabels map[string]map[string]string
+}
+
+// newPolicyRequest extracts the client identity and the endpoint of a request.
+func newPolicyRequest(r *http.Request, labelGetter LabelGetter) *policyRequest {
+	pr := &policyRequest{
+		r:           r,
+		method:      r.Method,
+		path:        "/" + versionPrefix.ReplaceAllString(r.URL.Path, ""),
+		labelGetter: labelGetter,
+	}
+	pr.path = path.Clean(pr.path)
+	if cert := VerifiedClientCertificate(r); cert != nil {
+		pr.user = cert.Subject.CommonName
+		pr.altNames = append(pr.altNames, cert.DNSNames...)
+		pr.altNames = append(pr.altNames, cert.EmailAddresses...)
+		for _, u := range cert.URIs {
+			pr.altNames = append(pr.altNames, u.String())
+		}
+	}
+	pr.peer, _ = ParsePeerCredentials(r.RemoteAddr)
+	return pr
+}
+
+// identity describes the client in the errors.
+func (pr *policyRequest) identity() string {
+	switch {
+	case pr.user != "":
+		return fmt.Sprintf("user %q", pr.user)
+	case pr.peer != nil:
+		return fmt.Sprintf("uid %d", pr.peer.UID)
+	default:
+		return "anonymous client"
+	}
+}
+
+// authorize returns an error if none of the rules of the policy allows the
+// request.
+func (p *Policy) authorize(pr *policyRequest) error {
+	for _, rule := range p.Rules {
+		ok, err := rule.matches(pr)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return authorizationError{error: fmt.Errorf("authorization denied by policy: %s is not allowed to %s %s", pr.identity(), pr.method, pr.path)}
+}
+
+func (rule *PolicyRule) matches(pr *policyRequest) (bool, error) {
+	if !rule.matchesClient(pr) {
+		return false, nil
+	}
+	if len(rule.Methods) > 0 && !contains(rule.Methods, pr.method) {
+		return false, nil
+	}
+	if len(rule.Endpoints) > 0 {
+		matched := false
+		for _, e := range rule.Endpoints {
+			if matchEndpoint(e, pr.path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	if len(rule.Labels) == 0 {
+		return true, nil
+	}
+	labels, err := pr.resourceLabels()
+	if err != nil || labels == nil {
+		return false, err
+	}
+	if !hasLabels(labels, rule.Labels) {
+		return false, nil
+	}
+	for _, name := range pr.joined {
+		labels, ok := pr.containerLabels(name)
+		if !ok || !hasLabels(labels, rule.Labels) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func hasLabels(labels, expected map[string]string) bool {
+	for k, v := range expected {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (rule *PolicyRule) matchesClient(pr *policyRequest) bool {
+	if len(rule.Users) == 0 && len(rule.UIDs) == 0 && len(rule.GIDs) == 0 {
+		return true
+	}
+	if pr.user != "" && contains(rule.Users, pr.user) {
+		return true
+	}
+	for _, name := range pr.altNames {
+		if contains(rule.Users, name) {
+			return true
+		}
+	}
+	if pr.peer == nil {
+		return false
+	}
+	for _, uid := range rule.UIDs {
+		if uid == pr.peer.UID {
+			return true
+		}
+	}
+	for _, gid := range rule.GIDs {
+		for _, g := range pr.peer.GIDs {
+			if gid == g {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resourceLabels returns the labels of the single resource targeted by the
+// request, or nil if it does not target a single resource which can be
+// restricted by its labels.
+func (pr *policyRequest) resourceLabels() (map[string]string, error) {
+	if pr.labelsLoaded {
+		return pr.labels, nil
+	}
+	pr.labelsLoaded = true
+
+	elems := strings.Split(strings.TrimPrefix(pr.path, "/"), "/")
+	if len(elems) < 2 || !labeledResources[elems[0]] {
+		return nil, nil
+	}
+	kind, name := elems[0], elems[1]
+	switch {
+	case name == "create" && len(elems) == 2 && pr.method == http.MethodPost && kind != "exec":
+		labels, joined, err := requestLabels(pr.r)
+		if err != nil {
+			return nil, err
+		}
+		if labels == nil {
+			return nil, nil
+		}
+		pr.labels = labels
+		if kind == "containers" {
+			pr.joined = joined
+		}
+	case name == "json" && len(elems) == 2, name == "prune" && len(elems) == 2:
+		// Listing and pruning target all the resources of a kind.
+		return nil, nil
+	default:
+		if pr.labelGetter == nil {
+			return nil, nil
+		}
+		name, err := url.PathUnescape(name)
+		if err != nil {
+			return nil, nil
+		}
+		labels, err := pr.labelGetter.ResourceLabels(kind, name)
+		if err != nil {
+			// The resource does not exist, so the rule cannot allow it.
+			return nil, nil
+		}
+		pr.labels = labels
+	}
+	if pr.labels == nil {
+		pr.labels = map[string]string{}
+	}
+	return pr.labels, nil
+}
+
+// containerLabels returns the labels of a container joined by the created
+// container, and false if it does not exist.
+func (pr *policyRequest) containerLabels(name string) (map[string]string, bool) {
+	if labels, ok := pr.joinedLabels[name]; ok {
+		return labels, labels != nil
+	}
+	var labels map[string]string
+	if pr.labelGetter != nil {
+		l, err := pr.labelGetter.ResourceLabels("containers", name)
+		if err == nil {
+			labels = l
+			if labels == nil {
+				labels = map[string]string{}
+			}
+		}
+	}
+	if pr.joinedLabels == nil {
+		pr.joinedLabels = make(map[string]map[string]string)
+	}
+	pr.joinedLabels[name] = labels
+	return labels, labels != nil
+}
+
+// requestLabels returns the labels of the resource created by a request, and
+// the containers whose namespaces or volumes are used by a created container.
+// The labels are nil if the body of the request cannot be decoded.
+func requestLabels(r *http.Request) (map[string]string, []string, error) {
+	if r.Body == nil || r.ContentLength >= maxBodySize {
+		return nil, nil, nil
+	}
+	body, newBody, err := drainBody(r.Body)
+	r.Body = newBody
+	if err != nil {
+		return nil, nil, err
+	}
+	var config struct {
+		Labels     map[string]string
+		HostConfig struct {
+			NetworkMode string
+			IpcMode     string
+			PidMode     string
+			Cgroup      string
+			VolumesFrom []string
+		}
+	}
+	if len(body) == 0 || json.Unmarshal(body, &config) != nil {
+		return nil, nil, nil
+	}
+	if config.Labels == nil {
+		config.Labels = map[string]string{}
+	}
+	var joined []string
+	hc := config.HostConfig
+	for _, mode := range []string{hc.NetworkMode, hc.IpcMode, hc.PidMode, hc.Cgroup} {
+		if strings.HasPrefix(mode, "container:") {
+			joined = append(joined, strings.TrimPrefix(mode, "container:"))
+		}
+	}
+	for _, v := range hc.VolumesFrom {
+		// The name of the container may be followed by the ":ro" or ":rw"
+		// mode.
+		joined = append(joined, strings.SplitN(v, ":", 2)[0])
+	}
+	return config.Labels, joined, nil
+}
+
+// matchEndpoint reports whether the path of a request matches an endpoint
+// pattern of a rule.
+func matchEndpoint(pattern, p string) bool {
+	pElems := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	elems := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i, pe := range pElems {
+		if pe == "**" && i == len(pElems)-1 {
+			return true
+		}
+		if i >= len(elems) {
+			return false
+		}
+		if ok, _ := path.Match(pe, elems[i]); !ok {
+			return false
+		}
+	}
+	return len(elems) == len(pElems)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package authorization // import "github.com/docker/docker/pkg/authorization"
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type fakeLabelGetter map[string]map[string]string
+
+func (g fakeLabelGetter) ResourceLabels(kind, name string) (map[string]string, error) {
+	labels, ok := g[kind+"/"+name]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return labels, nil
+}
+
+func TestMatchEndpoint(t *testing.T) {
+	cases := []struct {
+		pattern, path string
+		expected      bool
+	}{
+		{"/containers/json", "/containers/json", true},
+		{"/containers/*/logs", "/containers/abc/logs", true},
+		{"/containers/*/logs", "/containers/abc/start", false},
+		{"/containers/*", "/containers/abc/logs", false},
+		{"/containers/**", "/containers/abc/logs", true},
+		{"/containers/**", "/containers", true},
+		{"/containers/**", "/images/json", false},
+		{"/images/*/json", "/images/json", false},
+	}
+	for _, c := range cases {
+		assert.Check(t, is.Equal(matchEndpoint(c.pattern, c.path), c.expected), "%s %s", c.pattern, c.path)
+	}
+}
+
+func TestPeerCredentials(t *testing.T) {
+	c := PeerCredentials{PID: 42, UID: 1000, GIDs: []uint32{1000, 999}}
+	parsed, ok := ParsePeerCredentials(c.String())
+	assert.Assert(t, ok)
+	assert.Check(t, is.DeepEqual(*parsed, c))
+
+	_, ok = ParsePeerCredentials("127.0.0.1:4242")
+	assert.Check(t, !ok)
+}
+
+func TestCaller(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/containers/json", nil)
+	r.RemoteAddr = PeerCredentials{PID: 42, UID: 1000, GIDs: []uint32{1000}}.String()
+	assert.Check(t, is.Equal(Caller(r), "pid=42,uid=1000,gids=1000"))
+
+	r.RemoteAddr = "192.0.2.1:4242"
+	assert.Check(t, is.Equal(Caller(r), "192.0.2.1:4242"))
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "alice"}}
+	r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	assert.Check(t, is.Equal(Caller(r), "cn=alice"))
+
+	assert.Check(t, is.Equal(CallerFromContext(WithCaller(context.Background(), r)), "cn=alice"))
+	assert.Check(t, is.Equal(CallerFromContext(context.Background()), ""))
+}
+
+func TestPolicy(t *testing.T) {
+	policy := &Policy{Rules: []PolicyRule{
+		{Name: "admins", UIDs: []uint32{0}},
+		{Name: "readers", GIDs: []uint32{999}, Methods: []string{"GET"}},
+		{Name: "team-x", Users: []string{"alice", "bob@example.com"}, Endpoints: []string{"/containers/**"}, Labels: map[string]string{"team": "x"}},
+	}}
+	assert.NilError(t, policy.Validate())
+	labels := fakeLabelGetter{
+		"containers/web": {"team": "x"},
+		"containers/db":  {"team": "y"},
+	}
+
+	unix := func(method, target string, uid uint32, gids ...uint32) *http.Request {
+		r := httptest.NewRequest(method, target, nil)
+		r.RemoteAddr = PeerCredentials{PID: 1, UID: uid, GIDs: gids}.String()
+		return r
+	}
+	tlsRequest := func(method, target, body string, cert *x509.Certificate) *http.Request {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}, VerifiedChains: [][]*x509.Certificate{{cert}}}
+		return r
+	}
+	unverified := httptest.NewRequest(http.MethodGet, "/containers/web/json", nil)
+	alice := &x509.Certificate{Subject: pkix.Name{CommonName: "alice"}}
+	bob := &x509.Certificate{Subject: pkix.Name{CommonName: "bob"}, EmailAddresses: []string{"bob@example.com"}}
+	eve := &x509.Certificate{Subject: pkix.Name{CommonName: "eve"}}
+	unverified.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{alice}}
+
+	cases := []struct {
+		doc     string
+		r       *http.Request
+		allowed bool
+	}{
+		{"root is allowed everything", unix(http.MethodPost, "/v1.40/containers/db/stop", 0, 0), true},
+		{"readers can read", unix(http.MethodGet, "/v1.40/images/json", 1000, 1000, 999), true},
+		{"readers cannot write", unix(http.MethodPost, "/v1.40/images/create", 1000, 1000, 999), false},
+		{"unknown uid", unix(http.MethodGet, "/_ping", 1000, 1000), false},
+		{"labeled container", tlsRequest(http.MethodPost, "/v1.40/containers/web/start", "", alice), true},
+		{"container of another team", tlsRequest(http.MethodPost, "/v1.40/containers/db/start", "", alice), false},
+		{"unknown container", tlsRequest(http.MethodPost, "/v1.40/containers/foo/start", "", alice), false},
+		{"listing is not restricted to labels", tlsRequest(http.MethodGet, "/v1.40/containers/json", "", alice), false},
+		{"create with labels", tlsRequest(http.MethodPost, "/v1.40/containers/create", `{"Image":"busybox","Labels":{"team":"x"}}`, alice), true},
+		{"create without labels", tlsRequest(http.MethodPost, "/v1.40/containers/create", `{"Image":"busybox"}`, alice), false},
+		{"create joining a container of the team", tlsRequest(http.MethodPost, "/v1.40/containers/create", `{"Image":"busybox","Labels":{"team":"x"},"HostConfig":{"NetworkMode":"container:web","VolumesFrom":["web:ro"]}}`, alice), true},
+		{"create joining the network of another team", tlsRequest(http.MethodPost, "/v1.40/containers/create", `{"Image":"busybox","Labels":{"team":"x"},"HostConfig":{"NetworkMode":"container:db"}}`, alice), false},
+		{"create joining the pid namespace of another team", tlsRequest(http.MethodPost, "/v1.40/containers/create", `{"Image":"busybox","Labels":{"team":"x"},"HostConfig":{"PidMode":"container:db"}}`, alice), false},
+		{"create joining the ipc namespace of another team", tlsRequest(http.MethodPost, "/v1.40/containers/create", `{"Image":"busybox","Labels":{"team":"x"},"HostConfig":{"IpcMode":"container:db"}}`, alice), false},
+		{"create with the volumes of another team", tlsRequest(http.MethodPost, "/v1.40/containers/create", `{"Image":"busybox","Labels":{"team":"x"},"HostConfig":{"VolumesFrom":["db:rw"]}}`, alice), false},
+		{"create joining an unknown container", tlsRequest(http.MethodPost, "/v1.40/containers/create", `{"Image":"busybox","Labels":{"team":"x"},"HostConfig":{"NetworkMode":"container:foo"}}`, alice), false},
+		{"subject alternative name", tlsRequest(http.MethodGet, "/containers/web/json", "", bob), true},
+		{"unknown user", tlsRequest(http.MethodGet, "/containers/web/json", "", eve), false},
+		{"anonymous client", httptest.NewRequest(http.MethodGet, "/_ping", nil), false},
+		{"unverified certificate", unverified, false},
+	}
+	for _, c := range cases {
+		err := policy.authorize(newPolicyRequest(c.r, labels))
+		if c.allowed {
+			assert.Check(t, err, c.doc)
+		} else {
+			assert.Check(t, is.ErrorContains(err, "authorization denied by policy"), c.doc)
+		}
+	}
+}
+
+func TestPolicyKeepsRequestBody(t *testing.T) {
+	m := NewMiddleware(nil, nil)
+	m.SetPolicy(&Policy{Rules: []PolicyRule{{Labels: map[string]string{"team": "x"}}}})
+
+	body := `{"Image":"busybox","Labels":{"team":"x"}}`
+	r := httptest.NewRequest(http.MethodPost, "/containers/create", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		b, err := ioutil.ReadAll(r.Body)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(string(b), body))
+		return nil
+	}
+	assert.NilError(t, m.WrapHandler(handler)(context.Background(), httptest.NewRecorder(), r, nil))
+
+	m.SetPolicy(&Policy{})
+	r = httptest.NewRequest(http.MethodPost, "/containers/create", strings.NewReader(body))
+	err := m.WrapHandler(handler)(context.Background(), httptest.NewRecorder(), r, nil)
+	assert.Check(t, is.ErrorContains(err, "authorization denied by policy: anonymous client is not allowed to POST /containers/create"))
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "authz-policy")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "policy.json")
+	assert.NilError(t, ioutil.WriteFile(p, []byte(`{"rules":[{"name":"ops","uids":[0],"methods":["GET"],"endpoints":["/info"]}]}`), 0600))
+	policy, err := LoadPolicy(p)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(policy.Rules, 1))
+
+	assert.NilError(t, ioutil.WriteFile(p, []byte(`{"rules":[{"name":"ops","endpoints":["info"]}]}`), 0600))
+	_, err = LoadPolicy(p)
+	assert.Check(t, is.ErrorContains(err, "rule ops: endpoint \"info\" must start with /"))
+
+	assert.NilError(t, ioutil.WriteFile(p, []byte(`{"rules":[{"methods":["get"]}]}`), 0600))
+	_, err = LoadPolicy(p)
+	assert.Check(t, is.ErrorContains(err, "rule #0: invalid method \"get\""))
+}
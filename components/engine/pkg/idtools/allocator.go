@@ -0,0 +1,156 @@
+package idtools // import "github.com/docker/docker/pkg/idtools"
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RangeAllocator allocates distinct ranges of subordinate IDs, carved from the
+// /etc/subuid and /etc/subgid ranges of a user and a group, so that each
+// container can be given its own user namespace mapping.
+type RangeAllocator struct {
+	mu   sync.Mutex
+	size int
+	uids ranges
+	gids ranges
+	// allocated are the mappings which are in use, in no particular order.
+	allocated []*IdentityMapping
+}
+
+// NewRangeAllocator returns an allocator of ranges of size IDs from the
+// /etc/sub{uid,gid} ranges of the user and the group.
+func NewRangeAllocator(username, groupname string, size int) (*RangeAllocator, error) {
+	subuidRanges, err := parseSubuid(username)
+	if err != nil {
+		return nil, err
+	}
+	subgidRanges, err := parseSubgid(groupname)
+	if err != nil {
+		return nil, err
+	}
+	return newRangeAllocator(subuidRanges, subgidRanges, size)
+}
+
+func newRangeAllocator(uids, gids ranges, size int) (*RangeAllocator, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid size of ID ranges: %d", size)
+	}
+	a := &RangeAllocator{
+		size: size,
+		uids: uids,
+		gids: gids,
+	}
+	if len(a.slots(a.uids)) == 0 {
+		return nil, fmt.Errorf("No subuid range of at least %d IDs found", size)
+	}
+	if len(a.slots(a.gids)) == 0 {
+		return nil, fmt.Errorf("No subgid range of at least %d IDs found", size)
+	}
+	return a, nil
+}
+
+// Allocate returns a mapping of the container IDs 0 to size-1 to ranges of
+// host IDs which are not used by any other mapping of the allocator.
+func (a *RangeAllocator) Allocate() (*IdentityMapping, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	uid, ok := a.free(a.uids, (*IdentityMapping).UIDs)
+	if !ok {
+		return nil, fmt.Errorf("No free subuid range of %d IDs left", a.size)
+	}
+	gid, ok := a.free(a.gids, (*IdentityMapping).GIDs)
+	if !ok {
+		return nil, fmt.Errorf("No free subgid range of %d IDs left", a.size)
+	}
+	m := &IdentityMapping{
+		uids: []IDMap{{ContainerID: 0, HostID: uid, Size: a.size}},
+		gids: []IDMap{{ContainerID: 0, HostID: gid, Size: a.size}},
+	}
+	a.allocated = append(a.allocated, m)
+	return m, nil
+}
+
+// Reserve marks the ranges of a mapping allocated before, for example before
+// the daemon was restarted, as in use.
+func (a *RangeAllocator) Reserve(m *IdentityMapping) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, other := range a.allocated {
+		if overlaps(m.uids, other.uids) || overlaps(m.gids, other.gids) {
+			return fmt.Errorf("ID ranges %v:%v are already in use", m.uids, m.gids)
+		}
+	}
+	a.allocated = append(a.allocated, m)
+	return nil
+}
+
+// Release frees the ranges of a mapping returned by Allocate or passed to
+// Reserve.
+func (a *RangeAllocator) Release(m *IdentityMapping) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i, other := range a.allocated {
+		if sameIDMaps(m.uids, other.uids) && sameIDMaps(m.gids, other.gids) {
+			a.allocated = append(a.allocated[:i], a.allocated[i+1:]...)
+			return
+		}
+	}
+}
+
+// slots returns the first host IDs of the ranges of the allocator's size
+// which fit in the subordinate ranges.
+func (a *RangeAllocator) slots(subidRanges ranges) []int {
+	var starts []int
+	for _, r := range subidRanges {
+		for start := r.Start; start+a.size <= r.Start+r.Length; start += a.size {
+			starts = append(starts, start)
+		}
+	}
+	return starts
+}
+
+// free returns the first slot of the subordinate ranges which does not overlap
+// the ranges returned by idMaps for the allocated mappings.
+func (a *RangeAllocator) free(subidRanges ranges, idMaps func(*IdentityMapping) []IDMap) (int, bool) {
+	for _, start := range a.slots(subidRanges) {
+		slot := []IDMap{{HostID: start, Size: a.size}}
+		inUse := false
+		for _, m := range a.allocated {
+			if overlaps(slot, idMaps(m)) {
+				inUse = true
+				break
+			}
+		}
+		if !inUse {
+			return start, true
+		}
+	}
+	return 0, false
+}
+
+// overlaps returns whether some host IDs are mapped by both a and b.
+func overlaps(a, b []IDMap) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.HostID < y.HostID+y.Size && y.HostID < x.HostID+x.Size {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sameIDMaps(a, b []IDMap) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
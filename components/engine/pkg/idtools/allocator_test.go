@@ -0,0 +1,58 @@
+package idtools // import "github.com/docker/docker/pkg/idtools"
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestRangeAllocator(t *testing.T) {
+	a, err := newRangeAllocator(
+		ranges{{Start: 100000, Length: 65536}, {Start: 300000, Length: 10}},
+		ranges{{Start: 200000, Length: 65536}},
+		32768,
+	)
+	assert.NilError(t, err)
+
+	first, err := a.Allocate()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(first.UIDs(), []IDMap{{ContainerID: 0, HostID: 100000, Size: 32768}}))
+	assert.Check(t, is.DeepEqual(first.GIDs(), []IDMap{{ContainerID: 0, HostID: 200000, Size: 32768}}))
+	assert.Check(t, is.DeepEqual(first.RootPair(), Identity{UID: 100000, GID: 200000}))
+
+	second, err := a.Allocate()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(second.RootPair(), Identity{UID: 132768, GID: 232768}))
+
+	// The range at 300000 is too small.
+	_, err = a.Allocate()
+	assert.Check(t, is.ErrorContains(err, "No free subuid range"))
+
+	a.Release(first)
+	third, err := a.Allocate()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(third.RootPair(), Identity{UID: 100000, GID: 200000}))
+}
+
+func TestRangeAllocatorReserve(t *testing.T) {
+	a, err := newRangeAllocator(ranges{{Start: 100000, Length: 65536}}, ranges{{Start: 100000, Length: 65536}}, 1000)
+	assert.NilError(t, err)
+
+	// Mappings allocated with another size are kept.
+	reserved := NewIDMappingsFromMaps(
+		[]IDMap{{ContainerID: 0, HostID: 100500, Size: 1000}},
+		[]IDMap{{ContainerID: 0, HostID: 100500, Size: 1000}},
+	)
+	assert.NilError(t, a.Reserve(reserved))
+	assert.Check(t, is.ErrorContains(a.Reserve(reserved), "already in use"))
+
+	m, err := a.Allocate()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(m.RootPair(), Identity{UID: 102000, GID: 102000}))
+}
+
+func TestNewRangeAllocatorTooSmall(t *testing.T) {
+	_, err := newRangeAllocator(ranges{{Start: 100000, Length: 100}}, ranges{{Start: 100000, Length: 65536}}, 65536)
+	assert.Check(t, is.ErrorContains(err, "No subuid range"))
+}
@@ -0,0 +1,307 @@
+// Package jsonpatch applies JSON Patch documents, as defined in RFC 6902, to
+// JSON documents.
+package jsonpatch // import "github.com/docker/docker/pkg/jsonpatch"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Operation is an operation of a JSON patch.
+type Operation struct {
+	// Op is the operation, one of "add", "remove", "replace", "move", "copy"
+	// or "test".
+	Op string `json:"op"`
+	// Path is the JSON pointer to the target location of the operation.
+	Path string `json:"path"`
+	// From is the JSON pointer to the source location of the "move" and
+	// "copy" operations.
+	From string `json:"from,omitempty"`
+	// Value is the value of the "add", "replace" and "test" operations.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a JSON patch: a list of operations, applied in order.
+type Patch []Operation
+
+// Validate checks the syntax of the operations of the patch.
+func (p Patch) Validate() error {
+	for i, op := range p {
+		if _, err := parsePointer(op.Path); err != nil {
+			return fmt.Errorf("operation %d: %v", i, err)
+		}
+		switch op.Op {
+		case "add", "replace", "test":
+			if len(op.Value) == 0 {
+				return fmt.Errorf("operation %d: %s requires a value", i, op.Op)
+			}
+		case "remove":
+		case "move", "copy":
+			from, err := parsePointer(op.From)
+			if err != nil {
+				return fmt.Errorf("operation %d: invalid from: %v", i, err)
+			}
+			if op.Op == "move" && isPrefix(from, op.Path) {
+				return fmt.Errorf("operation %d: cannot move %s to one of its children", i, op.From)
+			}
+		default:
+			return fmt.Errorf("operation %d: invalid operation %q", i, op.Op)
+		}
+	}
+	return nil
+}
+
+// Apply applies the patch to the JSON document doc, and returns the patched
+// document. The document is not patched if any of the operations fails.
+func (p Patch) Apply(doc []byte) ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	d, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range p {
+		d, err = op.apply(d)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %v", i, err)
+		}
+	}
+	return json.Marshal(d)
+}
+
+func (op Operation) apply(doc interface{}) (interface{}, error) {
+	path, _ := parsePointer(op.Path)
+	switch op.Op {
+	case "add", "replace":
+		v, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, v, op.Op == "replace", op.Path)
+	case "remove":
+		doc, _, err := remove(doc, path, op.Path)
+		return doc, err
+	case "move":
+		from, _ := parsePointer(op.From)
+		doc, v, err := remove(doc, from, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, v, false, op.Path)
+	case "copy":
+		from, _ := parsePointer(op.From)
+		v, err := get(doc, from, op.From)
+		if err != nil {
+			return nil, err
+		}
+		// The value is copied, so that the copies are not shared.
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if v, err = decode(b); err != nil {
+			return nil, err
+		}
+		return add(doc, path, v, false, op.Path)
+	case "test":
+		expected, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		v, err := get(doc, path, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(v, expected) {
+			return nil, fmt.Errorf("test failed: %s is not %s", op.Path, op.Value)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("invalid operation %q", op.Op)
+}
+
+// decode decodes a JSON value, keeping the numbers as json.Number so that
+// large integers are not rounded.
+func decode(b []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// parsePointer parses a JSON pointer, as defined in RFC 6901, to the list of
+// its reference tokens.
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: it must start with /", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(t, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// isPrefix returns whether the location from is path, or one of its parents.
+func isPrefix(from []string, path string) bool {
+	p, err := parsePointer(path)
+	if err != nil || len(p) < len(from) {
+		return false
+	}
+	for i := range from {
+		if from[i] != p[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayIndex returns the index referenced by token in an array, which must
+// be lower than max.
+func arrayIndex(token string, max int, pointer string) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q in %s", token, pointer)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("invalid array index %q in %s", token, pointer)
+	}
+	if i >= max {
+		return 0, fmt.Errorf("array index %d out of range in %s", i, pointer)
+	}
+	return i, nil
+}
+
+func get(doc interface{}, path []string, pointer string) (interface{}, error) {
+	for _, token := range path {
+		switch d := doc.(type) {
+		case map[string]interface{}:
+			v, ok := d[token]
+			if !ok {
+				return nil, fmt.Errorf("%s does not exist", pointer)
+			}
+			doc = v
+		case []interface{}:
+			i, err := arrayIndex(token, len(d), pointer)
+			if err != nil {
+				return nil, err
+			}
+			doc = d[i]
+		default:
+			return nil, fmt.Errorf("%s does not exist", pointer)
+		}
+	}
+	return doc, nil
+}
+
+// add adds, or replaces, the value at path in doc, and returns the document.
+func add(doc interface{}, path []string, value interface{}, replace bool, pointer string) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token := path[0]
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			if _, ok := d[token]; replace && !ok {
+				return nil, fmt.Errorf("%s does not exist", pointer)
+			}
+			d[token] = value
+			return d, nil
+		}
+		v, ok := d[token]
+		if !ok {
+			return nil, fmt.Errorf("%s does not exist", pointer)
+		}
+		v, err := add(v, path[1:], value, replace, pointer)
+		if err != nil {
+			return nil, err
+		}
+		d[token] = v
+		return d, nil
+	case []interface{}:
+		if len(path) == 1 && !replace {
+			if token == "-" {
+				return append(d, value), nil
+			}
+			i, err := arrayIndex(token, len(d)+1, pointer)
+			if err != nil {
+				return nil, err
+			}
+			d = append(d, nil)
+			copy(d[i+1:], d[i:])
+			d[i] = value
+			return d, nil
+		}
+		i, err := arrayIndex(token, len(d), pointer)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 1 {
+			d[i] = value
+			return d, nil
+		}
+		v, err := add(d[i], path[1:], value, replace, pointer)
+		if err != nil {
+			return nil, err
+		}
+		d[i] = v
+		return d, nil
+	default:
+		return nil, fmt.Errorf("%s does not exist", pointer)
+	}
+}
+
+// remove removes the value at path in doc, and returns the document and the
+// removed value.
+func remove(doc interface{}, path []string, pointer string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the whole document")
+	}
+	token := path[0]
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		v, ok := d[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s does not exist", pointer)
+		}
+		if len(path) == 1 {
+			delete(d, token)
+			return d, v, nil
+		}
+		v, removed, err := remove(v, path[1:], pointer)
+		if err != nil {
+			return nil, nil, err
+		}
+		d[token] = v
+		return d, removed, nil
+	case []interface{}:
+		i, err := arrayIndex(token, len(d), pointer)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(path) == 1 {
+			v := d[i]
+			return append(d[:i], d[i+1:]...), v, nil
+		}
+		v, removed, err := remove(d[i], path[1:], pointer)
+		if err != nil {
+			return nil, nil, err
+		}
+		d[i] = v
+		return d, removed, nil
+	default:
+		return nil, nil, fmt.Errorf("%s does not exist", pointer)
+	}
+}
@@ -0,0 +1,85 @@
+package jsonpatch // import "github.com/docker/docker/pkg/jsonpatch"
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func mustPatch(t *testing.T, s string) Patch {
+	t.Helper()
+	var p Patch
+	assert.NilError(t, json.Unmarshal([]byte(s), &p))
+	return p
+}
+
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		doc, patch, expected string
+	}{
+		{
+			doc:      `{"a":{"b":1}}`,
+			patch:    `[{"op":"add","path":"/a/c","value":[1,2]}]`,
+			expected: `{"a":{"b":1,"c":[1,2]}}`,
+		},
+		{
+			doc:      `{"a":[1,3]}`,
+			patch:    `[{"op":"add","path":"/a/1","value":2},{"op":"add","path":"/a/-","value":4}]`,
+			expected: `{"a":[1,2,3,4]}`,
+		},
+		{
+			doc:      `{"a":[1,2,3],"b":"c"}`,
+			patch:    `[{"op":"remove","path":"/a/0"},{"op":"remove","path":"/b"}]`,
+			expected: `{"a":[2,3]}`,
+		},
+		{
+			doc:      `{"a":{"b":1},"c":[1]}`,
+			patch:    `[{"op":"replace","path":"/a/b","value":{"x":true}},{"op":"replace","path":"/c/0","value":null}]`,
+			expected: `{"a":{"b":{"x":true}},"c":[null]}`,
+		},
+		{
+			doc:      `{"a":{"b":1},"c":{}}`,
+			patch:    `[{"op":"move","from":"/a/b","path":"/c/d"},{"op":"copy","from":"/c","path":"/e"}]`,
+			expected: `{"a":{},"c":{"d":1},"e":{"d":1}}`,
+		},
+		{
+			doc:      `{"a/b":{"m~n":1}}`,
+			patch:    `[{"op":"test","path":"/a~1b/m~0n","value":1},{"op":"add","path":"/a~1b/x","value":2}]`,
+			expected: `{"a/b":{"m~n":1,"x":2}}`,
+		},
+		{
+			// large integers are not rounded
+			doc:      `{"limit":18446744073709551615}`,
+			patch:    `[{"op":"add","path":"/x","value":9223372036854775807}]`,
+			expected: `{"limit":18446744073709551615,"x":9223372036854775807}`,
+		},
+	}
+	for _, tc := range testCases {
+		out, err := mustPatch(t, tc.patch).Apply([]byte(tc.doc))
+		assert.NilError(t, err, tc.patch)
+		assert.Check(t, is.Equal(string(out), tc.expected), tc.patch)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	testCases := []struct {
+		patch, expected string
+	}{
+		{`[{"op":"add","path":"/x/y","value":1}]`, "operation 0: /x/y does not exist"},
+		{`[{"op":"replace","path":"/x","value":1}]`, "operation 0: /x does not exist"},
+		{`[{"op":"remove","path":"/a/5"}]`, "operation 0: array index 5 out of range in /a/5"},
+		{`[{"op":"remove","path":"/a/01"}]`, `operation 0: invalid array index "01" in /a/01`},
+		{`[{"op":"test","path":"/a/0","value":2}]`, "operation 0: test failed: /a/0 is not 2"},
+		{`[{"op":"add","path":"/b","value":1},{"op":"test","path":"/b","value":"1"}]`, `operation 1: test failed: /b is not "1"`},
+		{`[{"op":"add","path":"a"}]`, `operation 0: invalid JSON pointer "a": it must start with /`},
+		{`[{"op":"add","path":"/a"}]`, "operation 0: add requires a value"},
+		{`[{"op":"move","from":"/a","path":"/a/0"}]`, "operation 0: cannot move /a to one of its children"},
+		{`[{"op":"merge","path":"/a"}]`, `operation 0: invalid operation "merge"`},
+	}
+	for _, tc := range testCases {
+		_, err := mustPatch(t, tc.patch).Apply([]byte(`{"a":[1]}`))
+		assert.Check(t, is.Error(err, tc.expected), tc.patch)
+	}
+}
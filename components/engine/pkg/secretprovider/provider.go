@@ -0,0 +1,107 @@
+// Package secretprovider fetches the values of secrets from secret provider
+// plugins, so that secrets are stored outside of the daemon and of the swarm.
+//
+// The protocol is the one used by swarm for the secrets of services, extended
+// with the details of the container the secret is mounted in.
+package secretprovider // import "github.com/docker/docker/pkg/secretprovider"
+
+import (
+	"errors"
+
+	"github.com/docker/docker/pkg/plugingetter"
+	"github.com/docker/docker/pkg/plugins"
+)
+
+const (
+	// SecretProviderAPIImplements is the name of the interface that all
+	// secret provider plugins implement.
+	SecretProviderAPIImplements = "secretprovider"
+
+	// SecretProviderAPIGetSecret is the url for fetching the value of a
+	// secret.
+	SecretProviderAPIGetSecret = "/SecretProvider.GetSecret"
+)
+
+// Request is the request sent to a secret provider plugin to fetch the value
+// of a secret.
+type Request struct {
+	SecretName   string            `json:",omitempty"` // SecretName is the name of the secret to request from the plugin
+	SecretLabels map[string]string `json:",omitempty"` // SecretLabels capture environment names and other metadata pertaining to the secret
+
+	// The fields below are set for the secrets of swarm services.
+	ServiceHostname string            `json:",omitempty"` // ServiceHostname is the hostname of the service, can be used for x509 certificate
+	ServiceID       string            `json:",omitempty"` // ServiceID is the name of the service that requested the secret
+	ServiceName     string            `json:",omitempty"` // ServiceName is the name of the service that requested the secret
+	ServiceLabels   map[string]string `json:",omitempty"` // ServiceLabels capture environment names and other metadata pertaining to the service
+	TaskID          string            `json:",omitempty"` // TaskID is the ID of the task that the secret will be assigned to
+	TaskName        string            `json:",omitempty"` // TaskName is the name of the task that the secret will be assigned to
+	TaskImage       string            `json:",omitempty"` // TaskImage is the image of the task that the secret will be assigned to
+	NodeID          string            `json:",omitempty"` // NodeID is the ID of the node that the task will be executed on
+
+	// The fields below are set for the secrets of containers which are not
+	// swarm tasks.
+	ContainerID       string            `json:",omitempty"` // ContainerID is the ID of the container that the secret is mounted in
+	ContainerName     string            `json:",omitempty"` // ContainerName is the name of the container that the secret is mounted in
+	ContainerHostname string            `json:",omitempty"` // ContainerHostname is the hostname of the container
+	ContainerImage    string            `json:",omitempty"` // ContainerImage is the image of the container
+	ContainerLabels   map[string]string `json:",omitempty"` // ContainerLabels are the labels of the container
+	DriverOptions     map[string]string `json:",omitempty"` // DriverOptions are the options of the driver of the secret
+}
+
+// Response is the response of a secret provider plugin.
+type Response struct {
+	Value []byte `json:",omitempty"` // Value is the value of the secret
+	Err   string `json:",omitempty"` // Err is the error response of the plugin
+
+	// DoNotReuse indicates that the value should only be used for the
+	// container or the task it was requested for, and that the plugin must
+	// be called again for other containers.
+	DoNotReuse bool `json:",omitempty"`
+}
+
+// Provider is a secret provider plugin.
+type Provider interface {
+	// Name returns the name of the plugin.
+	Name() string
+	// GetSecret fetches the value of a secret. It returns whether the value
+	// can be reused for other containers.
+	GetSecret(req *Request) (value []byte, reuse bool, err error)
+}
+
+type provider struct {
+	name   string
+	client *plugins.Client
+}
+
+// Get returns the secret provider plugin with the given name.
+func Get(pg plugingetter.PluginGetter, name string) (Provider, error) {
+	if pg == nil {
+		return nil, errors.New("secret provider plugins are not supported")
+	}
+	p, err := pg.Get(name, SecretProviderAPIImplements, plugingetter.Lookup)
+	if err != nil {
+		return nil, err
+	}
+	return NewProvider(p.Name(), p.Client()), nil
+}
+
+// NewProvider returns a secret provider which calls the plugin with the given
+// name through client.
+func NewProvider(name string, client *plugins.Client) Provider {
+	return &provider{name: name, client: client}
+}
+
+func (p *provider) Name() string {
+	return p.name
+}
+
+func (p *provider) GetSecret(req *Request) ([]byte, bool, error) {
+	var resp Response
+	if err := p.client.Call(SecretProviderAPIGetSecret, req, &resp); err != nil {
+		return nil, false, err
+	}
+	if resp.Err != "" {
+		return nil, false, errors.New(resp.Err)
+	}
+	return resp.Value, !resp.DoNotReuse, nil
+}
@@ -0,0 +1,77 @@
+package secretprovider // import "github.com/docker/docker/pkg/secretprovider"
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/go-connections/tlsconfig"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newTestProvider(t *testing.T, handler func(req Request) Response) (Provider, func()) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc(SecretProviderAPIGetSecret, func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		json.NewEncoder(w).Encode(handler(req))
+	})
+
+	u, err := url.Parse(server.URL)
+	assert.NilError(t, err)
+	client, err := plugins.NewClient("tcp://"+u.Host, &tlsconfig.Options{InsecureSkipVerify: true})
+	assert.NilError(t, err)
+	return NewProvider("vault", client), server.Close
+}
+
+func TestGetSecret(t *testing.T) {
+	var received Request
+	p, cleanup := newTestProvider(t, func(req Request) Response {
+		received = req
+		return Response{Value: []byte("s3cr3t")}
+	})
+	defer cleanup()
+
+	value, reuse, err := p.GetSecret(&Request{
+		SecretName:      "db-password",
+		ContainerName:   "web",
+		ContainerLabels: map[string]string{"env": "prod"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(value), "s3cr3t"))
+	assert.Check(t, reuse)
+	assert.Check(t, is.Equal(received.SecretName, "db-password"))
+	assert.Check(t, is.Equal(received.ContainerName, "web"))
+	assert.Check(t, is.DeepEqual(received.ContainerLabels, map[string]string{"env": "prod"}))
+}
+
+func TestGetSecretDoNotReuse(t *testing.T) {
+	p, cleanup := newTestProvider(t, func(req Request) Response {
+		return Response{Value: []byte("one-time"), DoNotReuse: true}
+	})
+	defer cleanup()
+
+	value, reuse, err := p.GetSecret(&Request{SecretName: "token"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(value), "one-time"))
+	assert.Check(t, !reuse)
+}
+
+func TestGetSecretError(t *testing.T) {
+	p, cleanup := newTestProvider(t, func(req Request) Response {
+		return Response{Err: "permission denied"}
+	})
+	defer cleanup()
+
+	_, _, err := p.GetSecret(&Request{SecretName: "token"})
+	assert.Check(t, is.Error(err, "permission denied"))
+}
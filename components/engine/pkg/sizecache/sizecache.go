@@ -0,0 +1,135 @@
+/*
+Package sizecache provides a cache for expensive disk usage calculations.
+
+Sizes are cached per key (for example a volume name or a container ID) and
+are recalculated once they are older than the configured maximum age, or when
+the owner of the cache invalidates them because the underlying object changed.
+The number of calculations running at the same time is bounded so that disk
+usage requests cannot starve the rest of the daemon of I/O.
+*/
+package sizecache // import "github.com/docker/docker/pkg/sizecache"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/sync/singleflight"
+)
+
+// SizeFunc calculates the size of the object identified by a cache key.
+type SizeFunc func(ctx context.Context) (int64, error)
+
+type entry struct {
+	size       int64
+	computedAt time.Time
+}
+
+// calculation tracks a calculation in progress. A calculation is marked stale
+// if its key is invalidated while it runs, in which case its result is not
+// stored.
+type calculation struct {
+	stale bool
+}
+
+// Cache stores calculated sizes by key.
+type Cache struct {
+	mu       sync.Mutex
+	maxAge   time.Duration
+	entries  map[string]entry
+	inflight map[string]*calculation
+
+	sem   *semaphore.Weighted
+	group singleflight.Group
+	now   func() time.Time
+}
+
+// New creates a new cache. Cached sizes older than maxAge are recalculated
+// on the next lookup; a maxAge of zero disables expiry. At most concurrency
+// calculations are run at the same time.
+func New(maxAge time.Duration, concurrency int) *Cache {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Cache{
+		maxAge:   maxAge,
+		entries:  make(map[string]entry),
+		inflight: make(map[string]*calculation),
+		sem:      semaphore.NewWeighted(int64(concurrency)),
+		now:      time.Now,
+	}
+}
+
+// Get returns the size for key. The cached size is returned if it is still
+// fresh; otherwise, or if fresh is set, the size is recalculated using fn.
+// Concurrent lookups of the same key share a single calculation. The
+// calculation is not bound to the context of the lookup which started it, so
+// that canceling one lookup does not fail the others; each lookup only stops
+// waiting when its own context is done.
+func (c *Cache) Get(ctx context.Context, key string, fresh bool, fn SizeFunc) (int64, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && !fresh && (c.maxAge == 0 || c.now().Sub(e.computedAt) < c.maxAge) {
+		return e.size, nil
+	}
+
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		ctx := context.Background()
+		if err := c.sem.Acquire(ctx, 1); err != nil {
+			return int64(0), err
+		}
+		defer c.sem.Release(1)
+
+		calc := &calculation{}
+		c.mu.Lock()
+		c.inflight[key] = calc
+		c.mu.Unlock()
+
+		size, err := fn(ctx)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.inflight[key] == calc {
+			delete(c.inflight, key)
+		}
+		if err != nil {
+			return size, err
+		}
+		if !calc.stale {
+			c.entries[key] = entry{size: size, computedAt: c.now()}
+		}
+		return size, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return 0, res.Err
+		}
+		return res.Val.(int64), nil
+	}
+}
+
+// Invalidate drops the cached size for key, so that it is recalculated on
+// the next lookup.
+func (c *Cache) Invalidate(key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	if calc, ok := c.inflight[key]; ok {
+		calc.stale = true
+	}
+	c.mu.Unlock()
+	c.group.Forget(key)
+}
+
+// Len returns the number of cached sizes.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -0,0 +1,184 @@
+package sizecache // import "github.com/docker/docker/pkg/sizecache"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func counter(size int64, calls *int32) SizeFunc {
+	return func(ctx context.Context) (int64, error) {
+		atomic.AddInt32(calls, 1)
+		return size, nil
+	}
+}
+
+func TestGetCaches(t *testing.T) {
+	c := New(0, 1)
+	var calls int32
+
+	size, err := c.Get(context.Background(), "a", false, counter(42, &calls))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(int64(42), size))
+
+	size, err = c.Get(context.Background(), "a", false, counter(43, &calls))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(int64(42), size))
+	assert.Check(t, is.Equal(int32(1), atomic.LoadInt32(&calls)))
+	assert.Check(t, is.Equal(1, c.Len()))
+}
+
+func TestGetFresh(t *testing.T) {
+	c := New(0, 1)
+	var calls int32
+
+	_, err := c.Get(context.Background(), "a", false, counter(42, &calls))
+	assert.NilError(t, err)
+
+	size, err := c.Get(context.Background(), "a", true, counter(43, &calls))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(int64(43), size))
+	assert.Check(t, is.Equal(int32(2), atomic.LoadInt32(&calls)))
+}
+
+func TestGetExpired(t *testing.T) {
+	c := New(time.Minute, 1)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	var calls int32
+
+	_, err := c.Get(context.Background(), "a", false, counter(42, &calls))
+	assert.NilError(t, err)
+
+	now = now.Add(30 * time.Second)
+	size, err := c.Get(context.Background(), "a", false, counter(43, &calls))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(int64(42), size))
+
+	now = now.Add(time.Minute)
+	size, err = c.Get(context.Background(), "a", false, counter(43, &calls))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(int64(43), size))
+	assert.Check(t, is.Equal(int32(2), atomic.LoadInt32(&calls)))
+}
+
+func TestInvalidate(t *testing.T) {
+	c := New(0, 1)
+	var calls int32
+
+	_, err := c.Get(context.Background(), "a", false, counter(42, &calls))
+	assert.NilError(t, err)
+
+	c.Invalidate("a")
+	assert.Check(t, is.Equal(0, c.Len()))
+
+	size, err := c.Get(context.Background(), "a", false, counter(43, &calls))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(int64(43), size))
+}
+
+func TestInvalidateDuringCalculation(t *testing.T) {
+	c := New(0, 1)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		size, err := c.Get(context.Background(), "a", false, func(ctx context.Context) (int64, error) {
+			close(started)
+			<-release
+			return 42, nil
+		})
+		assert.Check(t, err)
+		assert.Check(t, is.Equal(int64(42), size))
+	}()
+
+	<-started
+	c.Invalidate("a")
+	close(release)
+	<-done
+
+	// the result of the calculation was invalidated while it was running, so
+	// it must not have been cached.
+	assert.Check(t, is.Equal(0, c.Len()))
+}
+
+func TestGetCanceledDoesNotFailWaiters(t *testing.T) {
+	c := New(0, 1)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	// the first lookup starts the calculation, and is canceled while it runs
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := c.Get(ctx, "a", false, func(ctx context.Context) (int64, error) {
+			close(started)
+			<-release
+			return 42, ctx.Err()
+		})
+		first <- err
+	}()
+	<-started
+
+	second := make(chan int64)
+	go func() {
+		size, err := c.Get(context.Background(), "a", false, counter(43, new(int32)))
+		assert.Check(t, err)
+		second <- size
+	}()
+
+	cancel()
+	assert.Check(t, is.ErrorContains(<-first, "context canceled"))
+	close(release)
+	assert.Check(t, is.Equal(int64(42), <-second))
+	assert.Check(t, is.Equal(1, c.Len()))
+}
+
+func TestGetError(t *testing.T) {
+	c := New(0, 1)
+
+	_, err := c.Get(context.Background(), "a", false, func(ctx context.Context) (int64, error) {
+		return 0, errors.New("boom")
+	})
+	assert.Check(t, is.Error(err, "boom"))
+	assert.Check(t, is.Equal(0, c.Len()))
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	const limit = 2
+	c := New(0, limit)
+
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			_, err := c.Get(context.Background(), key, false, func(ctx context.Context) (int64, error) {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return 1, nil
+			})
+			assert.Check(t, err)
+		}(key)
+	}
+	wg.Wait()
+
+	assert.Check(t, atomic.LoadInt32(&maxRunning) <= limit)
+	assert.Check(t, is.Equal(6, c.Len()))
+}
@@ -491,6 +491,10 @@ func (r *pluginReference) References(id digest.Digest) []reference.Named {
 }
 
 func (r *pluginReference) ReferencesByName(ref reference.Named) []refstore.Association {
+	return r.Associations()
+}
+
+func (r *pluginReference) Associations() []refstore.Association {
 	return []refstore.Association{
 		{
 			Ref: r.name,
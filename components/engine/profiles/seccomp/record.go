@@ -0,0 +1,93 @@
+// +build linux
+
+package seccomp // import "github.com/docker/docker/profiles/seccomp"
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/docker/docker/api/types"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	libseccomp "github.com/seccomp/libseccomp-golang"
+)
+
+// x32SyscallBit is set in the numbers of the x32 system calls, which are
+// logged with the x86_64 audit architecture.
+const x32SyscallBit = 0x40000000
+
+// auditArches maps the AUDIT_ARCH_* values logged by the kernel for the
+// system calls to the libseccomp architectures.
+var auditArches = map[uint32]libseccomp.ScmpArch{
+	0x40000003: libseccomp.ArchX86,
+	0xc000003e: libseccomp.ArchAMD64,
+	0x40000028: libseccomp.ArchARM,
+	0xc00000b7: libseccomp.ArchARM64,
+	0x00000008: libseccomp.ArchMIPS,
+	0x80000008: libseccomp.ArchMIPS64,
+	0xa0000008: libseccomp.ArchMIPS64N32,
+	0x40000008: libseccomp.ArchMIPSEL,
+	0xc0000008: libseccomp.ArchMIPSEL64,
+	0xe0000008: libseccomp.ArchMIPSEL64N32,
+	0x00000014: libseccomp.ArchPPC,
+	0x80000015: libseccomp.ArchPPC64,
+	0xc0000015: libseccomp.ArchPPC64LE,
+	0x00000016: libseccomp.ArchS390,
+	0x80000016: libseccomp.ArchS390X,
+}
+
+// GetRecordingProfile returns a profile which allows all the system calls and
+// logs them to the kernel log, so that the system calls used by a container
+// can be recorded.
+func GetRecordingProfile(rs *specs.Spec) (*specs.LinuxSeccomp, error) {
+	return setupSeccomp(&types.Seccomp{
+		DefaultAction: types.ActLog,
+		ArchMap:       arches(),
+	}, rs)
+}
+
+// SyscallName returns the name of a system call from its number and the
+// audit architecture it was made with, as logged by the kernel.
+func SyscallName(auditArch uint32, nr int) (string, error) {
+	arch, ok := auditArches[auditArch]
+	if !ok {
+		return "", fmt.Errorf("unknown audit architecture %x", auditArch)
+	}
+	if arch == libseccomp.ArchAMD64 && nr&x32SyscallBit != 0 {
+		arch = libseccomp.ArchX32
+	}
+	return libseccomp.ScmpSyscall(nr).GetNameByArch(arch)
+}
+
+// ProfileFromSyscalls returns a profile which only allows the given system
+// calls, on the architectures of the default profile.
+func ProfileFromSyscalls(names []string) *types.Seccomp {
+	names = append([]string(nil), names...)
+	sort.Strings(names)
+	return &types.Seccomp{
+		DefaultAction: types.ActErrno,
+		ArchMap:       arches(),
+		Syscalls: []*types.Syscall{
+			{
+				Names:  names,
+				Action: types.ActAllow,
+				Args:   []*types.Arg{},
+			},
+		},
+	}
+}
+
+// AllowedSyscalls returns the names of the system calls which a profile
+// allows unconditionally.
+func AllowedSyscalls(p *types.Seccomp) []string {
+	var names []string
+	for _, call := range p.Syscalls {
+		if call.Action != types.ActAllow || len(call.Args) > 0 {
+			continue
+		}
+		if call.Name != "" {
+			names = append(names, call.Name)
+		}
+		names = append(names, call.Names...)
+	}
+	return names
+}
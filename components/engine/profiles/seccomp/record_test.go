@@ -0,0 +1,44 @@
+// +build linux
+
+package seccomp // import "github.com/docker/docker/profiles/seccomp"
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/oci"
+)
+
+func TestProfileFromSyscalls(t *testing.T) {
+	p := ProfileFromSyscalls([]string{"write", "read", "exit_group"})
+	if p.DefaultAction != types.ActErrno {
+		t.Fatalf("expected default action %s, got %s", types.ActErrno, p.DefaultAction)
+	}
+	if names := AllowedSyscalls(p); !reflect.DeepEqual(names, []string{"exit_group", "read", "write"}) {
+		t.Fatalf("unexpected allowed syscalls: %v", names)
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := oci.DefaultSpec()
+	if _, err := LoadProfile(string(b), &rs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyscallName(t *testing.T) {
+	name, err := SyscallName(0xc000003e, 59)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "execve" {
+		t.Fatalf("expected execve, got %s", name)
+	}
+	if _, err := SyscallName(0x12345678, 59); err == nil {
+		t.Fatal("expected an error for an unknown architecture")
+	}
+}
@@ -30,6 +30,7 @@ type Association struct {
 type Store interface {
 	References(id digest.Digest) []reference.Named
 	ReferencesByName(ref reference.Named) []Association
+	Associations() []Association
 	AddTag(ref reference.Named, id digest.Digest, force bool) error
 	AddDigest(ref reference.Canonical, id digest.Digest, force bool) error
 	Delete(ref reference.Named) (bool, error)
@@ -311,6 +312,28 @@ func (store *store) ReferencesByName(ref reference.Named) []Association {
 	return associations
 }
 
+// Associations returns all references in the store, sorted by reference.
+func (store *store) Associations() []Association {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	var associations []Association
+	for _, repository := range store.Repositories {
+		for refStr, refID := range repository {
+			ref, err := reference.ParseNormalizedNamed(refStr)
+			if err != nil {
+				// Should never happen
+				continue
+			}
+			associations = append(associations, Association{Ref: ref, ID: refID})
+		}
+	}
+
+	sort.Sort(lexicalAssociations(associations))
+
+	return associations
+}
+
 func (store *store) save() error {
 	// Store the json
 	jsonData, err := json.Marshal(store)
@@ -62,6 +62,27 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestAssociations(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "tag-store-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	jsonFile := filepath.Join(tmpDir, "repositories.json")
+	assert.NilError(t, ioutil.WriteFile(jsonFile, marshalledSaveLoadTestCases, 0600))
+
+	store, err := NewReferenceStore(jsonFile)
+	assert.NilError(t, err)
+
+	associations := store.Associations()
+	assert.Check(t, is.Len(associations, len(saveLoadTestCases)))
+	for i, a := range associations {
+		if i > 0 {
+			assert.Check(t, associations[i-1].Ref.String() < a.Ref.String())
+		}
+		assert.Check(t, is.Equal(saveLoadTestCases[reference.FamiliarString(a.Ref)], a.ID))
+	}
+}
+
 func TestSave(t *testing.T) {
 	jsonFile, err := ioutil.TempFile("", "tag-store-test")
 	assert.NilError(t, err)
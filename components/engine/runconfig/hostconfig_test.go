@@ -121,11 +121,14 @@ func TestIpcModeTest(t *testing.T) {
 
 func TestUTSModeTest(t *testing.T) {
 	utsModes := map[container.UTSMode][]bool{
-		// private, host, valid
-		"":                {true, false, true},
-		"something:weird": {true, false, false},
-		"host":            {false, true, true},
-		"host:name":       {true, false, true},
+		// private, host, container, valid
+		"":                {true, false, false, true},
+		"something:weird": {true, false, false, false},
+		"host":            {false, true, false, true},
+		"host:name":       {true, false, false, true},
+		"container":       {true, false, false, false},
+		"container:":      {false, false, true, false},
+		"container:name":  {false, false, true, true},
 	}
 	for utsMode, state := range utsModes {
 		if utsMode.IsPrivate() != state[0] {
@@ -134,10 +137,14 @@ func TestUTSModeTest(t *testing.T) {
 		if utsMode.IsHost() != state[1] {
 			t.Fatalf("UtsMode.IsHost for %v should have been %v but was %v", utsMode, state[1], utsMode.IsHost())
 		}
-		if utsMode.Valid() != state[2] {
-			t.Fatalf("UtsMode.Valid for %v should have been %v but was %v", utsMode, state[2], utsMode.Valid())
+		if utsMode.IsContainer() != state[2] {
+			t.Fatalf("UtsMode.IsContainer for %v should have been %v but was %v", utsMode, state[2], utsMode.IsContainer())
+		}
+		if utsMode.Valid() != state[3] {
+			t.Fatalf("UtsMode.Valid for %v should have been %v but was %v", utsMode, state[3], utsMode.Valid())
 		}
 	}
+	assert.Check(t, is.Equal(container.UTSMode("container:name").Container(), "name"))
 }
 
 func TestUsernsModeTest(t *testing.T) {
@@ -147,6 +154,7 @@ func TestUsernsModeTest(t *testing.T) {
 		"something:weird": {true, false, false},
 		"host":            {false, true, true},
 		"host:name":       {true, false, true},
+		"auto":            {true, false, true},
 	}
 	for usernsMode, state := range usrensMode {
 		if usernsMode.IsPrivate() != state[0] {
@@ -35,7 +35,7 @@ func validateNetMode(c *container.Config, hc *container.HostConfig) error {
 		return err
 	}
 
-	if hc.UTSMode.IsHost() && c.Hostname != "" {
+	if (hc.UTSMode.IsHost() || hc.UTSMode.IsContainer()) && c.Hostname != "" {
 		return ErrConflictUTSHostname
 	}
 
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -9,6 +10,7 @@ import (
 	"github.com/docker/docker/pkg/directory"
 	"github.com/docker/docker/volume"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/semaphore"
 )
 
 // convertOpts are used to pass options to `volumeToAPI`
@@ -24,14 +26,21 @@ type calcSize bool
 
 func (calcSize) isConvertOpt() {}
 
+// freshSize forces the size of volumes to be recalculated instead of using
+// the cached size.
+type freshSize bool
+
+func (freshSize) isConvertOpt() {}
+
 type pathCacher interface {
 	CachedPath() string
 }
 
-func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volume, opts ...convertOpt) []*types.Volume {
+func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volume, opts ...convertOpt) ([]*types.Volume, error) {
 	var (
 		out        = make([]*types.Volume, 0, len(volumes))
 		getSize    bool
+		fresh      bool
 		cachedPath bool
 	)
 
@@ -39,6 +48,8 @@ func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volu
 		switch t := o.(type) {
 		case calcSize:
 			getSize = bool(t)
+		case freshSize:
+			fresh = bool(t)
 		case useCachedPath:
 			cachedPath = bool(t)
 		}
@@ -46,7 +57,7 @@ func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volu
 	for _, v := range volumes {
 		select {
 		case <-ctx.Done():
-			return nil
+			return nil, ctx.Err()
 		default:
 		}
 		apiV := volumeToAPIType(v)
@@ -59,22 +70,47 @@ func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volu
 			apiV.Mountpoint = v.Path()
 		}
 
-		if getSize {
+		if getSize && apiV.Mountpoint == "" {
+			apiV.Mountpoint = v.Path()
+		}
+
+		out = append(out, &apiV)
+	}
+	if getSize {
+		if err := s.volumesSize(ctx, volumes, out, fresh); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// volumesSize sets the usage data of the API volumes, calculating at most
+// sizeConcurrency volume sizes at the same time.
+func (s *VolumesService) volumesSize(ctx context.Context, volumes []volume.Volume, out []*types.Volume, fresh bool) error {
+	var wg sync.WaitGroup
+	sem := semaphore.NewWeighted(sizeConcurrency)
+	for i, v := range volumes {
+		if err := sem.Acquire(ctx, 1); err != nil {
+			wg.Wait()
+			return err
+		}
+		wg.Add(1)
+		go func(v volume.Volume, apiV *types.Volume) {
+			defer wg.Done()
+			defer sem.Release(1)
 			p := v.Path()
-			if apiV.Mountpoint == "" {
-				apiV.Mountpoint = p
-			}
-			sz, err := directory.Size(ctx, p)
+			sz, err := s.sizes.Get(ctx, v.Name(), fresh, func(ctx context.Context) (int64, error) {
+				return directory.Size(ctx, p)
+			})
 			if err != nil {
 				logrus.WithError(err).WithField("volume", v.Name()).Warnf("Failed to determine size of volume")
 				sz = -1
 			}
 			apiV.UsageData = &types.VolumeUsageData{Size: sz, RefCount: int64(s.vs.CountReferences(v))}
-		}
-
-		out = append(out, &apiV)
+		}(v, out[i])
 	}
-	return out
+	wg.Wait()
+	return nil
 }
 
 func volumeToAPIType(v volume.Volume) types.Volume {
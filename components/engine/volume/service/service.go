@@ -2,7 +2,9 @@ package service // import "github.com/docker/docker/volume/service"
 
 import (
 	"context"
+	"os"
 	"sync/atomic"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -10,6 +12,7 @@ import (
 	"github.com/docker/docker/pkg/directory"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/plugingetter"
+	"github.com/docker/docker/pkg/sizecache"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/volume"
 	"github.com/docker/docker/volume/drivers"
@@ -26,6 +29,15 @@ type volumeEventLogger interface {
 	LogVolumeEvent(volumeID, action string, attributes map[string]string)
 }
 
+const (
+	// sizeCacheMaxAge is the maximum age of a cached volume size before it
+	// is recalculated.
+	sizeCacheMaxAge = 5 * time.Minute
+	// sizeConcurrency is the maximum number of volume sizes that are
+	// calculated at the same time.
+	sizeConcurrency = 4
+)
+
 // VolumesService manages access to volumes
 // This is used as the main access point for volumes to higher level services and the API.
 type VolumesService struct {
@@ -33,6 +45,7 @@ type VolumesService struct {
 	ds           ds
 	pruneRunning int32
 	eventLogger  volumeEventLogger
+	sizes        *sizecache.Cache
 }
 
 // NewVolumeService creates a new volume service
@@ -46,7 +59,7 @@ func NewVolumeService(root string, pg plugingetter.PluginGetter, rootIDs idtools
 	if err != nil {
 		return nil, err
 	}
-	return &VolumesService{vs: vs, ds: ds, eventLogger: logger}, nil
+	return &VolumesService{vs: vs, ds: ds, eventLogger: logger, sizes: sizecache.New(sizeCacheMaxAge, sizeConcurrency)}, nil
 }
 
 // GetDriverList gets the list of registered volume drivers
@@ -128,11 +141,14 @@ func (s *VolumesService) Unmount(ctx context.Context, vol *types.Volume, ref str
 		}
 		return err
 	}
+	// the volume may have been written to while it was mounted
+	s.sizes.Invalidate(v.Name())
 	return v.Unmount(ref)
 }
 
 // Release releases a volume reference
 func (s *VolumesService) Release(ctx context.Context, name string, ref string) error {
+	s.sizes.Invalidate(name)
 	return s.vs.Release(ctx, name, ref)
 }
 
@@ -162,6 +178,7 @@ func (s *VolumesService) Remove(ctx context.Context, name string, rmOpts ...opts
 	}
 
 	if err == nil {
+		s.sizes.Invalidate(v.Name())
 		s.eventLogger.LogVolumeEvent(v.Name(), "destroy", map[string]string{"driver": v.DriverName()})
 	}
 	return err
@@ -183,7 +200,8 @@ var acceptedListFilters = map[string]bool{
 // Note that this intentionally skips volumes which have mount options. Typically
 // volumes with mount options are not really local even if they are using the
 // local driver.
-func (s *VolumesService) LocalVolumesSize(ctx context.Context) ([]*types.Volume, error) {
+// Sizes are cached between calls; set fresh to force them to be recalculated.
+func (s *VolumesService) LocalVolumesSize(ctx context.Context, fresh bool) ([]*types.Volume, error) {
 	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), CustomFilter(func(v volume.Volume) bool {
 		dv, ok := v.(volume.DetailedVolume)
 		return ok && len(dv.Options()) == 0
@@ -191,7 +209,23 @@ func (s *VolumesService) LocalVolumesSize(ctx context.Context) ([]*types.Volume,
 	if err != nil {
 		return nil, err
 	}
-	return s.volumesToAPI(ctx, ls, calcSize(true)), nil
+	return s.volumesToAPI(ctx, ls, calcSize(true), freshSize(fresh))
+}
+
+// MissingLocalVolumes returns the names of local volumes whose data
+// directory no longer exists.
+func (s *VolumesService) MissingLocalVolumes(ctx context.Context) ([]string, error) {
+	ls, _, err := s.vs.Find(ctx, ByDriver(volume.DefaultDriverName))
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, v := range ls {
+		if _, err := os.Stat(v.Path()); os.IsNotExist(err) {
+			missing = append(missing, v.Name())
+		}
+	}
+	return missing, nil
 }
 
 // Prune removes (local) volumes which match the past in filter arguments.
@@ -235,6 +269,7 @@ func (s *VolumesService) Prune(ctx context.Context, filter filters.Args) (*types
 			logrus.WithError(err).WithField("volume", v.Name()).Warnf("Could not determine size of volume")
 			continue
 		}
+		s.sizes.Invalidate(v.Name())
 		rep.SpaceReclaimed += uint64(vSize)
 		rep.VolumesDeleted = append(rep.VolumesDeleted, v.Name())
 	}
@@ -254,7 +289,11 @@ func (s *VolumesService) List(ctx context.Context, filter filters.Args) (volumes
 		return nil, nil, err
 	}
 
-	return s.volumesToAPI(ctx, volumes, useCachedPath(true)), warnings, nil
+	volumesOut, err = s.volumesToAPI(ctx, volumes, useCachedPath(true))
+	if err != nil {
+		return nil, nil, err
+	}
+	return volumesOut, warnings, nil
 }
 
 // Shutdown shuts down the image service and dependencies
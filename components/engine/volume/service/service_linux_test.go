@@ -47,7 +47,7 @@ func TestLocalVolumeSize(t *testing.T) {
 	err = ioutil.WriteFile(filepath.Join(v2.Mountpoint, "data"), data[:1], 0644)
 	assert.NilError(t, err)
 
-	ls, err := service.LocalVolumesSize(ctx)
+	ls, err := service.LocalVolumesSize(ctx, false)
 	assert.NilError(t, err)
 	assert.Assert(t, is.Len(ls, 2))
 
@@ -64,3 +64,93 @@ func TestLocalVolumeSize(t *testing.T) {
 		}
 	}
 }
+
+func TestLocalVolumeSizeCached(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	ctx := context.Background()
+	v, err := service.Create(ctx, "test1", volume.DefaultDriverName)
+	assert.NilError(t, err)
+
+	data := make([]byte, 1024)
+	err = ioutil.WriteFile(filepath.Join(v.Mountpoint, "data"), data, 0644)
+	assert.NilError(t, err)
+
+	ls, err := service.LocalVolumesSize(ctx, false)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(ls, 1))
+	assert.Check(t, is.Equal(ls[0].UsageData.Size, int64(len(data))))
+
+	err = ioutil.WriteFile(filepath.Join(v.Mountpoint, "more-data"), data, 0644)
+	assert.NilError(t, err)
+
+	// the cached size is returned until it is invalidated or refreshed
+	ls, err = service.LocalVolumesSize(ctx, false)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ls[0].UsageData.Size, int64(len(data))))
+
+	ls, err = service.LocalVolumesSize(ctx, true)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ls[0].UsageData.Size, int64(2*len(data))))
+
+	err = ioutil.WriteFile(filepath.Join(v.Mountpoint, "even-more-data"), data, 0644)
+	assert.NilError(t, err)
+	assert.NilError(t, service.Release(ctx, v.Name, "foo"))
+
+	ls, err = service.LocalVolumesSize(ctx, false)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ls[0].UsageData.Size, int64(3*len(data))))
+
+	// the volumes are not returned without their size if it is canceled
+	vols, _, err := service.vs.Find(ctx, ByDriver(volume.DefaultDriverName))
+	assert.NilError(t, err)
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	ls, err = service.volumesToAPI(canceled, vols, calcSize(true), freshSize(true))
+	assert.Check(t, is.ErrorContains(err, context.Canceled.Error()))
+	assert.Check(t, is.Len(ls, 0))
+}
+
+func TestMissingLocalVolumes(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	ctx := context.Background()
+	v1, err := service.Create(ctx, "test1", volume.DefaultDriverName)
+	assert.NilError(t, err)
+	_, err = service.Create(ctx, "test2", volume.DefaultDriverName)
+	assert.NilError(t, err)
+
+	missing, err := service.MissingLocalVolumes(ctx)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(missing, 0))
+
+	assert.NilError(t, os.RemoveAll(v1.Mountpoint))
+
+	missing, err = service.MissingLocalVolumes(ctx)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{"test1"}, missing))
+}